@@ -123,9 +123,9 @@ func PreviewThenPrompt(ctx context.Context, kind apitype.UpdateKind, stack Stack
 	// If there are no changes, or we're auto-approving or just previewing, we can skip the confirmation prompt.
 	if op.Opts.AutoApprove || kind == apitype.PreviewUpdate {
 		close(eventsChannel)
-		// If we're running in experimental mode then return the plan generated, else discard it. The user may
-		// be explicitly setting a plan but that's handled higher up the call stack.
-		if !op.Opts.Engine.Experimental {
+		// If we're running in experimental mode or deploying in waves then return the plan generated, else discard
+		// it. The user may be explicitly setting a plan but that's handled higher up the call stack.
+		if !op.Opts.Engine.UsePreviewPlan() {
 			plan = nil
 		}
 		return plan, changes, nil
@@ -210,8 +210,8 @@ func confirmBeforeUpdating(kind apitype.UpdateKind, stack Stack,
 		}
 
 		if response == string(yes) {
-			// If we're in experimental mode always use the plan
-			if opts.Engine.Experimental {
+			// If we're in experimental mode or deploying in waves always use the plan
+			if opts.Engine.UsePreviewPlan() {
 				return plan, nil
 			}
 			return nil, nil
@@ -200,6 +200,11 @@ type Backend interface {
 		imports []deploy.Import) (sdkDisplay.ResourceChanges, error)
 	// Refresh refreshes the stack's state from the cloud provider.
 	Refresh(ctx context.Context, stack Stack, op UpdateOperation) (sdkDisplay.ResourceChanges, error)
+	// DetectDrift previews a refresh of the stack's state from the cloud provider, streaming its events to the given
+	// channel. It never writes the stack's state.
+	DetectDrift(
+		ctx context.Context, stack Stack, op UpdateOperation, events chan<- engine.Event,
+	) (sdkDisplay.ResourceChanges, error)
 	// Destroy destroys all of this stack's resources.
 	Destroy(ctx context.Context, stack Stack, op UpdateOperation) (sdkDisplay.ResourceChanges, error)
 	// Watch watches the project's working directory for changes and automatically updates the active stack.
@@ -302,6 +307,15 @@ type SpecificDeploymentExporter interface {
 	ExportDeploymentForVersion(ctx context.Context, stack Stack, version string) (*apitype.UntypedDeployment, error)
 }
 
+// DeploymentRollbacker is an interface defining an additional capability of a Backend, specifically the ability to
+// replace a stack's deployment with one from its history and record that as a new update. Backends that don't
+// implement it are rolled back by importing the old deployment. This should be checked for dynamically.
+type DeploymentRollbacker interface {
+	// RollbackDeployment replaces the stack's deployment with the given deployment, which was exported from the
+	// update with the given version, and records the rollback in the stack's history.
+	RollbackDeployment(ctx context.Context, stack Stack, version string, deployment *apitype.UntypedDeployment) error
+}
+
 // UpdateOperation is a complete stack update operation (preview, update, import, refresh, or destroy).
 type UpdateOperation struct {
 	Proj               *workspace.Project
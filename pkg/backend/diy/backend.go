@@ -25,6 +25,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -51,6 +52,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
 	"github.com/pulumi/pulumi/pkg/v3/util/nosleep"
+	"github.com/pulumi/pulumi/pkg/v3/util/validation"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
@@ -93,6 +95,11 @@ type Backend interface {
 
 	// Lock the specified stack reference in this backend.
 	Lock(ctx context.Context, stackRef backend.StackReference) error
+
+	// CompactHistory removes the updates of a stack's history that the retention policy doesn't keep.
+	CompactHistory(
+		ctx context.Context, stackRef backend.StackReference, retention HistoryRetention,
+	) (HistoryCompaction, error)
 }
 
 type diyBackend struct {
@@ -108,6 +115,16 @@ type diyBackend struct {
 	mutex  sync.Mutex
 
 	lockID string
+	// heartbeats holds the *lockHeartbeat for each lock held by this backend, keyed by lock path.
+	heartbeats sync.Map
+	// checkpointVersions holds the *objectVersion of each checkpoint this backend has read or written, keyed by
+	// path, so that checkpoint writes can detect changes made by other processes.
+	checkpointVersions sync.Map
+	// fileRoot is the local directory that a file:// backend keeps its objects in, or empty for other backends.
+	fileRoot string
+	// journals holds the journal directories that this backend has seen or written journal batches in, which must be
+	// cleared when the checkpoint they build on is replaced.
+	journals sync.Map
 
 	gzip bool
 
@@ -186,6 +203,19 @@ func (r *diyBackendReference) StackBasePath() string { return r.store.StackBaseP
 func (r *diyBackendReference) HistoryDir() string    { return r.store.HistoryDir(r) }
 func (r *diyBackendReference) BackupDir() string     { return r.store.BackupDir(r) }
 
+// TagsPath returns the path to the file holding the stack's tags.
+//
+// The file sits next to the stack's checkpoint.
+// Its extension is deliberately not a known checkpoint extension
+// so that listing stacks ignores it.
+func (r *diyBackendReference) TagsPath() string { return r.StackBasePath() + ".tags" }
+
+// JournalDir returns the directory holding the journal of changes made to the stack's checkpoint by an update that
+// hasn't finished.
+//
+// Listing stacks ignores directories, so the journal can sit next to the stack's checkpoint.
+func (r *diyBackendReference) JournalDir() string { return r.StackBasePath() + ".journal" }
+
 func IsDIYBackendURL(urlstr string) bool {
 	u, err := url.Parse(urlstr)
 	if err != nil {
@@ -282,11 +312,22 @@ func newDIYBackend(
 	//nolint:wastedassign
 	bucket = nil
 
+	var fileRoot string
+	if p.Scheme == "file" {
+		// massageBlobPath has made the path absolute, but on Windows it starts with a "/" before the drive letter.
+		fileRoot = p.Path
+		if os.PathSeparator != '/' {
+			fileRoot = strings.TrimPrefix(fileRoot, "/")
+		}
+		fileRoot = filepath.FromSlash(fileRoot)
+	}
+
 	backend := &diyBackend{
 		d:           d,
 		originalURL: originalURL,
 		url:         u,
 		bucket:      wbucket,
+		fileRoot:    fileRoot,
 		lockID:      lockID.String(),
 		gzip:        gzipCompression,
 		Env:         opts.Env,
@@ -622,30 +663,8 @@ func (b *diyBackend) SetCurrentProject(project *workspace.Project) {
 	b.currentProject.Store(project)
 }
 
-func (b *diyBackend) GetPolicyPack(ctx context.Context, policyPack string,
-	d diag.Sink,
-) (backend.PolicyPack, error) {
-	return nil, errors.New("DIY backend does not support resource policy")
-}
-
-func (b *diyBackend) ListPolicyGroups(ctx context.Context, orgName string, _ backend.ContinuationToken) (
-	apitype.ListPolicyGroupsResponse, backend.ContinuationToken, error,
-) {
-	return apitype.ListPolicyGroupsResponse{}, nil, errors.New("DIY backend does not support resource policy")
-}
-
-func (b *diyBackend) ListPolicyPacks(ctx context.Context, orgName string, _ backend.ContinuationToken) (
-	apitype.ListPolicyPacksResponse, backend.ContinuationToken, error,
-) {
-	return apitype.ListPolicyPacksResponse{}, nil, errors.New("DIY backend does not support resource policy")
-}
-
 func (b *diyBackend) SupportsTags() bool {
-	return false
-}
-
-func (b *diyBackend) SupportsTemplates() bool {
-	return false
+	return true
 }
 
 func (b *diyBackend) SupportsOrganizations() bool {
@@ -653,7 +672,7 @@ func (b *diyBackend) SupportsOrganizations() bool {
 }
 
 func (b *diyBackend) SupportsProgress() bool {
-	return false
+	return true
 }
 
 func (b *diyBackend) SupportsDeployments() bool {
@@ -764,7 +783,16 @@ func (b *diyBackend) CreateStack(
 		}
 	}
 
-	stack := newStack(diyStackRef, b)
+	// TODO: This should load project config and pass it as the last parameter to GetEnvironmentTagsForCurrentStack.
+	tags, err := backend.GetEnvironmentTagsForCurrentStack(root, b.currentProject.Load(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting stack tags: %w", err)
+	}
+	if err := b.saveStackTags(ctx, diyStackRef, tags); err != nil {
+		return nil, err
+	}
+
+	stack := newStack(diyStackRef, b, tags)
 	b.d.Infof(diag.Message("", "Created stack '%s'"), stack.Ref())
 
 	return stack, nil
@@ -784,7 +812,12 @@ func (b *diyBackend) GetStack(ctx context.Context, stackRef backend.StackReferen
 		return nil, err
 	}
 
-	return newStack(diyStackRef, b), nil
+	tags, err := b.getStackTags(ctx, diyStackRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStack(diyStackRef, b, tags), nil
 }
 
 func (b *diyBackend) ListStacks(
@@ -799,7 +832,7 @@ func (b *diyBackend) ListStacks(
 	// Get the parallel value from environment variable or use a default value
 	parallel := b.getParallel()
 
-	// Note that the provided stack filter is only partially honored, since fields like organizations and tags
+	// Note that the provided stack filter is only partially honored, since organizations
 	// aren't persisted in the diy backend.
 	filteredStacks := slice.Prealloc[*diyBackendReference](len(stacks))
 	for _, stackRef := range stacks {
@@ -817,8 +850,9 @@ func (b *diyBackend) ListStacks(
 
 	// Create a slice to store results in the same order as filteredStacks
 	type checkpointResult struct {
-		ref *diyBackendReference
-		chk *apitype.CheckpointV3
+		ref  *diyBackendReference
+		chk  *apitype.CheckpointV3
+		lock *lockContent
 	}
 	results := make([]checkpointResult, len(filteredStacks))
 
@@ -840,7 +874,21 @@ func (b *diyBackend) ListStacks(
 				}
 				return err
 			}
-			results[i] = checkpointResult{ref: stackRef, chk: chk}
+			// Tags live in a separate file, so only read them if the filter asks for them.
+			if filterHasTags(filter) {
+				tags, err := b.getStackTags(ctx, stackRef)
+				if err != nil {
+					return err
+				}
+				if !tagsMatchFilter(filter, tags) {
+					return nil
+				}
+			}
+			lock, err := b.getActiveLock(ctx, stackRef)
+			if err != nil {
+				return fmt.Errorf("reading locks of stack %v: %w", stackRef, err)
+			}
+			results[i] = checkpointResult{ref: stackRef, chk: chk, lock: lock}
 			return nil
 		})
 	}
@@ -854,7 +902,7 @@ func (b *diyBackend) ListStacks(
 	summaries := []backend.StackSummary{}
 	for _, result := range results {
 		if result.ref != nil { // Skip entries where processing failed or stack disappeared
-			summaries = append(summaries, newDIYStackSummary(result.ref, result.chk))
+			summaries = append(summaries, newDIYStackSummary(result.ref, result.chk, result.lock))
 		}
 	}
 
@@ -959,12 +1007,13 @@ func (b *diyBackend) renameStack(ctx context.Context, oldRef *diyBackendReferenc
 	// To remove the old stack, just make a backup of the file and don't write out anything new.
 	file := b.stackPath(ctx, oldRef)
 	backupTarget(ctx, b.bucket, file, false)
+	b.forgetCheckpointVersion(file)
 
-	// And rename the history folder as well.
+	// And rename the history folder and tags as well.
 	if err = b.renameHistory(ctx, oldRef, newRef); err != nil {
 		return err
 	}
-	return err
+	return b.renameStackTags(ctx, oldRef, newRef)
 }
 
 func (b *diyBackend) GetLatestConfiguration(ctx context.Context,
@@ -1064,6 +1113,21 @@ func (b *diyBackend) Refresh(ctx context.Context, stack backend.Stack,
 	return backend.PreviewThenPromptThenExecute(ctx, apitype.RefreshUpdate, stack, op, b.apply)
 }
 
+func (b *diyBackend) DetectDrift(ctx context.Context, stack backend.Stack,
+	op backend.UpdateOperation, events chan<- engine.Event,
+) (sdkDisplay.ResourceChanges, error) {
+	// Detecting drift is a refresh that is only ever previewed, so the stack's state is never written and, like a
+	// preview, it doesn't need to lock the stack.
+	opts := backend.ApplierOptions{
+		DryRun:   true,
+		ShowLink: true,
+	}
+
+	op.Opts.Engine.GeneratePlan = false
+	_, changes, err := b.apply(ctx, apitype.RefreshUpdate, stack, op, opts, events)
+	return changes, err
+}
+
 func (b *diyBackend) Destroy(ctx context.Context, stack backend.Stack,
 	op backend.UpdateOperation,
 ) (sdkDisplay.ResourceChanges, error) {
@@ -1127,6 +1191,28 @@ func (b *diyBackend) apply(
 		return nil, nil, err
 	}
 
+	// Run the policy packs required by the backend's policy groups, just like the service does.
+	requiredPolicies, err := b.getRequiredPolicies(ctx, diyStackRef)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading required policies: %w", err)
+	}
+	op.Opts.Engine.RequiredPolicies = append(op.Opts.Engine.RequiredPolicies, requiredPolicies...)
+
+	// Refresh the stack's tags with the latest values from the environment and Pulumi.yaml,
+	// just like the service does when an update starts.
+	if !opts.DryRun {
+		tags, err := backend.GetMergedStackTags(ctx, stack, op.Root, op.Proj, op.StackConfiguration.Config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting stack tags: %w", err)
+		}
+		if err := b.saveStackTags(ctx, diyStackRef, tags); err != nil {
+			return nil, nil, fmt.Errorf("saving stack tags: %w", err)
+		}
+		if s, ok := stack.(*diyStack); ok {
+			s.tags = tags
+		}
+	}
+
 	// Spawn a display loop to show events on the CLI.
 	displayEvents := make(chan engine.Event)
 	displayDone := make(chan bool)
@@ -1235,6 +1321,14 @@ func (b *diyBackend) apply(
 	if !opts.DryRun {
 		saveErr = b.addToHistory(ctx, diyStackRef, info)
 		backupErr = b.backupStack(ctx, diyStackRef)
+
+		// Apply the configured history retention policy. Failing to do so doesn't affect the update itself, and
+		// will be retried after the next one, so only warn about it.
+		if retention := b.historyRetention(); saveErr == nil && (retention.prunes() || retention.Compress) {
+			if _, err := b.compactHistory(ctx, diyStackRef, retention); err != nil {
+				b.d.Warningf(diag.Message("", "Unable to apply the history retention policy: %v"), err)
+			}
+		}
 	}
 
 	if updateErr != nil {
@@ -1368,6 +1462,46 @@ func (b *diyBackend) ExportDeployment(ctx context.Context,
 	}, nil
 }
 
+var _ backend.SpecificDeploymentExporter = (*diyBackend)(nil)
+
+// ExportDeploymentForVersion exports the deployment saved by a past update of the stack. Versions number the updates
+// in the stack's history, starting from 1.
+func (b *diyBackend) ExportDeploymentForVersion(ctx context.Context, stk backend.Stack,
+	version string,
+) (*apitype.UntypedDeployment, error) {
+	diyStackRef, err := b.getReference(stk.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := strconv.Atoi(version)
+	if err != nil || v < 1 {
+		return nil, fmt.Errorf("invalid update version %q: versions are positive integers", version)
+	}
+
+	checkpointFile, err := b.getHistoryCheckpoint(ctx, diyStackRef, v)
+	if err != nil {
+		return nil, err
+	}
+	chk, err := readCheckpoint(ctx, b.bucket, checkpointFile)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, fmt.Errorf("the checkpoint of update %d of stack %s is no longer available", v, diyStackRef)
+		}
+		return nil, fmt.Errorf("read %q: %w", checkpointFile, err)
+	}
+
+	data, err := encoding.JSON.Marshal(chk.Latest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apitype.UntypedDeployment{
+		Version:    3,
+		Deployment: json.RawMessage(data),
+	}, nil
+}
+
 func (b *diyBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 	deployment *apitype.UntypedDeployment,
 ) error {
@@ -1382,6 +1516,12 @@ func (b *diyBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 	}
 	defer b.Unlock(ctx, diyStackRef)
 
+	return b.importDeployment(ctx, diyStackRef, deployment)
+}
+
+func (b *diyBackend) importDeployment(ctx context.Context, diyStackRef *diyBackendReference,
+	deployment *apitype.UntypedDeployment,
+) error {
 	stackName := diyStackRef.FullyQualifiedName()
 	chk, err := stack.MarshalUntypedDeploymentToVersionedCheckpoint(stackName, deployment)
 	if err != nil {
@@ -1392,6 +1532,38 @@ func (b *diyBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 	return err
 }
 
+var _ backend.DeploymentRollbacker = (*diyBackend)(nil)
+
+// RollbackDeployment replaces the stack's checkpoint with a deployment from its history. Unlike a plain import, the
+// rollback is recorded in the stack's history, so that it can itself be rolled back.
+func (b *diyBackend) RollbackDeployment(ctx context.Context, stk backend.Stack, version string,
+	deployment *apitype.UntypedDeployment,
+) error {
+	diyStackRef, err := b.getReference(stk.Ref())
+	if err != nil {
+		return err
+	}
+
+	err = b.Lock(ctx, diyStackRef)
+	if err != nil {
+		return err
+	}
+	defer b.Unlock(ctx, diyStackRef)
+
+	start := time.Now().Unix()
+	if err := b.importDeployment(ctx, diyStackRef, deployment); err != nil {
+		return err
+	}
+
+	return b.addToHistory(ctx, diyStackRef, backend.UpdateInfo{
+		Kind:      apitype.StackImportUpdate,
+		Message:   "Rollback to update " + version,
+		StartTime: start,
+		Result:    backend.SucceededResult,
+		EndTime:   time.Now().Unix(),
+	})
+}
+
 func (b *diyBackend) CurrentUser() (string, []string, *workspace.TokenInformation, error) {
 	user, err := user.Current()
 	if err != nil {
@@ -1408,8 +1580,24 @@ func (b *diyBackend) getStacks(ctx context.Context) ([]*diyBackendReference, err
 func (b *diyBackend) UpdateStackTags(ctx context.Context,
 	stack backend.Stack, tags map[apitype.StackTagName]string,
 ) error {
-	// The diy backend does not currently persist tags.
-	return errors.New("stack tags not supported in diy mode")
+	diyStackRef, err := b.getReference(stack.Ref())
+	if err != nil {
+		return err
+	}
+
+	if err := validation.ValidateStackTags(tags); err != nil {
+		return err
+	}
+
+	if err := b.saveStackTags(ctx, diyStackRef, tags); err != nil {
+		return err
+	}
+
+	// Keep the in-memory view of the stack consistent with what we just wrote.
+	if s, ok := stack.(*diyStack); ok {
+		s.tags = tags
+	}
+	return nil
 }
 
 func (b *diyBackend) EncryptStackDeploymentSettingsSecret(ctx context.Context,
@@ -1445,15 +1633,10 @@ func (b *diyBackend) GetStackDeploymentSettings(ctx context.Context,
 	return nil, errors.New("stack deployments not supported with diy backends")
 }
 
-func (b *diyBackend) ListTemplates(context.Context, string) (apitype.ListOrgTemplatesResponse, error) {
-	return apitype.ListOrgTemplatesResponse{}, errors.New("list templates not supported with diy backends")
-}
-
-func (b *diyBackend) DownloadTemplate(context.Context, string, string) (backend.TarReaderCloser, error) {
-	return nil, errors.New("download template not supported with diy backends")
-}
-
 func (b *diyBackend) CancelCurrentUpdate(ctx context.Context, stackRef backend.StackReference) error {
+	// If we hold a lock ourselves, stop keeping it alive before it is deleted.
+	b.stopLockHeartbeat(stackRef)
+
 	// Try to delete ALL the lock files
 	allFiles, err := listBucket(ctx, b.bucket, stackLockDir(stackRef.FullyQualifiedName()))
 	if err != nil {
@@ -1499,7 +1682,3 @@ func (b *diyBackend) getParallel() int {
 	}
 	return parallel
 }
-
-func (b *diyBackend) GetPackageRegistry() (backend.PackageRegistry, error) {
-	return nil, errors.New("package registry is not supported by diy backends")
-}
@@ -31,6 +31,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gocloud.dev/blob/fileblob"
@@ -1812,3 +1814,363 @@ func TestParallelStackFetchDefaultValue(t *testing.T) {
 		assert.True(t, stackNames[stackName], "Stack %s should be in the results", stackName)
 	}
 }
+
+func TestStackTags(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+	assert.True(t, b.SupportsTags())
+
+	aRef, err := b.ParseStackReference("organization/proj/a")
+	require.NoError(t, err)
+	aStack, err := b.CreateStack(ctx, aRef, "", nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, aStack.Tags())
+
+	// Set some tags and check they are visible on the stack object and on a fresh load.
+	tags := map[apitype.StackTagName]string{"owner": "platform", "env": "prod"}
+	require.NoError(t, b.UpdateStackTags(ctx, aStack, tags))
+	assert.Equal(t, tags, aStack.Tags())
+
+	aStack, err = b.GetStack(ctx, aRef)
+	require.NoError(t, err)
+	assert.Equal(t, tags, aStack.Tags())
+
+	// Invalid tag names are rejected.
+	err = b.UpdateStackTags(ctx, aStack, map[apitype.StackTagName]string{"bad name": "x"})
+	assert.ErrorContains(t, err, "stack tag names may only contain")
+
+	// Tags follow the stack when it's renamed.
+	bRefI, err := b.RenameStack(ctx, aStack, "organization/proj/b")
+	require.NoError(t, err)
+	bStack, err := b.GetStack(ctx, bRefI)
+	require.NoError(t, err)
+	assert.Equal(t, tags, bStack.Tags())
+
+	lb := b.(*diyBackend)
+	exists, err := lb.bucket.Exists(ctx, aRef.(*diyBackendReference).TagsPath())
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Removing the stack removes its tags.
+	_, err = b.RemoveStack(ctx, bStack, false)
+	require.NoError(t, err)
+	exists, err = lb.bucket.Exists(ctx, bRefI.(*diyBackendReference).TagsPath())
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestListStacksFilter_tags(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+
+	stackTags := map[string]map[apitype.StackTagName]string{
+		"organization/proj/a": {"owner": "alice", "env": "prod"},
+		"organization/proj/b": {"owner": "bob", "env": "prod"},
+		"organization/proj/c": nil,
+	}
+	for name, tags := range stackTags {
+		ref, err := b.ParseStackReference(name)
+		require.NoError(t, err)
+		s, err := b.CreateStack(ctx, ref, "", nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, b.UpdateStackTags(ctx, s, tags))
+	}
+
+	ptr := func(s string) *string { return &s }
+	tests := []struct {
+		desc   string
+		filter backend.ListStacksFilter
+		want   []string
+	}{
+		{
+			desc:   "name only",
+			filter: backend.ListStacksFilter{TagName: ptr("owner")},
+			want:   []string{"organization/proj/a", "organization/proj/b"},
+		},
+		{
+			desc:   "name and value",
+			filter: backend.ListStacksFilter{TagName: ptr("owner"), TagValue: ptr("bob")},
+			want:   []string{"organization/proj/b"},
+		},
+		{
+			desc:   "value only",
+			filter: backend.ListStacksFilter{TagValue: ptr("alice")},
+			want:   []string{"organization/proj/a"},
+		},
+		{
+			desc:   "no match",
+			filter: backend.ListStacksFilter{TagName: ptr("team")},
+			want:   nil,
+		},
+		{
+			desc:   "no tag filter",
+			filter: backend.ListStacksFilter{},
+			want:   []string{"organization/proj/a", "organization/proj/b", "organization/proj/c"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			stacks, _, err := b.ListStacks(ctx, tt.filter, nil /* inContToken */)
+			require.NoError(t, err)
+
+			var got []string
+			for _, s := range stacks {
+				got = append(got, s.Name().String())
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestSaveCheckpoint_conflict(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	newBackend := func() *diyBackend {
+		be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+		require.NoError(t, err)
+		return be.(*diyBackend)
+	}
+	b1, b2 := newBackend(), newBackend()
+
+	stackRef, err := b1.ParseStackReference("organization/project/stack")
+	require.NoError(t, err)
+	_, err = b1.CreateStack(ctx, stackRef, "", nil, nil)
+	require.NoError(t, err)
+	ref, err := b1.getReference(stackRef)
+	require.NoError(t, err)
+
+	// Our own writes don't conflict with each other.
+	_, err = b1.saveStack(ctx, ref, nil)
+	require.NoError(t, err)
+
+	// Another process reads and writes the checkpoint behind our back.
+	_, err = b2.getCheckpoint(ctx, ref)
+	require.NoError(t, err)
+	_, err = b2.saveStack(ctx, ref, nil)
+	require.NoError(t, err)
+
+	_, err = b1.saveStack(ctx, ref, nil)
+	assert.ErrorIs(t, err, errCheckpointConflict)
+
+	// Once we've seen the latest checkpoint we can write again.
+	_, err = b1.getCheckpoint(ctx, ref)
+	require.NoError(t, err)
+	_, err = b1.saveStack(ctx, ref, nil)
+	assert.NoError(t, err)
+
+	// The lock file that guards file:// checkpoint writes is gone once the writes are done.
+	matches, err := filepath.Glob(filepath.Join(tmpDir, ".pulumi", "stacks", "project", "*.lock"))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestLockLocalFile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "stacks", "dev.json")
+
+	unlock, err := lockLocalFile(ctx, path, localLockHeartbeat)
+	require.NoError(t, err)
+
+	// Someone else can't take the lock while we hold it.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = lockLocalFile(timeoutCtx, path, localLockHeartbeat)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+	unlock, err = lockLocalFile(ctx, path, localLockHeartbeat)
+	require.NoError(t, err)
+
+	// A lock left behind by a process that died is eventually taken over.
+	old := time.Now().Add(-2 * staleLocalLockHeartbeats * localLockHeartbeat)
+	require.NoError(t, os.Chtimes(path+".lock", old, old))
+	unlockStale, err := lockLocalFile(ctx, path, localLockHeartbeat)
+	require.NoError(t, err)
+	unlockStale()
+	unlock()
+}
+
+func TestLockLocalFile_heartbeat(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "stack.json")
+	heartbeat := 50 * time.Millisecond
+	unlock, err := lockLocalFile(ctx, path, heartbeat)
+	require.NoError(t, err)
+
+	// A held lock is refreshed, so it isn't taken over once it's older than the stale age.
+	time.Sleep(5 * staleLocalLockHeartbeats * heartbeat)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 2*staleLocalLockHeartbeats*heartbeat)
+	defer cancel()
+	_, err = lockLocalFile(timeoutCtx, path, heartbeat)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Once it's released it can be taken again.
+	unlock()
+	unlock, err = lockLocalFile(ctx, path, heartbeat)
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestIsPreconditionFailed(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isPreconditionFailed(awserr.New("PreconditionFailed", "At least one of the pre-conditions "+
+		"you specified did not hold", nil)))
+	assert.True(t, isPreconditionFailed(fmt.Errorf("upload: %w",
+		&smithy.GenericAPIError{Code: "ConditionalRequestConflict"})))
+	assert.False(t, isPreconditionFailed(awserr.New("NoSuchBucket", "The specified bucket does not exist", nil)))
+	assert.False(t, isPreconditionFailed(errors.New("boom")))
+}
+
+func TestExportDeploymentForVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+
+	stackRef, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	stk, err := b.CreateStack(ctx, stackRef, "", nil, nil)
+	require.NoError(t, err)
+	lb, ok := b.(*diyBackend)
+	require.True(t, ok)
+	ref, err := lb.getReference(stackRef)
+	require.NoError(t, err)
+
+	importResources := func(names ...string) {
+		var resources []*resource.State
+		for _, name := range names {
+			resources = append(resources, &resource.State{
+				URN:  resource.NewURN("a", "project", "", "a:b:c", name),
+				Type: "a:b:c",
+			})
+		}
+		snap := deploy.NewSnapshot(deploy.Manifest{}, nil, resources, nil, deploy.SnapshotMetadata{})
+		sdep, err := stack.SerializeDeployment(ctx, snap, false)
+		require.NoError(t, err)
+		data, err := encoding.JSON.Marshal(sdep)
+		require.NoError(t, err)
+		require.NoError(t, b.ImportDeployment(ctx, stk, &apitype.UntypedDeployment{
+			Version:    3,
+			Deployment: json.RawMessage(data),
+		}))
+		// Record the new state in the history, as an update would.
+		require.NoError(t, lb.addToHistory(ctx, ref, backend.UpdateInfo{
+			Kind:   apitype.UpdateUpdate,
+			Result: backend.SucceededResult,
+		}))
+	}
+	exportedResources := func(deployment *apitype.UntypedDeployment) []string {
+		snap, err := stack.DeserializeUntypedDeployment(ctx, deployment, b64.Base64SecretsProvider)
+		require.NoError(t, err)
+		var names []string
+		for _, r := range snap.Resources {
+			names = append(names, r.URN.Name())
+		}
+		return names
+	}
+
+	importResources("first")
+	importResources("first", "second")
+
+	exporter, ok := b.(backend.SpecificDeploymentExporter)
+	require.True(t, ok)
+	deployment, err := exporter.ExportDeploymentForVersion(ctx, stk, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first"}, exportedResources(deployment))
+	deployment, err = exporter.ExportDeploymentForVersion(ctx, stk, "2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, exportedResources(deployment))
+
+	// Plain imports, such as `pulumi stack import`, aren't recorded in the history.
+	history, err := b.GetHistory(ctx, stackRef, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	// Rolling back imports an old deployment, and is recorded as a new update.
+	deployment, err = exporter.ExportDeploymentForVersion(ctx, stk, "1")
+	require.NoError(t, err)
+	require.NoError(t, lb.RollbackDeployment(ctx, stk, "1", deployment))
+	current, err := b.ExportDeployment(ctx, stk)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first"}, exportedResources(current))
+
+	history, err = b.GetHistory(ctx, stackRef, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, 3, history[0].Version)
+	assert.Equal(t, apitype.StackImportUpdate, history[0].Kind)
+	assert.Equal(t, "Rollback to update 1", history[0].Message)
+
+	_, err = exporter.ExportDeploymentForVersion(ctx, stk, "4")
+	assert.ErrorContains(t, err, "has no update with version 4")
+	_, err = exporter.ExportDeploymentForVersion(ctx, stk, "latest")
+	assert.ErrorContains(t, err, "invalid update version")
+}
+
+func TestSaveStack_gzipStreaming(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	ctx := context.Background()
+
+	s := make(env.MapStore)
+	s[env.DIYBackendGzip.Var().Name()] = "true"
+	b, err := newDIYBackend(
+		ctx,
+		diagtest.LogSink(t), "file://"+filepath.ToSlash(stateDir),
+		&workspace.Project{Name: "testproj"},
+		&diyBackendOptions{Env: env.NewEnv(s)},
+	)
+	require.NoError(t, err)
+
+	stackRef, err := b.ParseStackReference("foo")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, stackRef, "", nil, nil)
+	require.NoError(t, err)
+	ref := stackRef.(*diyBackendReference)
+
+	var resources []*resource.State
+	for i := 0; i < 100; i++ {
+		resources = append(resources, &resource.State{
+			URN:  resource.NewURN("foo", "testproj", "", "a:b:c", fmt.Sprintf("res-%d", i)),
+			Type: "a:b:c",
+			Inputs: resource.PropertyMap{
+				"index": resource.NewNumberProperty(float64(i)),
+			},
+		})
+	}
+	snap := deploy.NewSnapshot(deploy.Manifest{}, nil, resources, nil, deploy.SnapshotMetadata{})
+	file, err := b.saveStack(ctx, ref, snap)
+	require.NoError(t, err)
+	assert.Equal(t, ".gz", filepath.Ext(file))
+
+	byts, err := b.bucket.ReadAll(ctx, file)
+	require.NoError(t, err)
+	assert.True(t, encoding.IsCompressed(byts))
+
+	chk, err := b.getCheckpoint(ctx, ref)
+	require.NoError(t, err)
+	require.NotNil(t, chk.Latest)
+	require.Len(t, chk.Latest.Resources, 100)
+	assert.Equal(t, resources[42].URN, chk.Latest.Resources[42].URN)
+}
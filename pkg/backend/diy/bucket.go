@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"gocloud.dev/blob"
 )
@@ -36,7 +37,10 @@ type Bucket interface {
 	SignedURL(ctx context.Context, key string, opts *blob.SignedURLOptions) (string, error)
 	ReadAll(ctx context.Context, key string) (_ []byte, err error)
 	WriteAll(ctx context.Context, key string, p []byte, opts *blob.WriterOptions) (err error)
+	NewReader(ctx context.Context, key string, opts *blob.ReaderOptions) (*blob.Reader, error)
+	NewWriter(ctx context.Context, key string, opts *blob.WriterOptions) (*blob.Writer, error)
 	Exists(ctx context.Context, key string) (bool, error)
+	Attributes(ctx context.Context, key string) (*blob.Attributes, error)
 }
 
 // wrappedBucket encapsulates a true gocloud blob.Bucket, but ensures that all paths we send to it
@@ -89,10 +93,22 @@ func (b *wrappedBucket) WriteAll(ctx context.Context, key string, p []byte, opts
 	return b.bucket.WriteAll(ctx, filepath.ToSlash(key), p, opts)
 }
 
+func (b *wrappedBucket) NewReader(ctx context.Context, key string, opts *blob.ReaderOptions) (*blob.Reader, error) {
+	return b.bucket.NewReader(ctx, filepath.ToSlash(key), opts)
+}
+
+func (b *wrappedBucket) NewWriter(ctx context.Context, key string, opts *blob.WriterOptions) (*blob.Writer, error) {
+	return b.bucket.NewWriter(ctx, filepath.ToSlash(key), opts)
+}
+
 func (b *wrappedBucket) Exists(ctx context.Context, key string) (bool, error) {
 	return b.bucket.Exists(ctx, filepath.ToSlash(key))
 }
 
+func (b *wrappedBucket) Attributes(ctx context.Context, key string) (*blob.Attributes, error) {
+	return b.bucket.Attributes(ctx, filepath.ToSlash(key))
+}
+
 // listBucket returns a list of all files in the bucket within a given directory. go-cloud sorts the results by key
 func listBucket(ctx context.Context, bucket Bucket, dir string) ([]*blob.ListObject, error) {
 	bucketIter := bucket.List(&blob.ListOptions{
@@ -116,6 +132,27 @@ func listBucket(ctx context.Context, bucket Bucket, dir string) ([]*blob.ListObj
 	return files, nil
 }
 
+// writeObject streams an object to the bucket. If write fails, the write is abandoned and any existing object with
+// the same key is left as it was.
+func writeObject(
+	ctx context.Context, bucket Bucket, key string, opts *blob.WriterOptions, write func(io.Writer) error,
+) error {
+	// Cancelling the context a writer was created with is how gocloud abandons a write.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w, err := bucket.NewWriter(ctx, key, opts)
+	if err != nil {
+		return err
+	}
+	if err := write(w); err != nil {
+		cancel()
+		contract.IgnoreClose(w)
+		return err
+	}
+	return w.Close()
+}
+
 // objectName returns the filename of a ListObject (an object from a bucket).
 func objectName(obj *blob.ListObject) string {
 	// If obj.Key ends in "/" we want to trim that to get the name just before
@@ -17,6 +17,7 @@ package diy
 import (
 	"context"
 	"errors"
+	"io"
 	"path/filepath"
 	"testing"
 
@@ -140,3 +141,26 @@ func TestRetry(t *testing.T) {
 	result = retryOp(retryFunc)
 	require.ErrorContains(t, result, "retry")
 }
+
+func TestWriteObject_abandonedOnError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, err := New(ctx, nil, "file://"+filepath.ToSlash(t.TempDir()), nil)
+	require.NoError(t, err)
+	bucket := b.(*diyBackend).bucket
+
+	require.NoError(t, bucket.WriteAll(ctx, "object", []byte("original"), nil))
+
+	giveErr := errors.New("great sadness")
+	err = writeObject(ctx, bucket, "object", nil, func(w io.Writer) error {
+		_, err := w.Write([]byte("partial"))
+		require.NoError(t, err)
+		return giveErr
+	})
+	assert.ErrorIs(t, err, giveErr)
+
+	byts, err := bucket.ReadAll(ctx, "object")
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(byts))
+}
@@ -0,0 +1,403 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	azblobblob "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	s3managerv2 "github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// errCheckpointConflict is returned when a checkpoint was changed in the bucket by someone else since this backend
+// last read or wrote it.
+var errCheckpointConflict = errors.New("checkpoint was modified by another process")
+
+// objectVersion identifies a particular revision of an object in the bucket.
+type objectVersion struct {
+	// ETag is the entity tag reported by the bucket. All drivers report one, although for file:// buckets it is
+	// derived from the modification time and size of the file.
+	ETag string
+	// Generation is the GCS object generation, which is what GCS write preconditions are expressed in.
+	Generation int64
+}
+
+// matches reports whether two versions identify the same revision of an object. GCS versions are compared by
+// generation, as the ETags GCS reports aren't quoted consistently; everything else is compared by ETag.
+func (v *objectVersion) matches(o *objectVersion) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+	if v.Generation != 0 || o.Generation != 0 {
+		return v.Generation == o.Generation
+	}
+	return v.ETag == o.ETag
+}
+
+// statObject returns the current version of the given object, or nil if it doesn't exist.
+func statObject(ctx context.Context, bucket Bucket, key string) (*objectVersion, error) {
+	attrs, err := bucket.Attributes(ctx, key)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	v := &objectVersion{ETag: attrs.ETag}
+	var gcsAttrs storage.ObjectAttrs
+	if attrs.As(&gcsAttrs) {
+		v.Generation = gcsAttrs.Generation
+	}
+	return v, nil
+}
+
+// rememberCheckpointVersion records the version of the checkpoint at the given path, so that the next write to it
+// can check that nobody else has written it in the meantime.
+func (b *diyBackend) rememberCheckpointVersion(path string, v *objectVersion) {
+	if v == nil {
+		b.checkpointVersions.Delete(path)
+		return
+	}
+	b.checkpointVersions.Store(path, v)
+}
+
+// forgetCheckpointVersion drops any recorded version of the checkpoint at the given path, for example because the
+// checkpoint has been deliberately deleted or moved.
+func (b *diyBackend) forgetCheckpointVersion(path string) {
+	b.checkpointVersions.Delete(path)
+}
+
+// writeCheckpointIfUnchanged writes a checkpoint, failing with errCheckpointConflict if it was changed since this
+// backend last read or wrote it. Checkpoints this backend has never seen are written unconditionally.
+func (b *diyBackend) writeCheckpointIfUnchanged(ctx context.Context, path string, write func(io.Writer) error) error {
+	var expected *objectVersion
+	if v, ok := b.checkpointVersions.Load(path); ok {
+		expected = v.(*objectVersion)
+	}
+
+	v, err := b.writeIfUnchanged(ctx, path, expected, write)
+	if err != nil {
+		if errors.Is(err, errObjectChanged) {
+			return checkpointConflict(path)
+		}
+		return err
+	}
+
+	// Record what we just wrote, so that our own writes don't look like conflicts next time around.
+	b.rememberCheckpointVersion(path, v)
+	return nil
+}
+
+// errObjectChanged is returned by writeIfUnchanged when the object is no longer at the expected version.
+var errObjectChanged = errors.New("object changed")
+
+// writeIfUnchanged writes an object as long as it is still at the expected version, and returns the version that
+// the write created. If expected is nil the object is written unconditionally. If the object has been changed or
+// deleted, errObjectChanged is returned.
+//
+// How the check is enforced depends on the bucket:
+//
+//   - gs://, azblob:// and s3:// writes carry a precondition (a generation match or an If-Match header) so the bucket
+//     itself rejects the write if the object has changed, and the version of the new object is taken from the
+//     response to the write. S3-compatible stores that ignore If-Match do not get this protection.
+//   - file:// writes are made while holding an exclusive lock file next to the object, which every conditional
+//     writer takes, so the check and the write can't interleave with another writer's.
+//   - Other drivers can't make the check atomically. For them the object is compared immediately before the write,
+//     which narrows but doesn't close the window in which a concurrent write can be lost.
+func (b *diyBackend) writeIfUnchanged(
+	ctx context.Context, key string, expected *objectVersion, write func(io.Writer) error,
+) (*objectVersion, error) {
+	if b.fileRoot != "" {
+		unlock, err := lockLocalFile(ctx, filepath.Join(b.fileRoot, filepath.FromSlash(key)), localLockHeartbeat)
+		if err != nil {
+			return nil, fmt.Errorf("lock %q: %w", key, err)
+		}
+		defer unlock()
+	}
+
+	if expected != nil {
+		current, err := statObject(ctx, b.bucket, key)
+		if err != nil {
+			return nil, fmt.Errorf("stat %q: %w", key, err)
+		}
+		if !current.matches(expected) {
+			return nil, errObjectChanged
+		}
+	}
+
+	cw := &conditionalWrite{expected: expected}
+	ctx = policy.WithCaptureResponse(ctx, &cw.azureResponse)
+	if err := writeObject(ctx, b.bucket, key, &blob.WriterOptions{BeforeWrite: cw.beforeWrite}, write); err != nil {
+		if expected != nil && (isPreconditionFailed(err) || gcerrors.Code(err) == gcerrors.NotFound) {
+			return nil, errObjectChanged
+		}
+		return nil, err
+	}
+
+	// Where the bucket told us the version it wrote we use that, as by the time we could stat the object someone
+	// else may have written it. file:// writes are still under the lock, so a stat sees our own write.
+	if v := cw.written(); v != nil {
+		return v, nil
+	}
+	v, err := statObject(ctx, b.bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("stat %q: %w", key, err)
+	}
+	return v, nil
+}
+
+func checkpointConflict(path string) error {
+	return fmt.Errorf("write %q: %w since it was read; refusing to overwrite it. "+
+		"Re-run the operation to pick up the latest state", path, errCheckpointConflict)
+}
+
+// conditionalWrite adds write preconditions to a write for the drivers that support them, and captures the version
+// of the object that the write created.
+type conditionalWrite struct {
+	// expected is the version the object must be at for the write to go ahead, or nil to write unconditionally.
+	expected *objectVersion
+
+	gcsWriter     *storage.Writer
+	azureResponse *http.Response
+	// s3ETag is the ETag of the object an S3 write created. It's set by a response handler, but only for the single
+	// request that completes the upload, and only read once the write has finished.
+	s3ETag string
+}
+
+// s3CompletesUpload reports whether the named S3 operation is the one that creates the object in an upload, which is
+// the request that preconditions must be sent with and the response that holds the new ETag.
+func s3CompletesUpload(operation string) bool {
+	return operation == "PutObject" || operation == "CompleteMultipartUpload"
+}
+
+func (cw *conditionalWrite) beforeWrite(as func(interface{}) bool) error {
+	var gcsObj **storage.ObjectHandle
+	if as(&gcsObj) {
+		if cw.expected != nil && cw.expected.Generation != 0 {
+			*gcsObj = (*gcsObj).If(storage.Conditions{GenerationMatch: cw.expected.Generation})
+		}
+		if !as(&cw.gcsWriter) {
+			cw.gcsWriter = nil
+		}
+		return nil
+	}
+
+	var azOpts *azblob.UploadStreamOptions
+	if as(&azOpts) {
+		if cw.expected != nil && cw.expected.ETag != "" {
+			etag := azcore.ETag(cw.expected.ETag)
+			azOpts.AccessConditions = &azblob.AccessConditions{
+				ModifiedAccessConditions: &azblobblob.ModifiedAccessConditions{IfMatch: &etag},
+			}
+		}
+		return nil
+	}
+
+	var s3Uploader *s3manager.Uploader
+	if as(&s3Uploader) {
+		s3Uploader.RequestOptions = append(s3Uploader.RequestOptions, func(r *request.Request) {
+			if !s3CompletesUpload(r.Operation.Name) {
+				return
+			}
+			if cw.expected != nil && cw.expected.ETag != "" {
+				r.Handlers.Build.PushBack(func(r *request.Request) {
+					r.HTTPRequest.Header.Set("If-Match", cw.expected.ETag)
+				})
+			}
+			r.Handlers.Complete.PushBack(func(r *request.Request) {
+				if r.Error == nil && r.HTTPResponse != nil {
+					cw.s3ETag = r.HTTPResponse.Header.Get("ETag")
+				}
+			})
+		})
+		return nil
+	}
+
+	var s3UploaderV2 *s3managerv2.Uploader
+	if as(&s3UploaderV2) {
+		s3UploaderV2.ClientOptions = append(s3UploaderV2.ClientOptions, func(o *s3v2.Options) {
+			o.APIOptions = append(o.APIOptions, cw.addS3Middleware)
+		})
+	}
+	return nil
+}
+
+// addS3Middleware adds the If-Match header to, and captures the ETag from, the request that completes an upload with
+// the AWS SDK v2.
+func (cw *conditionalWrite) addS3Middleware(stack *middleware.Stack) error {
+	if cw.expected != nil && cw.expected.ETag != "" {
+		ifMatch := middleware.BuildMiddlewareFunc("PulumiIfMatch", func(
+			ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+		) (middleware.BuildOutput, middleware.Metadata, error) {
+			if req, ok := in.Request.(*smithyhttp.Request); ok && s3CompletesUpload(awsmiddleware.GetOperationName(ctx)) {
+				req.Header.Set("If-Match", cw.expected.ETag)
+			}
+			return next.HandleBuild(ctx, in)
+		})
+		if err := stack.Build.Add(ifMatch, middleware.After); err != nil {
+			return err
+		}
+	}
+
+	captureETag := middleware.DeserializeMiddlewareFunc("PulumiCaptureETag", func(
+		ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+	) (middleware.DeserializeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleDeserialize(ctx, in)
+		if resp, ok := out.RawResponse.(*smithyhttp.Response); ok && err == nil &&
+			s3CompletesUpload(awsmiddleware.GetOperationName(ctx)) {
+			cw.s3ETag = resp.Header.Get("ETag")
+		}
+		return out, metadata, err
+	})
+	return stack.Deserialize.Add(captureETag, middleware.Before)
+}
+
+// written returns the version of the object that the write created, or nil if the driver didn't report it.
+func (cw *conditionalWrite) written() *objectVersion {
+	switch {
+	case cw.gcsWriter != nil && cw.gcsWriter.Attrs() != nil:
+		return &objectVersion{Generation: cw.gcsWriter.Attrs().Generation}
+	case cw.azureResponse != nil && cw.azureResponse.Header.Get("ETag") != "":
+		return &objectVersion{ETag: cw.azureResponse.Header.Get("ETag")}
+	case cw.s3ETag != "":
+		return &objectVersion{ETag: cw.s3ETag}
+	default:
+		return nil
+	}
+}
+
+// isPreconditionFailed reports whether a write was rejected because of its preconditions.
+func isPreconditionFailed(err error) bool {
+	if gcerrors.Code(err) == gcerrors.FailedPrecondition || bloberror.HasCode(err, bloberror.ConditionNotMet) {
+		return true
+	}
+
+	// S3 reports a failed If-Match as PreconditionFailed, or as ConditionalRequestConflict if another conditional
+	// write to the same key is in flight.
+	isS3Conflict := func(code string) bool {
+		return code == "PreconditionFailed" || code == "ConditionalRequestConflict"
+	}
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && isS3Conflict(awsErr.Code()) {
+		return true
+	}
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && isS3Conflict(apiErr.ErrorCode())
+}
+
+// localLockHeartbeat is how often a file:// checkpoint lock file is touched while it's held, so that it can be told
+// apart from one left behind by a process that died while holding it.
+const localLockHeartbeat = 10 * time.Second
+
+// staleLocalLockHeartbeats is how many heartbeats a file:// checkpoint lock file must miss before it's assumed to have
+// been left behind.
+const staleLocalLockHeartbeats = 3
+
+// lockLocalFile takes an exclusive lock on a file on the local filesystem by creating a lock file next to it, waiting
+// for any other holder to release it. The lock file is touched every heartbeat while it's held, and lock files that
+// haven't been touched for several heartbeats are taken over. The returned function releases the lock.
+func lockLocalFile(ctx context.Context, path string, heartbeat time.Duration) (func(), error) {
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return nil, err
+	}
+
+	staleAge := staleLocalLockHeartbeats * heartbeat
+	backoff := 10 * time.Millisecond
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			contract.IgnoreClose(f)
+			stop := startLocalLockHeartbeat(lockPath, heartbeat)
+			return func() {
+				stop()
+				contract.IgnoreError(os.Remove(lockPath))
+			}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleAge {
+			logging.V(5).Infof("removing stale checkpoint lock %s", lockPath)
+			contract.IgnoreError(os.Remove(lockPath))
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// startLocalLockHeartbeat touches a lock file every heartbeat until the returned function is called. The returned
+// function waits for any in-flight touch to finish, so that the lock file can be removed safely afterwards.
+func startLocalLockHeartbeat(lockPath string, heartbeat time.Duration) func() {
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			now := time.Now()
+			if err := os.Chtimes(lockPath, now, now); err != nil {
+				logging.V(5).Infof("refreshing checkpoint lock %s: %v", lockPath, err)
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// A stack's journal records the changes made to its checkpoint by an update that hasn't finished, so that updates
+// don't have to rewrite the whole checkpoint after every step. Objects can't be appended to, so the journal is a
+// directory with an object for each batch of changes, named so that the batches sort in the order they were written.
+//
+// Every batch names the checkpoint it builds on by the time in its manifest. Whenever the checkpoint is replaced the
+// journal is cleared, and should that be interrupted the batches left behind are ignored, because they build on a
+// checkpoint that no longer exists.
+
+// journalBatch is a batch of records written to a stack's journal at once.
+type journalBatch struct {
+	// Base is the time in the manifest of the checkpoint that the records build on.
+	Base    time.Time                         `json:"base"`
+	Records []backend.SerializedJournalRecord `json:"records"`
+}
+
+// diyJournalPersister is a diySnapshotPersister that journals the changes made to the snapshot in between saves.
+type diyJournalPersister struct {
+	diySnapshotPersister
+
+	// base is the time in the manifest of the snapshot that was last saved.
+	base time.Time
+	// secretsManager is the secrets manager of the snapshot that was last saved, which encrypts the journal's states.
+	secretsManager secrets.Manager
+	// seq is the sequence number of the last batch written to the journal.
+	seq int64
+}
+
+var _ backend.JournalPersister = (*diyJournalPersister)(nil)
+
+func (sp *diyJournalPersister) Save(snapshot *deploy.Snapshot) error {
+	if err := sp.diySnapshotPersister.Save(snapshot); err != nil {
+		return err
+	}
+	sp.base, sp.secretsManager = snapshot.Manifest.Time, snapshot.SecretsManager
+	return nil
+}
+
+func (sp *diyJournalPersister) Append(records []backend.JournalRecord) error {
+	var enc config.Encrypter = config.NewPanicCrypter()
+	if sp.secretsManager != nil {
+		enc = sp.secretsManager.Encrypter()
+	}
+	serialized, err := backend.SerializeJournalRecords(sp.ctx, records, enc)
+	if err != nil {
+		return err
+	}
+	batch := journalBatch{Base: sp.base, Records: serialized}
+
+	// Batches are named by the time they were written, but never share a name even if the clock doesn't move on.
+	seq := time.Now().UnixNano()
+	if seq <= sp.seq {
+		seq = sp.seq + 1
+	}
+	sp.seq = seq
+	return sp.backend.appendJournal(sp.ctx, sp.ref, seq, batch)
+}
+
+// appendJournal writes a batch of records to a stack's journal.
+func (b *diyBackend) appendJournal(
+	ctx context.Context, ref *diyBackendReference, seq int64, batch journalBatch,
+) error {
+	byts, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("marshalling journal: %w", err)
+	}
+
+	dir := ref.JournalDir()
+	b.journals.Store(dir, true)
+	key := fmt.Sprintf("%s/%020d.json", dir, seq)
+	if err := b.bucket.WriteAll(ctx, key, byts, nil); err != nil {
+		return fmt.Errorf("write %q: %w", key, err)
+	}
+	return nil
+}
+
+// readJournal reads the batches in a stack's journal, in the order they were written.
+func (b *diyBackend) readJournal(ctx context.Context, ref *diyBackendReference) ([]journalBatch, error) {
+	dir := ref.JournalDir()
+	files, err := listBucket(ctx, b.bucket, dir)
+	if err != nil {
+		// The journal doesn't exist unless an update is in progress, or was interrupted.
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var keys []string
+	for _, file := range files {
+		if !file.IsDir {
+			keys = append(keys, file.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	b.journals.Store(dir, true)
+	sort.Strings(keys)
+
+	batches := make([]journalBatch, len(keys))
+	for i, key := range keys {
+		byts, err := b.bucket.ReadAll(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("read %q: %w", key, err)
+		}
+		if err := json.Unmarshal(byts, &batches[i]); err != nil {
+			return nil, fmt.Errorf("corrupt store: unmarshal %q: %w", key, err)
+		}
+	}
+	return batches, nil
+}
+
+// clearJournal removes a stack's journal if this backend has seen one, because the checkpoint it builds on has been
+// replaced or removed.
+func (b *diyBackend) clearJournal(ctx context.Context, ref *diyBackendReference) error {
+	dir := ref.JournalDir()
+	if _, has := b.journals.LoadAndDelete(dir); !has {
+		return nil
+	}
+	return removeAllByPrefix(ctx, b.bucket, dir)
+}
+
+// applyJournal brings a checkpoint up to date with the records in its stack's journal. Records that build on other
+// checkpoints are stale, and ignored.
+func (b *diyBackend) applyJournal(
+	ctx context.Context, ref *diyBackendReference, chk *apitype.CheckpointV3,
+) (*apitype.CheckpointV3, error) {
+	batches, err := b.readJournal(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+	if len(batches) == 0 || chk == nil || chk.Latest == nil {
+		return chk, nil
+	}
+
+	var records []backend.SerializedJournalRecord
+	for _, batch := range batches {
+		if batch.Base.Equal(chk.Latest.Manifest.Time) {
+			records = append(records, batch.Records...)
+		}
+	}
+	if len(records) == 0 {
+		return chk, nil
+	}
+
+	logging.V(5).Infof("applying %d journal record(s) to the checkpoint of %s", len(records), ref.FullyQualifiedName())
+	replay := backend.NewJournalReplay(*chk.Latest)
+	if err := replay.Replay(records...); err != nil {
+		return nil, fmt.Errorf("replaying journal: %w", err)
+	}
+	replayed := *chk
+	replayed.Latest = replay.Deployment()
+	return &replayed, nil
+}
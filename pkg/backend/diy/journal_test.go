@@ -0,0 +1,179 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+type testRegisterResourceEvent struct {
+	deploy.SourceEvent
+}
+
+func (testRegisterResourceEvent) Goal() *resource.Goal               { return nil }
+func (testRegisterResourceEvent) Done(result *deploy.RegisterResult) {}
+
+// newJournalTestStack creates a stack in a new backend that journals updates, and saves a checkpoint with the given
+// resources.
+func newJournalTestStack(t *testing.T, names ...string) (*diyBackend, *diyBackendReference) {
+	ctx := context.Background()
+
+	s := make(env.MapStore)
+	s[env.DIYBackendJournal.Var().Name()] = "true"
+	b, err := newDIYBackend(
+		ctx,
+		diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()),
+		&workspace.Project{Name: "testproj"},
+		&diyBackendOptions{Env: env.NewEnv(s)},
+	)
+	require.NoError(t, err)
+
+	stackRef, err := b.ParseStackReference("foo")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, stackRef, "", nil, nil)
+	require.NoError(t, err)
+	ref := stackRef.(*diyBackendReference)
+
+	var resources []*resource.State
+	for _, name := range names {
+		resources = append(resources, newJournalTestResource(name))
+	}
+	snap := deploy.NewSnapshot(deploy.Manifest{Time: time.Now()}, nil, resources, nil, deploy.SnapshotMetadata{})
+	_, err = b.saveStack(ctx, ref, snap)
+	require.NoError(t, err)
+	return b, ref
+}
+
+func newJournalTestResource(name string) *resource.State {
+	return &resource.State{
+		URN:     resource.NewURN("foo", "testproj", "", "a:b:c", name),
+		Type:    "a:b:c",
+		Inputs:  resource.PropertyMap{},
+		Outputs: resource.PropertyMap{},
+	}
+}
+
+func resourceNames(resources []apitype.ResourceV3) []string {
+	names := make([]string, len(resources))
+	for i, res := range resources {
+		names[i] = res.URN.Name()
+	}
+	return names
+}
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newJournalTestStack(t, "a", "b")
+
+	snap, err := b.getSnapshot(ctx, stack.DefaultSecretsProvider, ref)
+	require.NoError(t, err)
+	a, bee := snap.Resources[0], snap.Resources[1]
+
+	persister := b.newSnapshotPersister(ctx, ref)
+	require.Implements(t, (*backend.JournalPersister)(nil), persister)
+	manager := backend.NewSnapshotManager(persister, nil, snap)
+
+	applyStep := func(step deploy.Step, successful bool) {
+		mutation, err := manager.BeginMutation(step)
+		require.NoError(t, err)
+		require.NoError(t, mutation.End(step, successful))
+	}
+
+	newA := newJournalTestResource("a")
+	newA.Outputs["foo"] = resource.NewStringProperty("bar")
+	applyStep(deploy.NewSameStep(nil, nil, a, newA), true)
+	applyStep(deploy.NewCreateStep(nil, testRegisterResourceEvent{}, newJournalTestResource("c")), true)
+	applyStep(deploy.NewDeleteStep(nil, map[resource.URN]bool{}, bee), true)
+	applyStep(deploy.NewCreateStep(nil, testRegisterResourceEvent{}, newJournalTestResource("d")), false)
+	_, err = manager.BeginMutation(deploy.NewCreateStep(nil, testRegisterResourceEvent{}, newJournalTestResource("e")))
+	require.NoError(t, err)
+
+	// While the update is in progress, reading the checkpoint replays the journal.
+	journal, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	assert.NotEmpty(t, journal)
+
+	chk, err := b.getCheckpoint(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, resourceNames(chk.Latest.Resources))
+	assert.Equal(t, "bar", chk.Latest.Resources[0].Outputs["foo"])
+	require.Len(t, chk.Latest.PendingOperations, 1)
+	assert.Equal(t, "e", chk.Latest.PendingOperations[0].Resource.URN.Name())
+	assert.Equal(t, apitype.OperationTypeCreating, chk.Latest.PendingOperations[0].Type)
+
+	_, err = b.getSnapshot(ctx, stack.DefaultSecretsProvider, ref)
+	require.NoError(t, err)
+
+	// Closing the manager compacts the journal into the checkpoint.
+	require.NoError(t, manager.Close())
+	journal, err = listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	assert.Empty(t, journal)
+
+	compacted, err := b.getCheckpoint(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, resourceNames(chk.Latest.Resources), resourceNames(compacted.Latest.Resources))
+	require.Len(t, compacted.Latest.PendingOperations, 1)
+}
+
+func TestJournal_stale(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newJournalTestStack(t, "a")
+
+	chk, err := b.getCheckpoint(ctx, ref)
+	require.NoError(t, err)
+
+	// A batch that builds on another checkpoint is ignored.
+	state := chk.Latest.Resources[0]
+	err = b.appendJournal(ctx, ref, 1, journalBatch{
+		Base: chk.Latest.Manifest.Time.Add(-time.Minute),
+		Records: []backend.SerializedJournalRecord{
+			{Kind: engine.JournalEntryBegin, Op: deploy.OpDelete, Old: 0, New: -1, OldState: &state},
+			{Kind: engine.JournalEntrySuccess, Op: deploy.OpDelete, Old: 0, New: -1, OldState: &state},
+		},
+	})
+	require.NoError(t, err)
+
+	replayed, err := b.getCheckpoint(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, resourceNames(replayed.Latest.Resources))
+
+	// And cleared once the checkpoint is replaced.
+	_, err = b.saveStack(ctx, ref, nil)
+	require.NoError(t, err)
+	journal, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	assert.Empty(t, journal)
+}
@@ -1,4 +1,4 @@
-// Copyright 2016-2018, Pulumi Corporation.
+// Copyright 2016-2024, Pulumi Corporation.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -19,18 +19,24 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"os/user"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"gocloud.dev/gcerrors"
+
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/fsutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
@@ -39,9 +45,27 @@ type lockContent struct {
 	Username  string    `json:"username"`
 	Hostname  string    `json:"hostname"`
 	Timestamp time.Time `json:"timestamp"`
+	// Heartbeat is refreshed periodically while the lock is held. Locks written by older versions of the CLI
+	// don't have a heartbeat, and are never considered stale.
+	Heartbeat time.Time `json:"heartbeat,omitempty"`
+}
+
+// defaultLockTimeout is how long a lock's heartbeat may go without being refreshed before the lock is considered
+// stale, unless overridden by PULUMI_DIY_BACKEND_LOCK_TIMEOUT.
+const defaultLockTimeout = 5 * time.Minute
+
+// isStale reports whether the process holding this lock has stopped refreshing its heartbeat.
+func (l *lockContent) isStale(now time.Time, timeout time.Duration) bool {
+	return !l.Heartbeat.IsZero() && now.Sub(l.Heartbeat) > timeout
+}
+
+// author returns a human readable description of who holds the lock.
+func (l *lockContent) author() string {
+	return l.Username + "@" + l.Hostname
 }
 
 func newLockContent() (*lockContent, error) {
+	now := time.Now()
 	u, err := user.Current()
 	if err != nil {
 		return nil, err
@@ -54,14 +78,81 @@ func newLockContent() (*lockContent, error) {
 		Pid:       os.Getpid(),
 		Username:  u.Username,
 		Hostname:  hostname,
-		Timestamp: time.Now(),
+		Timestamp: now,
+		Heartbeat: now,
 	}, nil
 }
 
+// lockFile is a lock held on a stack, along with where it is stored in the bucket.
+type lockFile struct {
+	key     string
+	content *lockContent
+}
+
+// readLocks reads all the locks currently held on the given stack, including locks held by this backend.
+// Locks that are deleted while they are being read are skipped.
+func (b *diyBackend) readLocks(ctx context.Context, stackRef backend.StackReference) ([]lockFile, error) {
+	allFiles, err := listBucket(ctx, b.bucket, stackLockDir(stackRef.FullyQualifiedName()))
+	if err != nil {
+		return nil, err
+	}
+
+	var locks []lockFile
+	for _, file := range allFiles {
+		// Skip the lock files that guard conditional writes to locks on file:// backends.
+		if file.IsDir || !strings.HasSuffix(file.Key, ".json") {
+			continue
+		}
+		content, err := b.bucket.ReadAll(ctx, file.Key)
+		if err != nil {
+			// The lock was released between listing and reading it.
+			if gcerrors.Code(err) == gcerrors.NotFound {
+				continue
+			}
+			return nil, err
+		}
+		l := &lockContent{}
+		if err := json.Unmarshal(content, &l); err != nil {
+			return nil, err
+		}
+		locks = append(locks, lockFile{key: file.Key, content: l})
+	}
+	return locks, nil
+}
+
+// getActiveLock returns the most recently taken lock on the given stack that isn't stale, or nil if the stack
+// isn't locked.
+func (b *diyBackend) getActiveLock(ctx context.Context, stackRef backend.StackReference) (*lockContent, error) {
+	locks, err := b.readLocks(ctx, stackRef)
+	if err != nil {
+		return nil, err
+	}
+
+	now, timeout := time.Now(), b.lockTimeout()
+	var active *lockContent
+	for _, l := range locks {
+		if l.content.isStale(now, timeout) {
+			continue
+		}
+		if active == nil || l.content.Timestamp.After(active.Timestamp) {
+			active = l.content
+		}
+	}
+	return active, nil
+}
+
+// lockTimeout returns how long a lock may go without a heartbeat before it can be taken over.
+func (b *diyBackend) lockTimeout() time.Duration {
+	if seconds := b.Env.GetInt(env.DIYBackendLockTimeout); seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultLockTimeout
+}
+
 // checkForLock looks for any existing locks for this stack, and returns a helpful diagnostic if there is one.
+// Locks whose heartbeat has expired are taken over: they are deleted, and a warning is printed.
 func (b *diyBackend) checkForLock(ctx context.Context, stackRef backend.StackReference) error {
-	stackName := stackRef.FullyQualifiedName()
-	allFiles, err := listBucket(ctx, b.bucket, stackLockDir(stackName))
+	locks, err := b.readLocks(ctx, stackRef)
 	if err != nil {
 		return err
 	}
@@ -70,37 +161,38 @@ func (b *diyBackend) checkForLock(ctx context.Context, stackRef backend.StackRef
 	// We need to convert it to a slash path (/) to compare it to
 	// the keys in the bucket which are always slash paths.
 	wantLock := filepath.ToSlash(b.lockPath(stackRef))
-	var lockKeys []string
-	for _, file := range allFiles {
-		if file.IsDir {
+	now, timeout := time.Now(), b.lockTimeout()
+	var otherLocks []lockFile
+	for _, l := range locks {
+		if l.key == wantLock {
 			continue
 		}
-		if file.Key != wantLock {
-			lockKeys = append(lockKeys, file.Key)
+		if l.content.isStale(now, timeout) {
+			b.d.Warningf(diag.Message("", "taking over stale lock %v created by %v (pid %v) at %v: "+
+				"no heartbeat since %v"),
+				b.lockURLForError(l.key),
+				l.content.author(),
+				l.content.Pid,
+				l.content.Timestamp.Format(time.RFC3339),
+				l.content.Heartbeat.Format(time.RFC3339))
+			if err := b.bucket.Delete(ctx, l.key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return fmt.Errorf("delete stale lock %q: %w", l.key, err)
+			}
+			continue
 		}
+		otherLocks = append(otherLocks, l)
 	}
 
-	if len(lockKeys) > 0 {
+	if len(otherLocks) > 0 {
 		errorString := fmt.Sprintf("the stack is currently locked by %v lock(s). Either wait for the other "+
-			"process(es) to end or delete the lock file with `pulumi cancel`.", len(lockKeys))
+			"process(es) to end or delete the lock file with `pulumi cancel`.", len(otherLocks))
 
-		for _, lock := range lockKeys {
-			content, err := b.bucket.ReadAll(ctx, lock)
-			if err != nil {
-				return err
-			}
-			l := &lockContent{}
-			err = json.Unmarshal(content, &l)
-			if err != nil {
-				return err
-			}
-
-			errorString += fmt.Sprintf("\n  %v: created by %v@%v (pid %v) at %v",
-				b.lockURLForError(lock),
-				l.Username,
-				l.Hostname,
-				l.Pid,
-				l.Timestamp.Format(time.RFC3339),
+		for _, l := range otherLocks {
+			errorString += fmt.Sprintf("\n  %v: created by %v (pid %v) at %v",
+				b.lockURLForError(l.key),
+				l.content.author(),
+				l.content.Pid,
+				l.content.Timestamp.Format(time.RFC3339),
 			)
 		}
 
@@ -134,7 +226,10 @@ func (b *diyBackend) Lock(ctx context.Context, stackRef backend.StackReference)
 	if err != nil {
 		return err
 	}
-	err = b.bucket.WriteAll(ctx, b.lockPath(stackRef), content, nil)
+	version, err := b.writeIfUnchanged(ctx, b.lockPath(stackRef), nil, func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -143,10 +238,13 @@ func (b *diyBackend) Lock(ctx context.Context, stackRef backend.StackReference)
 		b.Unlock(ctx, stackRef)
 		return err
 	}
+	b.startLockHeartbeat(stackRef, lockContent, version)
 	return nil
 }
 
 func (b *diyBackend) Unlock(ctx context.Context, stackRef backend.StackReference) {
+	b.stopLockHeartbeat(stackRef)
+
 	err := b.bucket.Delete(ctx, b.lockPath(stackRef))
 	if err != nil {
 		b.d.Errorf(
@@ -156,6 +254,76 @@ func (b *diyBackend) Unlock(ctx context.Context, stackRef backend.StackReference
 	}
 }
 
+// lockHeartbeat tracks the goroutine that keeps a lock held by this backend alive.
+type lockHeartbeat struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startLockHeartbeat starts refreshing the heartbeat of the given lock so that other processes don't consider it
+// stale. The heartbeat runs until Unlock is called. Each refresh only writes the lock if it is still at the version
+// we last wrote, so that a lock another process has taken over as stale is never recreated.
+func (b *diyBackend) startLockHeartbeat(
+	stackRef backend.StackReference, content *lockContent, version *objectVersion,
+) {
+	lockPath := b.lockPath(stackRef)
+	// The heartbeat must outlive the context of the operation that took the lock, since Unlock may be called
+	// after that context has been canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	hb := &lockHeartbeat{cancel: cancel, done: make(chan struct{})}
+	if old, loaded := b.heartbeats.Swap(lockPath, hb); loaded {
+		old.(*lockHeartbeat).stop()
+	}
+
+	interval := b.lockTimeout() / 3
+	go func() {
+		defer close(hb.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			content.Heartbeat = time.Now()
+			byts, err := json.Marshal(content)
+			contract.AssertNoErrorf(err, "marshalling lock content")
+			v, err := b.writeIfUnchanged(ctx, lockPath, version, func(w io.Writer) error {
+				_, err := w.Write(byts)
+				return err
+			})
+			switch {
+			case errors.Is(err, errObjectChanged):
+				if ctx.Err() == nil {
+					b.d.Warningf(diag.Message("", "the lock at %v was removed by another process"),
+						b.lockURLForError(lockPath))
+				}
+				return
+			case err != nil:
+				logging.V(5).Infof("refreshing lock %v: %v", lockPath, err)
+			default:
+				version = v
+			}
+		}
+	}()
+}
+
+// stopLockHeartbeat stops refreshing the heartbeat of the given lock, and waits for any in-flight refresh to finish
+// so that it can't recreate the lock after it has been deleted.
+func (b *diyBackend) stopLockHeartbeat(stackRef backend.StackReference) {
+	if hb, loaded := b.heartbeats.LoadAndDelete(b.lockPath(stackRef)); loaded {
+		hb.(*lockHeartbeat).stop()
+	}
+}
+
+func (hb *lockHeartbeat) stop() {
+	hb.cancel()
+	<-hb.done
+}
+
 func lockDir() string {
 	return path.Join(workspace.BookkeepingDir, workspace.LockDir)
 }
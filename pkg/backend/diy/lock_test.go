@@ -15,11 +15,32 @@
 package diy
 
 import (
+	"context"
+	"encoding/json"
+	"path"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
 )
 
+// writeForeignLock writes a lock for the stack as if it had been taken by another process.
+func writeForeignLock(t *testing.T, b *diyBackend, stackRef backend.StackReference, content lockContent) string {
+	t.Helper()
+
+	key := path.Join(stackLockDir(stackRef.FullyQualifiedName()), "other.json")
+	byts, err := json.Marshal(content)
+	require.NoError(t, err)
+	require.NoError(t, b.bucket.WriteAll(context.Background(), key, byts, nil))
+	return key
+}
+
 func TestLockURLForError(t *testing.T) {
 	t.Parallel()
 
@@ -80,3 +101,167 @@ func TestLockURLForError(t *testing.T) {
 		})
 	}
 }
+
+func TestLock_staleLocks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		heartbeat time.Time
+		takenOver bool
+	}{
+		{name: "live", heartbeat: time.Now(), takenOver: false},
+		{name: "stale", heartbeat: time.Now().Add(-time.Hour), takenOver: true},
+		// Locks from older CLIs have no heartbeat, so we can't tell whether they are still held.
+		{name: "legacy", takenOver: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			tmpDir := t.TempDir()
+			be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+			require.NoError(t, err)
+			b := be.(*diyBackend)
+
+			stackRef, err := b.ParseStackReference("organization/project/stack")
+			require.NoError(t, err)
+			otherLock := writeForeignLock(t, b, stackRef, lockContent{
+				Pid:       1,
+				Username:  "ci",
+				Hostname:  "runner",
+				Timestamp: time.Now().Add(-2 * time.Hour),
+				Heartbeat: tt.heartbeat,
+			})
+
+			err = b.Lock(ctx, stackRef)
+			otherExists, existsErr := b.bucket.Exists(ctx, otherLock)
+			require.NoError(t, existsErr)
+			if tt.takenOver {
+				require.NoError(t, err)
+				defer b.Unlock(ctx, stackRef)
+				assert.False(t, otherExists)
+			} else {
+				assert.ErrorContains(t, err, "created by ci@runner (pid 1)")
+				assert.True(t, otherExists)
+			}
+		})
+	}
+}
+
+func TestLock_heartbeat(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := newDIYBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil,
+		&diyBackendOptions{Env: env.NewEnv(env.MapStore{"PULUMI_DIY_BACKEND_LOCK_TIMEOUT": "1"})})
+	require.NoError(t, err)
+
+	stackRef, err := b.ParseStackReference("organization/project/stack")
+	require.NoError(t, err)
+	require.NoError(t, b.Lock(ctx, stackRef))
+
+	readLock := func() lockContent {
+		byts, err := b.bucket.ReadAll(ctx, b.lockPath(stackRef))
+		require.NoError(t, err)
+		var l lockContent
+		require.NoError(t, json.Unmarshal(byts, &l))
+		return l
+	}
+	initial := readLock()
+	assert.False(t, initial.Heartbeat.IsZero())
+	assert.Eventually(t, func() bool {
+		return readLock().Heartbeat.After(initial.Heartbeat)
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// Unlocking stops the heartbeat, so the lock must not be recreated.
+	b.Unlock(ctx, stackRef)
+	time.Sleep(500 * time.Millisecond)
+	exists, err := b.bucket.Exists(ctx, b.lockPath(stackRef))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestLock_heartbeatAfterTakeover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := newDIYBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil,
+		&diyBackendOptions{Env: env.NewEnv(env.MapStore{"PULUMI_DIY_BACKEND_LOCK_TIMEOUT": "1"})})
+	require.NoError(t, err)
+
+	lock := func(name string) backend.StackReference {
+		stackRef, err := b.ParseStackReference("organization/project/" + name)
+		require.NoError(t, err)
+		require.NoError(t, b.Lock(ctx, stackRef))
+		t.Cleanup(func() { b.stopLockHeartbeat(stackRef) })
+		return stackRef
+	}
+
+	// Another process overwrites the lock. The heartbeat must not write over it.
+	overwritten := lock("overwritten")
+	foreign := []byte(`{"pid":1,"username":"ci","hostname":"runner"}`)
+	require.NoError(t, b.bucket.WriteAll(ctx, b.lockPath(overwritten), foreign, nil))
+
+	// Another process deletes the lock as stale. The heartbeat must not recreate it.
+	deleted := lock("deleted")
+	require.NoError(t, b.bucket.Delete(ctx, b.lockPath(deleted)))
+
+	// Wait for a few heartbeats.
+	time.Sleep(time.Second)
+
+	byts, err := b.bucket.ReadAll(ctx, b.lockPath(overwritten))
+	require.NoError(t, err)
+	assert.Equal(t, foreign, byts)
+	exists, err := b.bucket.Exists(ctx, b.lockPath(deleted))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestListStacks_updateInProgress(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+	b := be.(*diyBackend)
+
+	lockedRef, err := b.ParseStackReference("organization/project/locked")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, lockedRef, "", nil, nil)
+	require.NoError(t, err)
+	staleRef, err := b.ParseStackReference("organization/project/stale")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, staleRef, "", nil, nil)
+	require.NoError(t, err)
+
+	writeForeignLock(t, b, lockedRef, lockContent{
+		Username: "ci", Hostname: "runner", Timestamp: time.Now(), Heartbeat: time.Now(),
+	})
+	writeForeignLock(t, b, staleRef, lockContent{
+		Username: "ci", Hostname: "runner", Timestamp: time.Now(), Heartbeat: time.Now().Add(-time.Hour),
+	})
+
+	summaries, _, err := b.ListStacks(ctx, backend.ListStacksFilter{}, nil)
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	for _, s := range summaries {
+		op := s.(diyStackSummary).CurrentOperation()
+		switch s.Name().Name().String() {
+		case "locked":
+			require.NotNil(t, op)
+			assert.Equal(t, "ci@runner", op.Author)
+			assert.Equal(t, int64(0), s.LastUpdate().Unix())
+		case "stale":
+			assert.Nil(t, op)
+		default:
+			t.Fatalf("unexpected stack %v", s.Name())
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// PackagesDir is a path under the state's root directory
+// where the diy backend stores published packages.
+//
+// Each package version lives in <source>/<publisher>/<name>/<version>/,
+// which holds the package's schema, README and installation docs,
+// and a package.json metadata file that is written last to mark the version as published.
+var PackagesDir = filepath.Join(workspace.BookkeepingDir, "packages")
+
+const (
+	packageMetadataFile    = "package.json"
+	packageSchemaFile      = "schema.json"
+	packageReadmeFile      = "README.md"
+	packageInstallDocsFile = "installation-configuration.md"
+)
+
+// diyPackageRegistry is a package registry stored in the bucket of a diy backend.
+type diyPackageRegistry struct {
+	bucket Bucket
+}
+
+var (
+	_ backend.PackageRegistry = (*diyPackageRegistry)(nil)
+	_ backend.PackageResolver = (*diyPackageRegistry)(nil)
+)
+
+func (b *diyBackend) GetPackageRegistry() (backend.PackageRegistry, error) {
+	return &diyPackageRegistry{bucket: b.bucket}, nil
+}
+
+// packageVersionDir returns the directory in the bucket holding the given package version.
+func packageVersionDir(source, publisher, name string, version semver.Version) string {
+	return path.Join(filepath.ToSlash(PackagesDir), source, publisher, name, version.String())
+}
+
+// validatePackagePathPart checks that a part of a package's identity can be safely used as a path segment.
+func validatePackagePathPart(kind, part string) error {
+	if part == "" {
+		return fmt.Errorf("package %s must not be empty", kind)
+	}
+	if part == "." || part == ".." || strings.ContainsAny(part, `/\`) {
+		return fmt.Errorf("invalid package %s %q", kind, part)
+	}
+	return nil
+}
+
+func (r *diyPackageRegistry) Publish(ctx context.Context, op apitype.PackagePublishOp) error {
+	for _, p := range []struct{ kind, value string }{
+		{"source", op.Source}, {"publisher", op.Publisher}, {"name", op.Name},
+	} {
+		if err := validatePackagePathPart(p.kind, p.value); err != nil {
+			return err
+		}
+	}
+
+	dir := packageVersionDir(op.Source, op.Publisher, op.Name, op.Version)
+	metadataPath := path.Join(dir, packageMetadataFile)
+	exists, err := r.bucket.Exists(ctx, metadataPath)
+	if err != nil {
+		return fmt.Errorf("check %q: %w", metadataPath, err)
+	}
+	if exists {
+		return fmt.Errorf("package %s/%s/%s@%s has already been published",
+			op.Source, op.Publisher, op.Name, op.Version)
+	}
+
+	if op.Schema == nil {
+		return fmt.Errorf("package %s has no schema", op.Name)
+	}
+	schemaBytes, err := io.ReadAll(op.Schema)
+	if err != nil {
+		return fmt.Errorf("reading schema: %w", err)
+	}
+	// Only pick out the fields we need for the metadata; the schema itself was validated before publishing.
+	var spec struct {
+		DisplayName string `json:"displayName"`
+		Description string `json:"description"`
+		Repository  string `json:"repository"`
+	}
+	if err := json.Unmarshal(schemaBytes, &spec); err != nil {
+		return fmt.Errorf("unmarshalling schema: %w", err)
+	}
+
+	artifacts := []struct {
+		file   string
+		reader io.Reader
+	}{
+		{packageSchemaFile, bytes.NewReader(schemaBytes)},
+		{packageReadmeFile, op.Readme},
+		{packageInstallDocsFile, op.InstallDocs},
+	}
+	for _, a := range artifacts {
+		if a.reader == nil {
+			continue
+		}
+		byts, err := io.ReadAll(a.reader)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", a.file, err)
+		}
+		artifactPath := path.Join(dir, a.file)
+		if err := r.bucket.WriteAll(ctx, artifactPath, byts, nil); err != nil {
+			return fmt.Errorf("write %q: %w", artifactPath, err)
+		}
+	}
+
+	metadata, err := json.MarshalIndent(apitype.PackageMetadata{
+		Name:        op.Name,
+		Publisher:   op.Publisher,
+		Source:      op.Source,
+		Version:     op.Version,
+		Title:       spec.DisplayName,
+		Description: spec.Description,
+		RepoURL:     spec.Repository,
+		CreatedAt:   time.Now().UTC(),
+	}, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshalling package metadata: %w", err)
+	}
+	if err := r.bucket.WriteAll(ctx, metadataPath, metadata, nil); err != nil {
+		return fmt.Errorf("write %q: %w", metadataPath, err)
+	}
+	return nil
+}
+
+// publishedPackage identifies a published package version by the location of its metadata file.
+type publishedPackage struct {
+	source, publisher, name string
+	version                 semver.Version
+}
+
+// listPublishedPackages finds every published version of the packages with the given name. source and publisher
+// narrow the search if they are set; otherwise the directories of every source and publisher are walked to find the
+// package's directories, so that the bucket is only ever listed under prefixes that can hold the package.
+func (r *diyPackageRegistry) listPublishedPackages(
+	ctx context.Context, source, publisher, name string,
+) ([]publishedPackage, error) {
+	root := filepath.ToSlash(PackagesDir)
+
+	sources := []string{source}
+	if source == "" {
+		var err error
+		if sources, err = r.listPackageDirs(ctx, root); err != nil {
+			return nil, err
+		}
+	}
+
+	var pkgs []publishedPackage
+	for _, source := range sources {
+		publishers := []string{publisher}
+		if publisher == "" {
+			var err error
+			if publishers, err = r.listPackageDirs(ctx, path.Join(root, source)); err != nil {
+				return nil, err
+			}
+		}
+		for _, publisher := range publishers {
+			versions, err := r.listPackageDirs(ctx, path.Join(root, source, publisher, name))
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range versions {
+				version, err := semver.Parse(v)
+				if err != nil {
+					continue
+				}
+				// Only versions whose metadata file has been written are published.
+				metadataPath := path.Join(packageVersionDir(source, publisher, name, version), packageMetadataFile)
+				exists, err := r.bucket.Exists(ctx, metadataPath)
+				if err != nil {
+					return nil, fmt.Errorf("check %q: %w", metadataPath, err)
+				}
+				if !exists {
+					continue
+				}
+				pkgs = append(pkgs, publishedPackage{
+					source: source, publisher: publisher, name: name, version: version,
+				})
+			}
+		}
+	}
+	return pkgs, nil
+}
+
+// listPackageDirs returns the names of the directories directly under the given directory of the package registry.
+func (r *diyPackageRegistry) listPackageDirs(ctx context.Context, dir string) ([]string, error) {
+	files, err := listBucket(ctx, r.bucket, dir)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, file := range files {
+		if !file.IsDir {
+			continue
+		}
+		dirs = append(dirs, path.Base(strings.TrimSuffix(file.Key, "/")))
+	}
+	return dirs, nil
+}
+
+func (r *diyPackageRegistry) GetPackage(
+	ctx context.Context, source, publisher, name string, version *semver.Version,
+) (apitype.PackageMetadata, error) {
+	// The reference is used to build the paths that are listed, so it must not be able to escape the registry.
+	for _, part := range []string{source, publisher, name} {
+		if part != "" && validatePackagePathPart("name", part) != nil {
+			return apitype.PackageMetadata{}, fmt.Errorf("%w: %s", backend.ErrPackageNotFound, name)
+		}
+	}
+	pkgs, err := r.listPublishedPackages(ctx, source, publisher, name)
+	if err != nil {
+		return apitype.PackageMetadata{}, err
+	}
+
+	var found *publishedPackage
+	for i, p := range pkgs {
+		if (source != "" && p.source != source) || (publisher != "" && p.publisher != publisher) {
+			continue
+		}
+		if version != nil && !p.version.Equals(*version) {
+			continue
+		}
+		if found != nil && (found.source != p.source || found.publisher != p.publisher) {
+			return apitype.PackageMetadata{}, fmt.Errorf(
+				"package %s is ambiguous: it is published as both %s/%s/%s and %s/%s/%s",
+				name, found.source, found.publisher, name, p.source, p.publisher, name)
+		}
+		if found == nil || p.version.GT(found.version) {
+			found = &pkgs[i]
+		}
+	}
+	if found == nil {
+		return apitype.PackageMetadata{}, fmt.Errorf("%w: %s", backend.ErrPackageNotFound, name)
+	}
+
+	metadataPath := path.Join(
+		packageVersionDir(found.source, found.publisher, found.name, found.version), packageMetadataFile)
+	byts, err := r.bucket.ReadAll(ctx, metadataPath)
+	if err != nil {
+		return apitype.PackageMetadata{}, fmt.Errorf("read %q: %w", metadataPath, err)
+	}
+	var metadata apitype.PackageMetadata
+	if err := json.Unmarshal(byts, &metadata); err != nil {
+		return apitype.PackageMetadata{}, fmt.Errorf("corrupt store: unmarshal %q: %w", metadataPath, err)
+	}
+	return metadata, nil
+}
+
+func (r *diyPackageRegistry) GetPackageSchema(
+	ctx context.Context, pkg apitype.PackageMetadata,
+) (io.ReadCloser, error) {
+	schemaPath := path.Join(
+		packageVersionDir(pkg.Source, pkg.Publisher, pkg.Name, pkg.Version), packageSchemaFile)
+	byts, err := r.bucket.ReadAll(ctx, schemaPath)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, fmt.Errorf("%w: %s/%s/%s@%s has no schema",
+				backend.ErrPackageNotFound, pkg.Source, pkg.Publisher, pkg.Name, pkg.Version)
+		}
+		return nil, fmt.Errorf("read %q: %w", schemaPath, err)
+	}
+	return io.NopCloser(bytes.NewReader(byts)), nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+func TestPackageRegistry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+
+	registry, err := be.GetPackageRegistry()
+	require.NoError(t, err)
+	resolver := registry.(backend.PackageResolver)
+
+	publish := func(publisher, version string) error {
+		return registry.Publish(ctx, apitype.PackagePublishOp{
+			Source:    "pulumi",
+			Publisher: publisher,
+			Name:      "component",
+			Version:   semver.MustParse(version),
+			Schema:    strings.NewReader(`{"name": "component", "displayName": "Component v` + version + `"}`),
+			Readme:    strings.NewReader("# Component"),
+		})
+	}
+	require.NoError(t, publish("acme", "1.0.0"))
+	require.NoError(t, publish("acme", "1.2.0"))
+	assert.ErrorContains(t, publish("acme", "1.2.0"), "has already been published")
+
+	// Without a version we get the latest one.
+	pkg, err := resolver.GetPackage(ctx, "", "", "component", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", pkg.Publisher)
+	assert.Equal(t, semver.MustParse("1.2.0"), pkg.Version)
+	assert.Equal(t, "Component v1.2.0", pkg.Title)
+
+	v := semver.MustParse("1.0.0")
+	pkg, err = resolver.GetPackage(ctx, "pulumi", "acme", "component", &v)
+	require.NoError(t, err)
+	schema, err := resolver.GetPackageSchema(ctx, pkg)
+	require.NoError(t, err)
+	defer schema.Close()
+	byts, err := io.ReadAll(schema)
+	require.NoError(t, err)
+	assert.Contains(t, string(byts), "Component v1.0.0")
+
+	_, err = resolver.GetPackage(ctx, "", "", "missing", nil)
+	assert.ErrorIs(t, err, backend.ErrPackageNotFound)
+
+	// The same name from two publishers needs the publisher to disambiguate.
+	require.NoError(t, publish("other", "2.0.0"))
+	_, err = resolver.GetPackage(ctx, "", "", "component", nil)
+	assert.ErrorContains(t, err, "is ambiguous")
+	pkg, err = resolver.GetPackage(ctx, "", "other", "component", nil)
+	require.NoError(t, err)
+	assert.Equal(t, semver.MustParse("2.0.0"), pkg.Version)
+
+	assert.ErrorContains(t, registry.Publish(ctx, apitype.PackagePublishOp{
+		Source: "pulumi", Publisher: "../escape", Name: "component", Version: v,
+	}), "invalid package publisher")
+}
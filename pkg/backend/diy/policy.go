@@ -0,0 +1,664 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	resourceanalyzer "github.com/pulumi/pulumi/pkg/v3/resource/analyzer"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// These should be constants
+// but we can't make a constant from filepath.Join.
+var (
+	// policyGroupsPath is the path inside the bucket of the file
+	// that registers the policy groups enforced by the backend.
+	//
+	// It lives next to the metadata file (see pulumiMetaPath).
+	policyGroupsPath = filepath.Join(workspace.BookkeepingDir, "policygroups.json")
+
+	// PolicyPacksDir is a path under the state's root directory
+	// where the diy backend stores published policy packs.
+	PolicyPacksDir = filepath.Join(workspace.BookkeepingDir, "policies")
+)
+
+// diyOrgName is the name of the one and only organization in a diy backend.
+const diyOrgName = "organization"
+
+// defaultPolicyGroup is the name of the policy group that applies to every stack in the backend.
+const defaultPolicyGroup = "default-policy-group"
+
+// policyGroups holds the contents of the policy groups file in a diy backend.
+//
+// Policy groups decide which published policy packs are required for which stacks.
+// The default policy group applies to every stack,
+// other policy groups apply to the stacks listed in them.
+// The file may be edited by hand to change which stacks belong to a policy group.
+type policyGroups struct {
+	PolicyGroups []*policyGroup `json:"policyGroups"`
+}
+
+// policyGroup is a named set of required policy packs and the stacks they apply to.
+type policyGroup struct {
+	Name string `json:"name"`
+	// IsOrgDefault is true for the policy group that applies to all stacks.
+	IsOrgDefault bool `json:"isOrgDefault,omitempty"`
+	// Stacks lists the fully qualified names of the stacks this policy group applies to.
+	// It is ignored for the default policy group.
+	Stacks []string `json:"stacks,omitempty"`
+	// PolicyPacks lists the policy packs that are enabled for this policy group.
+	PolicyPacks []*enabledPolicyPack `json:"policyPacks,omitempty"`
+}
+
+// enabledPolicyPack is a policy pack version enabled for a policy group.
+type enabledPolicyPack struct {
+	Name       string                      `json:"name"`
+	VersionTag string                      `json:"versionTag"`
+	Config     map[string]*json.RawMessage `json:"config,omitempty"`
+}
+
+// policyPackMetadata is stored next to every published policy pack tarball.
+type policyPackMetadata struct {
+	Name        string           `json:"name"`
+	DisplayName string           `json:"displayName"`
+	VersionTag  string           `json:"versionTag"`
+	Policies    []apitype.Policy `json:"policies"`
+	// Published records when this version was published. It is used to find the latest version.
+	Published time.Time `json:"published"`
+}
+
+// appliesTo reports whether the policy group applies to the given stack.
+func (g *policyGroup) appliesTo(stackName string) bool {
+	return g.IsOrgDefault || slices.Contains(g.Stacks, stackName)
+}
+
+// group returns the policy group with the given name, or nil if there is none.
+// An empty name refers to the default policy group.
+func (pgs *policyGroups) group(name string) *policyGroup {
+	for _, g := range pgs.PolicyGroups {
+		if (name == "" && g.IsOrgDefault) || (name != "" && g.Name == name) {
+			return g
+		}
+	}
+	return nil
+}
+
+// readPolicyGroups loads the policy groups file from the bucket.
+// If the file does not exist, it returns an empty set of policy groups.
+func readPolicyGroups(ctx context.Context, b Bucket) (*policyGroups, error) {
+	body, err := b.ReadAll(ctx, policyGroupsPath)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return &policyGroups{}, nil
+		}
+		return nil, fmt.Errorf("read %q: %w", policyGroupsPath, err)
+	}
+
+	var pgs policyGroups
+	if err := json.Unmarshal(body, &pgs); err != nil {
+		return nil, fmt.Errorf("corrupt store: unmarshal %q: %w", policyGroupsPath, err)
+	}
+	return &pgs, nil
+}
+
+// WriteTo writes the policy groups to the bucket, overwriting any existing policy groups.
+func (pgs *policyGroups) WriteTo(ctx context.Context, b Bucket) error {
+	bs, err := json.MarshalIndent(pgs, "", "    ")
+	contract.AssertNoErrorf(err, "Could not marshal diy.policyGroups to JSON")
+
+	if err := b.WriteAll(ctx, policyGroupsPath, bs, nil); err != nil {
+		return fmt.Errorf("write %q: %w", policyGroupsPath, err)
+	}
+	return nil
+}
+
+func policyPackDir(name string) string {
+	return path.Join(filepath.ToSlash(PolicyPacksDir), strings.ReplaceAll(name, tokens.QNameDelimiter, "_"))
+}
+
+func policyPackTarballPath(name, versionTag string) string {
+	return path.Join(policyPackDir(name), versionTag+".tgz")
+}
+
+func policyPackMetadataPath(name, versionTag string) string {
+	return path.Join(policyPackDir(name), versionTag+".json")
+}
+
+// listPolicyPackVersions returns the metadata of every published version of the named policy pack,
+// oldest first.
+func (b *diyBackend) listPolicyPackVersions(ctx context.Context, name string) ([]*policyPackMetadata, error) {
+	files, err := listBucket(ctx, b.bucket, policyPackDir(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*policyPackMetadata
+	for _, file := range files {
+		if file.IsDir || path.Ext(file.Key) != ".json" {
+			continue
+		}
+		body, err := b.bucket.ReadAll(ctx, file.Key)
+		if err != nil {
+			return nil, fmt.Errorf("read %q: %w", file.Key, err)
+		}
+		var meta policyPackMetadata
+		if err := json.Unmarshal(body, &meta); err != nil {
+			return nil, fmt.Errorf("corrupt store: unmarshal %q: %w", file.Key, err)
+		}
+		versions = append(versions, &meta)
+	}
+
+	slices.SortFunc(versions, func(a, b *policyPackMetadata) int {
+		return a.Published.Compare(b.Published)
+	})
+	return versions, nil
+}
+
+// getPolicyPackVersion returns the metadata of a published policy pack version.
+// If versionTag is nil, the most recently published version is returned.
+func (b *diyBackend) getPolicyPackVersion(
+	ctx context.Context, name string, versionTag *string,
+) (*policyPackMetadata, error) {
+	versions, err := b.listPolicyPackVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("policy pack %q has not been published", name)
+	}
+	if versionTag == nil {
+		return versions[len(versions)-1], nil
+	}
+	for _, v := range versions {
+		if v.VersionTag == *versionTag {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("policy pack %q has no published version %q", name, *versionTag)
+}
+
+// getRequiredPolicies returns the policy packs that every operation on the given stack must run.
+func (b *diyBackend) getRequiredPolicies(
+	ctx context.Context, ref *diyBackendReference,
+) ([]engine.RequiredPolicy, error) {
+	pgs, err := readPolicyGroups(ctx, b.bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	stackName := string(ref.FullyQualifiedName())
+
+	var required []engine.RequiredPolicy
+	seen := map[string]bool{}
+	for _, g := range pgs.PolicyGroups {
+		if !g.appliesTo(stackName) {
+			continue
+		}
+		for _, pack := range g.PolicyPacks {
+			// A stack may be in several policy groups that enable the same pack.
+			// Only run each version once.
+			key := pack.Name + "@" + pack.VersionTag
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			meta, err := b.getPolicyPackVersion(ctx, pack.Name, &pack.VersionTag)
+			if err != nil {
+				return nil, fmt.Errorf("policy group %q: %w", g.Name, err)
+			}
+			required = append(required, &diyRequiredPolicy{
+				b:           b,
+				name:        pack.Name,
+				displayName: meta.DisplayName,
+				versionTag:  pack.VersionTag,
+				config:      pack.Config,
+			})
+		}
+	}
+	return required, nil
+}
+
+func (b *diyBackend) GetPolicyPack(ctx context.Context, policyPack string,
+	d diag.Sink,
+) (backend.PolicyPack, error) {
+	// Policy packs are referred to as <org-name>/<policy-pack-name>.
+	// DIY backends only have one organization, so the org name is ignored.
+	_, name, found := strings.Cut(policyPack, "/")
+	if !found {
+		name = policyPack
+	}
+
+	return &diyPolicyPack{
+		ref: &diyPolicyPackReference{name: tokens.QName(name)},
+		b:   b,
+	}, nil
+}
+
+func (b *diyBackend) ListPolicyGroups(ctx context.Context, orgName string, _ backend.ContinuationToken) (
+	apitype.ListPolicyGroupsResponse, backend.ContinuationToken, error,
+) {
+	pgs, err := readPolicyGroups(ctx, b.bucket)
+	if err != nil {
+		return apitype.ListPolicyGroupsResponse{}, nil, err
+	}
+
+	numStacks := -1
+	summaries := make([]apitype.PolicyGroupSummary, 0, len(pgs.PolicyGroups))
+	for _, g := range pgs.PolicyGroups {
+		stacks := len(g.Stacks)
+		if g.IsOrgDefault {
+			// The default policy group applies to every stack. Only count them if we need to.
+			if numStacks < 0 {
+				refs, err := b.getStacks(ctx)
+				if err != nil {
+					return apitype.ListPolicyGroupsResponse{}, nil, err
+				}
+				numStacks = len(refs)
+			}
+			stacks = numStacks
+		}
+		summaries = append(summaries, apitype.PolicyGroupSummary{
+			Name:                  g.Name,
+			IsOrgDefault:          g.IsOrgDefault,
+			NumStacks:             stacks,
+			NumEnabledPolicyPacks: len(g.PolicyPacks),
+		})
+	}
+	return apitype.ListPolicyGroupsResponse{PolicyGroups: summaries}, nil, nil
+}
+
+func (b *diyBackend) ListPolicyPacks(ctx context.Context, orgName string, _ backend.ContinuationToken) (
+	apitype.ListPolicyPacksResponse, backend.ContinuationToken, error,
+) {
+	files, err := listBucket(ctx, b.bucket, filepath.ToSlash(PolicyPacksDir))
+	if err != nil {
+		return apitype.ListPolicyPacksResponse{}, nil, err
+	}
+
+	packs := []apitype.PolicyPackWithVersions{}
+	for _, file := range files {
+		if !file.IsDir {
+			continue
+		}
+		versions, err := b.listPolicyPackVersions(ctx, objectName(file))
+		if err != nil {
+			return apitype.ListPolicyPacksResponse{}, nil, err
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		latest := versions[len(versions)-1]
+		pack := apitype.PolicyPackWithVersions{
+			Name:        latest.Name,
+			DisplayName: latest.DisplayName,
+		}
+		// Newest first, matching the service.
+		for i := len(versions) - 1; i >= 0; i-- {
+			pack.Versions = append(pack.Versions, i+1)
+			pack.VersionTags = append(pack.VersionTags, versions[i].VersionTag)
+		}
+		packs = append(packs, pack)
+	}
+	return apitype.ListPolicyPacksResponse{PolicyPacks: packs}, nil, nil
+}
+
+// diyRequiredPolicy is a policy pack that a policy group requires, installed from the diy backend.
+type diyRequiredPolicy struct {
+	b           *diyBackend
+	name        string
+	displayName string
+	versionTag  string
+	config      map[string]*json.RawMessage
+}
+
+var _ engine.RequiredPolicy = (*diyRequiredPolicy)(nil)
+
+func (rp *diyRequiredPolicy) Name() string                        { return rp.name }
+func (rp *diyRequiredPolicy) Version() string                     { return rp.versionTag }
+func (rp *diyRequiredPolicy) Config() map[string]*json.RawMessage { return rp.config }
+
+func (rp *diyRequiredPolicy) Install(ctx context.Context) (string, error) {
+	policyPackPath, installed, err := workspace.GetPolicyPath(diyOrgName,
+		strings.ReplaceAll(rp.name, tokens.QNameDelimiter, "_"), rp.versionTag)
+	if err != nil {
+		// Failed to get a sensible PolicyPack path.
+		return "", err
+	} else if installed {
+		// We've already downloaded and installed the PolicyPack. Return.
+		return policyPackPath, nil
+	}
+
+	fmt.Printf("Installing policy pack %s %s...\r\n", rp.name, rp.versionTag)
+
+	tarballPath := policyPackTarballPath(rp.name, rp.versionTag)
+	logging.V(7).Infof("Downloading policy pack %s %s from %s", rp.name, rp.versionTag, tarballPath)
+	tarball, err := rp.b.bucket.ReadAll(ctx, tarballPath)
+	if err != nil {
+		return "", fmt.Errorf("read %q: %w", tarballPath, err)
+	}
+
+	return policyPackPath, backend.InstallPolicyPack(ctx, policyPackPath, io.NopCloser(bytes.NewReader(tarball)))
+}
+
+// diyPolicyPackReference is a reference to a PolicyPack stored in a diy backend.
+type diyPolicyPackReference struct {
+	// name of the PolicyPack.
+	name tokens.QName
+}
+
+var _ backend.PolicyPackReference = (*diyPolicyPackReference)(nil)
+
+func (pr *diyPolicyPackReference) String() string {
+	return fmt.Sprintf("%s/%s", diyOrgName, pr.name)
+}
+
+func (pr *diyPolicyPackReference) OrgName() string {
+	return diyOrgName
+}
+
+func (pr *diyPolicyPackReference) Name() tokens.QName {
+	return pr.name
+}
+
+// diyPolicyPack is the diy implementation of the PolicyPack interface.
+type diyPolicyPack struct {
+	// ref uniquely identifies the PolicyPack in the backend.
+	ref *diyPolicyPackReference
+	// b is a pointer to the backend that this PolicyPack belongs to.
+	b *diyBackend
+}
+
+var _ backend.PolicyPack = (*diyPolicyPack)(nil)
+
+func (pack *diyPolicyPack) Ref() backend.PolicyPackReference {
+	return pack.ref
+}
+
+func (pack *diyPolicyPack) Backend() backend.Backend {
+	return pack.b
+}
+
+func (pack *diyPolicyPack) Publish(
+	ctx context.Context, op backend.PublishOperation,
+) error {
+	//
+	// Get PolicyPack metadata from the plugin.
+	//
+
+	fmt.Println("Obtaining policy metadata from policy plugin")
+
+	abs, err := filepath.Abs(op.PlugCtx.Pwd)
+	if err != nil {
+		return err
+	}
+
+	analyzer, err := op.PlugCtx.Host.PolicyAnalyzer(tokens.QName(abs), op.PlugCtx.Pwd, nil /*opts*/)
+	if err != nil {
+		return err
+	}
+
+	analyzerInfo, err := analyzer.GetAnalyzerInfo()
+	if err != nil {
+		return err
+	}
+
+	// The version tag is part of the storage path, so it must be present.
+	if err := validatePolicyPackVersion(analyzerInfo.Version); err != nil {
+		return err
+	}
+
+	// Update the name from the metadata.
+	pack.ref.name = tokens.QName(analyzerInfo.Name)
+
+	// Published versions are immutable, just like in the service.
+	metaPath := policyPackMetadataPath(analyzerInfo.Name, analyzerInfo.Version)
+	exists, err := pack.b.bucket.Exists(ctx, metaPath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("version %q of policy pack %q has already been published",
+			analyzerInfo.Version, analyzerInfo.Name)
+	}
+
+	policies := make([]apitype.Policy, len(analyzerInfo.Policies))
+	for i, policy := range analyzerInfo.Policies {
+		configSchema, err := convertPolicyConfigSchema(policy.ConfigSchema)
+		if err != nil {
+			return err
+		}
+
+		policies[i] = apitype.Policy{
+			Name:             policy.Name,
+			DisplayName:      policy.DisplayName,
+			Description:      policy.Description,
+			EnforcementLevel: policy.EnforcementLevel,
+			Message:          policy.Message,
+			ConfigSchema:     configSchema,
+		}
+	}
+
+	fmt.Println("Compressing policy pack")
+
+	packTarball, err := backend.PackPolicyPack(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	//
+	// Publish.
+	//
+
+	fmt.Printf("Publishing %q - version %s to %s\n", analyzerInfo.Name, analyzerInfo.Version, pack.b.URL())
+
+	// Write the tarball first: the metadata file is what makes a version visible.
+	tarballPath := policyPackTarballPath(analyzerInfo.Name, analyzerInfo.Version)
+	if err := pack.b.bucket.WriteAll(ctx, tarballPath, packTarball, nil); err != nil {
+		return fmt.Errorf("write %q: %w", tarballPath, err)
+	}
+
+	meta, err := json.MarshalIndent(policyPackMetadata{
+		Name:        analyzerInfo.Name,
+		DisplayName: analyzerInfo.DisplayName,
+		VersionTag:  analyzerInfo.Version,
+		Policies:    policies,
+		Published:   time.Now(),
+	}, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := pack.b.bucket.WriteAll(ctx, metaPath, meta, nil); err != nil {
+		return fmt.Errorf("write %q: %w", metaPath, err)
+	}
+
+	return nil
+}
+
+func (pack *diyPolicyPack) Enable(ctx context.Context, groupName string, op backend.PolicyPackOperation) error {
+	name := string(pack.ref.name)
+	meta, err := pack.b.getPolicyPackVersion(ctx, name, op.VersionTag)
+	if err != nil {
+		return err
+	}
+
+	pgs, err := readPolicyGroups(ctx, pack.b.bucket)
+	if err != nil {
+		return err
+	}
+
+	g := pgs.group(groupName)
+	if g == nil {
+		g = &policyGroup{Name: groupName}
+		if groupName == "" {
+			g.Name = defaultPolicyGroup
+			g.IsOrgDefault = true
+		}
+		pgs.PolicyGroups = append(pgs.PolicyGroups, g)
+	}
+
+	// Only one version of a policy pack can be enabled per policy group.
+	g.PolicyPacks = slices.DeleteFunc(g.PolicyPacks, func(p *enabledPolicyPack) bool {
+		return p.Name == name
+	})
+	g.PolicyPacks = append(g.PolicyPacks, &enabledPolicyPack{
+		Name:       name,
+		VersionTag: meta.VersionTag,
+		Config:     op.Config,
+	})
+
+	return pgs.WriteTo(ctx, pack.b.bucket)
+}
+
+func (pack *diyPolicyPack) Disable(ctx context.Context, groupName string, op backend.PolicyPackOperation) error {
+	pgs, err := readPolicyGroups(ctx, pack.b.bucket)
+	if err != nil {
+		return err
+	}
+
+	g := pgs.group(groupName)
+	if g == nil {
+		return fmt.Errorf("policy group %q does not exist", groupName)
+	}
+
+	name := string(pack.ref.name)
+	before := len(g.PolicyPacks)
+	g.PolicyPacks = slices.DeleteFunc(g.PolicyPacks, func(p *enabledPolicyPack) bool {
+		return p.Name == name && (op.VersionTag == nil || p.VersionTag == *op.VersionTag)
+	})
+	if len(g.PolicyPacks) == before {
+		return fmt.Errorf("policy pack %q is not enabled for policy group %q", name, g.Name)
+	}
+
+	return pgs.WriteTo(ctx, pack.b.bucket)
+}
+
+func (pack *diyPolicyPack) Validate(ctx context.Context, op backend.PolicyPackOperation) error {
+	meta, err := pack.b.getPolicyPackVersion(ctx, string(pack.ref.name), op.VersionTag)
+	if err != nil {
+		return err
+	}
+
+	schema := make(map[string]apitype.PolicyConfigSchema)
+	for _, p := range meta.Policies {
+		if p.ConfigSchema != nil {
+			schema[p.Name] = *p.ConfigSchema
+		}
+	}
+	return resourceanalyzer.ValidatePolicyPackConfig(schema, op.Config)
+}
+
+func (pack *diyPolicyPack) Remove(ctx context.Context, op backend.PolicyPackOperation) error {
+	name := string(pack.ref.name)
+
+	// Like the service, refuse to remove policy packs that are still enforced.
+	pgs, err := readPolicyGroups(ctx, pack.b.bucket)
+	if err != nil {
+		return err
+	}
+	for _, g := range pgs.PolicyGroups {
+		for _, p := range g.PolicyPacks {
+			if p.Name == name && (op.VersionTag == nil || p.VersionTag == *op.VersionTag) {
+				return fmt.Errorf("policy pack %q is enabled for policy group %q; disable it first", name, g.Name)
+			}
+		}
+	}
+
+	versions, err := pack.b.listPolicyPackVersions(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, v := range versions {
+		if op.VersionTag != nil && v.VersionTag != *op.VersionTag {
+			continue
+		}
+		// Delete the metadata first so a half-removed version is never visible.
+		for _, file := range []string{
+			policyPackMetadataPath(name, v.VersionTag),
+			policyPackTarballPath(name, v.VersionTag),
+		} {
+			if err := pack.b.bucket.Delete(ctx, file); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return fmt.Errorf("delete %q: %w", file, err)
+			}
+		}
+		removed++
+	}
+	if removed == 0 {
+		return fmt.Errorf("policy pack %q not found", name)
+	}
+	return nil
+}
+
+// convertPolicyConfigSchema converts a policy's schema from the analyzer to the apitype.
+func convertPolicyConfigSchema(schema *plugin.AnalyzerPolicyConfigSchema) (*apitype.PolicyConfigSchema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	properties := map[string]*json.RawMessage{}
+	for k, v := range schema.Properties {
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw := json.RawMessage(bytes)
+		properties[k] = &raw
+	}
+	return &apitype.PolicyConfigSchema{
+		Type:       apitype.Object,
+		Properties: properties,
+		Required:   schema.Required,
+	}, nil
+}
+
+var policyPackVersionTagRE = regexp.MustCompile("^[a-zA-Z0-9-_.]{1,100}$")
+
+// validatePolicyPackVersion validates the version of a Policy Pack.
+// Unlike the service, the version may not be empty because it names the stored tarball.
+func validatePolicyPackVersion(s string) error {
+	if s == "" {
+		return errors.New("policy packs published to a DIY backend must have a version; " +
+			"please upgrade to a newer version of the pulumi/policy library")
+	}
+	if !policyPackVersionTagRE.MatchString(s) {
+		return fmt.Errorf("invalid version %q - version may only contain alphanumeric, hyphens, or underscores. "+
+			"It must also be between 1 and 100 characters long.", s)
+	}
+	return nil
+}
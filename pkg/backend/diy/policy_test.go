@@ -0,0 +1,161 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+// publishFakePolicyPack writes the bucket objects that Publish would write,
+// without needing a policy plugin.
+func publishFakePolicyPack(t *testing.T, b *diyBackend, name, version string, published time.Time) {
+	t.Helper()
+
+	ctx := context.Background()
+	meta, err := json.Marshal(policyPackMetadata{
+		Name:        name,
+		DisplayName: name + " display",
+		VersionTag:  version,
+		Published:   published,
+		Policies: []apitype.Policy{{
+			Name: "max-size",
+			ConfigSchema: &apitype.PolicyConfigSchema{
+				Type: apitype.Object,
+				Properties: map[string]*json.RawMessage{
+					"size": rawJSON(`{"type": "integer"}`),
+				},
+				Required: []string{"size"},
+			},
+		}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, b.bucket.WriteAll(ctx, policyPackTarballPath(name, version), []byte("tgz"), nil))
+	require.NoError(t, b.bucket.WriteAll(ctx, policyPackMetadataPath(name, version), meta, nil))
+}
+
+func rawJSON(s string) *json.RawMessage {
+	raw := json.RawMessage(s)
+	return &raw
+}
+
+func TestPolicyPacks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+	b := be.(*diyBackend)
+
+	now := time.Now()
+	publishFakePolicyPack(t, b, "security", "1.0.0", now.Add(-time.Hour))
+	publishFakePolicyPack(t, b, "security", "1.1.0", now)
+
+	packs, _, err := b.ListPolicyPacks(ctx, "anyone", nil)
+	require.NoError(t, err)
+	require.Len(t, packs.PolicyPacks, 1)
+	assert.Equal(t, "security", packs.PolicyPacks[0].Name)
+	assert.Equal(t, []string{"1.1.0", "1.0.0"}, packs.PolicyPacks[0].VersionTags)
+
+	pack, err := b.GetPolicyPack(ctx, "organization/security", diagtest.LogSink(t))
+	require.NoError(t, err)
+	assert.Equal(t, "organization/security", pack.Ref().String())
+
+	// Validate checks config against the published schema.
+	v := "1.0.0"
+	err = pack.Validate(ctx, backend.PolicyPackOperation{
+		VersionTag: &v,
+		Config:     map[string]*json.RawMessage{"max-size": rawJSON(`{"size": "big"}`)},
+	})
+	assert.Error(t, err)
+
+	// Enabling without a version picks the latest one.
+	config := map[string]*json.RawMessage{"max-size": rawJSON(`{"size": 3}`)}
+	require.NoError(t, pack.Enable(ctx, "", backend.PolicyPackOperation{Config: config}))
+
+	// Enabling a specific version in another group only applies to that group's stacks.
+	require.NoError(t, pack.Enable(ctx, "legacy", backend.PolicyPackOperation{VersionTag: &v}))
+	pgs, err := readPolicyGroups(ctx, b.bucket)
+	require.NoError(t, err)
+	pgs.group("legacy").Stacks = []string{"organization/proj/old"}
+	require.NoError(t, pgs.WriteTo(ctx, b.bucket))
+
+	groups, _, err := b.ListPolicyGroups(ctx, "anyone", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []apitype.PolicyGroupSummary{
+		{Name: defaultPolicyGroup, IsOrgDefault: true, NumStacks: 0, NumEnabledPolicyPacks: 1},
+		{Name: "legacy", NumStacks: 1, NumEnabledPolicyPacks: 1},
+	}, groups.PolicyGroups)
+
+	newRef, err := b.parseStackReference("organization/proj/new")
+	require.NoError(t, err)
+	required, err := b.getRequiredPolicies(ctx, newRef)
+	require.NoError(t, err)
+	require.Len(t, required, 1)
+	assert.Equal(t, "security", required[0].Name())
+	assert.Equal(t, "1.1.0", required[0].Version())
+	require.Contains(t, required[0].Config(), "max-size")
+	assert.JSONEq(t, `{"size": 3}`, string(*required[0].Config()["max-size"]))
+
+	oldRef, err := b.parseStackReference("organization/proj/old")
+	require.NoError(t, err)
+	required, err = b.getRequiredPolicies(ctx, oldRef)
+	require.NoError(t, err)
+	require.Len(t, required, 2)
+
+	// Enabled packs can't be removed.
+	err = pack.Remove(ctx, backend.PolicyPackOperation{})
+	assert.ErrorContains(t, err, "disable it first")
+
+	require.NoError(t, pack.Disable(ctx, "", backend.PolicyPackOperation{}))
+	require.NoError(t, pack.Disable(ctx, "legacy", backend.PolicyPackOperation{}))
+	err = pack.Disable(ctx, "legacy", backend.PolicyPackOperation{})
+	assert.ErrorContains(t, err, "is not enabled")
+
+	required, err = b.getRequiredPolicies(ctx, oldRef)
+	require.NoError(t, err)
+	assert.Empty(t, required)
+
+	require.NoError(t, pack.Remove(ctx, backend.PolicyPackOperation{VersionTag: &v}))
+	packs, _, err = b.ListPolicyPacks(ctx, "anyone", nil)
+	require.NoError(t, err)
+	require.Len(t, packs.PolicyPacks, 1)
+	assert.Equal(t, []string{"1.1.0"}, packs.PolicyPacks[0].VersionTags)
+}
+
+func TestPolicyPacks_enableUnpublished(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+
+	pack, err := be.GetPolicyPack(ctx, "organization/missing", diagtest.LogSink(t))
+	require.NoError(t, err)
+	err = pack.Enable(ctx, "", backend.PolicyPackOperation{})
+	assert.ErrorContains(t, err, `policy pack "missing" has not been published`)
+}
@@ -0,0 +1,234 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// HistoryRetention is a policy for how much of a stack's update history to keep.
+//
+// An update is kept if any of the limits that are set keeps it, and the most recent update is always kept.
+type HistoryRetention struct {
+	// KeepUpdates is the number of most recent updates to keep. Zero means no limit on the number of updates.
+	KeepUpdates int
+	// KeepDays is the number of days for which updates are kept. Zero means no limit on the age of updates.
+	KeepDays int
+	// Compress gzips the updates that are kept, if they aren't already.
+	Compress bool
+}
+
+// prunes reports whether the policy removes any updates.
+func (r HistoryRetention) prunes() bool {
+	return r.KeepUpdates > 0 || r.KeepDays > 0
+}
+
+// keeps reports whether the policy keeps the update at the given position of the (oldest first) history.
+func (r HistoryRetention) keeps(index, count int, written, now time.Time) bool {
+	if !r.prunes() || index == count-1 {
+		return true
+	}
+	if r.KeepUpdates > 0 && index >= count-r.KeepUpdates {
+		return true
+	}
+	return r.KeepDays > 0 && now.Sub(written) < time.Duration(r.KeepDays)*24*time.Hour
+}
+
+// HistoryCompaction describes the changes made to a stack's history by CompactHistory.
+type HistoryCompaction struct {
+	// Removed is the number of updates that were removed.
+	Removed int
+	// Compressed is the number of updates that were gzipped.
+	Compressed int
+}
+
+// historyRetention returns the retention policy configured in the environment.
+func (b *diyBackend) historyRetention() HistoryRetention {
+	return HistoryRetention{
+		KeepUpdates: b.Env.GetInt(env.DIYBackendHistoryKeepUpdates),
+		KeepDays:    b.Env.GetInt(env.DIYBackendHistoryKeepDays),
+		Compress:    b.Env.GetBool(env.DIYBackendHistoryCompress),
+	}
+}
+
+// historyFileTime returns the time at which a history file was written, which is part of its name.
+func historyFileTime(key string) (time.Time, bool) {
+	// The filename format is <stack-name>-<timestamp>.history.json[.gz].
+	name := path.Base(key)
+	name = name[strings.LastIndex(name, "-")+1:]
+	name, _, _ = strings.Cut(name, ".")
+	nanos, err := strconv.ParseInt(name, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
+
+func (b *diyBackend) CompactHistory(
+	ctx context.Context, stackRef backend.StackReference, retention HistoryRetention,
+) (HistoryCompaction, error) {
+	ref, err := b.getReference(stackRef)
+	if err != nil {
+		return HistoryCompaction{}, err
+	}
+
+	if err := b.Lock(ctx, ref); err != nil {
+		return HistoryCompaction{}, err
+	}
+	defer b.Unlock(ctx, ref)
+
+	return b.compactHistory(ctx, ref, retention)
+}
+
+// compactHistory removes the updates of a stack's history that the retention policy doesn't keep, and compresses
+// the ones it does if requested. The stack must be locked. If an update can't be removed, compaction stops and the
+// error is returned along with the changes made so far.
+func (b *diyBackend) compactHistory(
+	ctx context.Context, ref *diyBackendReference, retention HistoryRetention,
+) (HistoryCompaction, error) {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	var result HistoryCompaction
+	historyFiles, err := b.listHistoryFiles(ctx, ref)
+	if err != nil {
+		return result, err
+	}
+
+	now := time.Now()
+	var kept []string
+	// index is the position of each kept update in the history before compaction.
+	var keptIndex []int
+	for i, file := range historyFiles {
+		written, ok := historyFileTime(file)
+		if !ok {
+			// Treat files whose timestamp we can't make sense of as new, so that age limits don't remove them.
+			written = now
+		}
+		if retention.keeps(i, len(historyFiles), written, now) {
+			kept = append(kept, file)
+			keptIndex = append(keptIndex, i)
+			continue
+		}
+
+		// Remove the update first, so that an interrupted compaction never leaves behind an update
+		// without its checkpoint.
+		if err := b.bucket.Delete(ctx, file); err != nil {
+			return result, fmt.Errorf("delete %q: %w", file, err)
+		}
+		result.Removed++
+		checkpointFile := historyCheckpointFile(file)
+		if err := b.bucket.Delete(ctx, checkpointFile); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return result, fmt.Errorf("delete %q: %w", checkpointFile, err)
+		}
+	}
+
+	// Updates written before versions were stored in the history are numbered by their position, so stamp the
+	// updates that are left with the version they had before the older ones were removed. Versions have been stored
+	// since they were introduced, so if the oldest update left has one, all the others do too.
+	stamp := false
+	if result.Removed > 0 && len(kept) > 0 {
+		oldest, err := b.readHistoryFile(ctx, kept[0])
+		if err != nil {
+			return result, err
+		}
+		stamp = oldest.Version == 0
+	}
+
+	for i, file := range kept {
+		compress := retention.Compress && !strings.HasSuffix(file, encoding.GZIPExt)
+		if !stamp && !compress {
+			continue
+		}
+
+		update, err := b.readHistoryFile(ctx, file)
+		if err != nil {
+			return result, err
+		}
+		if update.Version == 0 {
+			update.Version = historyVersion(update, keptIndex[i])
+		}
+		if err := b.rewriteHistoryFile(ctx, file, update, compress); err != nil {
+			return result, err
+		}
+		if compress {
+			result.Compressed++
+		}
+	}
+
+	return result, nil
+}
+
+// rewriteHistoryFile replaces an update record, and if compress is set gzips it and the checkpoint saved with it.
+func (b *diyBackend) rewriteHistoryFile(
+	ctx context.Context, file string, update backend.UpdateInfo, compress bool,
+) error {
+	m := encoding.JSON
+	if compress || strings.HasSuffix(file, encoding.GZIPExt) {
+		m = encoding.Gzip(m)
+	}
+	byts, err := m.Marshal(&update)
+	if err != nil {
+		return err
+	}
+	if !compress {
+		return b.bucket.WriteAll(ctx, file, byts, nil)
+	}
+
+	checkpointFile := historyCheckpointFile(file)
+	chk, err := b.bucket.ReadAll(ctx, checkpointFile)
+	if err != nil {
+		return fmt.Errorf("read %q: %w", checkpointFile, err)
+	}
+	if !encoding.IsCompressed(chk) {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(chk); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		chk = buf.Bytes()
+	}
+
+	// Write the compressed checkpoint before the update that refers to it, and delete the update before the
+	// checkpoint it refers to, so that every update in the history always has a checkpoint.
+	if err := b.bucket.WriteAll(ctx, checkpointFile+encoding.GZIPExt, chk, nil); err != nil {
+		return fmt.Errorf("write %q: %w", checkpointFile+encoding.GZIPExt, err)
+	}
+	if err := b.bucket.WriteAll(ctx, file+encoding.GZIPExt, byts, nil); err != nil {
+		return fmt.Errorf("write %q: %w", file+encoding.GZIPExt, err)
+	}
+	for _, key := range []string{file, checkpointFile} {
+		if err := b.bucket.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete %q: %w", key, err)
+		}
+	}
+	return nil
+}
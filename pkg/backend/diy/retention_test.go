@@ -0,0 +1,190 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+func TestHistoryRetention_keeps(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	old := now.Add(-72 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	tests := []struct {
+		desc      string
+		retention HistoryRetention
+		index     int
+		written   time.Time
+		want      bool
+	}{
+		{"no limits", HistoryRetention{}, 0, old, true},
+		{"latest is always kept", HistoryRetention{KeepUpdates: 1, KeepDays: 1}, 4, old, true},
+		{"within count", HistoryRetention{KeepUpdates: 2}, 3, old, true},
+		{"beyond count", HistoryRetention{KeepUpdates: 2}, 2, recent, false},
+		{"within days", HistoryRetention{KeepDays: 1}, 0, recent, true},
+		{"beyond days", HistoryRetention{KeepDays: 1}, 3, old, false},
+		{"either limit keeps", HistoryRetention{KeepUpdates: 1, KeepDays: 1}, 0, recent, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.retention.keeps(tt.index, 5, tt.written, now))
+		})
+	}
+}
+
+// writeLegacyHistory writes update records the way addToHistory did before versions were stored in them.
+func writeLegacyHistory(t *testing.T, b *diyBackend, ref *diyBackendReference, written ...time.Time) {
+	t.Helper()
+
+	ctx := context.Background()
+	checkpoint, err := b.bucket.ReadAll(ctx, b.stackPath(ctx, ref))
+	require.NoError(t, err)
+	for i, at := range written {
+		prefix := path.Join(ref.HistoryDir(), fmt.Sprintf("%s-%d", ref.name, at.UnixNano()))
+		update, err := encoding.JSON.Marshal(backend.UpdateInfo{
+			Kind:    apitype.UpdateUpdate,
+			Message: fmt.Sprintf("update %d", i+1),
+		})
+		require.NoError(t, err)
+		require.NoError(t, b.bucket.WriteAll(ctx, prefix+".history.json", update, nil))
+		require.NoError(t, b.bucket.WriteAll(ctx, prefix+".checkpoint.json", checkpoint, nil))
+	}
+}
+
+func TestCompactHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+	b := be.(*diyBackend)
+
+	stackRef, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	stk, err := b.CreateStack(ctx, stackRef, "", nil, nil)
+	require.NoError(t, err)
+	ref := stackRef.(*diyBackendReference)
+
+	now := time.Now()
+	writeLegacyHistory(t, b, ref,
+		now.Add(-10*24*time.Hour), now.Add(-9*24*time.Hour), now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+	result, err := b.CompactHistory(ctx, stackRef, HistoryRetention{KeepUpdates: 1, KeepDays: 1, Compress: true})
+	require.NoError(t, err)
+	assert.Equal(t, HistoryCompaction{Removed: 2, Compressed: 2}, result)
+
+	// The updates that are left keep their versions and their checkpoints.
+	history, err := b.GetHistory(ctx, stackRef, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, 4, history[0].Version)
+	assert.Equal(t, "update 4", history[0].Message)
+	assert.Equal(t, 3, history[1].Version)
+	assert.Equal(t, "update 3", history[1].Message)
+
+	files, err := listBucket(ctx, b.bucket, ref.HistoryDir())
+	require.NoError(t, err)
+	assert.Len(t, files, 4)
+	for _, file := range files {
+		assert.True(t, strings.HasSuffix(file.Key, ".json.gz"), "%q should be compressed", file.Key)
+	}
+
+	_, err = b.ExportDeploymentForVersion(ctx, stk, "3")
+	assert.NoError(t, err)
+	_, err = b.ExportDeploymentForVersion(ctx, stk, "1")
+	assert.ErrorContains(t, err, "has no update with version 1")
+
+	// New updates are numbered after the ones that were removed.
+	require.NoError(t, b.addToHistory(ctx, ref, backend.UpdateInfo{Kind: apitype.UpdateUpdate}))
+	history, err = b.GetHistory(ctx, stackRef, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 5, history[0].Version)
+}
+
+// failingDeleteBucket is a bucket that fails to delete the keys that have the given suffix.
+type failingDeleteBucket struct {
+	Bucket
+	suffix string
+}
+
+func (b *failingDeleteBucket) Delete(ctx context.Context, key string) error {
+	if strings.HasSuffix(key, b.suffix) {
+		return fmt.Errorf("cannot delete %q", key)
+	}
+	return b.Bucket.Delete(ctx, key)
+}
+
+func TestCompactHistory_deleteError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+	b := be.(*diyBackend)
+
+	stackRef, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, stackRef, "", nil, nil)
+	require.NoError(t, err)
+	ref := stackRef.(*diyBackendReference)
+
+	now := time.Now()
+	writeLegacyHistory(t, b, ref, now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+	// Updates that can't be deleted aren't counted as removed, and the error is returned.
+	b.bucket = &failingDeleteBucket{Bucket: b.bucket, suffix: ".history.json"}
+	result, err := b.CompactHistory(ctx, stackRef, HistoryRetention{KeepUpdates: 1})
+	assert.ErrorContains(t, err, "cannot delete")
+	assert.Equal(t, HistoryCompaction{}, result)
+
+	history, err := b.GetHistory(ctx, stackRef, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+}
+
+func TestHistoryRetention_env(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := newDIYBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil,
+		&diyBackendOptions{Env: env.NewEnv(env.MapStore{"PULUMI_DIY_BACKEND_HISTORY_KEEP_UPDATES": "2"})})
+	require.NoError(t, err)
+	assert.Equal(t, HistoryRetention{KeepUpdates: 2}, b.historyRetention())
+}
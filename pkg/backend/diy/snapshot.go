@@ -17,7 +17,9 @@ package diy
 import (
 	"context"
 
+	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
 )
 
 // diySnapshotPersister is a simple SnapshotManager implementation that persists snapshots
@@ -39,6 +41,10 @@ func (sp *diySnapshotPersister) Save(snapshot *deploy.Snapshot) error {
 func (b *diyBackend) newSnapshotPersister(
 	ctx context.Context,
 	ref *diyBackendReference,
-) *diySnapshotPersister {
-	return &diySnapshotPersister{ctx: ctx, ref: ref, backend: b}
+) backend.SnapshotPersister {
+	sp := diySnapshotPersister{ctx: ctx, ref: ref, backend: b}
+	if b.Env.GetBool(env.DIYBackendJournal) {
+		return &diyJournalPersister{diySnapshotPersister: sp}
+	}
+	return &sp
 }
@@ -42,14 +42,17 @@ type diyStack struct {
 	snapshot atomic.Pointer[*deploy.Snapshot]
 	// a pointer to the backend this stack belongs to.
 	b *diyBackend
+	// tags contains metadata tags describing additional, extensible properties about this stack.
+	tags map[apitype.StackTagName]string
 }
 
-func newStack(ref *diyBackendReference, b *diyBackend) backend.Stack {
+func newStack(ref *diyBackendReference, b *diyBackend, tags map[apitype.StackTagName]string) backend.Stack {
 	contract.Requiref(ref != nil, "ref", "ref was nil")
 
 	return &diyStack{
-		ref: ref,
-		b:   b,
+		ref:  ref,
+		b:    b,
+		tags: tags,
 	}
 }
 
@@ -68,7 +71,7 @@ func (s *diyStack) Snapshot(ctx context.Context, secretsProvider secrets.Provide
 	return snap, nil
 }
 func (s *diyStack) Backend() backend.Backend              { return s.b }
-func (s *diyStack) Tags() map[apitype.StackTagName]string { return nil }
+func (s *diyStack) Tags() map[apitype.StackTagName]string { return s.tags }
 
 func (s *diyStack) Remove(ctx context.Context, force bool) (bool, error) {
 	return backend.RemoveStack(ctx, s, force)
@@ -128,10 +131,14 @@ func (s *diyStack) DefaultSecretManager(info *workspace.ProjectStack) (secrets.M
 type diyStackSummary struct {
 	name backend.StackReference
 	chk  *apitype.CheckpointV3
+	// lock is the active lock on the stack, if an update is in progress.
+	lock *lockContent
 }
 
-func newDIYStackSummary(name backend.StackReference, chk *apitype.CheckpointV3) diyStackSummary {
-	return diyStackSummary{name: name, chk: chk}
+func newDIYStackSummary(
+	name backend.StackReference, chk *apitype.CheckpointV3, lock *lockContent,
+) diyStackSummary {
+	return diyStackSummary{name: name, chk: chk, lock: lock}
 }
 
 func (lss diyStackSummary) Name() backend.StackReference {
@@ -139,6 +146,11 @@ func (lss diyStackSummary) Name() backend.StackReference {
 }
 
 func (lss diyStackSummary) LastUpdate() *time.Time {
+	// When an update is in progress the last update time is reported as zero.
+	if lss.lock != nil {
+		t := time.Unix(0, 0)
+		return &t
+	}
 	if lss.chk != nil && lss.chk.Latest != nil {
 		if t := lss.chk.Latest.Manifest.Time; !t.IsZero() {
 			return &t
@@ -147,6 +159,17 @@ func (lss diyStackSummary) LastUpdate() *time.Time {
 	return nil
 }
 
+// CurrentOperation returns the update currently in progress on the stack, if any.
+func (lss diyStackSummary) CurrentOperation() *apitype.OperationStatus {
+	if lss.lock == nil {
+		return nil
+	}
+	return &apitype.OperationStatus{
+		Author:  lss.lock.author(),
+		Started: lss.lock.Timestamp.Unix(),
+	}
+}
+
 func (lss diyStackSummary) ResourceCount() *int {
 	if lss.chk != nil && lss.chk.Latest != nil {
 		count := len(lss.chk.Latest.Resources)
@@ -15,6 +15,8 @@
 package diy
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
@@ -170,22 +172,86 @@ func (b *diyBackend) getSnapshot(ctx context.Context,
 // GetCheckpoint loads a checkpoint file for the given stack in this project, from the current project workspace.
 func (b *diyBackend) getCheckpoint(ctx context.Context, ref *diyBackendReference) (*apitype.CheckpointV3, error) {
 	chkpath := b.stackPath(ctx, ref)
-	bytes, err := b.bucket.ReadAll(ctx, chkpath)
+	// Stat before reading, so that if the checkpoint changes in between we remember the older version, and the
+	// next write reports a conflict instead of silently overwriting the newer checkpoint.
+	version, err := statObject(ctx, b.bucket, chkpath)
 	if err != nil {
 		return nil, err
 	}
-	m := encoding.JSON
-	if encoding.IsCompressed(bytes) {
-		m = encoding.Gzip(m)
+	chk, err := readCheckpoint(ctx, b.bucket, chkpath)
+	if err != nil {
+		return nil, err
+	}
+	b.rememberCheckpointVersion(chkpath, version)
+
+	// If an update is in progress, or was interrupted, bring the checkpoint up to date with its journal.
+	return b.applyJournal(ctx, ref, chk)
+}
+
+// readCheckpoint streams a checkpoint file, which may be gzipped, from the bucket.
+func readCheckpoint(ctx context.Context, bucket Bucket, key string) (*apitype.CheckpointV3, error) {
+	r, err := bucket.NewReader(ctx, key, nil)
+	if err != nil {
+		return nil, err
 	}
+	defer contract.IgnoreClose(r)
 
-	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(m, bytes)
+	br := bufio.NewReader(r)
+	var chk io.Reader = br
+	// Errors peeking mean the file is too short to be gzipped, and will be reported by the decoder.
+	if magic, _ := br.Peek(3); encoding.IsCompressed(magic) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer contract.IgnoreClose(gz)
+		chk = gz
+	}
+	return stack.DecodeCheckpoint(chk)
 }
 
 func (b *diyBackend) saveCheckpoint(
 	ctx context.Context,
 	ref *diyBackendReference,
 	checkpoint *apitype.VersionedCheckpoint,
+) (backupFile string, file string, _ error) {
+	return b.writeCheckpoint(ctx, ref, func(w io.Writer, m encoding.Marshaler) error {
+		byts, err := m.Marshal(checkpoint)
+		if err != nil {
+			return fmt.Errorf("An IO error occurred while marshalling the checkpoint: %w", err)
+		}
+		_, err = w.Write(byts)
+		return err
+	})
+}
+
+// streamSnapshot is like saveCheckpoint, but serializes the snapshot and encodes it straight to the bucket, a batch
+// of resources at a time, rather than building the whole checkpoint in memory first. Only JSON checkpoints can be
+// streamed, which is what stackPath always picks.
+func (b *diyBackend) streamSnapshot(
+	ctx context.Context,
+	ref *diyBackendReference,
+	snap *deploy.Snapshot,
+) (backupFile string, file string, _ error) {
+	chk := &apitype.CheckpointV3{Stack: ref.FullyQualifiedName()}
+	return b.writeCheckpoint(ctx, ref, func(w io.Writer, m encoding.Marshaler) error {
+		if m != encoding.JSON {
+			return fmt.Errorf("streaming checkpoints is only supported for JSON checkpoints")
+		}
+		if err := stack.EncodeSnapshotCheckpoint(ctx, w, chk, snap, false /* showSecrets */); err != nil {
+			return fmt.Errorf("serializing checkpoint: %w", err)
+		}
+		return nil
+	})
+}
+
+// writeCheckpoint writes the checkpoint of a stack, backing up the previous one. encode is called with the
+// marshaler for the checkpoint file's format to write the uncompressed checkpoint; the checkpoint is gzipped as it's
+// written if the backend is configured to do so. encode may be called more than once if writes need to be retried.
+func (b *diyBackend) writeCheckpoint(
+	ctx context.Context,
+	ref *diyBackendReference,
+	encode func(w io.Writer, m encoding.Marshaler) error,
 ) (backupFile string, file string, _ error) {
 	// Make a serializable stack and then use the encoder to encode it.
 	file = b.stackPath(ctx, ref)
@@ -200,17 +266,31 @@ func (b *diyBackend) saveCheckpoint(
 		if filepath.Ext(file) != encoding.GZIPExt {
 			file = file + ".gz"
 		}
-		m = encoding.Gzip(m)
 	} else {
 		file = strings.TrimSuffix(file, ".gz")
 	}
 
-	byts, err := m.Marshal(checkpoint)
-	if err != nil {
-		return "", "", fmt.Errorf("An IO error occurred while marshalling the checkpoint: %w", err)
+	// Errors encoding the checkpoint won't go away by writing it again, so unlike errors writing to the bucket they
+	// aren't retried.
+	var encodeErr error
+	write := func(w io.Writer) error {
+		bw := &errWriter{w: w}
+		var err error
+		if !b.gzip {
+			err = encode(bw, m)
+		} else {
+			gz := gzip.NewWriter(bw)
+			if err = encode(gz, m); err == nil {
+				err = gz.Close()
+			}
+		}
+		if err != nil && bw.err == nil {
+			encodeErr = err
+		}
+		return err
 	}
 
-	// Back up the existing file if it already exists. Don't delete the original, the following WriteAll will
+	// Back up the existing file if it already exists. Don't delete the original, the following write will
 	// atomically replace it anyway and various other bits of the system depend on being able to find the
 	// .json file to know the stack currently exists (see https://github.com/pulumi/pulumi/issues/9033 for
 	// context).
@@ -226,8 +306,13 @@ func (b *diyBackend) saveCheckpoint(
 		backupFile = bckPlain
 	}
 
-	// And now write out the new snapshot file, overwriting that location.
-	if err = b.bucket.WriteAll(ctx, file, byts, nil); err != nil {
+	// And now write out the new snapshot file, overwriting that location, as long as nobody else has written it
+	// since we last read it.
+	if err := b.writeCheckpointIfUnchanged(ctx, file, write); err != nil {
+		if errors.Is(err, errCheckpointConflict) || encodeErr != nil {
+			return backupFile, "", err
+		}
+
 		b.mutex.Lock()
 		defer b.mutex.Unlock()
 
@@ -243,7 +328,10 @@ func (b *diyBackend) saveCheckpoint(
 			Backoff:  &backoff,
 			Accept: func(try int, nextRetryTime time.Duration) (bool, interface{}, error) {
 				// And now write out the new snapshot file, overwriting that location.
-				err := b.bucket.WriteAll(ctx, file, byts, nil)
+				err := b.writeCheckpointIfUnchanged(ctx, file, write)
+				if errors.Is(err, errCheckpointConflict) || encodeErr != nil {
+					return false, nil, err
+				}
 				if err != nil {
 					logging.V(7).Infof("Error while writing snapshot to: %s (attempt=%d, error=%s)", file, try, err)
 					if try > 10 {
@@ -261,9 +349,14 @@ func (b *diyBackend) saveCheckpoint(
 
 	logging.V(7).Infof("Saved stack %s checkpoint to: %s (backup=%s)", ref.FullyQualifiedName(), file, backupFile)
 
+	// Any journal was built on the checkpoint we just replaced, so it is stale now; remove it.
+	if err := b.clearJournal(ctx, ref); err != nil {
+		logging.V(5).Infof("error clearing journal of %s: %v (skipping)", ref.FullyQualifiedName(), err)
+	}
+
 	// And if we are retaining historical checkpoint information, write it out again
 	if b.Env.GetBool(env.DIYBackendRetainCheckpoints) {
-		if err = b.bucket.WriteAll(ctx, fmt.Sprintf("%v.%v", file, time.Now().UnixNano()), byts, nil); err != nil {
+		if err := b.bucket.Copy(ctx, fmt.Sprintf("%v.%v", file, time.Now().UnixNano()), file, nil); err != nil {
 			return backupFile, "", fmt.Errorf("An IO error occurred while writing the new snapshot file: %w", err)
 		}
 	}
@@ -271,17 +364,27 @@ func (b *diyBackend) saveCheckpoint(
 	return backupFile, file, nil
 }
 
+// errWriter is an io.Writer that remembers the first error that writing to w returned.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	n, err := ew.w.Write(p)
+	if err != nil && ew.err == nil {
+		ew.err = err
+	}
+	return n, err
+}
+
 func (b *diyBackend) saveStack(
 	ctx context.Context,
 	ref *diyBackendReference, snap *deploy.Snapshot,
 ) (string, error) {
 	contract.Requiref(ref != nil, "ref", "ref was nil")
-	chk, err := stack.SerializeCheckpoint(ref.FullyQualifiedName(), snap, false /* showSecrets */)
-	if err != nil {
-		return "", fmt.Errorf("serializaing checkpoint: %w", err)
-	}
-
-	backup, file, err := b.saveCheckpoint(ctx, ref, chk)
+	// If snap is nil, that's okay, we will just create an empty deployment.
+	backup, file, err := b.streamSnapshot(ctx, ref, snap)
 	if err != nil {
 		return "", err
 	}
@@ -307,6 +410,16 @@ func (b *diyBackend) removeStack(ctx context.Context, ref *diyBackendReference)
 	// Just make a backup of the file and don't write out anything new.
 	file := b.stackPath(ctx, ref)
 	backupTarget(ctx, b.bucket, file, false)
+	b.forgetCheckpointVersion(file)
+
+	if err := b.removeStackTags(ctx, ref); err != nil {
+		return err
+	}
+
+	b.journals.Delete(ref.JournalDir())
+	if err := removeAllByPrefix(ctx, b.bucket, ref.JournalDir()); err != nil {
+		return err
+	}
 
 	historyDir := ref.HistoryDir()
 	return removeAllByPrefix(ctx, b.bucket, historyDir)
@@ -346,12 +459,8 @@ func (b *diyBackend) backupStack(ctx context.Context, ref *diyBackendReference)
 		return nil
 	}
 
-	// Read the current checkpoint file. (Assuming it aleady exists.)
+	// Copy the current checkpoint file. (Assuming it aleady exists.)
 	stackPath := b.stackPath(ctx, ref)
-	byts, err := b.bucket.ReadAll(ctx, stackPath)
-	if err != nil {
-		return err
-	}
 
 	// Get the backup directory.
 	backupDir := ref.BackupDir()
@@ -368,7 +477,7 @@ func (b *diyBackend) backupStack(ctx context.Context, ref *diyBackendReference)
 		base = strings.TrimSuffix(base, ext2)
 	}
 	backupFile := fmt.Sprintf("%s.%v%s", base, time.Now().UnixNano(), ext)
-	return b.bucket.WriteAll(ctx, filepath.Join(backupDir, backupFile), byts, nil)
+	return b.bucket.Copy(ctx, filepath.Join(backupDir, backupFile), stackPath, nil)
 }
 
 func (b *diyBackend) stackPath(ctx context.Context, ref *diyBackendReference) string {
@@ -414,19 +523,24 @@ func (b *diyBackend) stackPath(ctx context.Context, ref *diyBackendReference) st
 	return plainPath
 }
 
-// getHistory returns stored update history. The first element of the result will be
-// the most recent update record.
-func (b *diyBackend) getHistory(
-	ctx context.Context,
-	stack *diyBackendReference,
-	pageSize int, page int,
-) ([]backend.UpdateInfo, error) {
-	contract.Requiref(stack != nil, "stack", "must not be nil")
+// isHistoryFile reports whether a key in a stack's history directory is an update record, as opposed to the
+// checkpoint saved alongside it.
+func isHistoryFile(key string) bool {
+	return strings.HasSuffix(key, ".history.json") || strings.HasSuffix(key, ".history.json.gz")
+}
+
+// historyCheckpointFile returns the key of the checkpoint copy saved alongside the given history file.
+func historyCheckpointFile(historyFile string) string {
+	i := strings.LastIndex(historyFile, ".history.")
+	contract.Assertf(i != -1, "%q is not a history file", historyFile)
+	return historyFile[:i] + ".checkpoint." + historyFile[i+len(".history."):]
+}
 
-	dir := stack.HistoryDir()
+// listHistoryFiles returns the keys of the update records of a stack, oldest first.
+func (b *diyBackend) listHistoryFiles(ctx context.Context, stack *diyBackendReference) ([]string, error) {
 	// TODO: we could consider optimizing the list operation using `page` and `pageSize`.
 	// Unfortunately, this is mildly invasive given the gocloud List API.
-	allFiles, err := listBucket(ctx, b.bucket, dir)
+	allFiles, err := listBucket(ctx, b.bucket, stack.HistoryDir())
 	if err != nil {
 		// History doesn't exist until a stack has been updated.
 		if gcerrors.Code(err) == gcerrors.NotFound {
@@ -435,61 +549,116 @@ func (b *diyBackend) getHistory(
 		return nil, err
 	}
 
-	var historyEntries []*blob.ListObject
-
-	// filter down to just history entries, reversing list to be in most recent order.
-	// listBucket returns the array sorted by file name, but because of how we name files, older updates come before
-	// newer ones.
-	for i := len(allFiles) - 1; i >= 0; i-- {
-		file := allFiles[i]
-		filepath := file.Key
-
+	// listBucket returns the array sorted by file name, and because of how we name files, older updates come
+	// before newer ones.
+	var historyFiles []string
+	for _, file := range allFiles {
 		// ignore checkpoints
-		if !strings.HasSuffix(filepath, ".history.json") &&
-			!strings.HasSuffix(filepath, ".history.json.gz") {
-			continue
+		if isHistoryFile(file.Key) {
+			historyFiles = append(historyFiles, file.Key)
 		}
+	}
+	return historyFiles, nil
+}
+
+// readHistoryFile reads a single update record.
+func (b *diyBackend) readHistoryFile(ctx context.Context, filepath string) (backend.UpdateInfo, error) {
+	var update backend.UpdateInfo
+	byts, err := b.bucket.ReadAll(ctx, filepath)
+	if err != nil {
+		return update, fmt.Errorf("reading history file %s: %w", filepath, err)
+	}
+	m := encoding.JSON
+	if encoding.IsCompressed(byts) {
+		m = encoding.Gzip(m)
+	}
+	if err = m.Unmarshal(byts, &update); err != nil {
+		return update, fmt.Errorf("reading history file %s: %w", filepath, err)
+	}
+	return update, nil
+}
+
+// historyVersion returns the version of the update record at the given position of the (oldest first) history.
+// Records written before versions were stored are numbered by their position, the first update being version 1.
+func historyVersion(update backend.UpdateInfo, index int) int {
+	if update.Version > 0 {
+		return update.Version
+	}
+	return index + 1
+}
+
+// getHistory returns stored update history. The first element of the result will be
+// the most recent update record.
+func (b *diyBackend) getHistory(
+	ctx context.Context,
+	stack *diyBackendReference,
+	pageSize int, page int,
+) ([]backend.UpdateInfo, error) {
+	contract.Requiref(stack != nil, "stack", "must not be nil")
 
-		historyEntries = append(historyEntries, file)
+	historyFiles, err := b.listHistoryFiles(ctx, stack)
+	if err != nil {
+		return nil, err
 	}
 
 	start := 0
-	end := len(historyEntries) - 1
+	end := len(historyFiles) - 1
 	if pageSize > 0 {
 		if page < 1 {
 			page = 1
 		}
 		start = (page - 1) * pageSize
 		end = start + pageSize - 1
-		if end > len(historyEntries)-1 {
-			end = len(historyEntries) - 1
+		if end > len(historyFiles)-1 {
+			end = len(historyFiles) - 1
 		}
 	}
 
 	var updates []backend.UpdateInfo
 
+	// Pages count back from the most recent update.
 	for i := start; i <= end; i++ {
-		file := historyEntries[i]
-		filepath := file.Key
+		index := len(historyFiles) - 1 - i
+		update, err := b.readHistoryFile(ctx, historyFiles[index])
+		if err != nil {
+			return nil, err
+		}
+		update.Version = historyVersion(update, index)
+		updates = append(updates, update)
+	}
+
+	return updates, nil
+}
 
-		var update backend.UpdateInfo
-		b, err := b.bucket.ReadAll(ctx, filepath)
+// getHistoryCheckpoint returns the key of the checkpoint saved by the update with the given version.
+func (b *diyBackend) getHistoryCheckpoint(
+	ctx context.Context, stack *diyBackendReference, version int,
+) (string, error) {
+	historyFiles, err := b.listHistoryFiles(ctx, stack)
+	if err != nil {
+		return "", err
+	}
+
+	// Unless the history has been compacted, the update is at the position given by its version.
+	if index := version - 1; index < len(historyFiles) {
+		update, err := b.readHistoryFile(ctx, historyFiles[index])
 		if err != nil {
-			return nil, fmt.Errorf("reading history file %s: %w", filepath, err)
+			return "", err
 		}
-		m := encoding.JSON
-		if encoding.IsCompressed(b) {
-			m = encoding.Gzip(m)
+		if historyVersion(update, index) == version {
+			return historyCheckpointFile(historyFiles[index]), nil
 		}
-		err = m.Unmarshal(b, &update)
+	}
+	for index, file := range historyFiles {
+		update, err := b.readHistoryFile(ctx, file)
 		if err != nil {
-			return nil, fmt.Errorf("reading history file %s: %w", filepath, err)
+			return "", err
+		}
+		if historyVersion(update, index) == version {
+			return historyCheckpointFile(file), nil
 		}
-
-		updates = append(updates, update)
 	}
-
-	return updates, nil
+	return "", fmt.Errorf("stack %s has no update with version %d", stack, version)
 }
 
 func (b *diyBackend) renameHistory(ctx context.Context, oldName, newName *diyBackendReference) error {
@@ -541,6 +710,20 @@ func (b *diyBackend) addToHistory(ctx context.Context, ref *diyBackendReference,
 
 	dir := ref.HistoryDir()
 
+	// Number the update after the latest one in the history.
+	historyFiles, err := b.listHistoryFiles(ctx, ref)
+	if err != nil {
+		return err
+	}
+	update.Version = 1
+	if n := len(historyFiles); n > 0 {
+		latest, err := b.readHistoryFile(ctx, historyFiles[n-1])
+		if err != nil {
+			return err
+		}
+		update.Version = historyVersion(latest, n-1) + 1
+	}
+
 	// Prefix for the update and checkpoint files.
 	pathPrefix := path.Join(dir, fmt.Sprintf("%s-%d", ref.name, time.Now().UnixNano()))
 
@@ -0,0 +1,134 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+// stackTags is the on-disk format of the tags file stored next to each stack's checkpoint.
+type stackTags struct {
+	Tags map[apitype.StackTagName]string `json:"tags"`
+}
+
+// getStackTags loads the tags for the given stack.
+// If the stack has no tags file, it returns nil and no error.
+func (b *diyBackend) getStackTags(
+	ctx context.Context, ref *diyBackendReference,
+) (map[apitype.StackTagName]string, error) {
+	tagsPath := ref.TagsPath()
+	byts, err := b.bucket.ReadAll(ctx, tagsPath)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %q: %w", tagsPath, err)
+	}
+
+	var st stackTags
+	if err := json.Unmarshal(byts, &st); err != nil {
+		return nil, fmt.Errorf("corrupt store: unmarshal %q: %w", tagsPath, err)
+	}
+	return st.Tags, nil
+}
+
+// saveStackTags writes the tags for the given stack, replacing any existing tags.
+// Writing an empty set of tags removes the tags file.
+func (b *diyBackend) saveStackTags(
+	ctx context.Context, ref *diyBackendReference, tags map[apitype.StackTagName]string,
+) error {
+	if len(tags) == 0 {
+		return b.removeStackTags(ctx, ref)
+	}
+
+	tagsPath := ref.TagsPath()
+	byts, err := json.MarshalIndent(stackTags{Tags: tags}, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshalling tags: %w", err)
+	}
+	if err := b.bucket.WriteAll(ctx, tagsPath, byts, nil); err != nil {
+		return fmt.Errorf("write %q: %w", tagsPath, err)
+	}
+	return nil
+}
+
+// removeStackTags deletes the tags file for the given stack, if any.
+func (b *diyBackend) removeStackTags(ctx context.Context, ref *diyBackendReference) error {
+	tagsPath := ref.TagsPath()
+	// Check first so we don't go through the bucket's delete retries for stacks that were never tagged.
+	exists, err := b.bucket.Exists(ctx, tagsPath)
+	if err != nil {
+		return fmt.Errorf("check %q: %w", tagsPath, err)
+	}
+	if !exists {
+		return nil
+	}
+	if err := b.bucket.Delete(ctx, tagsPath); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return fmt.Errorf("delete %q: %w", tagsPath, err)
+	}
+	return nil
+}
+
+// renameStackTags moves the tags of oldRef over to newRef.
+func (b *diyBackend) renameStackTags(ctx context.Context, oldRef, newRef *diyBackendReference) error {
+	tags, err := b.getStackTags(ctx, oldRef)
+	if err != nil {
+		return err
+	}
+	if tags == nil {
+		return nil
+	}
+
+	if err := b.saveStackTags(ctx, newRef, tags); err != nil {
+		return err
+	}
+	return b.removeStackTags(ctx, oldRef)
+}
+
+// filterHasTags reports whether the filter needs stack tags to be evaluated.
+func filterHasTags(filter backend.ListStacksFilter) bool {
+	return filter.TagName != nil || filter.TagValue != nil
+}
+
+// tagsMatchFilter reports whether the given tags satisfy the tag constraints in the filter.
+//
+// A TagName on its own matches any stack that has that tag.
+// A TagValue on its own matches any stack that has some tag with that value.
+func tagsMatchFilter(filter backend.ListStacksFilter, tags map[apitype.StackTagName]string) bool {
+	switch {
+	case filter.TagName != nil && filter.TagValue != nil:
+		v, has := tags[*filter.TagName]
+		return has && v == *filter.TagValue
+	case filter.TagName != nil:
+		_, has := tags[*filter.TagName]
+		return has
+	case filter.TagValue != nil:
+		for _, v := range tags {
+			if v == *filter.TagValue {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// TemplatesDir is a path under the state's root directory
+// where the diy backend looks for project templates.
+//
+// The directory holds an index.json file listing the templates (see templateIndex),
+// and a tarball for each template, optionally gzipped, with Pulumi.yaml at its root.
+var TemplatesDir = filepath.Join(workspace.BookkeepingDir, "templates")
+
+// templateIndexPath is the path inside the bucket of the template index.
+var templateIndexPath = path.Join(filepath.ToSlash(TemplatesDir), "index.json")
+
+// templateIndex is the on-disk format of the template index.
+//
+// Each template's sourceURL is the path of its tarball relative to TemplatesDir.
+// Templates without a sourceName are grouped under the backend's URL.
+type templateIndex struct {
+	Templates []*apitype.PulumiTemplateRemote `json:"templates"`
+}
+
+// readTemplateIndex reads the template index from the bucket.
+// A bucket without an index has no templates.
+func (b *diyBackend) readTemplateIndex(ctx context.Context) (*templateIndex, error) {
+	byts, err := b.bucket.ReadAll(ctx, templateIndexPath)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return &templateIndex{}, nil
+		}
+		return nil, fmt.Errorf("read %q: %w", templateIndexPath, err)
+	}
+
+	var index templateIndex
+	if err := json.Unmarshal(byts, &index); err != nil {
+		return nil, fmt.Errorf("corrupt store: unmarshal %q: %w", templateIndexPath, err)
+	}
+	return &index, nil
+}
+
+func (b *diyBackend) SupportsTemplates() bool {
+	return true
+}
+
+// ListTemplates lists the templates in the bucket's template index.
+// The organization is ignored, since diy backends only have one.
+func (b *diyBackend) ListTemplates(ctx context.Context, _ string) (apitype.ListOrgTemplatesResponse, error) {
+	index, err := b.readTemplateIndex(ctx)
+	if err != nil {
+		return apitype.ListOrgTemplatesResponse{}, err
+	}
+
+	templates := map[string][]*apitype.PulumiTemplateRemote{}
+	for _, t := range index.Templates {
+		if t.SourceName == "" {
+			t.SourceName = b.url
+		}
+		templates[t.SourceName] = append(templates[t.SourceName], t)
+	}
+	return apitype.ListOrgTemplatesResponse{
+		Templates:       templates,
+		OrgHasTemplates: len(index.Templates) > 0,
+	}, nil
+}
+
+// DownloadTemplate reads the tarball of a template listed by ListTemplates.
+// Only tarballs listed in the template index can be downloaded,
+// so this can't be used to read arbitrary files from the bucket.
+func (b *diyBackend) DownloadTemplate(ctx context.Context, _, sourceURL string) (backend.TarReaderCloser, error) {
+	index, err := b.readTemplateIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found bool
+	for _, t := range index.Templates {
+		if t.TemplateURL == sourceURL {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("template %q is not in the template index", sourceURL)
+	}
+
+	tarballPath := path.Join(filepath.ToSlash(TemplatesDir), sourceURL)
+	byts, err := b.bucket.ReadAll(ctx, tarballPath)
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %w", tarballPath, err)
+	}
+
+	if !encoding.IsCompressed(byts) {
+		return &tarReaderCloser{r: io.NopCloser(bytes.NewReader(byts))}, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(byts))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt store: decompress %q: %w", tarballPath, err)
+	}
+	return &tarReaderCloser{r: gz}, nil
+}
+
+// tarReaderCloser is a backend.TarReaderCloser over a template tarball.
+type tarReaderCloser struct {
+	r io.ReadCloser
+}
+
+func (t *tarReaderCloser) Tar() *tar.Reader { return tar.NewReader(t.r) }
+
+func (t *tarReaderCloser) Close() error { return t.r.Close() }
@@ -0,0 +1,121 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+func TestTemplates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+	b := be.(*diyBackend)
+
+	// A bucket without an index has no templates.
+	templates, err := b.ListTemplates(ctx, "")
+	require.NoError(t, err)
+	assert.False(t, templates.OrgHasTemplates)
+	assert.Empty(t, templates.Templates)
+
+	var tarball bytes.Buffer
+	gz := gzip.NewWriter(&tarball)
+	tw := tar.NewWriter(gz)
+	content := []byte("name: ${PROJECT}\nruntime: go\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "Pulumi.yaml", Mode: 0o600, Size: int64(len(content))}))
+	_, err = tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	templatesDir := filepath.ToSlash(TemplatesDir)
+	require.NoError(t, b.bucket.WriteAll(ctx, path.Join(templatesDir, "web-go.tgz"), tarball.Bytes(), nil))
+	require.NoError(t, b.bucket.WriteAll(ctx, path.Join(templatesDir, "index.json"), []byte(`{
+		"templates": [
+			{"name": "web-go", "sourceURL": "web-go.tgz", "runtime": "go", "description": "A web service"},
+			{"name": "web-ts", "sourceName": "platform", "sourceURL": "web-ts.tgz", "runtime": "nodejs"}
+		]
+	}`), nil))
+
+	templates, err = b.ListTemplates(ctx, "")
+	require.NoError(t, err)
+	assert.True(t, templates.OrgHasTemplates)
+	require.Len(t, templates.Templates[b.url], 1)
+	assert.Equal(t, "web-go", templates.Templates[b.url][0].Name)
+	assert.Equal(t, "A web service", templates.Templates[b.url][0].Description)
+	require.Len(t, templates.Templates["platform"], 1)
+
+	trc, err := b.DownloadTemplate(ctx, "", "web-go.tgz")
+	require.NoError(t, err)
+	defer trc.Close()
+	header, err := trc.Tar().Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Pulumi.yaml", header.Name)
+
+	// Only files in the index can be downloaded.
+	_, err = b.DownloadTemplate(ctx, "", "../meta.yaml")
+	assert.ErrorContains(t, err, "is not in the template index")
+
+	// Listed templates whose tarball is missing fail to download.
+	_, err = b.DownloadTemplate(ctx, "", "web-ts.tgz")
+	assert.Error(t, err)
+}
+
+func TestTemplates_uncompressed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	be, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+	b := be.(*diyBackend)
+
+	var tarball bytes.Buffer
+	tw := tar.NewWriter(&tarball)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "Pulumi.yaml", Mode: 0o600, Size: 3}))
+	_, err = tw.Write([]byte("a:b"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	templatesDir := filepath.ToSlash(TemplatesDir)
+	require.NoError(t, b.bucket.WriteAll(ctx, path.Join(templatesDir, "plain.tar"), tarball.Bytes(), nil))
+	require.NoError(t, b.bucket.WriteAll(ctx, path.Join(templatesDir, "index.json"),
+		[]byte(`{"templates": [{"name": "plain", "sourceURL": "plain.tar"}]}`), nil))
+
+	trc, err := b.DownloadTemplate(ctx, "", "plain.tar")
+	require.NoError(t, err)
+	defer trc.Close()
+	tr := trc.Tar()
+	_, err = tr.Next()
+	require.NoError(t, err)
+	byts, err := io.ReadAll(tr)
+	require.NoError(t, err)
+	assert.Equal(t, "a:b", string(byts))
+}
@@ -1170,6 +1170,20 @@ func (b *cloudBackend) Refresh(ctx context.Context, stack backend.Stack,
 	return backend.PreviewThenPromptThenExecute(ctx, apitype.RefreshUpdate, stack, op, b.apply)
 }
 
+func (b *cloudBackend) DetectDrift(ctx context.Context, stack backend.Stack,
+	op backend.UpdateOperation, events chan<- engine.Event,
+) (sdkDisplay.ResourceChanges, error) {
+	// Detecting drift is a refresh that is only ever previewed, so the stack's state is never written.
+	opts := backend.ApplierOptions{
+		DryRun:   true,
+		ShowLink: true,
+	}
+
+	op.Opts.Engine.GeneratePlan = false
+	_, changes, err := b.apply(ctx, apitype.RefreshUpdate, stack, op, opts, events)
+	return changes, err
+}
+
 func (b *cloudBackend) Destroy(ctx context.Context, stack backend.Stack,
 	op backend.UpdateOperation,
 ) (sdkDisplay.ResourceChanges, error) {
@@ -1424,7 +1438,7 @@ func (b *cloudBackend) runEngineAction(
 	// We only need a snapshot manager if we're doing an update.
 	var snapshotManager *backend.SnapshotManager
 	if kind != apitype.PreviewUpdate && !dryRun {
-		persister := b.newSnapshotPersister(ctx, u.update, u.tokenSource)
+		persister := b.newUpdatePersister(ctx, u.update, u.tokenSource)
 		snapshotManager = backend.NewSnapshotManager(persister, op.SecretsManager, u.GetTarget().Snapshot)
 	}
 
@@ -1,4 +1,4 @@
-// Copyright 2016-2022, Pulumi Corporation.
+// Copyright 2016-2024, Pulumi Corporation.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -23,6 +23,11 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend/httpstate/client"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 )
 
@@ -42,6 +47,12 @@ func (persister *cloudSnapshotPersister) Save(snapshot *deploy.Snapshot) error {
 	if err != nil {
 		return fmt.Errorf("serializing deployment: %w", err)
 	}
+	return persister.saveDeployment(deploymentV3)
+}
+
+// saveDeployment saves a serialized snapshot, as a diff against the last one saved if the service supports it.
+func (persister *cloudSnapshotPersister) saveDeployment(deploymentV3 *apitype.DeploymentV3) error {
+	ctx := persister.context
 
 	// Diff capability can be nil because of feature flagging.
 	if persister.deploymentDiffState == nil {
@@ -100,6 +111,50 @@ func (persister *cloudSnapshotPersister) saveFullVerbatim(ctx context.Context,
 
 var _ backend.SnapshotPersister = (*cloudSnapshotPersister)(nil)
 
+// cloudJournalPersister is a cloudSnapshotPersister that journals the changes made to the snapshot in between saves.
+// The service stores whole checkpoints, so the journal is replayed onto the snapshot that was last saved, and the
+// result saved in turn. This only serializes the resources that changed, rather than the whole snapshot, and with
+// delta checkpoint uploads only sends the service what changed as well.
+type cloudJournalPersister struct {
+	*cloudSnapshotPersister
+
+	// replay replays the journal onto the snapshot that was last saved.
+	replay *backend.JournalReplay
+	// secretsManager is the secrets manager of the snapshot that was last saved, which encrypts the journal's states.
+	secretsManager secrets.Manager
+}
+
+var _ backend.JournalPersister = (*cloudJournalPersister)(nil)
+
+func (persister *cloudJournalPersister) Save(snapshot *deploy.Snapshot) error {
+	deployment, err := stack.SerializeDeployment(persister.context, snapshot, false /* showSecrets */)
+	if err != nil {
+		return fmt.Errorf("serializing deployment: %w", err)
+	}
+	if err := persister.saveDeployment(deployment); err != nil {
+		return err
+	}
+	persister.replay, persister.secretsManager = backend.NewJournalReplay(*deployment), snapshot.SecretsManager
+	return nil
+}
+
+func (persister *cloudJournalPersister) Append(records []backend.JournalRecord) error {
+	contract.Assertf(persister.replay != nil, "the snapshot must be saved before the journal is appended to")
+
+	var enc config.Encrypter = config.NewPanicCrypter()
+	if persister.secretsManager != nil {
+		enc = persister.secretsManager.Encrypter()
+	}
+	serialized, err := backend.SerializeJournalRecords(persister.context, records, enc)
+	if err != nil {
+		return err
+	}
+	if err := persister.replay.Replay(serialized...); err != nil {
+		return fmt.Errorf("replaying journal: %w", err)
+	}
+	return persister.saveDeployment(persister.replay.Deployment())
+}
+
 func (b *cloudBackend) newSnapshotPersister(ctx context.Context, update client.UpdateIdentifier,
 	tokenSource tokenSourceCapability,
 ) *cloudSnapshotPersister {
@@ -117,3 +172,15 @@ func (b *cloudBackend) newSnapshotPersister(ctx context.Context, update client.U
 	}
 	return p
 }
+
+// newUpdatePersister returns the persister for the snapshots of an update, which journals them if
+// PULUMI_SERVICE_BACKEND_JOURNAL is set.
+func (b *cloudBackend) newUpdatePersister(ctx context.Context, update client.UpdateIdentifier,
+	tokenSource tokenSourceCapability,
+) backend.SnapshotPersister {
+	p := b.newSnapshotPersister(ctx, update, tokenSource)
+	if env.ServiceBackendJournal.Value() {
+		return &cloudJournalPersister{cloudSnapshotPersister: p}
+	}
+	return p
+}
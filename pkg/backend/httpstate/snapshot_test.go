@@ -36,6 +36,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/httpstate/client"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	lt "github.com/pulumi/pulumi/pkg/v3/engine/lifecycletest/framework"
@@ -259,6 +260,101 @@ func TestCloudSnapshotPersisterUseOfDiffProtocol(t *testing.T) {
 	}, typedPersistedState().Resources)
 }
 
+type registerResourceEvent struct {
+	deploy.SourceEvent
+}
+
+func (registerResourceEvent) Goal() *resource.Goal               { return nil }
+func (registerResourceEvent) Done(result *deploy.RegisterResult) {}
+
+func TestCloudJournalPersister(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var saved []apitype.DeploymentV3
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/capabilities":
+			assert.NoError(t, json.NewEncoder(rw).Encode(apitype.CapabilitiesResponse{}))
+		case "/api/stacks/owner/project/stack/update/update-id/checkpoint":
+			reader, err := gzip.NewReader(req.Body)
+			require.NoError(t, err)
+			defer reader.Close()
+			var patch apitype.PatchUpdateCheckpointRequest
+			require.NoError(t, json.NewDecoder(reader).Decode(&patch))
+			var deployment apitype.DeploymentV3
+			require.NoError(t, json.Unmarshal(patch.Deployment, &deployment))
+			saved = append(saved, deployment)
+			_, err = rw.Write([]byte(`{}`))
+			assert.NoError(t, err)
+		default:
+			panic(fmt.Sprintf("Path not supported: %v", req.URL.Path))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	backendGeneric, err := New(ctx, nil, server.URL, nil, false)
+	require.NoError(t, err)
+	persister := &cloudJournalPersister{
+		cloudSnapshotPersister: backendGeneric.(*cloudBackend).newSnapshotPersister(ctx, client.UpdateIdentifier{
+			StackIdentifier: client.StackIdentifier{
+				Owner:   "owner",
+				Project: "project",
+				Stack:   tokens.MustParseStackName("stack"),
+			},
+			UpdateKind: apitype.UpdateUpdate,
+			UpdateID:   "update-id",
+		}, tokenSourceFn(func() (string, error) { return "token", nil })),
+	}
+
+	newState := func(name string) *resource.State {
+		return &resource.State{
+			Type: "pkg:index:typ",
+			URN:  resource.NewURN("stack", "project", "", "pkg:index:typ", name),
+		}
+	}
+	a, b := newState("a"), newState("b")
+	base := &deploy.Snapshot{Resources: []*resource.State{a, b}}
+	manager := backend.NewSnapshotManager(persister, nil, base)
+
+	urns := func(deployment apitype.DeploymentV3) []resource.URN {
+		var urns []resource.URN
+		for _, res := range deployment.Resources {
+			urns = append(urns, res.URN)
+		}
+		return urns
+	}
+
+	// The whole snapshot is saved for the journal to build on, and each change is replayed onto it.
+	c := newState("c")
+	create := deploy.NewCreateStep(nil, registerResourceEvent{}, c)
+	mutation, err := manager.BeginMutation(create)
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, []resource.URN{a.URN, b.URN}, urns(saved[0]))
+	assert.Equal(t, []resource.URN{a.URN, b.URN}, urns(saved[1]))
+	require.Len(t, saved[1].PendingOperations, 1)
+	assert.Equal(t, c.URN, saved[1].PendingOperations[0].Resource.URN)
+
+	require.NoError(t, mutation.End(create, true))
+	require.Len(t, saved, 3)
+	assert.Equal(t, []resource.URN{c.URN, a.URN, b.URN}, urns(saved[2]))
+	assert.Empty(t, saved[2].PendingOperations)
+
+	del := deploy.NewDeleteStep(nil, map[resource.URN]bool{}, b)
+	mutation, err = manager.BeginMutation(del)
+	require.NoError(t, err)
+	require.NoError(t, mutation.End(del, true))
+	require.Len(t, saved, 5)
+	assert.Equal(t, []resource.URN{c.URN, a.URN}, urns(saved[4]))
+
+	// Closing the manager saves the whole snapshot again, which the journal agrees with.
+	require.NoError(t, manager.Close())
+	require.Len(t, saved, 6)
+	assert.Equal(t, saved[4].Resources, saved[5].Resources)
+}
+
 type tokenSourceFn func() (string, error)
 
 var _ tokenSourceCapability = tokenSourceFn(nil)
@@ -0,0 +1,166 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+)
+
+// SerializedJournalRecord is a JournalRecord whose step has been replaced by the parts of it that are needed to replay
+// the record, with its states serialized.
+type SerializedJournalRecord struct {
+	Kind engine.JournalEntryKind `json:"kind"`
+	Op   display.StepOp          `json:"op"`
+	// Old and New identify the old and new states of the step, or are -1 if the step doesn't have the state.
+	Old int `json:"old"`
+	New int `json:"new"`
+	// OldState and NewState are the old and new states of the step, as they were when the change was made.
+	OldState *apitype.ResourceV3 `json:"oldState,omitempty"`
+	NewState *apitype.ResourceV3 `json:"newState,omitempty"`
+	// SkippedCreate is set for same steps that stand in for resources whose creation was skipped.
+	SkippedCreate bool `json:"skippedCreate,omitempty"`
+}
+
+// SerializeJournalRecords serializes journal records, encrypting the secrets in their states with the given encrypter.
+func SerializeJournalRecords(
+	ctx context.Context, records []JournalRecord, enc config.Encrypter,
+) ([]SerializedJournalRecord, error) {
+	serialize := func(state *resource.State) (*apitype.ResourceV3, error) {
+		if state == nil {
+			return nil, nil
+		}
+		res, err := stack.SerializeResource(ctx, state, enc, false /* showSecrets */)
+		if err != nil {
+			return nil, fmt.Errorf("serializing resource %s: %w", state.URN, err)
+		}
+		return &res, nil
+	}
+
+	serialized := make([]SerializedJournalRecord, len(records))
+	for i, r := range records {
+		rec := SerializedJournalRecord{Kind: r.Kind, Op: r.Step.Op(), Old: r.Old, New: r.New}
+		if same, ok := r.Step.(*deploy.SameStep); ok {
+			rec.SkippedCreate = same.IsSkippedCreate()
+		}
+		var err error
+		if rec.OldState, err = serialize(r.Step.Old()); err != nil {
+			return nil, err
+		}
+		if rec.NewState, err = serialize(r.Step.New()); err != nil {
+			return nil, err
+		}
+		serialized[i] = rec
+	}
+	return serialized, nil
+}
+
+// JournalReplay replays serialized journal records onto the deployment they build on, with the same bookkeeping that
+// the SnapshotManager that recorded them used to build its snapshot.
+type JournalReplay struct {
+	base      apitype.DeploymentV3
+	states    map[int]apitype.ResourceV3
+	mutations *snapshotMutations[int]
+	replayed  int
+}
+
+// NewJournalReplay starts replaying a journal onto the deployment it builds on.
+func NewJournalReplay(base apitype.DeploymentV3) *JournalReplay {
+	r := &JournalReplay{
+		base:   base,
+		states: make(map[int]apitype.ResourceV3, len(base.Resources)),
+	}
+	for i, res := range base.Resources {
+		r.states[i] = res
+	}
+	r.mutations = newSnapshotMutations(-1, func(id int) bool {
+		return r.states[id].PendingReplacement
+	})
+	return r
+}
+
+// Replay applies the next records of the journal.
+func (r *JournalReplay) Replay(records ...SerializedJournalRecord) error {
+	for _, rec := range records {
+		i := r.replayed
+		r.replayed++
+
+		// States change in place while an update runs, so every record carries their latest contents.
+		if rec.Old >= 0 {
+			if rec.OldState == nil {
+				return fmt.Errorf("record %d is missing the state of %d", i, rec.Old)
+			}
+			r.states[rec.Old] = *rec.OldState
+		}
+		if rec.New >= 0 {
+			if rec.NewState == nil {
+				return fmt.Errorf("record %d is missing the state of %d", i, rec.New)
+			}
+			r.states[rec.New] = *rec.NewState
+		}
+		needsOld, needsNew := stepStates(rec.Op)
+		if needsOld && rec.Old < 0 || needsNew && rec.New < 0 {
+			return fmt.Errorf("record %d of a %s step is missing a state", i, rec.Op)
+		}
+
+		switch rec.Kind {
+		case engine.JournalEntryBegin:
+			r.mutations.begin(rec.Op, rec.Old, rec.New)
+		case engine.JournalEntrySuccess, engine.JournalEntryFailure:
+			r.mutations.end(rec.Op, rec.Old, rec.New, rec.Kind == engine.JournalEntrySuccess, rec.SkippedCreate)
+		}
+	}
+	return nil
+}
+
+// Deployment returns the deployment built by the records replayed so far.
+func (r *JournalReplay) Deployment() *apitype.DeploymentV3 {
+	ids := make([]int, len(r.base.Resources))
+	for i := range ids {
+		ids[i] = i
+	}
+	var resources []apitype.ResourceV3
+	for _, id := range r.mutations.merge(ids) {
+		resources = append(resources, r.states[id])
+	}
+
+	var pending []apitype.OperationV2
+	for _, op := range r.mutations.pendingOperations() {
+		pending = append(pending, apitype.OperationV2{
+			Resource: r.states[op.state],
+			Type:     apitype.OperationType(op.typ),
+		})
+	}
+	// As in SnapshotManager.snap, pending creates of the base deployment need user intervention to be cleared, so
+	// they are carried over.
+	for _, op := range r.base.PendingOperations {
+		if op.Type == apitype.OperationTypeCreating {
+			pending = append(pending, op)
+		}
+	}
+
+	deployment := r.base
+	deployment.Resources = resources
+	deployment.PendingOperations = pending
+	return &deployment
+}
@@ -85,6 +85,8 @@ type MockBackend struct {
 		UpdateOperation, []deploy.Import) (sdkDisplay.ResourceChanges, error)
 	RefreshF func(context.Context, Stack,
 		UpdateOperation) (sdkDisplay.ResourceChanges, error)
+	DetectDriftF func(context.Context, Stack,
+		UpdateOperation, chan<- engine.Event) (sdkDisplay.ResourceChanges, error)
 	DestroyF func(context.Context, Stack,
 		UpdateOperation) (sdkDisplay.ResourceChanges, error)
 	WatchF func(context.Context, Stack,
@@ -310,6 +312,15 @@ func (be *MockBackend) Refresh(ctx context.Context, stack Stack,
 	panic("not implemented")
 }
 
+func (be *MockBackend) DetectDrift(ctx context.Context, stack Stack,
+	op UpdateOperation, events chan<- engine.Event,
+) (sdkDisplay.ResourceChanges, error) {
+	if be.DetectDriftF != nil {
+		return be.DetectDriftF(ctx, stack, op, events)
+	}
+	panic("not implemented")
+}
+
 func (be *MockBackend) Destroy(ctx context.Context, stack Stack,
 	op UpdateOperation,
 ) (sdkDisplay.ResourceChanges, error) {
@@ -16,11 +16,29 @@ package backend
 
 import (
 	ctx "context"
+	"errors"
+	"io"
+
+	"github.com/blang/semver"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 )
 
+// ErrPackageNotFound is returned by PackageResolver when a package isn't in the registry.
+var ErrPackageNotFound = errors.New("package not found")
+
 type PackageRegistry interface {
 	// Publish publishes a package to the package registry.
 	Publish(ctx ctx.Context, op apitype.PackagePublishOp) error
 }
+
+// PackageResolver is implemented by package registries that can look up the packages published to them.
+type PackageResolver interface {
+	// GetPackage returns the metadata of a published package. The source and publisher may be empty if they are
+	// unambiguous, and a nil version returns the latest version of the package.
+	GetPackage(
+		ctx ctx.Context, source, publisher, name string, version *semver.Version,
+	) (apitype.PackageMetadata, error)
+	// GetPackageSchema returns the JSON schema of a published package version.
+	GetPackageSchema(ctx ctx.Context, pkg apitype.PackageMetadata) (io.ReadCloser, error)
+}
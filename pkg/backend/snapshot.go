@@ -47,6 +47,30 @@ type SnapshotPersister interface {
 	Save(snapshot *deploy.Snapshot) error
 }
 
+// JournalPersister is a SnapshotPersister that can also persist a snapshot as a journal of the mutations made to it,
+// rather than as a whole after every mutation.
+//
+// A SnapshotManager with a JournalPersister saves the whole snapshot before the first mutation that isn't a refresh,
+// appends every mutation after that to the journal, and saves the whole snapshot again when it is closed, at which
+// point the journal can be discarded.
+type JournalPersister interface {
+	SnapshotPersister
+
+	// Appends the given mutations, in order, to the journal of the snapshot that was last saved. Returns an error if
+	// the persistence failed.
+	Append(records []JournalRecord) error
+}
+
+// JournalRecord is a mutation made to a snapshot, as given to a JournalPersister.
+type JournalRecord struct {
+	engine.JournalEntry
+
+	// Old and New identify the old and new resource states of the entry's step, or are -1 if the step doesn't have
+	// the state. The resources of the snapshot that was last saved are identified by their index in it, and all other
+	// states by increasing numbers, in the order in which they first appear in the journal.
+	Old, New int
+}
+
 // SnapshotManager is an implementation of engine.SnapshotManager that inspects steps and performs
 // mutations on the global snapshot object serially. This implementation maintains two bits of state: the "base"
 // snapshot, which is completely immutable and represents the state of the world prior to the application
@@ -63,25 +87,25 @@ type SnapshotPersister interface {
 // This is subtle and a little confusing. The reason for this is that the engine directly mutates resource objects
 // that it creates and expects those mutations to be persisted directly to the snapshot.
 type SnapshotManager struct {
-	persister        SnapshotPersister        // The persister responsible for invalidating and persisting the snapshot
-	baseSnapshot     *deploy.Snapshot         // The base snapshot for this plan
-	secretsManager   secrets.Manager          // The default secrets manager to use
-	resources        []*resource.State        // The list of resources operated upon by this plan
-	operations       []resource.Operation     // The set of operations known to be outstanding in this plan
-	dones            map[*resource.State]bool // The set of resources that have been operated upon already by this plan
-	completeOps      map[*resource.State]bool // The set of resources that have completed their operation
-	mutationRequests chan<- mutationRequest   // The queue of mutation requests, to be retired serially by the manager
-	cancel           chan bool                // A channel used to request cancellation of any new mutation requests.
-	done             <-chan error             // A channel that sends a single result when the manager has shut down.
-
-	// The set of resources that have been deleted. These resources could also have been added to `resources`
-	// by other operations but need to be filtered out before writing the snapshot.
-	deletes map[*resource.State]bool
+	persister        SnapshotPersister      // The persister responsible for invalidating and persisting the snapshot
+	baseSnapshot     *deploy.Snapshot       // The base snapshot for this plan
+	secretsManager   secrets.Manager        // The default secrets manager to use
+	mutationRequests chan<- mutationRequest // The queue of mutation requests, to be retired serially by the manager
+	cancel           chan bool              // A channel used to request cancellation of any new mutation requests.
+	done             <-chan error           // A channel that sends a single result when the manager has shut down.
+
+	// The changes made to the base snapshot by this plan, shared with JournalReplay.
+	mutations *snapshotMutations[*resource.State]
+
+	journal        JournalPersister        // The persister of the journal, if the snapshot is journaled
+	journalIDs     map[*resource.State]int // The identities of the states in the journal, once it has been started
+	journalRecords []JournalRecord         // The records of mutations whose writes have been elided
 }
 
 var _ engine.SnapshotManager = (*SnapshotManager)(nil)
 
 type mutationRequest struct {
+	entry   engine.JournalEntry
 	mutator func() bool
 	result  chan<- error
 }
@@ -106,12 +130,15 @@ func (sm *SnapshotManager) Close() error {
 // meaningful changes (see sameSnapshotMutation.mustWrite for details). Any elided writes
 // are flushed by the next non-elided write or the next call to Close.
 //
+// The journal entry describes the mutation to persisters that journal the snapshot rather than
+// saving it whole (see JournalPersister).
+//
 // You should never observe or mutate the global snapshot without using this function unless
 // you have a very good justification.
-func (sm *SnapshotManager) mutate(mutator func() bool) error {
+func (sm *SnapshotManager) mutate(entry engine.JournalEntry, mutator func() bool) error {
 	result := make(chan error)
 	select {
-	case sm.mutationRequests <- mutationRequest{mutator: mutator, result: result}:
+	case sm.mutationRequests <- mutationRequest{entry: entry, mutator: mutator, result: result}:
 		return <-result
 	case <-sm.cancel:
 		return errors.New("snapshot manager closed")
@@ -130,7 +157,7 @@ func (sm *SnapshotManager) mutate(mutator func() bool) error {
 // Note that this is completely not thread-safe and defeats the purpose of having a `mutate` callback
 // entirely, but the hope is that this state of things will not be permament.
 func (sm *SnapshotManager) RegisterResourceOutputs(step deploy.Step) error {
-	return sm.mutate(func() bool {
+	return sm.mutate(engine.JournalEntry{Kind: engine.JournalEntryOutputs, Step: step}, func() bool {
 		old, new := step.Old(), step.New()
 		if old != nil && new != nil && old.Outputs.DeepEquals(new.Outputs) {
 			logging.V(9).Infof("SnapshotManager: eliding RegisterResourceOutputs due to equal outputs")
@@ -304,23 +331,16 @@ func (ssm *sameSnapshotMutation) End(step deploy.Step, successful bool) error {
 	contract.Requiref(step != nil, "step", "must not be nil")
 	contract.Requiref(step.Op() == deploy.OpSame, "step.Op()", "must be %q, got %q", deploy.OpSame, step.Op())
 	logging.V(9).Infof("SnapshotManager: sameSnapshotMutation.End(..., %v)", successful)
-	return ssm.manager.mutate(func() bool {
+	return ssm.manager.mutate(endEntry(step, successful), func() bool {
 		sameStep := step.(*deploy.SameStep)
 
-		ssm.manager.markOperationComplete(step.New())
+		ssm.manager.mutations.end(step.Op(), step.Old(), step.New(), successful, sameStep.IsSkippedCreate())
 		if successful {
-			ssm.manager.markDone(step.Old())
-
-			// In the case of a 'resource create' in a program that wasn't specified by the user in the
-			// --target list, we *never* want to write this to the checkpoint.  We treat it as if it
-			// doesn't exist at all.  That way when the program runs the next time, we'll actually
-			// create it.
+			// Skipped creates are never written to the checkpoint.
 			if sameStep.IsSkippedCreate() {
 				return false
 			}
 
-			ssm.manager.markNew(step.New())
-
 			// Note that "Same" steps only consider input and provider diffs, so it is possible to see a same step for a
 			// resource with new dependencies, outputs, parent, protection. etc.
 			//
@@ -339,8 +359,8 @@ func (ssm *sameSnapshotMutation) End(step deploy.Step, successful bool) error {
 
 func (sm *SnapshotManager) doCreate(step deploy.Step) (engine.SnapshotMutation, error) {
 	logging.V(9).Infof("SnapshotManager.doCreate(%s)", step.URN())
-	err := sm.mutate(func() bool {
-		sm.markOperationPending(step.New(), resource.OperationTypeCreating)
+	err := sm.mutate(engine.JournalEntry{Kind: engine.JournalEntryBegin, Step: step}, func() bool {
+		sm.mutations.begin(step.Op(), step.Old(), step.New())
 		return true
 	})
 	if err != nil {
@@ -357,34 +377,25 @@ type createSnapshotMutation struct {
 func (csm *createSnapshotMutation) End(step deploy.Step, successful bool) error {
 	contract.Requiref(step != nil, "step", "must not be nil")
 	logging.V(9).Infof("SnapshotManager: createSnapshotMutation.End(..., %v)", successful)
-	return csm.manager.mutate(func() bool {
-		csm.manager.markOperationComplete(step.New())
-		if successful {
-			// There is some very subtle behind-the-scenes magic here that
-			// comes into play whenever this create is a CreateReplacement.
-			//
-			// Despite intending for the base snapshot to be immutable, the engine
-			// does in fact mutate it by setting a `Delete` flag on resources
-			// being replaced as part of a Create-Before-Delete replacement sequence.
-			// Since we are storing the base snapshot and all resources by reference
-			// (we have pointers to engine-allocated objects), this transparently
-			// "just works" for the SnapshotManager.
-			csm.manager.markNew(step.New())
-
-			// If we had an old state that was marked as pending-replacement, mark its replacement as complete such
-			// that it is flushed from the state file.
-			if old := step.Old(); old != nil && old.PendingReplacement {
-				csm.manager.markDone(old)
-			}
-		}
+	return csm.manager.mutate(endEntry(step, successful), func() bool {
+		// There is some very subtle behind-the-scenes magic here that
+		// comes into play whenever this create is a CreateReplacement.
+		//
+		// Despite intending for the base snapshot to be immutable, the engine
+		// does in fact mutate it by setting a `Delete` flag on resources
+		// being replaced as part of a Create-Before-Delete replacement sequence.
+		// Since we are storing the base snapshot and all resources by reference
+		// (we have pointers to engine-allocated objects), this transparently
+		// "just works" for the SnapshotManager.
+		csm.manager.mutations.end(step.Op(), step.Old(), step.New(), successful, false)
 		return true
 	})
 }
 
 func (sm *SnapshotManager) doUpdate(step deploy.Step) (engine.SnapshotMutation, error) {
 	logging.V(9).Infof("SnapshotManager.doUpdate(%s)", step.URN())
-	err := sm.mutate(func() bool {
-		sm.markOperationPending(step.New(), resource.OperationTypeUpdating)
+	err := sm.mutate(engine.JournalEntry{Kind: engine.JournalEntryBegin, Step: step}, func() bool {
+		sm.mutations.begin(step.Op(), step.Old(), step.New())
 		return true
 	})
 	if err != nil {
@@ -401,20 +412,16 @@ type updateSnapshotMutation struct {
 func (usm *updateSnapshotMutation) End(step deploy.Step, successful bool) error {
 	contract.Requiref(step != nil, "step", "must not be nil")
 	logging.V(9).Infof("SnapshotManager: updateSnapshotMutation.End(..., %v)", successful)
-	return usm.manager.mutate(func() bool {
-		usm.manager.markOperationComplete(step.New())
-		if successful {
-			usm.manager.markDone(step.Old())
-			usm.manager.markNew(step.New())
-		}
+	return usm.manager.mutate(endEntry(step, successful), func() bool {
+		usm.manager.mutations.end(step.Op(), step.Old(), step.New(), successful, false)
 		return true
 	})
 }
 
 func (sm *SnapshotManager) doDelete(step deploy.Step) (engine.SnapshotMutation, error) {
 	logging.V(9).Infof("SnapshotManager.doDelete(%s)", step.URN())
-	err := sm.mutate(func() bool {
-		sm.markOperationPending(step.Old(), resource.OperationTypeDeleting)
+	err := sm.mutate(engine.JournalEntry{Kind: engine.JournalEntryBegin, Step: step}, func() bool {
+		sm.mutations.begin(step.Op(), step.Old(), step.New())
 		return true
 	})
 	if err != nil {
@@ -431,8 +438,7 @@ type deleteSnapshotMutation struct {
 func (dsm *deleteSnapshotMutation) End(step deploy.Step, successful bool) error {
 	contract.Requiref(step != nil, "step", "must not be nil")
 	logging.V(9).Infof("SnapshotManager: deleteSnapshotMutation.End(..., %v)", successful)
-	return dsm.manager.mutate(func() bool {
-		dsm.manager.markOperationComplete(step.Old())
+	return dsm.manager.mutate(endEntry(step, successful), func() bool {
 		if successful {
 			contract.Assertf(
 				!step.Old().Protect ||
@@ -441,23 +447,13 @@ func (dsm *deleteSnapshotMutation) End(step deploy.Step, successful bool) error
 				"Old must be unprotected (got %v) or the operation must be a replace (got %q)",
 				step.Old().Protect, step.Op())
 
-			if !step.Old().PendingReplacement {
-				// If this is a delete-replace operation, we don't want to mark the resource as deleted
-				// because we want to keep the new resource. If this is a normal delete/discard operation we
-				// need to add the resource to the "deletes" set so that we can filter it out when writing the
-				// snapshot.
-				op := step.Op()
-				contract.Assertf(
-					op == deploy.OpDiscardReplaced || op == deploy.OpReadDiscard ||
-						op == deploy.OpDeleteReplaced || op == deploy.OpDelete,
-					"unexpected step.Op(): %q", op)
-
-				if op == deploy.OpDelete || op == deploy.OpReadDiscard {
-					dsm.manager.deletes[step.Old()] = true
-				}
-				dsm.manager.markDone(step.Old())
-			}
+			op := step.Op()
+			contract.Assertf(
+				op == deploy.OpDiscardReplaced || op == deploy.OpReadDiscard ||
+					op == deploy.OpDeleteReplaced || op == deploy.OpDelete,
+				"unexpected step.Op(): %q", op)
 		}
+		dsm.manager.mutations.end(step.Op(), step.Old(), step.New(), successful, false)
 		return true
 	})
 }
@@ -473,8 +469,8 @@ func (rsm *replaceSnapshotMutation) End(step deploy.Step, successful bool) error
 
 func (sm *SnapshotManager) doRead(step deploy.Step) (engine.SnapshotMutation, error) {
 	logging.V(9).Infof("SnapshotManager.doRead(%s)", step.URN())
-	err := sm.mutate(func() bool {
-		sm.markOperationPending(step.New(), resource.OperationTypeReading)
+	err := sm.mutate(engine.JournalEntry{Kind: engine.JournalEntryBegin, Step: step}, func() bool {
+		sm.mutations.begin(step.Op(), step.Old(), step.New())
 		return true
 	})
 	if err != nil {
@@ -491,15 +487,8 @@ type readSnapshotMutation struct {
 func (rsm *readSnapshotMutation) End(step deploy.Step, successful bool) error {
 	contract.Requiref(step != nil, "step", "must not be nil")
 	logging.V(9).Infof("SnapshotManager: readSnapshotMutation.End(..., %v)", successful)
-	return rsm.manager.mutate(func() bool {
-		rsm.manager.markOperationComplete(step.New())
-		if successful {
-			if step.Old() != nil {
-				rsm.manager.markDone(step.Old())
-			}
-
-			rsm.manager.markNew(step.New())
-		}
+	return rsm.manager.mutate(endEntry(step, successful), func() bool {
+		rsm.manager.mutations.end(step.Op(), step.Old(), step.New(), successful, false)
 		return true
 	})
 }
@@ -512,7 +501,7 @@ func (rsm *refreshSnapshotMutation) End(step deploy.Step, successful bool) error
 	contract.Requiref(step != nil, "step", "must not be nil")
 	contract.Requiref(step.Op() == deploy.OpRefresh, "step.Op", "must be %q, got %q", deploy.OpRefresh, step.Op())
 	logging.V(9).Infof("SnapshotManager: refreshSnapshotMutation.End(..., %v)", successful)
-	return rsm.manager.mutate(func() bool {
+	return rsm.manager.mutate(endEntry(step, successful), func() bool {
 		// We always elide refreshes. The expectation is that all of these run before any actual mutations and that
 		// some other component will rewrite the base snapshot in-memory, so there's no action the snapshot
 		// manager needs to take other than to remember that the base snapshot--and therefore the actual snapshot--may
@@ -529,18 +518,18 @@ func (rsm *removePendingReplaceSnapshotMutation) End(step deploy.Step, successfu
 	contract.Requiref(step != nil, "step", "must not be nil")
 	contract.Requiref(step.Op() == deploy.OpRemovePendingReplace, "step.Op",
 		"must be %q, got %q", deploy.OpRemovePendingReplace, step.Op())
-	return rsm.manager.mutate(func() bool {
+	return rsm.manager.mutate(endEntry(step, successful), func() bool {
 		res := step.Old()
 		contract.Assertf(res.PendingReplacement, "resource %q must be pending replacement", res.URN)
-		rsm.manager.markDone(res)
+		rsm.manager.mutations.end(step.Op(), step.Old(), step.New(), successful, false)
 		return true
 	})
 }
 
 func (sm *SnapshotManager) doImport(step deploy.Step) (engine.SnapshotMutation, error) {
 	logging.V(9).Infof("SnapshotManager.doImport(%s)", step.URN())
-	err := sm.mutate(func() bool {
-		sm.markOperationPending(step.New(), resource.OperationTypeImporting)
+	err := sm.mutate(engine.JournalEntry{Kind: engine.JournalEntryBegin, Step: step}, func() bool {
+		sm.mutations.begin(step.Op(), step.Old(), step.New())
 		return true
 	})
 	if err != nil {
@@ -559,44 +548,18 @@ func (ism *importSnapshotMutation) End(step deploy.Step, successful bool) error
 	contract.Requiref(step.Op() == deploy.OpImport || step.Op() == deploy.OpImportReplacement, "step.Op",
 		"must be %q or %q, got %q", deploy.OpImport, deploy.OpImportReplacement, step.Op())
 
-	return ism.manager.mutate(func() bool {
-		ism.manager.markOperationComplete(step.New())
-		if successful {
-			ism.manager.markNew(step.New())
-		}
+	return ism.manager.mutate(endEntry(step, successful), func() bool {
+		ism.manager.mutations.end(step.Op(), step.Old(), step.New(), successful, false)
 		return true
 	})
 }
 
-// markDone marks a resource as having been processed. Resources that have been marked
-// in this manner won't be persisted in the snapshot.
-func (sm *SnapshotManager) markDone(state *resource.State) {
-	contract.Requiref(state != nil, "state", "must not be nil")
-	sm.dones[state] = true
-	logging.V(9).Infof("Marked old state snapshot as done: %v", state.URN)
-}
-
-// markNew marks a resource as existing in the new snapshot. This occurs on
-// successful non-deletion operations where the given state is the new state
-// of a resource that will be persisted to the snapshot.
-func (sm *SnapshotManager) markNew(state *resource.State) {
-	contract.Requiref(state != nil, "state", "must not be nil")
-	sm.resources = append(sm.resources, state)
-	logging.V(9).Infof("Appended new state snapshot to be written: %v", state.URN)
-}
-
-// markOperationPending marks a resource as undergoing an operation that will now be considered pending.
-func (sm *SnapshotManager) markOperationPending(state *resource.State, op resource.OperationType) {
-	contract.Requiref(state != nil, "state", "must not be nil")
-	sm.operations = append(sm.operations, resource.NewOperation(state, op))
-	logging.V(9).Infof("SnapshotManager.markPendingOperation(%s, %s)", state.URN, string(op))
-}
-
-// markOperationComplete marks a resource as having completed the operation that it previously was performing.
-func (sm *SnapshotManager) markOperationComplete(state *resource.State) {
-	contract.Requiref(state != nil, "state", "must not be nil")
-	sm.completeOps[state] = true
-	logging.V(9).Infof("SnapshotManager.markOperationComplete(%s)", state.URN)
+// endEntry returns the journal entry for the end of a step.
+func endEntry(step deploy.Step, successful bool) engine.JournalEntry {
+	if successful {
+		return engine.JournalEntry{Kind: engine.JournalEntrySuccess, Step: step}
+	}
+	return engine.JournalEntry{Kind: engine.JournalEntryFailure, Step: step}
 }
 
 // snap produces a new Snapshot given the base snapshot and a list of resources that the current
@@ -631,31 +594,16 @@ func (sm *SnapshotManager) snap() *deploy.Snapshot {
 	//         - If any of r's dependencies were not in the current list, they must already be in the merged list, as
 	//           they would have been appended to the list before r.
 
-	// Start with a copy of the resources produced during the evaluation of the current plan.
-	resources := make([]*resource.State, 0, len(sm.resources))
-
-	// if any resources have been deleted we need to filter them out here
-	for _, res := range sm.resources {
-		if !sm.deletes[res] {
-			resources = append(resources, res)
-		}
-	}
-
-	// Append any resources from the base plan that were not produced by the current plan.
-	if base := sm.baseSnapshot; base != nil {
-		for _, res := range base.Resources {
-			if !sm.dones[res] {
-				resources = append(resources, res)
-			}
-		}
+	var baseResources []*resource.State
+	if sm.baseSnapshot != nil {
+		baseResources = sm.baseSnapshot.Resources
 	}
+	resources := sm.mutations.merge(baseResources)
 
 	// Record any pending operations, if there are any outstanding that have not completed yet.
 	var operations []resource.Operation
-	for _, op := range sm.operations {
-		if !sm.completeOps[op.Resource] {
-			operations = append(operations, op)
-		}
+	for _, op := range sm.mutations.pendingOperations() {
+		operations = append(operations, resource.NewOperation(op.state, op.typ))
 	}
 
 	// Track pending create operations from the base snapshot
@@ -747,7 +695,9 @@ serviceLoop:
 		select {
 		case request := <-mutationRequests:
 			var err error
-			if request.mutator() {
+			if sm.journal != nil && request.entry.Step.Op() != deploy.OpRefresh {
+				err = sm.journalMutation(request)
+			} else if request.mutator() {
 				err = sm.saveSnapshot()
 				hasElidedWrites = false
 			} else {
@@ -759,15 +709,68 @@ serviceLoop:
 		}
 	}
 
-	// If we still have elided writes once the channel has closed, flush the snapshot.
+	// If we still have elided writes once the channel has closed, flush the snapshot. If we've been journaling the
+	// snapshot, save it whole so that the journal can be compacted.
 	var err error
-	if hasElidedWrites {
+	if hasElidedWrites || sm.journalIDs != nil {
 		logging.V(9).Infof("SnapshotManager: flushing elided writes...")
 		err = sm.saveSnapshot()
 	}
 	done <- err
 }
 
+// journalMutation applies a mutation and appends it to the journal, along with the mutations whose writes were
+// elided before it. The first mutation to be journaled saves the whole snapshot first, for the journal to build on.
+//
+// Refreshes are never journaled: they run before any other mutation, and the engine rewrites the base snapshot
+// in-memory once they're done, so the snapshot that the journal builds on is saved after them.
+func (sm *SnapshotManager) journalMutation(request mutationRequest) error {
+	if sm.journalIDs == nil {
+		if err := sm.saveSnapshot(); err != nil {
+			return err
+		}
+
+		// Nothing but refreshes has happened yet, so the resources of the snapshot we just saved are exactly those
+		// of the base snapshot, in the same order.
+		sm.journalIDs = make(map[*resource.State]int)
+		if sm.baseSnapshot != nil {
+			for i, res := range sm.baseSnapshot.Resources {
+				sm.journalIDs[res] = i
+			}
+		}
+	}
+
+	write := request.mutator()
+	sm.journalRecords = append(sm.journalRecords, JournalRecord{
+		JournalEntry: request.entry,
+		Old:          sm.journalID(request.entry.Step.Old()),
+		New:          sm.journalID(request.entry.Step.New()),
+	})
+	if !write {
+		return nil
+	}
+
+	records := sm.journalRecords
+	sm.journalRecords = nil
+	if err := sm.journal.Append(records); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// journalID returns the identity of a state in the journal, or -1 if the state is nil.
+func (sm *SnapshotManager) journalID(state *resource.State) int {
+	if state == nil {
+		return -1
+	}
+	id, has := sm.journalIDs[state]
+	if !has {
+		id = len(sm.journalIDs)
+		sm.journalIDs[state] = id
+	}
+	return id
+}
+
 // unsafeServiceLoop doesn't save Snapshots when mutations occur and instead saves Snapshots when
 // SnapshotManager.Close() is invoked. It trades reliability for speed as every mutation does not
 // cause a Snapshot to be serialized to the user's state backend.
@@ -787,6 +790,8 @@ func (sm *SnapshotManager) unsafeServiceLoop(mutationRequests chan mutationReque
 // NewSnapshotManager creates a new SnapshotManager for the given stack name, using the given persister, default secrets
 // manager and base snapshot.
 //
+// If the persister is a JournalPersister, the snapshot is journaled rather than saved whole after every mutation.
+//
 // It is *very important* that the baseSnap pointer refers to the same Snapshot given to the engine! The engine will
 // mutate this object and correctness of the SnapshotManager depends on being able to observe this mutation. (This is
 // not ideal...)
@@ -801,12 +806,15 @@ func NewSnapshotManager(
 		persister:        persister,
 		secretsManager:   secretsManager,
 		baseSnapshot:     baseSnap,
-		dones:            make(map[*resource.State]bool),
-		completeOps:      make(map[*resource.State]bool),
 		mutationRequests: mutationRequests,
 		cancel:           cancel,
 		done:             done,
-		deletes:          make(map[*resource.State]bool),
+		mutations: newSnapshotMutations(nil, func(state *resource.State) bool {
+			return state.PendingReplacement
+		}),
+	}
+	if journal, ok := persister.(JournalPersister); ok {
+		manager.journal = journal
 	}
 
 	serviceLoop := manager.defaultServiceLoop
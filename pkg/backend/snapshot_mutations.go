@@ -0,0 +1,230 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// snapshotMutations tracks how the steps of an update change the resources and pending operations of the snapshot
+// that the update started from. The SnapshotManager applies steps to it as they run, and JournalReplay applies the
+// records of a journal to it, so that replaying a journal always builds the snapshot that the SnapshotManager built.
+//
+// States are identified by S: the SnapshotManager uses the states allocated by the engine, and JournalReplay uses the
+// identities of the states in the journal.
+type snapshotMutations[S comparable] struct {
+	none               S            // The identity that stands for a missing state
+	pendingReplacement func(S) bool // Reports whether a state is pending replacement
+
+	resources   []S                   // The states written by this update, in order
+	written     map[S]int             // The positions of the states in resources
+	operations  []pendingOperation[S] // The operations started by this update, in order
+	dones       map[S]bool            // The states of the base snapshot that have been operated upon by this update
+	completeOps map[S]bool            // The states that have completed their operation
+
+	// The states that have been deleted. These could also have been added to resources by other operations, but need
+	// to be filtered out of the snapshot.
+	deletes map[S]bool
+}
+
+// pendingOperation is an operation on a state that has been started.
+type pendingOperation[S comparable] struct {
+	state S
+	typ   resource.OperationType
+}
+
+func newSnapshotMutations[S comparable](none S, pendingReplacement func(S) bool) *snapshotMutations[S] {
+	return &snapshotMutations[S]{
+		none:               none,
+		pendingReplacement: pendingReplacement,
+		written:            make(map[S]int),
+		dones:              make(map[S]bool),
+		completeOps:        make(map[S]bool),
+		deletes:            make(map[S]bool),
+	}
+}
+
+// begin records that a step has started. Steps that operate on a state mark the operation as pending until they end.
+func (m *snapshotMutations[S]) begin(op display.StepOp, old, new S) {
+	switch op {
+	case deploy.OpCreate, deploy.OpCreateReplacement:
+		m.markOperationPending(new, resource.OperationTypeCreating)
+	case deploy.OpUpdate:
+		m.markOperationPending(new, resource.OperationTypeUpdating)
+	case deploy.OpDelete, deploy.OpDeleteReplaced, deploy.OpReadDiscard, deploy.OpDiscardReplaced:
+		m.markOperationPending(old, resource.OperationTypeDeleting)
+	case deploy.OpRead, deploy.OpReadReplacement:
+		m.markOperationPending(new, resource.OperationTypeReading)
+	case deploy.OpImport, deploy.OpImportReplacement:
+		m.markOperationPending(new, resource.OperationTypeImporting)
+	}
+}
+
+// end records that a step has ended, successfully or not. skippedCreate is set for same steps that stand in for
+// resources whose creation was skipped.
+func (m *snapshotMutations[S]) end(op display.StepOp, old, new S, successful, skippedCreate bool) {
+	switch op {
+	case deploy.OpSame:
+		m.markOperationComplete(new)
+		if successful {
+			m.markDone(old)
+
+			// In the case of a 'resource create' in a program that wasn't specified by the user in the --target list,
+			// we *never* want to write this to the checkpoint. We treat it as if it doesn't exist at all. That way
+			// when the program runs the next time, we'll actually create it.
+			if !skippedCreate {
+				m.markNew(new)
+			}
+		}
+
+	case deploy.OpCreate, deploy.OpCreateReplacement:
+		m.markOperationComplete(new)
+		if successful {
+			m.markNew(new)
+
+			// If we had an old state that was marked as pending-replacement, mark its replacement as complete such
+			// that it is flushed from the state file.
+			if old != m.none && m.pendingReplacement(old) {
+				m.markDone(old)
+			}
+		}
+
+	case deploy.OpUpdate:
+		m.markOperationComplete(new)
+		if successful {
+			m.markUpdated(old, new)
+		}
+
+	case deploy.OpDelete, deploy.OpDeleteReplaced, deploy.OpReadDiscard, deploy.OpDiscardReplaced:
+		m.markOperationComplete(old)
+		if successful && !m.pendingReplacement(old) {
+			// If this is a delete-replace operation, we don't want to mark the resource as deleted because we want to
+			// keep the new resource. If this is a normal delete/discard operation we need to add the resource to the
+			// "deletes" set so that we can filter it out when writing the snapshot.
+			if op == deploy.OpDelete || op == deploy.OpReadDiscard {
+				m.deletes[old] = true
+			}
+			m.markDone(old)
+		}
+
+	case deploy.OpRead, deploy.OpReadReplacement:
+		m.markOperationComplete(new)
+		if successful {
+			if old != m.none {
+				m.markDone(old)
+			}
+			m.markNew(new)
+		}
+
+	case deploy.OpRemovePendingReplace:
+		m.markDone(old)
+
+	case deploy.OpImport, deploy.OpImportReplacement:
+		m.markOperationComplete(new)
+		if successful {
+			m.markNew(new)
+		}
+	}
+}
+
+// stepStates reports which of the old and new states of a step the bookkeeping of its operation requires.
+func stepStates(op display.StepOp) (old, new bool) {
+	switch op {
+	case deploy.OpSame, deploy.OpUpdate:
+		return true, true
+	case deploy.OpCreate, deploy.OpCreateReplacement, deploy.OpRead, deploy.OpReadReplacement,
+		deploy.OpImport, deploy.OpImportReplacement:
+		return false, true
+	case deploy.OpDelete, deploy.OpDeleteReplaced, deploy.OpReadDiscard, deploy.OpDiscardReplaced,
+		deploy.OpRemovePendingReplace:
+		return true, false
+	default:
+		return false, false
+	}
+}
+
+// markDone marks a state as having been processed. States of the base snapshot that have been marked in this manner
+// won't be persisted in the snapshot.
+func (m *snapshotMutations[S]) markDone(state S) {
+	contract.Requiref(state != m.none, "state", "must not be nil")
+	m.dones[state] = true
+}
+
+// markNew marks a state as existing in the new snapshot. This occurs on successful non-deletion operations where the
+// given state is the new state of a resource that will be persisted to the snapshot.
+func (m *snapshotMutations[S]) markNew(state S) {
+	contract.Requiref(state != m.none, "state", "must not be nil")
+	m.written[state] = len(m.resources)
+	m.resources = append(m.resources, state)
+}
+
+// markUpdated marks a resource as updated from the old state to the new one. The old state usually comes from the
+// base snapshot, but if it was written by this update, as when rolling back an update that this update made, the new
+// state takes its place in the new snapshot.
+func (m *snapshotMutations[S]) markUpdated(old, new S) {
+	if i, has := m.written[old]; has && old != new {
+		m.resources[i] = new
+		delete(m.written, old)
+		m.written[new] = i
+		return
+	}
+	m.markDone(old)
+	m.markNew(new)
+}
+
+// markOperationPending marks a state as undergoing an operation that will now be considered pending.
+func (m *snapshotMutations[S]) markOperationPending(state S, op resource.OperationType) {
+	contract.Requiref(state != m.none, "state", "must not be nil")
+	m.operations = append(m.operations, pendingOperation[S]{state: state, typ: op})
+}
+
+// markOperationComplete marks a state as having completed the operation that it previously was performing.
+func (m *snapshotMutations[S]) markOperationComplete(state S) {
+	contract.Requiref(state != m.none, "state", "must not be nil")
+	m.completeOps[state] = true
+}
+
+// merge returns the resources of the new snapshot given those of the base snapshot: the states written by this update
+// that haven't been deleted since, followed by the states of the base snapshot that haven't been operated upon. See
+// SnapshotManager.snap for why this order is a valid topological sort of the resources.
+func (m *snapshotMutations[S]) merge(base []S) []S {
+	resources := make([]S, 0, len(m.resources))
+	for _, res := range m.resources {
+		if !m.deletes[res] {
+			resources = append(resources, res)
+		}
+	}
+	for _, res := range base {
+		if !m.dones[res] {
+			resources = append(resources, res)
+		}
+	}
+	return resources
+}
+
+// pendingOperations returns the operations that this update has started but not completed, in the order they were
+// started.
+func (m *snapshotMutations[S]) pendingOperations() []pendingOperation[S] {
+	var operations []pendingOperation[S]
+	for _, op := range m.operations {
+		if !m.completeOps[op.state] {
+			operations = append(operations, op)
+		}
+	}
+	return operations
+}
@@ -15,12 +15,14 @@
 package backend
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
@@ -785,6 +787,43 @@ func TestRecordingUpdateSuccess(t *testing.T) {
 	assert.Equal(t, resource.NewStringProperty("new"), snap.Resources[0].Inputs["key"])
 }
 
+func TestRecordingUpdateOfNewState(t *testing.T) {
+	t.Parallel()
+
+	resourceA := NewResource("a")
+	resourceA.Inputs["key"] = resource.NewStringProperty("old")
+	resourceB := NewResource("b", "a")
+	snap := NewSnapshot([]*resource.State{
+		resourceA,
+		resourceB,
+	})
+
+	manager, sp := MockSetup(t, snap)
+	applyStep := func(step deploy.Step) {
+		mutation, err := manager.BeginMutation(step)
+		require.NoError(t, err)
+		require.NoError(t, mutation.End(step, true /* successful */))
+	}
+
+	resourceANew := NewResource("a")
+	resourceANew.Inputs["key"] = resource.NewStringProperty("new")
+	applyStep(deploy.NewUpdateStep(nil, &MockRegisterResourceEvent{}, resourceA, resourceANew, nil, nil, nil, nil))
+	applyStep(deploy.NewSameStep(nil, &MockRegisterResourceEvent{}, resourceB, NewResource("b", "a")))
+
+	// Rolling back the update replaces the state it wrote, which keeps the snapshot in dependency order.
+	resourceARestored := NewResource("a")
+	resourceARestored.Inputs["key"] = resource.NewStringProperty("old")
+	applyStep(deploy.NewUpdateStep(
+		nil, &MockRegisterResourceEvent{}, resourceANew, resourceARestored, nil, nil, nil, nil))
+
+	snap = sp.LastSnap()
+	require.Len(t, snap.Resources, 2)
+	assert.Equal(t, resource.URN("a"), snap.Resources[0].URN)
+	assert.Equal(t, resource.NewStringProperty("old"), snap.Resources[0].Inputs["key"])
+	assert.Equal(t, resource.URN("b"), snap.Resources[1].URN)
+	assert.NoError(t, snap.VerifyIntegrity())
+}
+
 func TestRecordingUpdateFailure(t *testing.T) {
 	t.Parallel()
 
@@ -1197,3 +1236,129 @@ func TestSnapshotIntegrityErrorMetadataIsClearedForValidSnapshotsChecksDisabled(
 	assert.NoError(t, err)
 	assert.Nil(t, sp.LastSnap().Metadata.IntegrityErrorMetadata)
 }
+
+type MockJournalPersister struct {
+	MockStackPersister
+	Appended [][]JournalRecord
+}
+
+func (m *MockJournalPersister) Append(records []JournalRecord) error {
+	m.Appended = append(m.Appended, records)
+	return nil
+}
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+
+	resourceA := NewResource(aUniqueUrnResourceA)
+	resourceB := NewResource(aUniqueUrnResourceB)
+	snap := NewSnapshot([]*resource.State{
+		resourceA,
+		resourceB,
+	})
+
+	sp := &MockJournalPersister{}
+	manager := NewSnapshotManager(sp, snap.SecretsManager, snap)
+
+	applyStep := func(step deploy.Step) {
+		mutation, err := manager.BeginMutation(step)
+		require.NoError(t, err)
+		require.NoError(t, mutation.End(step, true))
+	}
+
+	// The write of an identical same step is elided, but the whole snapshot is saved for the journal to build on.
+	applyStep(deploy.NewSameStep(nil, nil, resourceA, NewResource(resourceA.URN)))
+	require.Len(t, sp.SavedSnapshots, 1)
+	assert.Len(t, sp.SavedSnapshots[0].Resources, 2)
+	assert.Empty(t, sp.Appended)
+
+	// The elided same step is appended along with the beginning of the create.
+	resourceC := NewResource(aUniqueUrn)
+	applyStep(deploy.NewCreateStep(nil, &MockRegisterResourceEvent{}, resourceC))
+	require.Len(t, sp.Appended, 2)
+	require.Len(t, sp.Appended[0], 2)
+	assert.Equal(t, engine.JournalEntrySuccess, sp.Appended[0][0].Kind)
+	assert.Equal(t, deploy.OpSame, sp.Appended[0][0].Step.Op())
+	assert.Equal(t, 0, sp.Appended[0][0].Old)
+	assert.Equal(t, 2, sp.Appended[0][0].New)
+	assert.Equal(t, engine.JournalEntryBegin, sp.Appended[0][1].Kind)
+	assert.Equal(t, -1, sp.Appended[0][1].Old)
+	assert.Equal(t, 3, sp.Appended[0][1].New)
+	assert.Equal(t, []JournalRecord{{
+		JournalEntry: engine.JournalEntry{Kind: engine.JournalEntrySuccess, Step: sp.Appended[0][1].Step},
+		Old:          -1,
+		New:          3,
+	}}, sp.Appended[1])
+
+	// Base resources are identified by their index in the saved snapshot.
+	applyStep(deploy.NewDeleteStep(nil, map[resource.URN]bool{}, resourceB))
+	require.Len(t, sp.Appended, 4)
+	assert.Equal(t, 1, sp.Appended[3][0].Old)
+	assert.Equal(t, -1, sp.Appended[3][0].New)
+
+	// Closing the manager saves the whole snapshot again.
+	require.NoError(t, manager.Close())
+	require.Len(t, sp.SavedSnapshots, 2)
+	resources := sp.SavedSnapshots[1].Resources
+	require.Len(t, resources, 2)
+	assert.Equal(t, resourceA.URN, resources[0].URN)
+	assert.Equal(t, resourceC.URN, resources[1].URN)
+}
+
+func TestJournalReplay(t *testing.T) {
+	t.Parallel()
+
+	resourceA := NewResource(aUniqueUrnResourceA)
+	resourceB := NewResource(aUniqueUrnResourceB)
+	resourceC := NewResource(aUniqueUrnResourceP)
+	snap := NewSnapshot([]*resource.State{
+		resourceA,
+		resourceB,
+		resourceC,
+	})
+
+	sp := &MockJournalPersister{}
+	manager := NewSnapshotManager(sp, snap.SecretsManager, snap)
+
+	applyStep := func(step deploy.Step, successful bool) {
+		mutation, err := manager.BeginMutation(step)
+		require.NoError(t, err)
+		require.NoError(t, mutation.End(step, successful))
+	}
+
+	newA := NewResource(resourceA.URN)
+	newA.Outputs = resource.PropertyMap{"foo": resource.NewStringProperty("bar")}
+	applyStep(deploy.NewUpdateStep(nil, &MockRegisterResourceEvent{}, resourceA, newA, nil, nil, nil, nil), true)
+	applyStep(deploy.NewDeleteStep(nil, map[resource.URN]bool{}, resourceB), true)
+	applyStep(deploy.NewCreateStep(nil, &MockRegisterResourceEvent{}, NewResource(aUniqueUrn)), true)
+	applyStep(deploy.NewSameStep(nil, nil, resourceC, NewResource(resourceC.URN)), false)
+	// A create that fails is left pending in the snapshot.
+	_, err := manager.BeginMutation(deploy.NewCreateStep(nil, &MockRegisterResourceEvent{}, NewResource(
+		resource.NewURN("test-stack", "test-project", "", "pkg:typ", "a-unique-urn-resource-d"))))
+	require.NoError(t, err)
+
+	// Replaying the journal on top of the snapshot it builds on gives the snapshot the manager built.
+	ctx := context.Background()
+	require.Len(t, sp.SavedSnapshots, 1)
+	base, err := stack.SerializeDeployment(ctx, sp.SavedSnapshots[0], false /* showSecrets */)
+	require.NoError(t, err)
+	replay := NewJournalReplay(*base)
+	for _, records := range sp.Appended {
+		serialized, err := SerializeJournalRecords(ctx, records, snap.SecretsManager.Encrypter())
+		require.NoError(t, err)
+		require.NoError(t, replay.Replay(serialized...))
+	}
+
+	expected, err := stack.SerializeDeployment(ctx, manager.snap(), false /* showSecrets */)
+	require.NoError(t, err)
+	actual := replay.Deployment()
+	assert.Len(t, actual.Resources, 3)
+	assert.Equal(t, expected.Resources, actual.Resources)
+	require.Len(t, actual.PendingOperations, 1)
+	assert.Equal(t, expected.PendingOperations, actual.PendingOperations)
+
+	// Records of steps that are missing the states they operate on are rejected.
+	assert.ErrorContains(t, replay.Replay(SerializedJournalRecord{
+		Kind: engine.JournalEntrySuccess, Op: deploy.OpUpdate, Old: -1, New: 0, NewState: &base.Resources[0],
+	}), "of a update step is missing a state")
+}
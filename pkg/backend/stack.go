@@ -109,6 +109,13 @@ func RefreshStack(ctx context.Context, s Stack, op UpdateOperation) (display.Res
 	return s.Backend().Refresh(ctx, s, op)
 }
 
+// DetectStackDrift previews a refresh of the stack's state from the cloud provider without writing it.
+func DetectStackDrift(
+	ctx context.Context, s Stack, op UpdateOperation, events chan<- engine.Event,
+) (display.ResourceChanges, error) {
+	return s.Backend().DetectDrift(ctx, s, op, events)
+}
+
 // DestroyStack destroys all of this stack's resources.
 func DestroyStack(ctx context.Context, s Stack, op UpdateOperation) (display.ResourceChanges, error) {
 	return s.Backend().Destroy(ctx, s, op)
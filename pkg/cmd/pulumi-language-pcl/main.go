@@ -0,0 +1,309 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// pulumi-language-pcl is a language host that runs directories of PCL files directly, by evaluating the bound program
+// against the engine's resource monitor.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl/interpreter"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/version"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// Launches the language host, which in turn fires up an RPC server implementing the LanguageRuntimeServer endpoint.
+func main() {
+	var tracing string
+	flag.StringVar(&tracing, "tracing", "", "Emit tracing to a Zipkin-compatible tracing endpoint")
+	flag.String("root", "", "[obsolete] Project root path to use")
+	flag.Parse()
+
+	logging.InitLogging(false, 0, false)
+	cmdutil.InitTracing("pulumi-language-pcl", "pulumi-language-pcl", tracing)
+
+	var engineAddress string
+	if args := flag.Args(); len(args) > 0 {
+		engineAddress = args[0]
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	// map the context Done channel to the rpcutil boolean cancel channel
+	cancelChannel := make(chan bool)
+	go func() {
+		<-ctx.Done()
+		cancel() // deregister handler so we don't catch another interrupt
+		close(cancelChannel)
+	}()
+	if engineAddress != "" {
+		err := rpcutil.Healthcheck(ctx, engineAddress, 5*time.Minute, cancel)
+		if err != nil {
+			cmdutil.Exit(fmt.Errorf("could not start health check host RPC server: %w", err))
+		}
+	}
+
+	// Fire up a gRPC server, letting the kernel choose a free port.
+	handle, err := rpcutil.ServeWithOptions(rpcutil.ServeOptions{
+		Cancel: cancelChannel,
+		Init: func(srv *grpc.Server) error {
+			pulumirpc.RegisterLanguageRuntimeServer(srv, &pclLanguageHost{engineAddress: engineAddress})
+			return nil
+		},
+		Options: rpcutil.OpenTracingServerInterceptorOptions(nil),
+	})
+	if err != nil {
+		cmdutil.Exit(fmt.Errorf("could not start language host RPC server: %w", err))
+	}
+
+	// Otherwise, print out the port so that the spawner knows how to reach us.
+	fmt.Printf("%d\n", handle.Port)
+
+	// And finally wait for the server to stop serving.
+	if err := <-handle.Done; err != nil {
+		cmdutil.Exit(fmt.Errorf("language host RPC stopped serving: %w", err))
+	}
+}
+
+// pclLanguageHost implements the LanguageRuntimeServer interface for use as an API endpoint.
+type pclLanguageHost struct {
+	pulumirpc.UnimplementedLanguageRuntimeServer
+
+	engineAddress string
+}
+
+func (host *pclLanguageHost) Handshake(
+	ctx context.Context, req *pulumirpc.LanguageHandshakeRequest,
+) (*pulumirpc.LanguageHandshakeResponse, error) {
+	if req == nil || req.EngineAddress == "" {
+		return nil, errors.New("Must contain address in request")
+	}
+	host.engineAddress = req.EngineAddress
+
+	ctx, cancel := context.WithCancel(ctx)
+	err := rpcutil.Healthcheck(ctx, host.engineAddress, 5*time.Minute, cancel)
+	if err != nil {
+		return nil, fmt.Errorf("could not start health check host RPC server: %w", err)
+	}
+	return &pulumirpc.LanguageHandshakeResponse{}, nil
+}
+
+func (host *pclLanguageHost) GetPluginInfo(context.Context, *emptypb.Empty) (*pulumirpc.PluginInfo, error) {
+	return &pulumirpc.PluginInfo{Version: version.Version}, nil
+}
+
+// InstallDependencies has nothing to do: PCL programs have no dependencies other than the plugins that the engine
+// installs itself.
+func (host *pclLanguageHost) InstallDependencies(
+	req *pulumirpc.InstallDependenciesRequest, server pulumirpc.LanguageRuntime_InstallDependenciesServer,
+) error {
+	return nil
+}
+
+func (host *pclLanguageHost) GetProgramDependencies(
+	context.Context, *pulumirpc.GetProgramDependenciesRequest,
+) (*pulumirpc.GetProgramDependenciesResponse, error) {
+	return &pulumirpc.GetProgramDependenciesResponse{}, nil
+}
+
+// GetRequiredPackages returns the packages that the program's resources and invokes refer to, along with any packages
+// that are declared by package blocks. The program can't be bound until those packages are installed, so they are
+// read from the program's syntax.
+func (host *pclLanguageHost) GetRequiredPackages(
+	ctx context.Context, req *pulumirpc.GetRequiredPackagesRequest,
+) (*pulumirpc.GetRequiredPackagesResponse, error) {
+	packages := map[string]*pulumirpc.PackageDependency{}
+	if err := requiredPackages(req.Info.ProgramDirectory, packages, map[string]bool{}); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := &pulumirpc.GetRequiredPackagesResponse{}
+	for _, name := range names {
+		resp.Packages = append(resp.Packages, packages[name])
+	}
+	return resp, nil
+}
+
+// requiredPackages adds the packages used by the program in the given directory, and by the programs of its
+// components, to packages.
+func requiredPackages(dir string, packages map[string]*pulumirpc.PackageDependency, seen map[string]bool) error {
+	dir = filepath.Clean(dir)
+	if seen[dir] {
+		return nil
+	}
+	seen[dir] = true
+
+	parser := syntax.NewParser()
+	diags, err := pcl.ParseDirectory(parser, dir)
+	if err != nil {
+		return err
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	descriptors, diags := pcl.ReadAllPackageDescriptors(parser.Files)
+	if diags.HasErrors() {
+		return diags
+	}
+	for name, d := range descriptors {
+		dep := &pulumirpc.PackageDependency{Name: d.Name, Kind: "resource", Server: d.DownloadURL}
+		if d.Version != nil {
+			dep.Version = d.Version.String()
+		}
+		if p := d.Parameterization; p != nil {
+			dep.Parameterization = &pulumirpc.PackageParameterization{
+				Name:    p.Name,
+				Version: p.Version.String(),
+				Value:   p.Value,
+			}
+		}
+		packages[name] = dep
+	}
+
+	addPackage := func(token string) {
+		pkg, module, member, diags := pcl.DecomposeToken(token, hcl.Range{})
+		if diags.HasErrors() {
+			return
+		}
+		if pkg == "pulumi" && module == "providers" {
+			pkg = member
+		}
+		if _, ok := packages[pkg]; !ok && pkg != "pulumi" {
+			packages[pkg] = &pulumirpc.PackageDependency{Name: pkg, Kind: "resource"}
+		}
+	}
+
+	var components []string
+	for _, file := range parser.Files {
+		diags := hclsyntax.VisitAll(file.Body, func(n hclsyntax.Node) hcl.Diagnostics {
+			switch n := n.(type) {
+			case *hclsyntax.Block:
+				switch {
+				case n.Type == "resource" && len(n.Labels) == 2:
+					addPackage(n.Labels[1])
+				case n.Type == "component" && len(n.Labels) == 2:
+					components = append(components, n.Labels[1])
+				}
+			case *hclsyntax.FunctionCallExpr:
+				if n.Name == pcl.Invoke && len(n.Args) > 0 {
+					if token, ok := stringLiteral(n.Args[0]); ok {
+						addPackage(token)
+					}
+				}
+			}
+			return nil
+		})
+		if diags.HasErrors() {
+			return diags
+		}
+	}
+
+	for _, source := range components {
+		if !strings.HasPrefix(source, ".") {
+			continue
+		}
+		if err := requiredPackages(filepath.Join(dir, source), packages, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stringLiteral returns the value of a string literal expression.
+func stringLiteral(expr hclsyntax.Expression) (string, bool) {
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || !v.IsKnown() || v.IsNull() || !v.Type().Equals(cty.String) {
+		return "", false
+	}
+	return v.AsString(), true
+}
+
+// Run binds the program and evaluates it against the engine's resource monitor. Errors in the program are reported
+// through the response rather than failing the RPC.
+func (host *pclLanguageHost) Run(ctx context.Context, req *pulumirpc.RunRequest) (*pulumirpc.RunResponse, error) {
+	if req.LoaderTarget == "" {
+		return nil, errors.New("a schema loader is required to run PCL programs")
+	}
+	loader, err := schema.NewLoaderClient(req.LoaderTarget)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(loader)
+
+	dir := req.Pwd
+	if req.Info != nil && req.Info.ProgramDirectory != "" {
+		dir = req.Info.ProgramDirectory
+	}
+	program, diags, err := pcl.BindDirectory(dir, schema.NewCachedLoader(loader))
+	if err != nil {
+		return &pulumirpc.RunResponse{Error: err.Error()}, nil
+	}
+	if diags.HasErrors() {
+		return &pulumirpc.RunResponse{Error: diags.Error()}, nil
+	}
+
+	conn, err := grpc.NewClient(
+		req.MonitorAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		rpcutil.GrpcChannelOptions(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to resource monitor: %w", err)
+	}
+	defer contract.IgnoreClose(conn)
+
+	err = interpreter.Run(ctx, program, pulumirpc.NewResourceMonitorClient(conn), interpreter.Options{
+		Project:          req.Project,
+		Stack:            req.Stack,
+		Organization:     req.Organization,
+		RootDirectory:    dir,
+		Config:           req.Config,
+		ConfigSecretKeys: req.ConfigSecretKeys,
+		DryRun:           req.DryRun,
+	})
+	if err != nil {
+		return &pulumirpc.RunResponse{Error: err.Error()}, nil
+	}
+	return &pulumirpc.RunResponse{}, nil
+}
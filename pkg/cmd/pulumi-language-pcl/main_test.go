@@ -0,0 +1,67 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+func TestGetRequiredPackages(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "pets"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.pp"), []byte(`
+package aws {
+	baseProviderName = "aws"
+	baseProviderVersion = "6.0.0"
+}
+
+resource bucket "aws:s3:Bucket" {}
+
+resource provider "pulumi:providers:kubernetes" {}
+
+output result {
+	value = invoke("std:index:Abs", { a = 1, b = 2 }).result
+}
+
+component pets "./pets" {}
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pets", "main.pp"), []byte(`
+resource pet "random:index/randomPet:RandomPet" {}
+`), 0o600))
+
+	host := &pclLanguageHost{}
+	resp, err := host.GetRequiredPackages(context.Background(), &pulumirpc.GetRequiredPackagesRequest{
+		Info: &pulumirpc.ProgramInfo{ProgramDirectory: dir},
+	})
+	require.NoError(t, err)
+
+	names := make([]string, len(resp.Packages))
+	for i, pkg := range resp.Packages {
+		names[i] = pkg.Name
+		assert.Equal(t, "resource", pkg.Kind)
+	}
+	assert.Equal(t, []string{"aws", "kubernetes", "random", "std"}, names)
+	assert.Equal(t, "6.0.0", resp.Packages[0].Version)
+}
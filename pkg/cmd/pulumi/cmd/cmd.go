@@ -17,6 +17,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
@@ -44,6 +45,30 @@ func DisplayErrorMessage(err error) {
 	cmdutil.DisplayErrorMessage(err)
 }
 
+// ExitCodeError is an error that asks the CLI to exit with a particular exit code, rather than the generic one that it
+// exits with for any other error.
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code that the CLI should exit with after a command failed with the given error.
+func ExitCode(err error) int {
+	var exitErr *ExitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return -1
+}
+
 // Processes errors that may be returned from commands, providing a central
 // location to insert more human-friendly messages when certain errors occur, or
 // to perform other type-specific handling.
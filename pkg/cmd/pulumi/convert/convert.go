@@ -69,7 +69,12 @@ func NewConvertCmd() *cobra.Command {
 			"\n" +
 			"The source program to convert will default to the current working directory.\n" +
 			"\n" +
-			"Valid source languages: yaml, terraform, bicep, arm, kubernetes\n" +
+			"Valid source languages: yaml, terraform, bicep, arm, kubernetes, pcl, pulumi\n" +
+			"\n" +
+			"The pulumi source converts an existing Pulumi program, written in any language, by previewing it\n" +
+			"and generating a program that registers the same resources. Values that can't be traced back to\n" +
+			"another resource are written as literals. The pcl source does the same when the source directory\n" +
+			"doesn't contain any .pp files, so that programs generated from PCL can be converted back with it.\n" +
 			"\n" +
 			"Valid target languages: typescript, python, csharp, go, java, yaml" +
 			"\n" +
@@ -200,6 +205,8 @@ func runConvert(
 	switch strings.ToLower(from) {
 	case "tf", "terraform":
 		from = "terraform"
+	case "pulumi", "program":
+		from = "pulumi"
 	case "":
 		from = "yaml"
 	}
@@ -327,6 +334,18 @@ func runConvert(
 	}
 	defer os.RemoveAll(pclDirectory)
 
+	if from == "pcl" {
+		// `--from pcl` is also how programs that were generated from PCL are converted back to it, so a source
+		// directory without any PCL in it is captured as an existing program, just like `--from pulumi`.
+		isPCL, err := containsPCL(cwd)
+		if err != nil {
+			return err
+		}
+		if !isPCL {
+			from = "pulumi"
+		}
+	}
+
 	pCtx.Diag.Infof(diag.Message("", "Converting from %s..."), from)
 	if from == "pcl" {
 		// The source code is PCL, we don't need to do anything here, just repoint pclDirectory to it, but
@@ -336,6 +355,13 @@ func runConvert(
 			return fmt.Errorf("remove temporary directory: %w", err)
 		}
 		pclDirectory = cwd
+	} else if from == "pulumi" {
+		// The source is an existing Pulumi program, in any language. Run it to capture the resources it registers
+		// and generate PCL from those.
+		err = captureProgram(pCtx, cwd, pclDirectory, loader)
+		if err != nil {
+			return fmt.Errorf("capture program: %w", err)
+		}
 	} else {
 		converter, err := LoadConverterPlugin(pCtx, from, log)
 		if err != nil {
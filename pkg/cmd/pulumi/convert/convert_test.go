@@ -24,6 +24,9 @@ import (
 
 	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -155,3 +158,60 @@ func TestProjectNameOverrides(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, string(yamlBytes), "name: "+name)
 }
+
+func TestCaptureConfig(t *testing.T) {
+	t.Parallel()
+
+	proj := &workspace.Project{
+		Name: "proj",
+		Config: map[string]workspace.ProjectConfigType{
+			"name":       {Default: "pet"},
+			"count":      {Default: 3},
+			"aws:region": {Default: "us-west-2"},
+			"required":   {},
+		},
+	}
+
+	cfg, err := captureConfig(proj)
+	require.NoError(t, err)
+	assert.Equal(t, map[config.Key]string{
+		config.MustMakeKey("proj", "name"):  "pet",
+		config.MustMakeKey("proj", "count"): "3",
+		config.MustMakeKey("aws", "region"): "us-west-2",
+	}, cfg)
+}
+
+func TestPackageConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := map[config.Key]string{
+		config.MustMakeKey("proj", "name"):    "pet",
+		config.MustMakeKey("aws", "region"):   "us-west-2",
+		config.MustMakeKey("aws", "profile"):  "dev",
+		config.MustMakeKey("gcp", "project"):  "proj",
+		config.MustMakeKey("awsx", "enabled"): "true",
+	}
+
+	assert.Equal(t, resource.PropertyMap{
+		"region":  resource.NewStringProperty("us-west-2"),
+		"profile": resource.NewStringProperty("dev"),
+	}, packageConfig(cfg, "aws"))
+	assert.Empty(t, packageConfig(cfg, "random"))
+}
+
+func TestContainsPCL(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pulumi.yaml"), []byte("name: proj\nruntime: go\n"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested.pp"), 0o700))
+
+	isPCL, err := containsPCL(dir)
+	require.NoError(t, err)
+	assert.False(t, isPCL)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.pp"), []byte(""), 0o600))
+	isPCL, err = containsPCL(dir)
+	require.NoError(t, err)
+	assert.True(t, isPCL)
+}
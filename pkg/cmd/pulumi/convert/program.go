@@ -0,0 +1,281 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver"
+	"google.golang.org/grpc"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/importer"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// captureStack is the name of the stack that programs are run as while they are being captured.
+const captureStack = "dev"
+
+// captureProgram runs the Pulumi program in the given directory as a preview against a resource monitor that records
+// the resources it registers, and writes a PCL program that recreates them to pclDirectory. The program's dependencies
+// must already be installed. Invokes are forwarded to the providers that implement them, but no resources are created.
+func captureProgram(pCtx *plugin.Context, cwd, pclDirectory string, loader schema.ReferenceLoader) error {
+	path, err := workspace.DetectProjectPathFrom(cwd)
+	if err != nil {
+		return fmt.Errorf("find project: %w", err)
+	}
+	if path == "" {
+		return errors.New("no Pulumi.yaml project file found in the source directory")
+	}
+	proj, err := workspace.LoadProject(path)
+	if err != nil {
+		return fmt.Errorf("load project: %w", err)
+	}
+	root := filepath.Dir(path)
+	pwd, main, err := (&engine.Projinfo{Proj: proj, Root: root}).GetPwdMain()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := captureConfig(proj)
+	if err != nil {
+		return err
+	}
+
+	invoker := &captureInvoker{
+		host:      pCtx.Host,
+		project:   proj.Name,
+		config:    cfg,
+		providers: map[string]plugin.Provider{},
+	}
+	defer invoker.Close()
+
+	monitor := importer.NewCaptureMonitor(proj.Name, tokens.MustParseStackName(captureStack), invoker.Invoke)
+	grpcServer, err := plugin.NewServer(pCtx,
+		func(srv *grpc.Server) { pulumirpc.RegisterResourceMonitorServer(srv, monitor) },
+		schema.LoaderRegistration(schema.NewLoaderServer(loader)))
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(grpcServer)
+
+	runtime := proj.Runtime.Name()
+	programInfo := plugin.NewProgramInfo(root, pwd, main, proj.Runtime.Options())
+	languagePlugin, err := pCtx.Host.LanguageRuntime(runtime, programInfo)
+	if err != nil {
+		return fmt.Errorf("failed to launch language host %s: %w", runtime, err)
+	}
+
+	progerr, bail, err := languagePlugin.Run(plugin.RunInfo{
+		Info:           programInfo,
+		MonitorAddress: grpcServer.Addr(),
+		Project:        string(proj.Name),
+		Stack:          captureStack,
+		Pwd:            pwd,
+		Config:         cfg,
+		DryRun:         true,
+		LoaderAddress:  grpcServer.Addr(),
+	})
+	if err != nil {
+		return fmt.Errorf("run program: %w", err)
+	}
+	if bail {
+		return errors.New("the program exited with an error")
+	}
+	if progerr != "" {
+		return fmt.Errorf("an unhandled error occurred: %v", progerr)
+	}
+
+	f, err := os.Create(filepath.Join(pclDirectory, "main.pp"))
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(f)
+	return importer.GenerateCapturedProgram(f, loader, monitor)
+}
+
+// containsPCL returns true if the given directory contains any PCL files.
+func containsPCL(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, fmt.Errorf("read source directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".pp" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// captureConfig returns the configuration to run the project with. There is no stack to read configuration from, so
+// only the defaults declared by the project are set.
+func captureConfig(proj *workspace.Project) (map[config.Key]string, error) {
+	cfg := map[config.Key]string{}
+	for name, typ := range proj.Config {
+		if typ.Default == nil {
+			continue
+		}
+		if !strings.Contains(name, ":") {
+			name = string(proj.Name) + ":" + name
+		}
+		key, err := config.ParseKey(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config key %q: %w", name, err)
+		}
+
+		value, ok := typ.Default.(string)
+		if !ok {
+			bytes, err := json.Marshal(typ.Default)
+			if err != nil {
+				return nil, fmt.Errorf("config %v: %w", key, err)
+			}
+			value = string(bytes)
+		}
+		cfg[key] = value
+	}
+	return cfg, nil
+}
+
+// packageConfig returns the configuration for the given package, keyed by name without the package's namespace, in
+// the same way as the engine configures default providers.
+func packageConfig(cfg map[config.Key]string, pkg tokens.Package) resource.PropertyMap {
+	inputs := resource.PropertyMap{}
+	for k, v := range cfg {
+		if tokens.Package(k.Namespace()) == pkg {
+			inputs[resource.PropertyKey(k.Name())] = resource.NewStringProperty(v)
+		}
+	}
+	return inputs
+}
+
+// captureInvoker answers the invokes of a captured program by forwarding them to the default provider for their
+// package, configured from the program's configuration.
+type captureInvoker struct {
+	host    plugin.Host
+	project tokens.PackageName
+	config  map[config.Key]string
+
+	m         sync.Mutex
+	providers map[string]plugin.Provider
+}
+
+func (i *captureInvoker) provider(
+	ctx context.Context, req *pulumirpc.ResourceInvokeRequest,
+) (plugin.Provider, error) {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	pkg := tokens.ModuleMember(req.Tok).Package()
+	key := string(pkg) + "@" + req.Version
+	if p, ok := i.providers[key]; ok {
+		return p, nil
+	}
+
+	spec := workspace.PluginSpec{
+		Name:              string(pkg),
+		Kind:              apitype.ResourcePlugin,
+		PluginDownloadURL: req.PluginDownloadURL,
+	}
+	if req.Version != "" {
+		v, err := semver.ParseTolerant(req.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q for package %s: %w", req.Version, pkg, err)
+		}
+		spec.Version = &v
+	}
+	p, err := i.host.Provider(workspace.PackageDescriptor{PluginSpec: spec})
+	if err != nil {
+		return nil, fmt.Errorf("load provider for %s: %w", pkg, err)
+	}
+
+	urn := resource.NewURN(tokens.QName(captureStack), i.project, "", providers.MakeProviderType(pkg), "default")
+	check, err := p.CheckConfig(ctx, plugin.CheckConfigRequest{
+		URN:           urn,
+		News:          packageConfig(i.config, pkg),
+		AllowUnknowns: true,
+	})
+	if err != nil {
+		contract.IgnoreError(i.host.CloseProvider(p))
+		return nil, fmt.Errorf("check config for %s: %w", pkg, err)
+	}
+	if len(check.Failures) != 0 {
+		contract.IgnoreError(i.host.CloseProvider(p))
+		return nil, fmt.Errorf("invalid config for %s: %v: %s", pkg, check.Failures[0].Property, check.Failures[0].Reason)
+	}
+
+	name, typ := urn.Name(), urn.Type()
+	_, err = p.Configure(ctx, plugin.ConfigureRequest{URN: &urn, Name: &name, Type: &typ, Inputs: check.Properties})
+	if err != nil {
+		contract.IgnoreError(i.host.CloseProvider(p))
+		return nil, fmt.Errorf("configure provider for %s: %w", pkg, err)
+	}
+	i.providers[key] = p
+	return p, nil
+}
+
+func (i *captureInvoker) Invoke(
+	ctx context.Context, req *pulumirpc.ResourceInvokeRequest,
+) (*pulumirpc.InvokeResponse, error) {
+	p, err := i.provider(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true}
+	args, err := plugin.UnmarshalProperties(req.Args, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.Invoke(ctx, plugin.InvokeRequest{Tok: tokens.ModuleMember(req.Tok), Args: args})
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := plugin.MarshalProperties(resp.Properties, opts)
+	if err != nil {
+		return nil, err
+	}
+	failures := make([]*pulumirpc.CheckFailure, len(resp.Failures))
+	for j, f := range resp.Failures {
+		failures[j] = &pulumirpc.CheckFailure{Property: string(f.Property), Reason: f.Reason}
+	}
+	return &pulumirpc.InvokeResponse{Return: ret, Failures: failures}, nil
+}
+
+// Close shuts down the providers that have been loaded.
+func (i *captureInvoker) Close() {
+	i.m.Lock()
+	defer i.m.Unlock()
+	for _, p := range i.providers {
+		contract.IgnoreError(i.host.CloseProvider(p))
+	}
+}
@@ -61,7 +61,7 @@ func main() {
 
 	if err := NewPulumiCmd().Execute(); err != nil {
 		cmd.DisplayErrorMessage(err)
-		os.Exit(-1)
+		os.Exit(cmd.ExitCode(err))
 	}
 	*finished = true
 }
@@ -197,21 +197,35 @@ func runNew(ctx context.Context, args newArgs) error {
 			args.templateNameOrURL = conversationURL
 		}
 	}
+	// Templates offered by the backend take precedence over templates with the same name.
+	var remoteTemplates map[string]*apitype.PulumiTemplateRemote
+	if !args.offline {
+		if remoteTemplates, err = backendTemplates(ctx, b); err != nil {
+			return err
+		}
+	}
+
 	// Retrieve the template repo.
 	var repo workspace.TemplateRepository
-	repo, err = workspace.RetrieveTemplates(
-		ctx, args.templateNameOrURL, args.offline, workspace.TemplateKindPulumiProject)
-	if err != nil {
-		// Bail on all errors unless its a 401 from a Pulumi Cloud backend...
-		if !errors.Is(err, workspace.ErrPulumiCloudUnauthorized) {
+	if remote, has := remoteTemplates[args.templateNameOrURL]; has {
+		if repo, err = retrieveBackendTemplate(ctx, b, remote); err != nil {
 			return err
 		}
-
-		// ...If the request has 401'd AND we've identified the backend as being a Pulumi Cloud instance, we can
-		// attempt to retrieve the template using the user's Pulumi Cloud credentials.
-		repo, err = retrievePrivatePulumiCloudTemplate(args.templateNameOrURL)
+	} else {
+		repo, err = workspace.RetrieveTemplates(
+			ctx, args.templateNameOrURL, args.offline, workspace.TemplateKindPulumiProject)
 		if err != nil {
-			return fmt.Errorf("retrieving private pulumi cloud template: %w", err)
+			// Bail on all errors unless its a 401 from a Pulumi Cloud backend...
+			if !errors.Is(err, workspace.ErrPulumiCloudUnauthorized) {
+				return err
+			}
+
+			// ...If the request has 401'd AND we've identified the backend as being a Pulumi Cloud instance, we can
+			// attempt to retrieve the template using the user's Pulumi Cloud credentials.
+			repo, err = retrievePrivatePulumiCloudTemplate(args.templateNameOrURL)
+			if err != nil {
+				return fmt.Errorf("retrieving private pulumi cloud template: %w", err)
+			}
 		}
 	}
 
@@ -224,6 +238,9 @@ func runNew(ctx context.Context, args newArgs) error {
 	if err != nil {
 		return err
 	}
+	if args.templateNameOrURL == "" {
+		templates = withBackendTemplates(templates, remoteTemplates)
+	}
 
 	var template workspace.Template
 	if len(templates) == 0 {
@@ -235,6 +252,20 @@ func runNew(ctx context.Context, args newArgs) error {
 			return err
 		}
 	}
+
+	// Templates offered by the backend are only downloaded once they have been chosen.
+	if remote, has := remoteTemplates[template.Name]; has && template.Dir == "" {
+		backendRepo, err := retrieveBackendTemplate(ctx, b, remote)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			contract.IgnoreError(backendRepo.Delete())
+		}()
+		if template, err = workspace.LoadTemplate(backendRepo.SubDirectory); err != nil {
+			return fmt.Errorf("loading template %q: %w", remote.Name, err)
+		}
+	}
 	if template.Errored() {
 		return fmt.Errorf("template '%s' is currently broken: %w", template.Name, template.Error)
 	}
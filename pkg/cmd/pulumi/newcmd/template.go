@@ -15,18 +15,26 @@
 package newcmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"sort"
 
 	survey "github.com/AlecAivazis/survey/v2"
 	surveycore "github.com/AlecAivazis/survey/v2/core"
 
+	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/cmd"
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/ui"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/slice"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/archive"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 
 	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
@@ -114,3 +122,88 @@ func sanitizeTemplate(template string) string {
 	// Otherwise, return the original string.
 	return template
 }
+
+// backendTemplates returns the templates offered by the backend, keyed by name.
+//
+// Only DIY backends are consulted for now: listing Pulumi Cloud templates needs an organization,
+// which isn't known at this point.
+func backendTemplates(ctx context.Context, b backend.Backend) (map[string]*apitype.PulumiTemplateRemote, error) {
+	if _, isDIY := b.(diy.Backend); !isDIY || !b.SupportsTemplates() {
+		return nil, nil
+	}
+
+	// There is no --org flag at the moment. The backend determines the orgName value if it is "".
+	resp, err := b.ListTemplates(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("listing templates: %w", err)
+	}
+	templates := map[string]*apitype.PulumiTemplateRemote{}
+	for _, source := range resp.Templates {
+		for _, t := range source {
+			templates[t.Name] = t
+		}
+	}
+	return templates, nil
+}
+
+// retrieveBackendTemplate downloads a template offered by the backend into a temporary template repository.
+func retrieveBackendTemplate(
+	ctx context.Context, b backend.Backend, t *apitype.PulumiTemplateRemote,
+) (workspace.TemplateRepository, error) {
+	trc, err := b.DownloadTemplate(ctx, "", t.TemplateURL)
+	if err != nil {
+		return workspace.TemplateRepository{}, fmt.Errorf("downloading template %q: %w", t.Name, err)
+	}
+	defer contract.IgnoreClose(trc)
+
+	dir, err := os.MkdirTemp("", "pulumi-template-")
+	if err != nil {
+		return workspace.TemplateRepository{}, err
+	}
+	if err := archive.ExtractTar(trc.Tar(), dir); err != nil {
+		contract.IgnoreError(os.RemoveAll(dir))
+		return workspace.TemplateRepository{}, fmt.Errorf("extracting template %q: %w", t.Name, err)
+	}
+	return workspace.TemplateRepository{
+		Root:         dir,
+		SubDirectory: dir,
+		ShouldDelete: true,
+	}, nil
+}
+
+// withBackendTemplates adds the templates offered by the backend to the list of templates to choose from.
+// Backend templates haven't been downloaded yet, so they have no Dir. They take precedence over templates with the
+// same name from the template repository, and are listed after them in name order.
+func withBackendTemplates(
+	templates []workspace.Template, remotes map[string]*apitype.PulumiTemplateRemote,
+) []workspace.Template {
+	if len(remotes) == 0 {
+		return templates
+	}
+
+	result := slice.Prealloc[workspace.Template](len(templates) + len(remotes))
+	for _, t := range templates {
+		if _, has := remotes[t.Name]; !has {
+			result = append(result, t)
+		}
+	}
+	// Add the backend templates in name order, as the template repository lists its templates.
+	names := slice.Prealloc[string](len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r := remotes[name]
+		description := r.Description
+		if description == "" {
+			description = r.DisplayName
+		}
+		result = append(result, workspace.Template{
+			Name:        r.Name,
+			Description: description,
+			Quickstart:  r.Quickstart,
+		})
+	}
+	return result
+}
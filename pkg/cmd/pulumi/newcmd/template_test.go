@@ -18,6 +18,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
 func TestSanitizeTemplate(t *testing.T) {
@@ -42,3 +45,39 @@ func TestSanitizeTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestWithBackendTemplates(t *testing.T) {
+	t.Parallel()
+
+	templates := []workspace.Template{
+		{Name: "typescript", Dir: "/templates/typescript"},
+		{Name: "web-go", Dir: "/templates/web-go"},
+	}
+	remotes := map[string]*apitype.PulumiTemplateRemote{
+		"web-go": {
+			Name:            "web-go",
+			ProjectTemplate: apitype.ProjectTemplate{DisplayName: "Web service"},
+		},
+		"api-go": {
+			Name:            "api-go",
+			ProjectTemplate: apitype.ProjectTemplate{Description: "API service"},
+		},
+		"cli-go": {
+			Name:            "cli-go",
+			ProjectTemplate: apitype.ProjectTemplate{Description: "CLI tool"},
+		},
+	}
+
+	// Backend templates replace templates of the same name, aren't downloaded until chosen, and are listed in name
+	// order whatever order the map is ranged over in.
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, []workspace.Template{
+			{Name: "typescript", Dir: "/templates/typescript"},
+			{Name: "api-go", Description: "API service"},
+			{Name: "cli-go", Description: "CLI tool"},
+			{Name: "web-go", Description: "Web service"},
+		}, withBackendTemplates(templates, remotes))
+	}
+
+	assert.Equal(t, templates, withBackendTemplates(templates, nil))
+}
@@ -0,0 +1,306 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	cmdBackend "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/backend"
+	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/cmd"
+	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/config"
+	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/metadata"
+	cmdStack "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/stack"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// DriftExitCode is the exit code of `pulumi drift` when it finds that resources have drifted. It is distinct from the
+// exit code of a failure, so that scheduled jobs can tell drift apart from a failure to detect it.
+const DriftExitCode = 2
+
+func NewDriftCmd() *cobra.Command {
+	var debug bool
+	var stackName string
+	var execKind string
+	var execAgent string
+
+	var jsonDisplay bool
+	var diffDisplay bool
+	var eventLogPath string
+	var parallel int32
+	var showSames bool
+	var suppressProgress bool
+	var targets *[]string
+	var excludes *[]string
+
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Detect resources that have drifted from the stack's state",
+		Long: "Detect resources that have drifted from the stack's state.\n" +
+			"\n" +
+			"This command reads the current state of every resource in the stack from its provider and\n" +
+			"compares it with the state that Pulumi has recorded, reporting each resource that has been\n" +
+			"changed or deleted outside of Pulumi along with the properties that have drifted. Unlike\n" +
+			"`pulumi refresh`, it never changes the stack's state.\n" +
+			"\n" +
+			"The command exits with code 0 if no resources have drifted and with code 2 if any have.\n" +
+			"Any other non-zero exit code means that drift could not be detected. Pass `--json` to\n" +
+			"write a machine-readable report of the drift to stdout.\n" +
+			"\n" +
+			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
+			"`--cwd` flag to use a different directory.",
+		Args: cmdutil.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			ctx := cobraCmd.Context()
+			ssml := cmdStack.NewStackSecretsManagerLoaderFromEnv()
+			ws := pkgWorkspace.Instance
+
+			displayType := display.DisplayProgress
+			if diffDisplay {
+				displayType = display.DisplayDiff
+			}
+
+			opts := backend.UpdateOptions{PreviewOnly: true}
+			opts.Display = display.Options{
+				Color:             cmdutil.GetGlobalColorization(),
+				ShowSameResources: showSames,
+				SuppressOutputs:   true,
+				SuppressProgress:  suppressProgress,
+				SuppressPermalink: true,
+				IsInteractive:     cmdutil.Interactive(),
+				Type:              displayType,
+				EventLogPath:      eventLogPath,
+				Debug:             debug,
+			}
+
+			// The JSON report is the only thing written to stdout, so show the progress of the refresh on stderr.
+			stdout := io.Writer(os.Stdout)
+			if jsonDisplay {
+				opts.Display.Stdout = os.Stderr
+				opts.Display.IsInteractive = false
+			}
+
+			s, err := cmdStack.RequireStack(
+				ctx,
+				ws,
+				cmdBackend.DefaultLoginManager,
+				stackName,
+				cmdStack.LoadOnly,
+				opts.Display,
+			)
+			if err != nil {
+				return err
+			}
+
+			proj, root, err := ws.ReadProject()
+			if err != nil {
+				return err
+			}
+
+			cfg, sm, err := config.GetStackConfiguration(ctx, ssml, s, proj)
+			if err != nil {
+				return fmt.Errorf("getting stack configuration: %w", err)
+			}
+
+			m, err := metadata.GetUpdateMetadata("", root, execKind, execAgent, false, cfg, cobraCmd.Flags())
+			if err != nil {
+				return fmt.Errorf("gathering environment metadata: %w", err)
+			}
+
+			configErr := workspace.ValidateStackConfigAndApplyProjectConfig(
+				ctx,
+				s.Ref().Name().String(),
+				proj,
+				cfg.Environment,
+				cfg.Config,
+				sm.Encrypter(),
+				sm.Decrypter())
+			if configErr != nil {
+				return fmt.Errorf("validating stack config: %w", configErr)
+			}
+
+			opts.Engine = engine.UpdateOptions{
+				ParallelDiff:              env.ParallelDiff.Value(),
+				Parallel:                  parallel,
+				Debug:                     debug,
+				UseLegacyDiff:             env.EnableLegacyDiff.Value(),
+				UseLegacyRefreshDiff:      env.EnableLegacyRefreshDiff.Value(),
+				DisableProviderPreview:    env.DisableProviderPreview.Value(),
+				DisableResourceReferences: env.DisableResourceReferences.Value(),
+				DisableOutputValues:       env.DisableOutputValues.Value(),
+				Targets:                   deploy.NewUrnTargets(*targets),
+				Excludes:                  deploy.NewUrnTargets(*excludes),
+				Experimental:              env.Experimental.Value(),
+				ExecKind:                  execKind,
+			}
+
+			report, err := detectDrift(ctx, s, backend.UpdateOperation{
+				Proj:               proj,
+				Root:               root,
+				M:                  m,
+				Opts:               opts,
+				StackConfiguration: cfg,
+				SecretsManager:     sm,
+				SecretsProvider:    stack.DefaultSecretsProvider,
+				Scopes:             backend.CancellationScopes,
+			})
+			switch {
+			case errors.Is(err, context.Canceled):
+				return errors.New("drift detection cancelled")
+			case err != nil:
+				return err
+			}
+
+			if jsonDisplay {
+				encoder := json.NewEncoder(stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				printDriftReport(stdout, report, opts.Display.Color)
+			}
+
+			if report.HasDrift() {
+				return &cmd.ExitCodeError{Code: DriftExitCode, Err: result.BailErrorf("drift detected")}
+			}
+			return nil
+		},
+	}
+
+	driftCmd.PersistentFlags().BoolVarP(
+		&debug, "debug", "d", false,
+		"Print detailed debugging output during resource operations")
+	driftCmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	driftCmd.PersistentFlags().StringVar(
+		&cmdStack.ConfigFile, "config-file", "",
+		"Use the configuration values in the specified file rather than detecting the file name")
+
+	targets = driftCmd.PersistentFlags().StringArrayP(
+		"target", "t", []string{},
+		"Specify a single resource URN to check for drift. Multiple resources can be specified using: "+
+			"--target urn1 --target urn2")
+	excludes = driftCmd.PersistentFlags().StringArrayP(
+		"exclude", "x", []string{},
+		"Specify a resource URN to ignore. These resources will not be checked for drift."+
+			" Multiple resources can be specified using --exclude urn1 --exclude urn2."+
+			" Wildcards (*, **) are also supported")
+	driftCmd.MarkFlagsMutuallyExclusive("target", "exclude")
+
+	driftCmd.PersistentFlags().BoolVar(
+		&diffDisplay, "diff", false,
+		"Display the refresh as a rich diff showing the overall change")
+	driftCmd.Flags().BoolVarP(
+		&jsonDisplay, "json", "j", false,
+		"Write a machine-readable drift report to stdout as JSON")
+	driftCmd.PersistentFlags().Int32VarP(
+		&parallel, "parallel", "p", defaultParallel(),
+		"Allow P resource operations to run in parallel at once (1 for no parallelism).")
+	driftCmd.PersistentFlags().BoolVar(
+		&showSames, "show-sames", false,
+		"Show resources that haven't drifted, alongside those that have")
+	driftCmd.PersistentFlags().BoolVar(
+		&suppressProgress, "suppress-progress", false,
+		"Suppress display of periodic progress dots")
+
+	if env.DebugCommands.Value() {
+		driftCmd.PersistentFlags().StringVar(
+			&eventLogPath, "event-log", "",
+			"Log events to a file at this path")
+	}
+
+	// internal flags
+	driftCmd.PersistentFlags().StringVar(&execKind, "exec-kind", "", "")
+	// ignore err, only happens if flag does not exist
+	_ = driftCmd.PersistentFlags().MarkHidden("exec-kind")
+	driftCmd.PersistentFlags().StringVar(&execAgent, "exec-agent", "", "")
+	// ignore err, only happens if flag does not exist
+	_ = driftCmd.PersistentFlags().MarkHidden("exec-agent")
+
+	return driftCmd
+}
+
+// detectDrift previews a refresh of the given stack and builds a drift report from its events.
+func detectDrift(ctx context.Context, s backend.Stack, op backend.UpdateOperation) (apitype.DriftReport, error) {
+	events := make(chan engine.Event)
+	done := make(chan []engine.Event)
+	go func() {
+		var collected []engine.Event
+		for e := range events {
+			collected = append(collected, e)
+		}
+		done <- collected
+	}()
+
+	_, err := backend.DetectStackDrift(ctx, s, op, events)
+	close(events)
+	collected := <-done
+	if err != nil {
+		return apitype.DriftReport{}, err
+	}
+	return engine.NewDriftReport(collected), nil
+}
+
+// printDriftReport writes a human-readable summary of a drift report.
+func printDriftReport(w io.Writer, report apitype.DriftReport, color colors.Colorization) {
+	if !report.HasDrift() {
+		fmt.Fprintln(w, color.Colorize(colors.SpecHeadline+"No drift detected"+colors.Reset))
+		return
+	}
+
+	noun := "resources have"
+	if len(report.Resources) == 1 {
+		noun = "resource has"
+	}
+	fmt.Fprintln(w, color.Colorize(fmt.Sprintf("%sDrift detected:%s %d %s drifted",
+		colors.SpecHeadline, colors.Reset, len(report.Resources), noun)))
+	for _, r := range report.Resources {
+		if r.Kind == apitype.DriftDeleted {
+			fmt.Fprintln(w, color.Colorize(fmt.Sprintf("    %s- %s%s (deleted)", colors.SpecDelete, r.URN, colors.Reset)))
+			continue
+		}
+
+		fmt.Fprintln(w, color.Colorize(fmt.Sprintf("    %s~ %s%s", colors.SpecUpdate, r.URN, colors.Reset)))
+		for _, p := range r.Properties {
+			prefix, spec := "~", colors.SpecUpdate
+			switch p.Kind {
+			case apitype.DiffAdd:
+				prefix, spec = "+", colors.SpecCreate
+			case apitype.DiffDelete:
+				prefix, spec = "-", colors.SpecDelete
+			}
+			fmt.Fprintln(w, color.Colorize(fmt.Sprintf("        %s%s %s%s", spec, prefix, p.Path, colors.Reset)))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+)
+
+func TestPrintDriftReport(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printDriftReport(&buf, apitype.DriftReport{}, colors.Never)
+	assert.Equal(t, "No drift detected\n", buf.String())
+
+	buf.Reset()
+	printDriftReport(&buf, apitype.DriftReport{Resources: []apitype.ResourceDrift{
+		{
+			URN:  "urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs",
+			Type: "aws:s3/bucket:Bucket",
+			Kind: apitype.DriftDeleted,
+		},
+		{
+			URN:  "urn:pulumi:dev::proj::aws:s3/bucket:Bucket::site",
+			Type: "aws:s3/bucket:Bucket",
+			Kind: apitype.DriftModified,
+			Properties: []apitype.PropertyDrift{
+				{Path: "tags.env", Kind: apitype.DiffUpdate},
+				{Path: "tags.owner", Kind: apitype.DiffAdd},
+				{Path: "versioning", Kind: apitype.DiffDelete},
+			},
+		},
+	}}, colors.Never)
+	assert.Equal(t, "Drift detected: 2 resources have drifted\n"+
+		"    - urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs (deleted)\n"+
+		"    ~ urn:pulumi:dev::proj::aws:s3/bucket:Bucket::site\n"+
+		"        ~ tags.env\n"+
+		"        + tags.owner\n"+
+		"        - versioning\n", buf.String())
+}
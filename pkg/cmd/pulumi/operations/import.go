@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/blang/semver"
@@ -160,6 +161,7 @@ type importSpec struct {
 	Properties        []string    `json:"properties,omitempty"`
 	Component         bool        `json:"component,omitempty"`
 	Remote            bool        `json:"remote,omitempty"`
+	Discover          bool        `json:"discover,omitempty"`
 
 	// LogicalName is the resources Pulumi name (i.e. the first argument to `new Resource`).
 	LogicalName string `json:"logicalName,omitempty"`
@@ -401,6 +403,7 @@ func parseImportFile(
 			PluginDownloadURL: spec.PluginDownloadURL,
 			Component:         spec.Component,
 			Remote:            spec.Remote,
+			Discover:          spec.Discover,
 		}
 
 		if spec.Parent != "" {
@@ -464,6 +467,49 @@ func getCurrentDeploymentForStack(
 	return snap, err
 }
 
+// discoveredImports returns imports for the resources that discovery added to a stack: those in the snapshot after the
+// import that weren't in it before and weren't imported explicitly, other than providers and the stack itself. Each is
+// given a unique name in the name table so that the generated code can refer to it as a parent or dependency.
+func discoveredImports(before, after *deploy.Snapshot, names importer.NameTable) []deploy.Import {
+	existing := map[resource.URN]struct{}{}
+	if before != nil {
+		for _, r := range before.Resources {
+			existing[r.URN] = struct{}{}
+		}
+	}
+	taken := map[string]struct{}{}
+	for _, name := range names {
+		taken[name] = struct{}{}
+	}
+
+	var imports []deploy.Import
+	for _, r := range after.Resources {
+		if _, ok := existing[r.URN]; ok {
+			continue
+		}
+		if _, ok := names[r.URN]; ok || r.Delete || providers.IsProviderType(r.Type) || r.Type == resource.RootStackType {
+			continue
+		}
+
+		name := r.URN.Name()
+		for n := 2; ; n++ {
+			if _, ok := taken[name]; !ok {
+				break
+			}
+			name = fmt.Sprintf("%s-%d", r.URN.Name(), n)
+		}
+		taken[name] = struct{}{}
+		names[r.URN] = name
+
+		imp := deploy.Import{Type: r.Type, Name: r.URN.Name(), ID: r.ID}
+		if r.Parent != "" && r.Parent.QualifiedType() != resource.RootStackType {
+			imp.Parent = r.Parent
+		}
+		imports = append(imports, imp)
+	}
+	return imports
+}
+
 type programGeneratorFunc func(
 	p *pcl.Program,
 	loader schema.ReferenceLoader,
@@ -548,6 +594,7 @@ func NewImportCmd() *cobra.Command {
 	var importFilePath string
 	var outputFilePath string
 	var generateCode bool
+	var discover bool
 
 	var debug bool
 	var message string
@@ -626,6 +673,7 @@ func NewImportCmd() *cobra.Command {
 			"                \"properties\": [\"optional-property-names\"],\n" +
 			"                \"component\": false,\n" +
 			"                \"remote\": false,\n" +
+			"                \"discover\": false,\n" +
 			"            },\n" +
 			"            ...\n" +
 			"            {\n" +
@@ -658,6 +706,12 @@ func NewImportCmd() *cobra.Command {
 			"If a resource does not specify any properties the default behaviour is to\n" +
 			"import using all required properties.\n" +
 			"\n" +
+			"A resource may also set \"discover\" (or the `--discover` flag may be passed) to import\n" +
+			"the resources that its provider reports as related to it, such as the subnets and\n" +
+			"route tables of a network, along with it. Discovered resources are imported with the\n" +
+			"parents and dependencies that the provider reports, and are included in the generated\n" +
+			"code. Providers that don't support discovery don't report any related resources.\n" +
+			"\n" +
 			"You can use `pulumi preview` with the `--import-file` option to emit an import file\n" +
 			"for all resources that need creating from the preview. This will fill in all the name,\n" +
 			"type, parent and provider information for you and just require you to fill in resource\n" +
@@ -788,6 +842,12 @@ func NewImportCmd() *cobra.Command {
 				importFile = f
 			}
 
+			if discover {
+				for i := range importFile.Resources {
+					importFile.Resources[i].Discover = !importFile.Resources[i].Component
+				}
+			}
+
 			if !generateCode && outputFilePath != "" {
 				fmt.Fprintln(os.Stderr, "Output file will not be used as --generate-code is false.")
 			}
@@ -963,6 +1023,16 @@ func NewImportCmd() *cobra.Command {
 				Experimental:         env.Experimental.Value(),
 			}
 
+			// Discovered resources aren't in the list of imports, so to generate code for them we need to know which
+			// resources were in the stack before the import.
+			var before *deploy.Snapshot
+			if generateCode && slices.ContainsFunc(imports, func(imp deploy.Import) bool { return imp.Discover }) {
+				before, err = getCurrentDeploymentForStack(ctx, s)
+				if err != nil {
+					return err
+				}
+			}
+
 			_, err = s.Import(ctx, backend.UpdateOperation{
 				Proj:               proj,
 				Root:               root,
@@ -979,6 +1049,9 @@ func NewImportCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
+				if before != nil {
+					imports = append(imports, discoveredImports(before, deployment, nameTable)...)
+				}
 
 				validImports, err := generateImportedDefinitions(
 					pCtx, output, s.Ref().Name(), proj.Name, deployment, programGenerator, nameTable, imports,
@@ -1048,6 +1121,9 @@ func NewImportCmd() *cobra.Command {
 		&outputFilePath, "out", "o", "", "The path to the file that will contain the generated resource declarations")
 	cmd.PersistentFlags().BoolVar(
 		&generateCode, "generate-code", true, "Generate resource declaration code for the imported resources")
+	cmd.PersistentFlags().BoolVar(
+		&discover, "discover", false,
+		"Also import the resources that the provider reports as related to each resource being imported")
 
 	cmd.PersistentFlags().BoolVarP(
 		&debug, "debug", "d", false,
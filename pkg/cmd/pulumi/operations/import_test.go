@@ -492,3 +492,44 @@ func TestImportFileMarshal(t *testing.T) {
 		assert.NotContains(t, buffer.String(), "resources")
 	})
 }
+
+func TestDiscoveredImports(t *testing.T) {
+	t.Parallel()
+
+	stackURN := resource.URN("urn:pulumi:stack::proj::pulumi:pulumi:Stack::proj-stack")
+	existing := resource.URN("urn:pulumi:stack::proj::foo:bar:a::existing")
+	vpc := resource.URN("urn:pulumi:stack::proj::foo:bar:a::vpc")
+	subnet := resource.URN("urn:pulumi:stack::proj::foo:bar:a$foo:bar:a::subnet")
+	nic := resource.URN("urn:pulumi:stack::proj::foo:bar:a$foo:bar:a$foo:bar:a::subnet")
+	sg := resource.URN("urn:pulumi:stack::proj::foo:bar:a::sg")
+
+	before := &deploy.Snapshot{Resources: []*resource.State{
+		{URN: stackURN, Type: "pulumi:pulumi:Stack"},
+		{URN: existing, Type: "foo:bar:a", ID: "existing", Parent: stackURN},
+	}}
+	after := &deploy.Snapshot{Resources: []*resource.State{
+		{URN: stackURN, Type: "pulumi:pulumi:Stack"},
+		{URN: existing, Type: "foo:bar:a", ID: "existing", Parent: stackURN},
+		{URN: "urn:pulumi:stack::proj::pulumi:providers:foo::default", Type: "pulumi:providers:foo"},
+		{URN: vpc, Type: "foo:bar:a", ID: "vpc-1", Parent: stackURN},
+		{URN: subnet, Type: "foo:bar:a", ID: "subnet-1", Parent: vpc},
+		{URN: nic, Type: "foo:bar:a", ID: "nic-1", Parent: subnet},
+		{URN: sg, Type: "foo:bar:a", ID: "sg-1", Parent: stackURN, Dependencies: []resource.URN{vpc}},
+	}}
+	// The explicitly imported resource is already in the name table, under a name that a discovered resource would
+	// otherwise take.
+	names := importer.NameTable{vpc: "sg"}
+
+	imports := discoveredImports(before, after, names)
+	assert.Equal(t, []deploy.Import{
+		{Type: "foo:bar:a", Name: "subnet", ID: "subnet-1", Parent: vpc},
+		{Type: "foo:bar:a", Name: "subnet", ID: "nic-1", Parent: subnet},
+		{Type: "foo:bar:a", Name: "sg", ID: "sg-1"},
+	}, imports)
+	assert.Equal(t, importer.NameTable{
+		vpc:    "sg",
+		subnet: "subnet",
+		nic:    "subnet-2",
+		sg:     "sg-2",
+	}, names)
+}
@@ -16,11 +16,15 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -42,6 +46,7 @@ import (
 	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -97,6 +102,13 @@ func NewUpCmd() *cobra.Command {
 	var eventLogPath string
 	var parallel int32
 	var refresh string
+	var resume bool
+	var rollbackOnFailure bool
+	var waveSpecs []string
+	var autoApproveWaves bool
+	var waveApprovalDir string
+	var waves []deploy.DeploymentWave
+	var waveApprover deploy.WaveApprover
 	var showConfig bool
 	var showPolicyRemediations bool
 	var showReplacementSteps bool
@@ -203,6 +215,10 @@ func NewUpCmd() *cobra.Command {
 			Parallel:                  parallel,
 			Debug:                     debug,
 			Refresh:                   refreshOption,
+			Resume:                    resume,
+			RollbackOnFailure:         rollbackOnFailure,
+			Waves:                     waves,
+			WaveApprover:              waveApprover,
 			ReplaceTargets:            deploy.NewUrnTargets(replaceURNs),
 			UseLegacyDiff:             env.EnableLegacyDiff.Value(),
 			UseLegacyRefreshDiff:      env.EnableLegacyRefreshDiff.Value(),
@@ -435,15 +451,19 @@ func NewUpCmd() *cobra.Command {
 			return err
 		}
 		opts.Engine = engine.UpdateOptions{
-			ParallelDiff:     env.ParallelDiff.Value(),
-			LocalPolicyPacks: engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
-			Parallel:         parallel,
-			Debug:            debug,
-			Refresh:          refreshOption,
-			ShowSecrets:      showSecrets,
-			// If we're in experimental mode then we trigger a plan to be generated during the preview phase
-			// which will be constrained to during the update phase.
-			GeneratePlan: env.Experimental.Value(),
+			ParallelDiff:      env.ParallelDiff.Value(),
+			LocalPolicyPacks:  engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
+			Parallel:          parallel,
+			Debug:             debug,
+			Refresh:           refreshOption,
+			Resume:            resume,
+			RollbackOnFailure: rollbackOnFailure,
+			Waves:             waves,
+			WaveApprover:      waveApprover,
+			ShowSecrets:       showSecrets,
+			// If we're in experimental mode or deploying in waves then we trigger a plan to be generated during the
+			// preview phase which will be constrained to during the update phase.
+			GeneratePlan: env.Experimental.Value() || len(waves) > 0,
 			Experimental: env.Experimental.Value(),
 
 			UseLegacyRefreshDiff: env.EnableLegacyRefreshDiff.Value(),
@@ -547,6 +567,24 @@ func NewUpCmd() *cobra.Command {
 				ShowSecrets:            showSecrets,
 			}
 
+			waves, err = parseDeploymentWaves(waveSpecs)
+			if err != nil {
+				return err
+			}
+			if len(waves) > 0 {
+				if err := checkWaveApproval(skipPreview, yes, autoApproveWaves, waveApprovalDir); err != nil {
+					return err
+				}
+				switch {
+				case waveApprovalDir != "":
+					waveApprover = fileWaveApprover(waveApprovalDir)
+				case !autoApproveWaves:
+					// Each wave is approved at a prompt, which the interactive display would draw over.
+					opts.Display.IsInteractive = false
+					waveApprover = promptWaveApprover(opts.Display)
+				}
+			}
+
 			// we only suppress permalinks if the user passes true. the default is an empty string
 			// which we pass as 'false'
 			if suppressPermalink == "true" {
@@ -693,6 +731,29 @@ func NewUpCmd() *cobra.Command {
 		&refresh, "refresh", "r", "",
 		"Refresh the state of the stack's resources before this update")
 	cmd.PersistentFlags().Lookup("refresh").NoOptDefVal = "true"
+	cmd.PersistentFlags().BoolVar(
+		&resume, "resume", false,
+		"Resume an interrupted update: read the resources of its pending operations from their providers "+
+			"to reconcile them, then carry on with this update")
+	cmd.PersistentFlags().StringArrayVar(
+		&waveSpecs, "wave", []string{},
+		"Deploy the selected resources in a wave of their own, after the resources of earlier waves and before the "+
+			"resources that no wave selects. Takes a comma-separated list of type=<type>, tag=<key>=<value>, "+
+			"wave=<name> (resources with the deploymentWave option <name>) or resource URN selectors, and may be "+
+			"repeated to make more waves. Each wave must be approved at a prompt before it is deployed. The waves are "+
+			"worked out from the preview of the update, and the update is constrained to the preview's plan")
+	cmd.PersistentFlags().BoolVar(
+		&autoApproveWaves, "auto-approve-waves", false,
+		"Deploy every wave of --wave without asking for approval. Required to use --wave with --yes")
+	cmd.PersistentFlags().StringVar(
+		&waveApprovalDir, "wave-approval-dir", "",
+		"Exchange the approvals of the waves of --wave through files in this directory instead of asking at a prompt")
+	// Used by the Automation API to approve waves with a callback.
+	_ = cmd.PersistentFlags().MarkHidden("wave-approval-dir")
+	cmd.PersistentFlags().BoolVar(
+		&rollbackOnFailure, "rollback-on-failure", false,
+		"If the update fails, undo the changes it made to the stack's resources, and report the changes "+
+			"that could not be undone")
 	cmd.PersistentFlags().BoolVar(
 		&showConfig, "show-config", false,
 		"Show configuration keys and variables")
@@ -802,3 +863,126 @@ func autonamingStackContext(proj *workspace.Project, s backend.Stack) autonaming
 		Stack:        stack,
 	}
 }
+
+// parseDeploymentWaves parses the values of the --wave flag. Each value is a comma-separated list of selectors, each
+// of which is type=<type>, tag=<key>=<value>, wave=<name>, or a resource URN that may contain wildcards.
+func parseDeploymentWaves(specs []string) ([]deploy.DeploymentWave, error) {
+	waves := make([]deploy.DeploymentWave, 0, len(specs))
+	for _, spec := range specs {
+		var wave deploy.DeploymentWave
+		var urns []string
+		for _, selector := range strings.Split(spec, ",") {
+			selector = strings.TrimSpace(selector)
+			switch {
+			case selector == "":
+				continue
+			case strings.HasPrefix(selector, "type="):
+				wave.Types = append(wave.Types, tokens.Type(strings.TrimPrefix(selector, "type=")))
+			case strings.HasPrefix(selector, "tag="):
+				key, value, ok := strings.Cut(strings.TrimPrefix(selector, "tag="), "=")
+				if !ok || key == "" {
+					return nil, fmt.Errorf("invalid wave selector %q: tags must be selected with tag=<key>=<value>", selector)
+				}
+				if wave.Tags == nil {
+					wave.Tags = map[string]string{}
+				}
+				wave.Tags[key] = value
+			case strings.HasPrefix(selector, "wave="):
+				name := strings.TrimPrefix(selector, "wave=")
+				if name == "" {
+					return nil, fmt.Errorf("invalid wave selector %q: waves must be selected with wave=<name>", selector)
+				}
+				wave.Names = append(wave.Names, name)
+			default:
+				urns = append(urns, selector)
+			}
+		}
+		if len(wave.Types) == 0 && len(wave.Tags) == 0 && len(wave.Names) == 0 && len(urns) == 0 {
+			return nil, fmt.Errorf("invalid wave %q: a wave must select at least one resource", spec)
+		}
+		if len(urns) > 0 {
+			wave.Targets = deploy.NewUrnTargets(urns)
+		}
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+// checkWaveApproval returns an error if the waves of an update can't be planned or approved. Waves are worked out from
+// the plan made by the update's preview, so the preview can't be skipped. Skipping confirmation of the update doesn't
+// approve its waves, as the Automation API always does that, and approving the waves is the point of deploying in
+// waves, so without a prompt the waves must be approved with --auto-approve-waves or through --wave-approval-dir.
+func checkWaveApproval(skipPreview, yes, autoApprove bool, approvalDir string) error {
+	switch {
+	case skipPreview:
+		return errors.New("--wave can't be used with --skip-preview, as waves are worked out from the plan made by " +
+			"the preview of the update")
+	case autoApprove && approvalDir != "":
+		return errors.New("--auto-approve-waves and --wave-approval-dir can't be used together")
+	case yes && !autoApprove && approvalDir == "":
+		return errors.New("--wave needs each wave to be approved at a prompt, which --yes and " +
+			"PULUMI_SKIP_CONFIRMATIONS skip; pass --auto-approve-waves to deploy every wave without approval")
+	}
+	return nil
+}
+
+// promptWaveApprover returns a WaveApprover that asks the user to approve each wave of an update.
+func promptWaveApprover(opts display.Options) deploy.WaveApprover {
+	return deploy.WaveApproverFunc(func(_ context.Context, wave int, resources []resource.URN) (bool, error) {
+		var prompt strings.Builder
+		fmt.Fprintf(&prompt, "Wave %d is ready to deploy:\n", wave+1)
+		for _, urn := range resources {
+			fmt.Fprintf(&prompt, "    %s\n", urn)
+		}
+		prompt.WriteString("Do you want to carry on with this wave?")
+		return ui.ConfirmPrompt(prompt.String(), "yes", opts), nil
+	})
+}
+
+// waveApprovalPollInterval is how often fileWaveApprover looks for the response to an approval request.
+const waveApprovalPollInterval = 250 * time.Millisecond
+
+// fileWaveApprover returns a WaveApprover that writes a request to approve each wave to the given directory, and waits
+// for the response to be written there too.
+func fileWaveApprover(dir string) deploy.WaveApprover {
+	return deploy.WaveApproverFunc(func(ctx context.Context, wave int, resources []resource.URN) (bool, error) {
+		req := apitype.WaveApprovalRequest{Wave: wave + 1, Resources: make([]string, len(resources))}
+		for i, urn := range resources {
+			req.Resources[i] = string(urn)
+		}
+		byts, err := json.Marshal(req)
+		if err != nil {
+			return false, err
+		}
+		// Write the request under a temporary name first, so that it's never read before it's complete.
+		reqPath := filepath.Join(dir, apitype.WaveApprovalRequestFile(req.Wave))
+		if err := os.WriteFile(reqPath+".tmp", byts, 0o600); err != nil {
+			return false, fmt.Errorf("writing approval request for wave %d: %w", req.Wave, err)
+		}
+		if err := os.Rename(reqPath+".tmp", reqPath); err != nil {
+			return false, fmt.Errorf("writing approval request for wave %d: %w", req.Wave, err)
+		}
+
+		respPath := filepath.Join(dir, apitype.WaveApprovalResponseFile(req.Wave))
+		ticker := time.NewTicker(waveApprovalPollInterval)
+		defer ticker.Stop()
+		for {
+			byts, err := os.ReadFile(respPath)
+			if err == nil {
+				var resp apitype.WaveApprovalResponse
+				if err := json.Unmarshal(byts, &resp); err != nil {
+					return false, fmt.Errorf("reading approval of wave %d: %w", req.Wave, err)
+				}
+				return resp.Approved, nil
+			}
+			if !os.IsNotExist(err) {
+				return false, fmt.Errorf("reading approval of wave %d: %w", req.Wave, err)
+			}
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	})
+}
@@ -15,10 +15,20 @@
 package operations
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 )
 
 func TestValidatePolicyPackConfig(t *testing.T) {
@@ -89,3 +99,63 @@ func TestValidatePolicyPackConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDeploymentWaves(t *testing.T) {
+	t.Parallel()
+
+	waves, err := parseDeploymentWaves([]string{
+		"type=aws:rds/instance:Instance, tag=stage=canary",
+		"urn:pulumi:dev::proj::aws:s3/bucket:Bucket::*,wave=storage",
+	})
+	require.NoError(t, err)
+	require.Len(t, waves, 2)
+	assert.Equal(t, []tokens.Type{"aws:rds/instance:Instance"}, waves[0].Types)
+	assert.Equal(t, map[string]string{"stage": "canary"}, waves[0].Tags)
+	assert.False(t, waves[0].Targets.IsConstrained())
+	assert.True(t, waves[1].Targets.Contains("urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs"))
+	assert.Equal(t, []string{"storage"}, waves[1].Names)
+
+	_, err = parseDeploymentWaves([]string{"tag=stage"})
+	assert.ErrorContains(t, err, "tag=<key>=<value>")
+	_, err = parseDeploymentWaves([]string{"wave="})
+	assert.ErrorContains(t, err, "wave=<name>")
+	_, err = parseDeploymentWaves([]string{" , "})
+	assert.ErrorContains(t, err, "must select at least one resource")
+}
+
+func TestCheckWaveApproval(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkWaveApproval(false, false, false, ""))
+	require.NoError(t, checkWaveApproval(false, true /* yes */, true /* autoApprove */, ""))
+	require.NoError(t, checkWaveApproval(false, true /* yes */, false, t.TempDir()))
+	assert.ErrorContains(t, checkWaveApproval(false, true /* yes */, false, ""), "pass --auto-approve-waves")
+	assert.ErrorContains(t, checkWaveApproval(true /* skipPreview */, true, true, ""), "--skip-preview")
+	assert.ErrorContains(t, checkWaveApproval(false, true, true /* autoApprove */, t.TempDir()), "used together")
+}
+
+func TestFileWaveApprover(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	urn := resource.URN("urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs")
+	go func() {
+		// Answer the request once it's written.
+		for {
+			byts, err := os.ReadFile(filepath.Join(dir, apitype.WaveApprovalRequestFile(2)))
+			if err == nil {
+				var req apitype.WaveApprovalRequest
+				if json.Unmarshal(byts, &req) == nil && assert.Equal(t, []string{string(urn)}, req.Resources) {
+					resp, _ := json.Marshal(apitype.WaveApprovalResponse{Approved: true})
+					_ = os.WriteFile(filepath.Join(dir, apitype.WaveApprovalResponseFile(2)), resp, 0o600)
+				}
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	approved, err := fileWaveApprover(dir).ApproveWave(context.Background(), 1, []resource.URN{urn})
+	require.NoError(t, err)
+	assert.True(t, approved)
+}
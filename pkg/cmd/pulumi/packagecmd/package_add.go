@@ -21,10 +21,12 @@ import (
 	"path/filepath"
 
 	"github.com/hashicorp/hcl/v2"
+	cmdBackend "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/backend"
 	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -123,8 +125,14 @@ from the parameters, as in:
 When <schema> is a path to a local file with a '.json', '.yml' or '.yaml'
 extension, Pulumi package schema is read from it directly:
 
-  pulumi package add ./my/schema.json`,
+  pulumi package add ./my/schema.json
+
+When the current backend has a package registry, packages published to it
+are resolved by [SOURCE/]PUBLISHER/NAME[@VERSION] before resource plugins:
+
+  pulumi package add my-org/my-component@1.2.0`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			ws := pkgWorkspace.Instance
 			proj, root, err := ws.ReadProject()
 			if err != nil {
@@ -149,6 +157,25 @@ extension, Pulumi package schema is read from it directly:
 			plugin := args[0]
 			parameters := args[1:]
 
+			// Packages published to the current backend's package registry are resolved before plugins.
+			if ref, ok := parseRegistryReference(plugin, parameters); ok {
+				b, err := cmdBackend.NonInteractiveCurrentBackend(ctx, ws, cmdBackend.DefaultLoginManager, proj)
+				if err != nil {
+					logging.V(5).Infof("not resolving %s from a package registry: %v", plugin, err)
+				} else {
+					schemaPath, err := resolveRegistryPackage(ctx, b, ref)
+					if err != nil {
+						return err
+					}
+					if schemaPath != "" {
+						defer func() {
+							contract.IgnoreError(os.RemoveAll(filepath.Dir(schemaPath)))
+						}()
+						plugin = schemaPath
+					}
+				}
+			}
+
 			return InstallPackage(ws, pctx, language, root, plugin, parameters)
 		},
 	}
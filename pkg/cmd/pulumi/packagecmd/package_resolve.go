@@ -0,0 +1,138 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packagecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// registryReference is a [SOURCE/]PUBLISHER/NAME[@VERSION] reference to a package in a package registry.
+type registryReference struct {
+	source    string
+	publisher string
+	name      string
+	version   *semver.Version
+}
+
+func (r registryReference) String() string {
+	s := r.publisher + "/" + r.name
+	if r.source != "" {
+		s = r.source + "/" + s
+	}
+	if r.version != nil {
+		s += "@" + r.version.String()
+	}
+	return s
+}
+
+// parseRegistryReference parses the package source given to `pulumi package add` as a reference to a package in a
+// package registry. It returns false if the source doesn't look like one: because it has parameters, because it's a
+// local path or a URL, or because it doesn't name a publisher. Bare names are left to plugin resolution, so that
+// adding a provider such as `aws` doesn't need to look up the current backend.
+func parseRegistryReference(packageSource string, parameters []string) (registryReference, bool) {
+	if len(parameters) > 0 || strings.Contains(packageSource, "://") {
+		return registryReference{}, false
+	}
+	if ext := filepath.Ext(packageSource); ext == ".json" || ext == ".yaml" || ext == ".yml" {
+		return registryReference{}, false
+	}
+	if _, err := os.Stat(packageSource); err == nil {
+		return registryReference{}, false
+	}
+
+	var ref registryReference
+	name, versionStr, hasVersion := strings.Cut(packageSource, "@")
+	if hasVersion {
+		v, err := semver.ParseTolerant(versionStr)
+		if err != nil {
+			return registryReference{}, false
+		}
+		ref.version = &v
+	}
+	switch parts := strings.Split(name, "/"); len(parts) {
+	case 2:
+		ref.publisher, ref.name = parts[0], parts[1]
+	case 3:
+		// Sources that look like hostnames are git repositories, such as github.com/org/repo.
+		if strings.Contains(parts[0], ".") {
+			return registryReference{}, false
+		}
+		ref.source, ref.publisher, ref.name = parts[0], parts[1], parts[2]
+	default:
+		return registryReference{}, false
+	}
+	if ref.publisher == "" || ref.name == "" {
+		return registryReference{}, false
+	}
+	return ref, true
+}
+
+// resolveRegistryPackage looks up a package in the package registry of the given backend, and writes the schema of
+// the package to a file in a new temporary directory. It returns the path of the schema file, or an empty path if the
+// registry doesn't have the package.
+func resolveRegistryPackage(ctx context.Context, b backend.Backend, ref registryReference) (string, error) {
+	if b == nil {
+		return "", nil
+	}
+	registry, err := b.GetPackageRegistry()
+	if err != nil {
+		return "", nil
+	}
+	resolver, ok := registry.(backend.PackageResolver)
+	if !ok {
+		return "", nil
+	}
+
+	pkg, err := resolver.GetPackage(ctx, ref.source, ref.publisher, ref.name, ref.version)
+	if err != nil {
+		if errors.Is(err, backend.ErrPackageNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("resolving package %s: %w", ref, err)
+	}
+	schema, err := resolver.GetPackageSchema(ctx, pkg)
+	if err != nil {
+		return "", fmt.Errorf("downloading schema of %s/%s/%s@%s: %w",
+			pkg.Source, pkg.Publisher, pkg.Name, pkg.Version, err)
+	}
+	defer contract.IgnoreClose(schema)
+
+	dir, err := os.MkdirTemp("", "pulumi-package-schema-")
+	if err != nil {
+		return "", err
+	}
+	schemaPath := filepath.Join(dir, "schema.json")
+	f, err := os.Create(schemaPath)
+	if err == nil {
+		_, err = io.Copy(f, schema)
+		err = errors.Join(err, f.Close())
+	}
+	if err != nil {
+		contract.IgnoreError(os.RemoveAll(dir))
+		return "", fmt.Errorf("writing schema: %w", err)
+	}
+	return schemaPath, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packagecmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+func TestResolveRegistryPackage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, err := diy.New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()), nil)
+	require.NoError(t, err)
+	registry, err := b.GetPackageRegistry()
+	require.NoError(t, err)
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		require.NoError(t, registry.Publish(ctx, apitype.PackagePublishOp{
+			Source:    "pulumi",
+			Publisher: "acme",
+			Name:      "component",
+			Version:   semver.MustParse(version),
+			Schema:    strings.NewReader(`{"name": "component", "version": "` + version + `"}`),
+		}))
+	}
+
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{source: "acme/component", expected: "1.1.0"},
+		{source: "acme/component@1.0.0", expected: "1.0.0"},
+		{source: "pulumi/acme/component@v1.1.0", expected: "1.1.0"},
+		// Unknown packages are left to plugin resolution.
+		{source: "acme/aws"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.source, func(t *testing.T) {
+			t.Parallel()
+
+			ref, ok := parseRegistryReference(tt.source, nil)
+			require.True(t, ok)
+			schemaPath, err := resolveRegistryPackage(ctx, b, ref)
+			require.NoError(t, err)
+			if tt.expected == "" {
+				assert.Empty(t, schemaPath)
+				return
+			}
+			defer os.RemoveAll(filepath.Dir(schemaPath))
+			byts, err := os.ReadFile(schemaPath)
+			require.NoError(t, err)
+			assert.Contains(t, string(byts), `"version": "`+tt.expected+`"`)
+		})
+	}
+
+	// Backends without a resolvable registry never resolve anything.
+	schemaPath, err := resolveRegistryPackage(ctx, &backend.MockBackend{
+		GetPackageRegistryF: func() (backend.PackageRegistry, error) {
+			return &backend.MockPackageRegistry{}, nil
+		},
+	}, registryReference{publisher: "acme", name: "component"})
+	require.NoError(t, err)
+	assert.Empty(t, schemaPath)
+}
+
+func TestParseRegistryReference(t *testing.T) {
+	t.Parallel()
+
+	ref, ok := parseRegistryReference("pulumi/acme/component@v1.2.0", nil)
+	require.True(t, ok)
+	assert.Equal(t, "pulumi/acme/component@1.2.0", ref.String())
+
+	ref, ok = parseRegistryReference("acme/component", nil)
+	require.True(t, ok)
+	assert.Equal(t, registryReference{publisher: "acme", name: "component"}, ref)
+
+	// None of these need the package registry, so the current backend isn't looked up for them.
+	for _, source := range []string{
+		"aws",
+		"aws@6.0.0",
+		"./schema.json",
+		"github.com/acme/component",
+		"https://github.com/acme/component",
+		"acme/component@not-a-version",
+		"a/b/c/d",
+	} {
+		_, ok := parseRegistryReference(source, nil)
+		assert.False(t, ok, source)
+	}
+	_, ok = parseRegistryReference("acme/component", []string{"--flag"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,94 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	cmdBackend "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/backend"
+	cmdStack "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/stack"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func NewPlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Inspect update plans saved by `pulumi preview --save-plan`",
+		Long: "Inspect update plans saved by `pulumi preview --save-plan`.\n" +
+			"\n" +
+			"Subcommands of this command work with a saved plan without running the program that\n" +
+			"produced it, so that a plan made by one job can be reviewed by another before it is applied\n" +
+			"with `pulumi up --plan`.",
+		Args: cmdutil.NoArgs,
+	}
+
+	cmd.AddCommand(newPlanShowCmd(pkgWorkspace.Instance, cmdBackend.DefaultLoginManager))
+	cmd.AddCommand(newPlanDiffCmd(pkgWorkspace.Instance, cmdBackend.DefaultLoginManager))
+	cmd.AddCommand(newPlanValidateCmd(pkgWorkspace.Instance, cmdBackend.DefaultLoginManager))
+	return cmd
+}
+
+// planStack is the stack that saved plans are read against: plans are encrypted with the stack's secrets manager and
+// only record the changes that they make to the stack's current state.
+type planStack struct {
+	stack     backend.Stack
+	project   *workspace.Project
+	decrypter config.Decrypter
+	snapshot  *deploy.Snapshot
+}
+
+func requirePlanStack(ctx context.Context, ws pkgWorkspace.Context, lm cmdBackend.LoginManager,
+	stackName string, opts display.Options,
+) (*planStack, error) {
+	s, err := cmdStack.RequireStack(ctx, ws, lm, stackName, cmdStack.LoadOnly, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	project, _, err := ws.ReadProject()
+	if err != nil {
+		return nil, fmt.Errorf("loading project: %w", err)
+	}
+	ps, err := cmdStack.LoadProjectStack(project, s)
+	if err != nil {
+		return nil, fmt.Errorf("getting stack config: %w", err)
+	}
+	ssml := cmdStack.NewStackSecretsManagerLoaderFromEnv()
+	dec, state, err := ssml.GetDecrypter(ctx, s, ps)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting secrets: %w", err)
+	}
+	if state != cmdStack.SecretsManagerUnchanged {
+		if err = cmdStack.SaveProjectStack(s, ps); err != nil {
+			return nil, fmt.Errorf("saving stack config: %w", err)
+		}
+	}
+
+	snap, err := s.Snapshot(ctx, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, fmt.Errorf("loading stack state: %w", err)
+	}
+	return &planStack{stack: s, project: project, decrypter: dec, snapshot: snap}, nil
+}
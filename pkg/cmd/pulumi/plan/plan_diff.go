@@ -0,0 +1,139 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	cmdBackend "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/backend"
+	sdkDisplay "github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newPlanDiffCmd(ws pkgWorkspace.Context, lm cmdBackend.LoginManager) *cobra.Command {
+	var stackName string
+	var showSames bool
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "diff <plan-file-a> <plan-file-b>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Compare two saved update plans",
+		Long: "Compare two saved update plans.\n" +
+			"\n" +
+			"This command loads two plans saved by `pulumi preview --save-plan` for the same stack and\n" +
+			"displays how the goals of the second plan differ from those of the first: resources that\n" +
+			"only the second plan registers are shown as creates, those that only the first registers as\n" +
+			"deletes, and those whose planned inputs or options differ as updates. Resources that the\n" +
+			"plans expect different operations for are listed after the diff.\n" +
+			"\n" +
+			"This can be used to check that a plan that is about to be applied with `pulumi up --plan` is\n" +
+			"the plan that was reviewed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts := display.Options{
+				Color:             cmdutil.GetGlobalColorization(),
+				ShowSameResources: showSames,
+				ShowSecrets:       showSecrets,
+			}
+			ps, err := requirePlanStack(ctx, ws, lm, stackName, opts)
+			if err != nil {
+				return err
+			}
+
+			a, err := Read(args[0], ps.decrypter)
+			if err != nil {
+				return fmt.Errorf("reading plan %q: %w", args[0], err)
+			}
+			b, err := Read(args[1], ps.decrypter)
+			if err != nil {
+				return fmt.Errorf("reading plan %q: %w", args[1], err)
+			}
+
+			renderPlanEvents(os.Stdout, engine.NewPlanDiffEvents(a, b, ps.snapshot, showSecrets), opts)
+			printPlanOpsChanges(os.Stdout, changedPlanOps(a, b), opts.Color)
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack the plans were made for. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVar(
+		&showSames, "show-sames", false,
+		"Show resources whose goals are the same in both plans, alongside those that differ")
+	cmd.PersistentFlags().BoolVar(
+		&showSecrets, "show-secrets", false,
+		"Show secret values in the plans' property diffs")
+
+	return cmd
+}
+
+// planOpsChange records a resource that two plans expect different operations for.
+type planOpsChange struct {
+	URN resource.URN
+	Old []sdkDisplay.StepOp
+	New []sdkDisplay.StepOp
+}
+
+// changedPlanOps returns the resources that both plans have plans for but with different operations, in URN order.
+func changedPlanOps(a, b *deploy.Plan) []planOpsChange {
+	var changes []planOpsChange
+	for urn, old := range a.ResourcePlans {
+		if new, ok := b.ResourcePlans[urn]; ok && !slices.Equal(old.Ops, new.Ops) {
+			changes = append(changes, planOpsChange{URN: urn, Old: old.Ops, New: new.Ops})
+		}
+	}
+	slices.SortFunc(changes, func(x, y planOpsChange) int {
+		return strings.Compare(string(x.URN), string(y.URN))
+	})
+	return changes
+}
+
+// printPlanOpsChanges writes the resources that two plans expect different operations for.
+func printPlanOpsChanges(w io.Writer, changes []planOpsChange, color colors.Colorization) {
+	if len(changes) == 0 {
+		return
+	}
+
+	opsString := func(ops []sdkDisplay.StepOp) string {
+		if len(ops) == 0 {
+			return string(deploy.OpSame)
+		}
+		names := make([]string, len(ops))
+		for i, op := range ops {
+			names[i] = string(op)
+		}
+		return strings.Join(names, ", ")
+	}
+
+	fmt.Fprintln(w, color.Colorize(fmt.Sprintf("\n%sPlanned operations changed:%s", colors.SpecHeadline, colors.Reset)))
+	for _, c := range changes {
+		fmt.Fprintln(w, color.Colorize(fmt.Sprintf("    %s~ %s%s: %s => %s",
+			colors.SpecUpdate, c.URN, colors.Reset, opsString(c.Old), opsString(c.New))))
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sdkDisplay "github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestChangedPlanOps(t *testing.T) {
+	t.Parallel()
+
+	bucket := resource.NewURN("dev", "proj", "", "aws:s3/bucket:Bucket", "site")
+	topic := resource.NewURN("dev", "proj", "", "aws:sns/topic:Topic", "alerts")
+	queue := resource.NewURN("dev", "proj", "", "aws:sqs/queue:Queue", "jobs")
+	replace := []sdkDisplay.StepOp{deploy.OpCreateReplacement, deploy.OpReplace, deploy.OpDeleteReplaced}
+
+	a := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		bucket: {Ops: []sdkDisplay.StepOp{deploy.OpUpdate}},
+		topic:  {Ops: []sdkDisplay.StepOp{deploy.OpCreate}},
+		queue:  {},
+	}}
+	b := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		bucket: {Ops: replace},
+		topic:  {Ops: []sdkDisplay.StepOp{deploy.OpCreate}},
+		queue:  {Ops: []sdkDisplay.StepOp{deploy.OpUpdate}},
+	}}
+
+	changes := changedPlanOps(a, b)
+	assert.Equal(t, []planOpsChange{
+		{URN: bucket, Old: []sdkDisplay.StepOp{deploy.OpUpdate}, New: replace},
+		{URN: queue, New: []sdkDisplay.StepOp{deploy.OpUpdate}},
+	}, changes)
+
+	var buf bytes.Buffer
+	printPlanOpsChanges(&buf, changes, colors.Never)
+	assert.Equal(t, "\nPlanned operations changed:\n"+
+		"    ~ urn:pulumi:dev::proj::aws:s3/bucket:Bucket::site: update => create-replacement, replace, delete-replaced\n"+
+		"    ~ urn:pulumi:dev::proj::aws:sqs/queue:Queue::jobs: same => update\n", buf.String())
+
+	buf.Reset()
+	printPlanOpsChanges(&buf, changedPlanOps(a, a), colors.Never)
+	assert.Empty(t, buf.String())
+}
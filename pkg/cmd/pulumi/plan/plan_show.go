@@ -0,0 +1,89 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	cmdBackend "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/backend"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newPlanShowCmd(ws pkgWorkspace.Context, lm cmdBackend.LoginManager) *cobra.Command {
+	var stackName string
+	var showSames bool
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "show <plan-file>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Show the changes that a saved update plan makes",
+		Long: "Show the changes that a saved update plan makes.\n" +
+			"\n" +
+			"This command loads a plan saved by `pulumi preview --save-plan` and displays the resources\n" +
+			"that it creates, updates, replaces and deletes, along with the changes it makes to their\n" +
+			"inputs, in the same way that `pulumi preview --diff` does. The changes are shown against the\n" +
+			"stack's current state, so the plan should be shown for the stack that it was made for.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts := display.Options{
+				Color:             cmdutil.GetGlobalColorization(),
+				ShowSameResources: showSames,
+				ShowSecrets:       showSecrets,
+			}
+			ps, err := requirePlanStack(ctx, ws, lm, stackName, opts)
+			if err != nil {
+				return err
+			}
+
+			p, err := Read(args[0], ps.decrypter)
+			if err != nil {
+				return fmt.Errorf("reading plan: %w", err)
+			}
+
+			renderPlanEvents(os.Stdout, engine.NewPlanEvents(p, ps.snapshot, showSecrets), opts)
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack the plan was made for. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVar(
+		&showSames, "show-sames", false,
+		"Show resources that the plan doesn't change, alongside those it does")
+	cmd.PersistentFlags().BoolVar(
+		&showSecrets, "show-secrets", false,
+		"Show secret values in the plan's property diffs")
+
+	return cmd
+}
+
+// renderPlanEvents writes the events made from a saved plan using the diff display of a preview.
+func renderPlanEvents(w io.Writer, events []engine.Event, opts display.Options) {
+	opts.SummaryDiff = true
+	seen := make(map[resource.URN]engine.StepEventMetadata)
+	for _, e := range events {
+		fmt.Fprint(w, display.RenderDiffEvent(e, seen, opts))
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	sdkDisplay "github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestRenderPlanEvents(t *testing.T) {
+	t.Parallel()
+
+	bucket := resource.NewURN("dev", "proj", "", "aws:s3/bucket:Bucket", "site")
+	topic := resource.NewURN("dev", "proj", "", "aws:sns/topic:Topic", "alerts")
+	snap := &deploy.Snapshot{Resources: []*resource.State{
+		{URN: bucket, Type: "aws:s3/bucket:Bucket", Custom: true, Inputs: resource.PropertyMap{
+			"acl": resource.NewStringProperty("private"),
+		}},
+	}}
+	p := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		bucket: {
+			Goal: &deploy.GoalPlan{
+				Type:   "aws:s3/bucket:Bucket",
+				Name:   "site",
+				Custom: true,
+				InputDiff: deploy.PlanDiff{
+					Updates: resource.PropertyMap{"acl": resource.NewStringProperty("public-read")},
+				},
+			},
+			Ops: []sdkDisplay.StepOp{deploy.OpUpdate},
+		},
+		topic: {
+			Goal: &deploy.GoalPlan{
+				Type:      "aws:sns/topic:Topic",
+				Name:      "alerts",
+				Custom:    true,
+				InputDiff: deploy.PlanDiff{Adds: resource.PropertyMap{"name": resource.NewStringProperty("alerts")}},
+			},
+			Ops: []sdkDisplay.StepOp{deploy.OpCreate},
+		},
+	}}
+
+	var buf bytes.Buffer
+	renderPlanEvents(&buf, engine.NewPlanEvents(p, snap, false), display.Options{Color: colors.Never})
+	assert.Equal(t, "~ aws:s3/bucket:Bucket: (update)\n"+
+		"    [urn=urn:pulumi:dev::proj::aws:s3/bucket:Bucket::site]\n"+
+		"  ~ acl: \"private\" => \"public-read\"\n"+
+		"+ aws:sns/topic:Topic: (create)\n"+
+		"    [urn=urn:pulumi:dev::proj::aws:sns/topic:Topic::alerts]\n"+
+		"    name: \"alerts\"\n"+
+		"Resources:\n"+
+		"    + 1 to create\n"+
+		"    ~ 1 to update\n"+
+		"    2 changes\n", buf.String())
+}
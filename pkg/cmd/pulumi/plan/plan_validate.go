@@ -0,0 +1,195 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	cmdBackend "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/backend"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+// planViolation is the JSON representation of a policy violation found in a saved plan.
+type planViolation struct {
+	URN               string                   `json:"urn"`
+	PolicyPack        string                   `json:"policyPack"`
+	PolicyPackVersion string                   `json:"policyPackVersion,omitempty"`
+	PolicyName        string                   `json:"policyName"`
+	Description       string                   `json:"description,omitempty"`
+	Message           string                   `json:"message"`
+	EnforcementLevel  apitype.EnforcementLevel `json:"enforcementLevel"`
+}
+
+func newPlanValidateCmd(ws pkgWorkspace.Context, lm cmdBackend.LoginManager) *cobra.Command {
+	var stackName string
+	var jsonDisplay bool
+	var policyPackPaths []string
+	var policyPackConfigPaths []string
+
+	cmd := &cobra.Command{
+		Use:   "validate <plan-file>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Run policy packs against a saved update plan",
+		Long: "Run policy packs against a saved update plan.\n" +
+			"\n" +
+			"This command loads a plan saved by `pulumi preview --save-plan` and runs the given local\n" +
+			"policy packs against the goal state that the plan records for each resource, without\n" +
+			"running the program again. The planned inputs of each resource are worked out from the\n" +
+			"plan and the stack's current state, so the plan should be validated against the stack\n" +
+			"that it was made for.\n" +
+			"\n" +
+			"Only resource policies are run, as a plan doesn't record the outputs that stack policies\n" +
+			"validate. The command fails if any mandatory policy is violated.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if len(policyPackPaths) == 0 {
+				return errors.New(`at least one "--policy-pack" must be specified`)
+			}
+			if len(policyPackConfigPaths) > 0 && len(policyPackConfigPaths) != len(policyPackPaths) {
+				return errors.New(
+					`the number of "--policy-pack-config" flags must match the number of "--policy-pack" flags`)
+			}
+
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			ps, err := requirePlanStack(ctx, ws, lm, stackName, opts)
+			if err != nil {
+				return err
+			}
+
+			p, err := Read(args[0], ps.decrypter)
+			if err != nil {
+				return fmt.Errorf("reading plan: %w", err)
+			}
+			cfg, err := p.Config.Decrypt(ps.decrypter)
+			if err != nil {
+				return fmt.Errorf("decrypting plan config: %w", err)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			sink := cmdutil.Diag()
+			pctx, err := plugin.NewContext(sink, sink, nil, nil, cwd, nil, true, nil)
+			if err != nil {
+				return err
+			}
+			defer contract.IgnoreClose(pctx)
+
+			organization := "organization"
+			if orgNamer, ok := ps.stack.(interface{ OrgName() string }); ok {
+				organization = orgNamer.OrgName()
+			}
+			diagnostics, err := engine.AnalyzePlan(pctx, p, ps.snapshot,
+				engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
+				&plugin.PolicyAnalyzerOptions{
+					Organization: organization,
+					Project:      ps.project.Name.String(),
+					Stack:        ps.stack.Ref().Name().String(),
+					Config:       cfg,
+					DryRun:       true,
+				})
+			if err != nil {
+				return err
+			}
+
+			violations := make([]planViolation, len(diagnostics))
+			for i, d := range diagnostics {
+				violations[i] = planViolation{
+					URN:               string(d.URN),
+					PolicyPack:        d.PolicyPackName,
+					PolicyPackVersion: d.PolicyPackVersion,
+					PolicyName:        d.PolicyName,
+					Description:       d.Description,
+					Message:           d.Message,
+					EnforcementLevel:  d.EnforcementLevel,
+				}
+			}
+
+			if jsonDisplay {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(violations); err != nil {
+					return err
+				}
+			} else {
+				printPlanViolations(os.Stdout, violations, opts.Color)
+			}
+
+			for _, v := range violations {
+				if v.EnforcementLevel == apitype.Mandatory {
+					return result.BailErrorf("the plan violates mandatory policies")
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack the plan was made for. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonDisplay, "json", "j", false,
+		"Emit the policy violations as JSON")
+	cmd.PersistentFlags().StringSliceVar(
+		&policyPackPaths, "policy-pack", []string{},
+		"Run one or more policy packs against the plan")
+	cmd.PersistentFlags().StringSliceVar(
+		&policyPackConfigPaths, "policy-pack-config", []string{},
+		`Path to JSON file containing the config for the policy pack of the corresponding "--policy-pack" flag`)
+
+	return cmd
+}
+
+// printPlanViolations writes a human-readable list of the policy violations found in a plan.
+func printPlanViolations(w io.Writer, violations []planViolation, color colors.Colorization) {
+	if len(violations) == 0 {
+		fmt.Fprintln(w, color.Colorize(colors.SpecHeadline+"No policy violations"+colors.Reset))
+		return
+	}
+
+	fmt.Fprintln(w, color.Colorize(colors.SpecHeadline+"Policy violations:"+colors.Reset))
+	for _, v := range violations {
+		spec := colors.SpecWarning
+		if v.EnforcementLevel == apitype.Mandatory {
+			spec = colors.SpecError
+		}
+		pack := v.PolicyPack
+		if v.PolicyPackVersion != "" {
+			pack += "@v" + v.PolicyPackVersion
+		}
+		fmt.Fprintln(w, color.Colorize(fmt.Sprintf("    %s[%s]%s  %s  %s  (%s)",
+			spec, v.EnforcementLevel, colors.Reset, pack, v.PolicyName, v.URN)))
+		if v.Message != "" {
+			fmt.Fprintf(w, "        %s\n", v.Message)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+)
+
+func TestPrintPlanViolations(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printPlanViolations(&buf, nil, colors.Never)
+	assert.Equal(t, "No policy violations\n", buf.String())
+
+	buf.Reset()
+	printPlanViolations(&buf, []planViolation{
+		{
+			URN:               "urn:pulumi:dev::proj::aws:s3/bucket:Bucket::site",
+			PolicyPack:        "security",
+			PolicyPackVersion: "1.2.0",
+			PolicyName:        "s3-no-public-read",
+			Message:           "Buckets must not be publicly readable.",
+			EnforcementLevel:  apitype.Mandatory,
+		},
+		{
+			URN:              "urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs",
+			PolicyPack:       "tagging",
+			PolicyName:       "required-tags",
+			EnforcementLevel: apitype.Advisory,
+		},
+	}, colors.Never)
+	assert.Equal(t, "Policy violations:\n"+
+		"    [mandatory]  security@v1.2.0  s3-no-public-read  (urn:pulumi:dev::proj::aws:s3/bucket:Bucket::site)\n"+
+		"        Buckets must not be publicly readable.\n"+
+		"    [advisory]  tagging  required-tags  (urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs)\n", buf.String())
+}
@@ -63,6 +63,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/operations"
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/org"
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/packagecmd"
+	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/plan"
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/plugin"
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/policy"
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/schema"
@@ -345,6 +346,7 @@ func NewPulumiCmd() *cobra.Command {
 				console.NewConsoleCmd(),
 				operations.NewImportCmd(),
 				operations.NewRefreshCmd(),
+				operations.NewDriftCmd(),
 				state.NewStateCmd(),
 				install.NewInstallCmd(),
 			},
@@ -355,6 +357,7 @@ func NewPulumiCmd() *cobra.Command {
 				operations.NewUpCmd(),
 				operations.NewDestroyCmd(),
 				operations.NewPreviewCmd(),
+				plan.NewPlanCmd(),
 				cancel.NewCancelCmd(),
 			},
 		},
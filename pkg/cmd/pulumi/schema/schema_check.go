@@ -28,11 +28,15 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema/lint"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
 
 func newSchemaCheckCommand() *cobra.Command {
+	var runLint bool
+	var lintConfigFile string
+
 	cmd := &cobra.Command{
 		Use:   "check",
 		Args:  cmdutil.ExactArgs(1),
@@ -41,35 +45,43 @@ func newSchemaCheckCommand() *cobra.Command {
 			"\n" +
 			"Ensure that a Pulumi package schema meets the requirements imposed by the\n" +
 			"schema spec as well as additional requirements imposed by the supported\n" +
-			"target languages.",
+			"target languages.\n" +
+			"\n" +
+			"With --lint, the schema is also checked against rules for documentation,\n" +
+			"naming, secrets and unused types. Rules can be disabled, have their severity\n" +
+			"changed or be suppressed for individual tokens with a YAML or JSON file passed\n" +
+			"to --lint-config, for example:\n" +
+			"\n" +
+			"    rules:\n" +
+			"      missing-description:\n" +
+			"        ignore: [\"aws:s3/*\"]\n" +
+			"      unmarked-secret:\n" +
+			"        severity: error\n" +
+			"      property-casing:\n" +
+			"        disabled: true",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			file := args[0]
+			pkgSpec, err := readPackageSpec(args[0])
+			if err != nil {
+				return err
+			}
 
-			// Read from stdin or a specified file
-			reader := os.Stdin
-			if file != "-" {
-				f, err := os.Open(file)
+			var lintConfig lint.Config
+			if lintConfigFile != "" {
+				lintConfig, err = readLintConfig(lintConfigFile)
 				if err != nil {
-					return fmt.Errorf("could not open file %v: %w", file, err)
+					return err
 				}
-				reader = f
-			}
-			schemaBytes, err := io.ReadAll(reader)
-			if err != nil {
-				return fmt.Errorf("failed to read schema: %w", err)
+				runLint = true
 			}
 
-			var pkgSpec schema.PackageSpec
-			if ext := filepath.Ext(file); ext == ".yaml" || ext == ".yml" {
-				err = yaml.Unmarshal(schemaBytes, &pkgSpec)
-			} else {
-				err = json.Unmarshal(schemaBytes, &pkgSpec)
-			}
-			if err != nil {
-				return fmt.Errorf("failed to unmarshal schema: %w", err)
+			pkg, diags, err := schema.BindSpec(pkgSpec, nil)
+			if err == nil && !diags.HasErrors() && runLint {
+				findings, err := lint.Lint(pkg, lint.DefaultRules, lintConfig)
+				if err != nil {
+					return err
+				}
+				diags = diags.Extend(lint.Diagnostics(findings))
 			}
-
-			_, diags, err := schema.BindSpec(pkgSpec, nil)
 			diagWriter := hcl.NewDiagnosticTextWriter(os.Stderr, nil, 0, true)
 			wrErr := diagWriter.WriteDiagnostics(diags)
 			contract.IgnoreError(wrErr)
@@ -80,5 +92,54 @@ func newSchemaCheckCommand() *cobra.Command {
 		},
 	}
 
+	cmd.PersistentFlags().BoolVar(
+		&runLint, "lint", false, "Check the schema against lint rules as well as for errors")
+	cmd.PersistentFlags().StringVar(
+		&lintConfigFile, "lint-config", "", "A YAML or JSON file that configures the lint rules (implies --lint)")
+
 	return cmd
 }
+
+// readLintConfig reads the configuration for lint rules from the given file.
+func readLintConfig(file string) (lint.Config, error) {
+	configBytes, err := os.ReadFile(file)
+	if err != nil {
+		return lint.Config{}, fmt.Errorf("could not read lint config %v: %w", file, err)
+	}
+	// YAML is a superset of JSON, so both are read the same way.
+	var config lint.Config
+	if err := yaml.Unmarshal(configBytes, &config); err != nil {
+		return lint.Config{}, fmt.Errorf("failed to unmarshal lint config: %w", err)
+	}
+	return config, nil
+}
+
+// readPackageSpec reads a package schema from the given file, or from stdin if the file is "-". Files with a .yaml or
+// .yml extension are read as YAML, and all others as JSON.
+func readPackageSpec(file string) (schema.PackageSpec, error) {
+	// Read from stdin or a specified file
+	reader := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return schema.PackageSpec{}, fmt.Errorf("could not open file %v: %w", file, err)
+		}
+		defer contract.IgnoreClose(f)
+		reader = f
+	}
+	schemaBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return schema.PackageSpec{}, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	var pkgSpec schema.PackageSpec
+	if ext := filepath.Ext(file); ext == ".yaml" || ext == ".yml" {
+		err = yaml.Unmarshal(schemaBytes, &pkgSpec)
+	} else {
+		err = json.Unmarshal(schemaBytes, &pkgSpec)
+	}
+	if err != nil {
+		return schema.PackageSpec{}, fmt.Errorf("failed to unmarshal schema: %w", err)
+	}
+	return pkgSpec, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema/lint"
+)
+
+func TestReadLintConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "lint.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte(`
+rules:
+  missing-description:
+    ignore: ["pets:index:*"]
+  unmarked-secret:
+    severity: error
+  property-casing:
+    disabled: true
+`), 0o600))
+	jsonFile := filepath.Join(dir, "lint.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{
+  "rules": {
+    "missing-description": {"ignore": ["pets:index:*"]},
+    "unmarked-secret": {"severity": "error"},
+    "property-casing": {"disabled": true}
+  }
+}`), 0o600))
+
+	expected := lint.Config{Rules: map[string]lint.RuleConfig{
+		"missing-description": {Ignore: []string{"pets:index:*"}},
+		"unmarked-secret":     {Severity: lint.Error},
+		"property-casing":     {Disabled: true},
+	}}
+	for _, file := range []string{yamlFile, jsonFile} {
+		config, err := readLintConfig(file)
+		require.NoError(t, err)
+		assert.Equal(t, expected, config)
+	}
+}
@@ -0,0 +1,538 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/ui"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+// diffLanguages are the languages that schema changes are classified against.
+var diffLanguages = []string{"dotnet", "go", "java", "nodejs", "python"}
+
+func newSchemaDiffCommand() *cobra.Command {
+	var jsonOut bool
+	var languages []string
+
+	cmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Compare two versions of a Pulumi package schema",
+		Long: "Compare two versions of a Pulumi package schema.\n" +
+			"\n" +
+			"Reports the resources, functions, types, properties and enum values that were added,\n" +
+			"removed or changed between the two schemas. Each change is classified by the SDK\n" +
+			"languages in which it breaks existing programs. The command fails if any change is\n" +
+			"breaking, so that it can be used to gate releases in CI.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, l := range languages {
+				if !slices.Contains(diffLanguages, l) {
+					return fmt.Errorf("unknown language %q, expected one of %s", l, strings.Join(diffLanguages, ", "))
+				}
+			}
+
+			oldPkg, err := importPackage(args[0])
+			if err != nil {
+				return err
+			}
+			newPkg, err := importPackage(args[1])
+			if err != nil {
+				return err
+			}
+
+			changes := diffPackages(oldPkg, newPkg)
+			if len(languages) > 0 {
+				for i := range changes {
+					changes[i].Breaking = slices.DeleteFunc(changes[i].Breaking, func(l string) bool {
+						return !slices.Contains(languages, l)
+					})
+				}
+			}
+
+			if jsonOut {
+				if err := ui.PrintJSON(changes); err != nil {
+					return err
+				}
+			} else {
+				printSchemaChanges(os.Stdout, changes)
+			}
+
+			breaking := 0
+			for _, c := range changes {
+				if len(c.Breaking) > 0 {
+					breaking++
+				}
+			}
+			if breaking > 0 {
+				return fmt.Errorf("found %d breaking changes", breaking)
+			}
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit output as JSON")
+	cmd.PersistentFlags().StringSliceVar(
+		&languages, "language", nil,
+		"Only treat changes that break these languages as breaking (one of "+strings.Join(diffLanguages, ", ")+")")
+
+	return cmd
+}
+
+// importPackage reads and binds the package schema in the given file.
+func importPackage(file string) (*schema.Package, error) {
+	spec, err := readPackageSpec(file)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := schema.ImportSpec(spec, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind schema %v: %w", file, err)
+	}
+	return pkg, nil
+}
+
+// schemaChange is a single difference between two versions of a package schema. This is also the shape of the --json
+// output of `pulumi schema diff`, so fields should only be added to it.
+type schemaChange struct {
+	// Token is the token of the resource, function or type that changed.
+	Token string `json:"token"`
+	// Path is the path to the changed property or enum value within the token, if any.
+	Path string `json:"path,omitempty"`
+	// Description describes the change.
+	Description string `json:"description"`
+	// Breaking is the list of languages in which the change breaks existing programs.
+	Breaking []string `json:"breaking,omitempty"`
+}
+
+func printSchemaChanges(w io.Writer, changes []schemaChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes found.")
+		return
+	}
+
+	var breaking, nonBreaking []schemaChange
+	for _, c := range changes {
+		if len(c.Breaking) > 0 {
+			breaking = append(breaking, c)
+		} else {
+			nonBreaking = append(nonBreaking, c)
+		}
+	}
+
+	location := func(c schemaChange) string {
+		if c.Path == "" {
+			return c.Token
+		}
+		return c.Token + " " + c.Path
+	}
+	if len(breaking) > 0 {
+		fmt.Fprintln(w, "Breaking changes:")
+		for _, c := range breaking {
+			fmt.Fprintf(w, "  %s: %s (%s)\n", location(c), c.Description, strings.Join(c.Breaking, ", "))
+		}
+	}
+	if len(nonBreaking) > 0 {
+		if len(breaking) > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, "Non-breaking changes:")
+		for _, c := range nonBreaking {
+			fmt.Fprintf(w, "  %s: %s\n", location(c), c.Description)
+		}
+	}
+}
+
+// usage records whether a type is used by inputs, outputs or both.
+type usage int
+
+const (
+	inputUsage usage = 1 << iota
+	outputUsage
+)
+
+// diffPackages returns the changes between two versions of a package, sorted by token and path.
+func diffPackages(oldPkg, newPkg *schema.Package) []schemaChange {
+	d := &schemaDiffer{uses: map[string]usage{}, changes: []schemaChange{}}
+
+	// Changes to object types and enums are classified by how the old package used them.
+	for _, p := range oldPkg.Config {
+		d.markUsage(p.Type, inputUsage)
+	}
+	oldResources := resourcesByToken(oldPkg)
+	for _, r := range oldResources {
+		for _, p := range r.InputProperties {
+			d.markUsage(p.Type, inputUsage)
+		}
+		for _, p := range r.Properties {
+			d.markUsage(p.Type, outputUsage)
+		}
+	}
+	for _, f := range oldPkg.Functions {
+		if f.Inputs != nil {
+			d.markUsage(f.Inputs, inputUsage)
+		}
+		if f.Outputs != nil {
+			d.markUsage(f.Outputs, outputUsage)
+		}
+		if f.ReturnType != nil {
+			d.markUsage(f.ReturnType, outputUsage)
+		}
+	}
+
+	d.diffProperties("pulumi:config", "config.", oldPkg.Config, newPkg.Config, inputUsage)
+	d.diffResources(oldResources, resourcesByToken(newPkg))
+	d.diffFunctions(oldPkg.Functions, newPkg.Functions)
+	d.diffTypes(oldPkg.Types, newPkg.Types)
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		if d.changes[i].Token != d.changes[j].Token {
+			return d.changes[i].Token < d.changes[j].Token
+		}
+		return d.changes[i].Path < d.changes[j].Path
+	})
+	return d.changes
+}
+
+type schemaDiffer struct {
+	uses    map[string]usage
+	changes []schemaChange
+}
+
+func (d *schemaDiffer) add(token, path, description string, breaking []string) {
+	d.changes = append(d.changes, schemaChange{
+		Token:       token,
+		Path:        path,
+		Description: description,
+		Breaking:    slices.Clone(breaking),
+	})
+}
+
+func (d *schemaDiffer) markUsage(t schema.Type, u usage) {
+	switch t := t.(type) {
+	case *schema.InputType:
+		d.markUsage(t.ElementType, u)
+	case *schema.OptionalType:
+		d.markUsage(t.ElementType, u)
+	case *schema.ArrayType:
+		d.markUsage(t.ElementType, u)
+	case *schema.MapType:
+		d.markUsage(t.ElementType, u)
+	case *schema.UnionType:
+		for _, e := range t.ElementTypes {
+			d.markUsage(e, u)
+		}
+	case *schema.EnumType:
+		d.uses[t.Token] |= u
+	case *schema.ObjectType:
+		if d.uses[t.Token]&u == u {
+			return
+		}
+		d.uses[t.Token] |= u
+		for _, p := range t.Properties {
+			d.markUsage(p.Type, u)
+		}
+	}
+}
+
+// resourcesByToken returns the resources of a package, including its provider, keyed by token.
+func resourcesByToken(pkg *schema.Package) map[string]*schema.Resource {
+	resources := map[string]*schema.Resource{}
+	if pkg.Provider != nil {
+		resources[pkg.Provider.Token] = pkg.Provider
+	}
+	for _, r := range pkg.Resources {
+		resources[r.Token] = r
+	}
+	return resources
+}
+
+func (d *schemaDiffer) diffResources(oldResources, newResources map[string]*schema.Resource) {
+	for token, oldRes := range oldResources {
+		newRes, ok := newResources[token]
+		if !ok {
+			d.add(token, "", resourceRemoval(token, newResources), diffLanguages)
+			continue
+		}
+		d.diffProperties(token, "inputs.", oldRes.InputProperties, newRes.InputProperties, inputUsage)
+		d.diffProperties(token, "outputs.", oldRes.Properties, newRes.Properties, outputUsage)
+	}
+	for token := range newResources {
+		if _, ok := oldResources[token]; !ok {
+			d.add(token, "", "resource added", nil)
+		}
+	}
+}
+
+// resourceRemoval describes the removal of a resource, which is a rename if a new resource has an alias to it.
+func resourceRemoval(token string, newResources map[string]*schema.Resource) string {
+	for _, r := range newResources {
+		for _, a := range r.Aliases {
+			if a.Type == token {
+				return "resource renamed to " + r.Token
+			}
+		}
+	}
+	return "resource removed"
+}
+
+func (d *schemaDiffer) diffFunctions(oldFunctions, newFunctions []*schema.Function) {
+	newByToken := map[string]*schema.Function{}
+	for _, f := range newFunctions {
+		newByToken[f.Token] = f
+	}
+	oldByToken := map[string]*schema.Function{}
+	for _, oldFn := range oldFunctions {
+		oldByToken[oldFn.Token] = oldFn
+
+		newFn, ok := newByToken[oldFn.Token]
+		if !ok {
+			d.add(oldFn.Token, "", "function removed", diffLanguages)
+			continue
+		}
+		d.diffProperties(oldFn.Token, "inputs.", objectProperties(oldFn.Inputs), objectProperties(newFn.Inputs),
+			inputUsage)
+		d.diffProperties(oldFn.Token, "outputs.", objectProperties(oldFn.Outputs), objectProperties(newFn.Outputs),
+			outputUsage)
+		if oldFn.ReturnType != nil && newFn.ReturnType != nil {
+			if oldType, newType := typeName(oldFn.ReturnType), typeName(newFn.ReturnType); oldType != newType {
+				d.add(oldFn.Token, "return", fmt.Sprintf("return type changed from %s to %s", oldType, newType),
+					diffLanguages)
+			}
+		}
+	}
+	for _, f := range newFunctions {
+		if _, ok := oldByToken[f.Token]; !ok {
+			d.add(f.Token, "", "function added", nil)
+		}
+	}
+}
+
+func objectProperties(t *schema.ObjectType) []*schema.Property {
+	if t == nil {
+		return nil
+	}
+	return t.Properties
+}
+
+func (d *schemaDiffer) diffTypes(oldTypes, newTypes []schema.Type) {
+	newByToken := map[string]schema.Type{}
+	for _, t := range newTypes {
+		if token, ok := typeToken(t); ok {
+			newByToken[token] = t
+		}
+	}
+	oldByToken := map[string]schema.Type{}
+	for _, oldType := range oldTypes {
+		token, ok := typeToken(oldType)
+		if !ok {
+			continue
+		}
+		oldByToken[token] = oldType
+
+		newType, ok := newByToken[token]
+		if !ok {
+			d.add(token, "", "type removed", diffLanguages)
+			continue
+		}
+
+		switch oldType := oldType.(type) {
+		case *schema.ObjectType:
+			newType, ok := newType.(*schema.ObjectType)
+			if !ok {
+				d.add(token, "", "type changed from an object to an enum", diffLanguages)
+				continue
+			}
+			u := d.uses[token]
+			if u == 0 {
+				// Types that aren't reachable from any resource or function could be used either way.
+				u = inputUsage | outputUsage
+			}
+			d.diffProperties(token, "properties.", oldType.Properties, newType.Properties, u)
+		case *schema.EnumType:
+			newType, ok := newType.(*schema.EnumType)
+			if !ok {
+				d.add(token, "", "type changed from an enum to an object", diffLanguages)
+				continue
+			}
+			d.diffEnum(token, oldType, newType)
+		}
+	}
+	for _, t := range newTypes {
+		if token, ok := typeToken(t); ok {
+			if _, ok := oldByToken[token]; !ok {
+				d.add(token, "", "type added", nil)
+			}
+		}
+	}
+}
+
+// typeToken returns the token of a named type. The input shapes of object types share their token with the plain
+// shape, so only the plain shape is given one.
+func typeToken(t schema.Type) (string, bool) {
+	switch t := t.(type) {
+	case *schema.ObjectType:
+		return t.Token, t.PlainShape == nil
+	case *schema.EnumType:
+		return t.Token, true
+	default:
+		return "", false
+	}
+}
+
+func (d *schemaDiffer) diffEnum(token string, oldType, newType *schema.EnumType) {
+	if oldElem, newElem := typeName(oldType.ElementType), typeName(newType.ElementType); oldElem != newElem {
+		d.add(token, "", fmt.Sprintf("element type changed from %s to %s", oldElem, newElem), diffLanguages)
+		return
+	}
+
+	newByValue := map[string]*schema.Enum{}
+	for _, e := range newType.Elements {
+		newByValue[fmt.Sprint(e.Value)] = e
+	}
+	oldByValue := map[string]*schema.Enum{}
+	for _, oldElem := range oldType.Elements {
+		value := fmt.Sprint(oldElem.Value)
+		oldByValue[value] = oldElem
+
+		path := "values." + value
+		newElem, ok := newByValue[value]
+		switch {
+		case !ok:
+			d.add(token, path, fmt.Sprintf("enum value %v removed", oldElem.Value), diffLanguages)
+		case oldElem.Name != newElem.Name:
+			// Enum members are referred to by name in every language.
+			d.add(token, path, fmt.Sprintf("enum value %v renamed from %q to %q", oldElem.Value, oldElem.Name,
+				newElem.Name), diffLanguages)
+		}
+	}
+	for _, e := range newType.Elements {
+		if _, ok := oldByValue[fmt.Sprint(e.Value)]; !ok {
+			d.add(token, "values."+fmt.Sprint(e.Value), fmt.Sprintf("enum value %v added", e.Value), nil)
+		}
+	}
+}
+
+// requirednessBreaks returns the languages in which a property becoming optional or required is a breaking change.
+//
+// Inputs that become optional are only breaking in Go, where optional inputs are pointers. Outputs that become optional
+// add undefined, null or an option type to the output's type in every typed language.
+func requirednessBreaks(u usage, nowRequired bool) []string {
+	var breaks []string
+	if u&inputUsage != 0 {
+		if nowRequired {
+			breaks = append(breaks, diffLanguages...)
+		} else {
+			breaks = append(breaks, "go")
+		}
+	}
+	if u&outputUsage != 0 {
+		if nowRequired {
+			breaks = append(breaks, "go", "java")
+		} else {
+			breaks = append(breaks, "dotnet", "go", "java", "nodejs")
+		}
+	}
+	slices.Sort(breaks)
+	return slices.Compact(breaks)
+}
+
+func (d *schemaDiffer) diffProperties(token, prefix string, oldProps, newProps []*schema.Property, u usage) {
+	kind := "property"
+	switch u {
+	case inputUsage:
+		kind = "input property"
+	case outputUsage:
+		kind = "output property"
+	}
+
+	newByName := map[string]*schema.Property{}
+	for _, p := range newProps {
+		newByName[p.Name] = p
+	}
+	oldByName := map[string]*schema.Property{}
+	for _, oldProp := range oldProps {
+		oldByName[oldProp.Name] = oldProp
+
+		path := prefix + oldProp.Name
+		newProp, ok := newByName[oldProp.Name]
+		if !ok {
+			d.add(token, path, kind+" removed", diffLanguages)
+			continue
+		}
+		if oldType, newType := typeName(oldProp.Type), typeName(newProp.Type); oldType != newType {
+			d.add(token, path, fmt.Sprintf("type changed from %s to %s", oldType, newType), diffLanguages)
+		}
+		if oldProp.IsRequired() != newProp.IsRequired() {
+			description := kind + " is now optional"
+			if newProp.IsRequired() {
+				description = kind + " is now required"
+			}
+			d.add(token, path, description, requirednessBreaks(u, newProp.IsRequired()))
+		}
+	}
+	for _, p := range newProps {
+		if _, ok := oldByName[p.Name]; ok {
+			continue
+		}
+		if p.IsRequired() && u&inputUsage != 0 {
+			d.add(token, prefix+p.Name, "required "+kind+" added", diffLanguages)
+		} else {
+			d.add(token, prefix+p.Name, kind+" added", nil)
+		}
+	}
+}
+
+// typeName returns a name for a type that ignores whether it is optional or an input, so that types can be compared
+// across schemas.
+func typeName(t schema.Type) string {
+	switch t := t.(type) {
+	case *schema.InputType:
+		return typeName(t.ElementType)
+	case *schema.OptionalType:
+		return typeName(t.ElementType)
+	case *schema.ArrayType:
+		return "[]" + typeName(t.ElementType)
+	case *schema.MapType:
+		return "map[string]" + typeName(t.ElementType)
+	case *schema.UnionType:
+		names := make([]string, 0, len(t.ElementTypes))
+		for _, e := range t.ElementTypes {
+			names = append(names, typeName(e))
+		}
+		slices.Sort(names)
+		return strings.Join(slices.Compact(names), " | ")
+	case *schema.ObjectType:
+		return t.Token
+	case *schema.EnumType:
+		return t.Token
+	case *schema.ResourceType:
+		return t.Token
+	case *schema.TokenType:
+		return t.Token
+	default:
+		return t.String()
+	}
+}
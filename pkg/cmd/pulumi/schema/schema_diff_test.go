@@ -0,0 +1,202 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+func stringProperty() schema.PropertySpec {
+	return schema.PropertySpec{TypeSpec: schema.TypeSpec{Type: "string"}}
+}
+
+func diffSpecs(t *testing.T, oldSpec, newSpec schema.PackageSpec) []schemaChange {
+	oldPkg, err := schema.ImportSpec(oldSpec, nil)
+	require.NoError(t, err)
+	newPkg, err := schema.ImportSpec(newSpec, nil)
+	require.NoError(t, err)
+	return diffPackages(oldPkg, newPkg)
+}
+
+func TestDiffPackages(t *testing.T) {
+	t.Parallel()
+
+	oldSpec := schema.PackageSpec{
+		Name:    "pets",
+		Version: "1.0.0",
+		Resources: map[string]schema.ResourceSpec{
+			"pets:index:Dog": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{
+						"name": stringProperty(),
+						"age":  {TypeSpec: schema.TypeSpec{Type: "integer"}},
+						"size": {TypeSpec: schema.TypeSpec{Ref: "#/types/pets:index:Size"}},
+					},
+					Required: []string{"name", "age"},
+				},
+				InputProperties: map[string]schema.PropertySpec{
+					"name":  stringProperty(),
+					"breed": stringProperty(),
+					"size":  {TypeSpec: schema.TypeSpec{Ref: "#/types/pets:index:Size"}},
+				},
+				RequiredInputs: []string{"name"},
+			},
+			"pets:index:Cat": {},
+		},
+		Functions: map[string]schema.FunctionSpec{
+			"pets:index:getDog": {
+				Inputs: &schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{"name": stringProperty()},
+				},
+			},
+		},
+		Types: map[string]schema.ComplexTypeSpec{
+			"pets:index:Size": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{Type: "string"},
+				Enum: []schema.EnumValueSpec{
+					{Name: "Small", Value: "small"},
+					{Name: "Large", Value: "large"},
+					{Name: "Huge", Value: "huge"},
+				},
+			},
+		},
+	}
+
+	newSpec := schema.PackageSpec{
+		Name:    "pets",
+		Version: "2.0.0",
+		Resources: map[string]schema.ResourceSpec{
+			"pets:index:Dog": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{
+						"name": stringProperty(),
+						"age":  stringProperty(),
+						"size": {TypeSpec: schema.TypeSpec{Ref: "#/types/pets:index:Size"}},
+					},
+					Required: []string{"age"},
+				},
+				InputProperties: map[string]schema.PropertySpec{
+					"name":  stringProperty(),
+					"owner": stringProperty(),
+					"size":  {TypeSpec: schema.TypeSpec{Ref: "#/types/pets:index:Size"}},
+				},
+			},
+			"pets:index:Kitten": {
+				Aliases: []schema.AliasSpec{{Type: "pets:index:Cat"}},
+			},
+		},
+		Functions: map[string]schema.FunctionSpec{
+			"pets:index:getDog": {
+				Inputs: &schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{"name": stringProperty()},
+					Required:   []string{"name"},
+				},
+			},
+		},
+		Types: map[string]schema.ComplexTypeSpec{
+			"pets:index:Size": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{Type: "string"},
+				Enum: []schema.EnumValueSpec{
+					{Name: "Small", Value: "small"},
+					{Name: "Big", Value: "large"},
+					{Name: "Medium", Value: "medium"},
+				},
+			},
+		},
+	}
+
+	all := diffLanguages
+	assert.Equal(t, []schemaChange{
+		{Token: "pets:index:Cat", Description: "resource renamed to pets:index:Kitten", Breaking: all},
+		{Token: "pets:index:Dog", Path: "inputs.breed", Description: "input property removed", Breaking: all},
+		{Token: "pets:index:Dog", Path: "inputs.name", Description: "input property is now optional",
+			Breaking: []string{"go"}},
+		{Token: "pets:index:Dog", Path: "inputs.owner", Description: "input property added"},
+		{Token: "pets:index:Dog", Path: "outputs.age", Description: "type changed from integer to string",
+			Breaking: all},
+		{Token: "pets:index:Dog", Path: "outputs.name", Description: "output property is now optional",
+			Breaking: []string{"dotnet", "go", "java", "nodejs"}},
+		{Token: "pets:index:Kitten", Description: "resource added"},
+		{Token: "pets:index:Size", Path: "values.huge", Description: "enum value huge removed", Breaking: all},
+		{Token: "pets:index:Size", Path: "values.large", Description: `enum value large renamed from "Large" to "Big"`,
+			Breaking: all},
+		{Token: "pets:index:Size", Path: "values.medium", Description: "enum value medium added"},
+		{Token: "pets:index:getDog", Path: "inputs.name", Description: "input property is now required",
+			Breaking: all},
+	}, diffSpecs(t, oldSpec, newSpec))
+}
+
+func TestDiffPackagesObjectTypes(t *testing.T) {
+	t.Parallel()
+
+	spec := func(required ...string) schema.PackageSpec {
+		return schema.PackageSpec{
+			Name: "pets",
+			Resources: map[string]schema.ResourceSpec{
+				"pets:index:Dog": {
+					ObjectTypeSpec: schema.ObjectTypeSpec{
+						Properties: map[string]schema.PropertySpec{
+							"collar": {TypeSpec: schema.TypeSpec{Ref: "#/types/pets:index:Collar"}},
+						},
+					},
+				},
+			},
+			Types: map[string]schema.ComplexTypeSpec{
+				"pets:index:Collar": {
+					ObjectTypeSpec: schema.ObjectTypeSpec{
+						Type:       "object",
+						Properties: map[string]schema.PropertySpec{"color": stringProperty()},
+						Required:   required,
+					},
+				},
+			},
+		}
+	}
+
+	// Collar is only used as an output, so a property becoming required is only breaking where optional outputs have
+	// a different type.
+	assert.Equal(t, []schemaChange{
+		{Token: "pets:index:Collar", Path: "properties.color", Description: "output property is now required",
+			Breaking: []string{"go", "java"}},
+	}, diffSpecs(t, spec(), spec("color")))
+
+	assert.Empty(t, diffSpecs(t, spec("color"), spec("color")))
+}
+
+func TestPrintSchemaChanges(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	printSchemaChanges(&b, []schemaChange{
+		{Token: "pets:index:Dog", Path: "inputs.breed", Description: "input property removed", Breaking: diffLanguages},
+		{Token: "pets:index:Kitten", Description: "resource added"},
+	})
+	assert.Equal(t, `Breaking changes:
+  pets:index:Dog inputs.breed: input property removed (dotnet, go, java, nodejs, python)
+
+Non-breaking changes:
+  pets:index:Kitten: resource added
+`, b.String())
+
+	b.Reset()
+	printSchemaChanges(&b, nil)
+	assert.Equal(t, "No changes found.\n", b.String())
+}
@@ -449,6 +449,22 @@ func CopyEntireConfigMap(
 }
 
 func SaveSnapshot(ctx context.Context, s backend.Stack, snapshot *deploy.Snapshot, force bool) error {
+	dep, err := snapshotDeployment(ctx, s, snapshot, force)
+	if err != nil {
+		return err
+	}
+
+	// Now perform the deployment.
+	if err = s.ImportDeployment(ctx, dep); err != nil {
+		return fmt.Errorf("could not import deployment: %w", err)
+	}
+	return nil
+}
+
+// snapshotDeployment checks that a snapshot can be imported into the given stack, and serializes it to a deployment.
+func snapshotDeployment(
+	ctx context.Context, s backend.Stack, snapshot *deploy.Snapshot, force bool,
+) (*apitype.UntypedDeployment, error) {
 	stackName := s.Ref().Name()
 	var result error
 	for _, res := range snapshot.Resources {
@@ -479,7 +495,7 @@ func SaveSnapshot(ctx context.Context, s backend.Stack, snapshot *deploy.Snapsho
 		}
 	}
 	if result != nil {
-		return multierror.Append(result,
+		return nil, multierror.Append(result,
 			errors.New("importing this file could be dangerous; rerun with --force to proceed anyway"))
 	}
 
@@ -495,24 +511,18 @@ func SaveSnapshot(ctx context.Context, s backend.Stack, snapshot *deploy.Snapsho
 	}
 	sdp, err := stack.SerializeDeployment(ctx, snapshot, false /* showSecrets */)
 	if err != nil {
-		return fmt.Errorf("constructing deployment for upload: %w", err)
+		return nil, fmt.Errorf("constructing deployment for upload: %w", err)
 	}
 
 	bytes, err := json.Marshal(sdp)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	dep := apitype.UntypedDeployment{
+	return &apitype.UntypedDeployment{
 		Version:    apitype.DeploymentSchemaVersionCurrent,
 		Deployment: bytes,
-	}
-
-	// Now perform the deployment.
-	if err = s.ImportDeployment(ctx, &dep); err != nil {
-		return fmt.Errorf("could not import deployment: %w", err)
-	}
-	return nil
+	}, nil
 }
 
 func checkDeploymentVersionError(err error, stackName string) error {
@@ -36,6 +36,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/cmd"
 	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/ui"
 	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
@@ -216,6 +217,7 @@ type stackSummaryJSON struct {
 	Current          bool   `json:"current"`
 	LastUpdate       string `json:"lastUpdate,omitempty"`
 	UpdateInProgress *bool  `json:"updateInProgress,omitempty"`
+	UpdateAuthor     string `json:"updateAuthor,omitempty"`
 	ResourceCount    *int   `json:"resourceCount,omitempty"`
 	URL              string `json:"url,omitempty"`
 }
@@ -235,6 +237,7 @@ func formatStackSummariesJSON(
 			if isUpdateInProgress(summary) && b.SupportsProgress() {
 				updateInProgress := true
 				summaryJSON.UpdateInProgress = &updateInProgress
+				summaryJSON.UpdateAuthor = updateAuthor(summary)
 			} else {
 				if b.SupportsProgress() {
 					updateInProgress := false
@@ -282,6 +285,9 @@ func formatStackSummariesConsole(b backend.Backend, currentStack string, stackSu
 		if stackLastUpdate := summary.LastUpdate(); stackLastUpdate != nil {
 			if isUpdateInProgress(summary) {
 				lastUpdate = "in progress"
+				if author := updateAuthor(summary); author != "" {
+					lastUpdate += " (" + author + ")"
+				}
 			} else {
 				lastUpdate = humanize.Time(*stackLastUpdate)
 			}
@@ -321,3 +327,15 @@ func isUpdateInProgress(u backend.StackSummary) bool {
 	// When an update is in progress the last update time is set to zero.
 	return u.LastUpdate() != nil && u.LastUpdate().Unix() == 0
 }
+
+// updateAuthor returns who requested the update currently in progress on the stack, if the backend knows.
+func updateAuthor(u backend.StackSummary) string {
+	if s, ok := u.(interface {
+		CurrentOperation() *apitype.OperationStatus
+	}); ok {
+		if op := s.CurrentOperation(); op != nil {
+			return op.Author
+		}
+	}
+	return ""
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 )
 
@@ -76,8 +77,9 @@ func newContToken(s string) backend.ContinuationToken {
 
 // mockStackSummary implements the backend.StackSummary interface.
 type mockStackSummary struct {
-	name        string
-	LastUpdateF func() *time.Time
+	name              string
+	LastUpdateF       func() *time.Time
+	CurrentOperationF func() *apitype.OperationStatus
 }
 
 func (mss *mockStackSummary) Name() backend.StackReference {
@@ -96,6 +98,13 @@ func (mss *mockStackSummary) LastUpdate() *time.Time {
 	return nil
 }
 
+func (mss *mockStackSummary) CurrentOperation() *apitype.OperationStatus {
+	if mss.CurrentOperationF != nil {
+		return mss.CurrentOperationF()
+	}
+	return nil
+}
+
 func (mss *mockStackSummary) ResourceCount() *int {
 	return nil
 }
@@ -211,6 +220,9 @@ func TestListStacksJsonProgress(t *testing.T) {
 						t := time.Unix(0, 0)
 						return &t
 					},
+					CurrentOperationF: func() *apitype.OperationStatus {
+						return &apitype.OperationStatus{Author: "alice@ci"}
+					},
 				},
 				&mockStackSummary{
 					name: "stack-in-page-3",
@@ -245,6 +257,7 @@ func TestListStacksJsonProgress(t *testing.T) {
 			{
 				"name": "stack-in-page-2",
 				"updateInProgress": true,
+				"updateAuthor": "alice@ci",
 				"current": false
 			},
 			{
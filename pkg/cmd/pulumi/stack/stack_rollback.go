@@ -0,0 +1,132 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	cmdBackend "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/backend"
+	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/ui"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+func newStackRollbackCmd() *cobra.Command {
+	var stackName string
+	var version string
+	var yes bool
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Args:  cmdutil.NoArgs,
+		Short: "Restore a stack's state to the one saved by a previous update",
+		Long: "Restore a stack's state to the one saved by a previous update.\n" +
+			"\n" +
+			"This command replaces the stack's current state with the state as it was at the end of\n" +
+			"the update with the given version (see `pulumi stack history`), and records the change\n" +
+			"as a new entry in the stack's history. Only the state is restored: no resources are\n" +
+			"created, updated or deleted. Run `pulumi refresh` or `pulumi up` afterwards to reconcile\n" +
+			"the restored state with your infrastructure.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ws := pkgWorkspace.Instance
+			yes = yes || env.SkipConfirmations.Value()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if version == "" {
+				return errors.New("the --version flag is required")
+			}
+
+			s, err := RequireStack(
+				ctx,
+				ws,
+				cmdBackend.DefaultLoginManager,
+				stackName,
+				LoadOnly,
+				opts,
+			)
+			if err != nil {
+				return err
+			}
+
+			be := s.Backend()
+			specificExpBE, ok := be.(backend.SpecificDeploymentExporter)
+			if !ok {
+				return fmt.Errorf("the current backend (%s) does not provide the ability to export previous deployments",
+					be.Name())
+			}
+
+			deployment, err := specificExpBE.ExportDeploymentForVersion(ctx, s, version)
+			if err != nil {
+				return err
+			}
+			snapshot, err := stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+			if err != nil {
+				return checkDeploymentVersionError(err, s.Ref().Name().String())
+			}
+
+			if !cmdutil.Interactive() && !yes {
+				return errors.New("non-interactive mode requires --yes flag")
+			}
+
+			// Ensure the user really wants to do this.
+			prompt := fmt.Sprintf("This will replace the state of the '%s' stack with the state of update %s!",
+				s.Ref(), version)
+			if !yes && !ui.ConfirmPrompt(prompt, s.Ref().String(), opts) {
+				return result.FprintBailf(os.Stdout, "confirmation declined")
+			}
+
+			// Backends that can record the rollback in the stack's history do so, for the rest it's a plain import.
+			if rollbacker, ok := be.(backend.DeploymentRollbacker); ok {
+				dep, err := snapshotDeployment(ctx, s, snapshot, force)
+				if err != nil {
+					return err
+				}
+				if err := rollbacker.RollbackDeployment(ctx, s, version, dep); err != nil {
+					return fmt.Errorf("could not roll back deployment: %w", err)
+				}
+			} else if err := SaveSnapshot(ctx, s, snapshot, force); err != nil {
+				return err
+			}
+			fmt.Printf("Stack '%s' has been rolled back to the state of update %s.\n", s.Ref(), version)
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVarP(
+		&version, "version", "", "", "The version of the update whose state to restore")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Skip confirmation prompts, and proceed with the rollback anyway")
+	cmd.PersistentFlags().BoolVarP(
+		&force, "force", "f", false,
+		"Force the rollback to occur, even if apparent errors are discovered beforehand (not recommended)")
+
+	return cmd
+}
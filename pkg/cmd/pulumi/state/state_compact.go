@@ -0,0 +1,167 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
+	cmdBackend "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/backend"
+	cmdStack "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/stack"
+	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/ui"
+	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newStateCompactCommand(ws pkgWorkspace.Context, lm cmdBackend.LoginManager) *cobra.Command {
+	var stackName string
+	var all bool
+	var yes bool
+	var retention diy.HistoryRetention
+
+	cmd := &cobra.Command{
+		Use:   "compact",
+		Short: "Removes old updates from the history of DIY backend stacks",
+		Long: `Removes old updates from the history of DIY backend stacks
+
+This command removes the updates, and the checkpoints saved with them, that fall outside the given retention
+policy from the history of the current stack, or of every stack in the backend with --all. An update is kept if
+any of --keep-updates and --keep-days keeps it, and the most recent update is always kept. With --compress, the
+updates that are kept are gzipped.
+
+The same policy can be applied automatically after every update by setting the
+PULUMI_DIY_BACKEND_HISTORY_KEEP_UPDATES, PULUMI_DIY_BACKEND_HISTORY_KEEP_DAYS and
+PULUMI_DIY_BACKEND_HISTORY_COMPRESS environment variables.
+
+This only has an effect on DIY backends.
+`,
+		Args: cmdutil.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			stdout := cmd.OutOrStdout()
+			yes = yes || env.SkipConfirmations.Value()
+
+			if retention.KeepUpdates < 0 || retention.KeepDays < 0 {
+				return errors.New("--keep-updates and --keep-days must not be negative")
+			}
+			if retention.KeepUpdates == 0 && retention.KeepDays == 0 && !retention.Compress {
+				return errors.New("at least one of --keep-updates, --keep-days or --compress must be specified")
+			}
+			if all && stackName != "" {
+				return errors.New("only one of --stack or --all may be specified, not both")
+			}
+
+			opts := display.Options{
+				Color:  cmdutil.GetGlobalColorization(),
+				Stdin:  cmd.InOrStdin(),
+				Stdout: stdout,
+			}
+
+			var stacks []backend.StackReference
+			var b backend.Backend
+			if all {
+				var err error
+				b, err = cmdBackend.CurrentBackend(ctx, ws, lm, nil, opts)
+				if err != nil {
+					return err
+				}
+				if stacks, err = listAllStacks(ctx, b); err != nil {
+					return err
+				}
+			} else {
+				s, err := cmdStack.RequireStack(ctx, ws, lm, stackName, cmdStack.LoadOnly, opts)
+				if err != nil {
+					return err
+				}
+				b = s.Backend()
+				stacks = []backend.StackReference{s.Ref()}
+			}
+
+			lb, ok := b.(diy.Backend)
+			if !ok {
+				// Only the diy backend keeps its own history, but we don't want to error out here.
+				// Report the no-op.
+				fmt.Fprintln(stdout, "Nothing to do")
+				return nil
+			}
+
+			if !cmdutil.Interactive() && !yes {
+				return errors.New("non-interactive mode requires --yes flag")
+			}
+			prompt := fmt.Sprintf("This will permanently remove old updates from the history of %d stack(s).\n"+
+				"Are you sure you want to proceed?", len(stacks))
+			if !yes && !ui.ConfirmPrompt(prompt, "yes", opts) {
+				fmt.Fprintln(stdout, "Compaction cancelled")
+				return nil
+			}
+
+			for _, ref := range stacks {
+				result, err := lb.CompactHistory(ctx, ref, retention)
+				if err != nil {
+					return fmt.Errorf("compacting the history of %s: %w", ref, err)
+				}
+				fmt.Fprintf(stdout, "%s: removed %d update(s), compressed %d update(s)\n",
+					ref, result.Removed, result.Compressed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().BoolVar(
+		&all, "all", false,
+		"Compact the history of every stack in the backend")
+	cmd.Flags().IntVar(
+		&retention.KeepUpdates, "keep-updates", 0,
+		"The number of most recent updates to keep")
+	cmd.Flags().IntVar(
+		&retention.KeepDays, "keep-days", 0,
+		"The number of days for which to keep updates")
+	cmd.Flags().BoolVar(
+		&retention.Compress, "compress", false,
+		"Gzip the updates that are kept")
+	cmd.Flags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Skip confirmation prompts, and proceed with the compaction anyway")
+	return cmd
+}
+
+// listAllStacks returns the references of all the stacks in the backend.
+func listAllStacks(ctx context.Context, b backend.Backend) ([]backend.StackReference, error) {
+	var refs []backend.StackReference
+	var token backend.ContinuationToken
+	for {
+		summaries, next, err := b.ListStacks(ctx, backend.ListStacksFilter{}, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range summaries {
+			refs = append(refs, summary.Name())
+		}
+		if next == nil {
+			return refs, nil
+		}
+		token = next
+	}
+}
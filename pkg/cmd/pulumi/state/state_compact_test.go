@@ -0,0 +1,126 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
+	cmdBackend "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/backend"
+	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func TestStateCompactCommand_parseArgsErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc    string
+		give    []string
+		wantErr string
+	}{
+		{
+			desc:    "no policy",
+			give:    []string{"--all"},
+			wantErr: "at least one of --keep-updates, --keep-days or --compress must be specified",
+		},
+		{
+			desc:    "negative limit",
+			give:    []string{"--keep-updates", "-1"},
+			wantErr: "must not be negative",
+		},
+		{
+			desc:    "stack and all",
+			give:    []string{"--all", "--stack", "dev", "--compress"},
+			wantErr: "only one of --stack or --all may be specified",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ws := &pkgWorkspace.MockContext{}
+			lm := &cmdBackend.MockLoginManager{}
+
+			cmd := newStateCompactCommand(ws, lm)
+			cmd.SetArgs(tt.give)
+			err := cmd.Execute()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestStateCompactCommand_Run_all(t *testing.T) {
+	t.Parallel()
+
+	refs := []backend.StackReference{
+		&backend.MockStackReference{StringV: "organization/project/dev"},
+		&backend.MockStackReference{StringV: "organization/project/prod"},
+	}
+	var compacted []backend.StackReference
+	be := &stubDIYBackend{
+		ListStacksF: func(
+			context.Context, backend.ListStacksFilter, backend.ContinuationToken,
+		) ([]backend.StackSummary, backend.ContinuationToken, error) {
+			return []backend.StackSummary{stubStackSummary{refs[0]}, stubStackSummary{refs[1]}}, nil, nil
+		},
+		CompactHistoryF: func(
+			_ context.Context, ref backend.StackReference, retention diy.HistoryRetention,
+		) (diy.HistoryCompaction, error) {
+			assert.Equal(t, diy.HistoryRetention{KeepUpdates: 10, Compress: true}, retention)
+			compacted = append(compacted, ref)
+			return diy.HistoryCompaction{Removed: 3, Compressed: 10}, nil
+		},
+	}
+	ws := &pkgWorkspace.MockContext{}
+	lm := &cmdBackend.MockLoginManager{
+		LoginF: func(
+			context.Context, pkgWorkspace.Context, diag.Sink, string, *workspace.Project, bool, colors.Colorization,
+		) (backend.Backend, error) {
+			return be, nil
+		},
+	}
+
+	var stdout bytes.Buffer
+	cmd := newStateCompactCommand(ws, lm)
+	cmd.SetArgs([]string{"--all", "--keep-updates", "10", "--compress", "--yes"})
+	cmd.SetOut(&stdout)
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, refs, compacted)
+	assert.Equal(t,
+		"organization/project/dev: removed 3 update(s), compressed 10 update(s)\n"+
+			"organization/project/prod: removed 3 update(s), compressed 10 update(s)\n",
+		stdout.String())
+}
+
+type stubStackSummary struct {
+	ref backend.StackReference
+}
+
+func (s stubStackSummary) Name() backend.StackReference { return s.ref }
+func (s stubStackSummary) LastUpdate() *time.Time       { return nil }
+func (s stubStackSummary) ResourceCount() *int          { return nil }
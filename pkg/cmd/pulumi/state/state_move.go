@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 
 	mapset "github.com/deckarep/golang-set/v2"
@@ -38,6 +39,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/urn"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/spf13/cobra"
 )
@@ -48,8 +50,11 @@ type stateMoveCmd struct {
 	Colorizer      colors.Colorization
 	Yes            bool
 	IncludeParents bool
+	Adopt          bool
 
 	ws pkgWorkspace.Context
+	// previewDest previews the destination stack's program when adopting resources.
+	previewDest func(context.Context, backend.Stack) (*deploy.Plan, error)
 }
 
 func newStateMoveCommand() *cobra.Command {
@@ -57,6 +62,7 @@ func newStateMoveCommand() *cobra.Command {
 	var destStackName string
 	var yes bool
 	var includeParents bool
+	var adopt bool
 	stateMove := &stateMoveCmd{
 		Colorizer: cmdutil.GetGlobalColorization(),
 	}
@@ -67,6 +73,12 @@ func newStateMoveCommand() *cobra.Command {
 
 This command can be used to move resources from one stack to another. This can be useful when
 splitting a stack into multiple stacks or when merging multiple stacks into one.
+
+With --adopt, the destination stack's program, which must be in the current directory, is
+previewed first. The move only goes ahead if the program declares every moved resource with
+the inputs it has in the source stack. Moved resources are given the parents and providers that
+the program declares them with, so resources whose providers are configured differently in the
+two stacks are moved to the destination stack's providers.
 `,
 		Args: cmdutil.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -107,6 +119,11 @@ splitting a stack into multiple stacks or when merging multiple stacks into one.
 
 			stateMove.Yes = yes
 			stateMove.IncludeParents = includeParents
+			stateMove.Adopt = adopt
+			stateMove.previewDest = previewForAdoption(ws, display.Options{
+				Color:         cmdutil.GetGlobalColorization(),
+				IsInteractive: cmdutil.Interactive(),
+			}, cmd.Flags())
 
 			sourceSecretsProvider := stack.NamedStackSecretsProvider{
 				StackName: sourceStack.Ref().FullyQualifiedName().String(),
@@ -124,6 +141,8 @@ splitting a stack into multiple stacks or when merging multiple stacks into one.
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Automatically approve and perform the move")
 	cmd.Flags().BoolVarP(&includeParents, "include-parents", "", false,
 		"Include all the parents of the moved resources as well")
+	cmd.Flags().BoolVarP(&adopt, "adopt", "", false,
+		"Preview the destination stack's program and only move the resources if it declares all of them")
 
 	return cmd
 }
@@ -322,7 +341,17 @@ func (cmd *stateMoveCmd) Run(
 		destResMap[res.URN] = res
 	}
 
+	var destPlan *deploy.Plan
+	if cmd.Adopt {
+		contract.Assertf(cmd.previewDest != nil, "previewDest must be set to adopt resources")
+		destPlan, err = cmd.previewDest(ctx, dest)
+		if err != nil {
+			return err
+		}
+	}
+
 	rewriteMap := make(map[string]string)
+	var copiedProviders []*resource.State
 	for _, res := range providers {
 		// Providers stay in the source stack, so we need a copy of the provider to be able to
 		// rewrite the URNs of the resource.
@@ -351,10 +380,16 @@ func (cmd *stateMoveCmd) Run(
 				rewriteMap[fmt.Sprintf("%s::%s", res.URN, res.ID)] = fmt.Sprintf("%s::%s", destRes.URN, destRes.ID)
 				continue
 			}
+			// When adopting, resources are moved to the provider that the destination program declares them with,
+			// so a conflicting provider is only a problem if the program still uses it for them.
+			if cmd.Adopt {
+				continue
+			}
 			return fmt.Errorf("provider %s already exists in destination stack", r.URN)
 		}
 
 		destSnapshot.Resources = append(destSnapshot.Resources, r)
+		copiedProviders = append(copiedProviders, r)
 	}
 
 	fmt.Fprintf(cmd.Stdout, cmd.Colorizer.Colorize(
@@ -367,7 +402,19 @@ func (cmd *stateMoveCmd) Run(
 	}
 	fmt.Fprintf(cmd.Stdout, "\n")
 
+	movedURNs := make(map[urn.URN]struct{})
+	if cmd.Adopt {
+		for _, res := range resourcesToMoveOrdered {
+			newURN, err := renameStackAndProject(res.URN, dest)
+			if err != nil {
+				return err
+			}
+			movedURNs[newURN] = struct{}{}
+		}
+	}
+
 	var brokenDestDependencies []brokenDependency
+	var adoptionProblems []adoptionProblem
 	for _, res := range resourcesToMoveOrdered {
 		// We need the original resources URNs later in case of errors, so make a copy here before modifying them.
 		r := res.Copy()
@@ -389,9 +436,38 @@ func (cmd *stateMoveCmd) Run(
 			return fmt.Errorf("resource %s already exists in destination stack", r.URN)
 		}
 
+		if cmd.Adopt {
+			if problem := adoptResource(r, destPlan, destResMap, movedURNs); problem != nil {
+				adoptionProblems = append(adoptionProblems, *problem)
+			}
+		}
+
 		destSnapshot.Resources = append(destSnapshot.Resources, r)
 	}
 
+	if len(adoptionProblems) > 0 {
+		fmt.Fprintf(cmd.Stdout, cmd.Colorizer.Colorize(
+			colors.SpecError+"The program for %s does not declare the following resources as they are in %s:\n\n"+
+				colors.Reset), dest.Ref().FullyQualifiedName(), source.Ref().FullyQualifiedName())
+		cmd.printAdoptionProblems(adoptionProblems)
+		return errors.New("the destination program does not declare all of the moved resources; no resources were moved")
+	}
+
+	if cmd.Adopt {
+		// Providers copied from the source stack that no moved resource uses any more don't need to be copied.
+		used := make(map[string]struct{})
+		for _, r := range destSnapshot.Resources {
+			used[r.Provider] = struct{}{}
+		}
+		destSnapshot.Resources = slices.DeleteFunc(destSnapshot.Resources, func(r *resource.State) bool {
+			if !slices.Contains(copiedProviders, r) {
+				return false
+			}
+			_, ok := used[fmt.Sprintf("%s::%s", r.URN, r.ID)]
+			return !ok
+		})
+	}
+
 	if len(brokenSourceDependencies) > 0 {
 		fmt.Fprintf(cmd.Stdout, cmd.Colorizer.Colorize(
 			colors.SpecWarning+"The following resources remaining in %s have dependencies on resources moved to %s:\n\n"+
@@ -0,0 +1,203 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/config"
+	"github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/metadata"
+	cmdStack "github.com/pulumi/pulumi/pkg/v3/cmd/pulumi/stack"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	pkgWorkspace "github.com/pulumi/pulumi/pkg/v3/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/urn"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// previewForAdoption returns a function that previews the program in the current directory against a stack, and
+// returns the plan that the preview makes. The program must be the destination stack's own program.
+func previewForAdoption(
+	ws pkgWorkspace.Context, displayOpts display.Options, flags *pflag.FlagSet,
+) func(context.Context, backend.Stack) (*deploy.Plan, error) {
+	return func(ctx context.Context, s backend.Stack) (*deploy.Plan, error) {
+		proj, root, err := ws.ReadProject()
+		if err != nil {
+			return nil, fmt.Errorf("loading the destination project: %w", err)
+		}
+		if project, ok := s.Ref().Project(); ok && string(proj.Name) != string(project) {
+			return nil, fmt.Errorf("the project in the current directory is %q, but the destination stack is in %q; "+
+				"run the move from the destination project's directory to adopt resources", proj.Name, project)
+		}
+
+		ssml := cmdStack.NewStackSecretsManagerLoaderFromEnv()
+		cfg, sm, err := config.GetStackConfiguration(ctx, ssml, s, proj)
+		if err != nil {
+			return nil, fmt.Errorf("getting stack configuration: %w", err)
+		}
+		m, err := metadata.GetUpdateMetadata("", root, "", "", false, cfg, flags)
+		if err != nil {
+			return nil, fmt.Errorf("gathering environment metadata: %w", err)
+		}
+		if err := workspace.ValidateStackConfigAndApplyProjectConfig(
+			ctx, s.Ref().Name().String(), proj, cfg.Environment, cfg.Config, sm.Encrypter(), sm.Decrypter(),
+		); err != nil {
+			return nil, fmt.Errorf("validating stack config: %w", err)
+		}
+
+		plan, _, err := backend.PreviewStack(ctx, s, backend.UpdateOperation{
+			Proj: proj,
+			Root: root,
+			M:    m,
+			Opts: backend.UpdateOptions{
+				Engine: engine.UpdateOptions{
+					UseLegacyDiff:             env.EnableLegacyDiff.Value(),
+					DisableProviderPreview:    env.DisableProviderPreview.Value(),
+					DisableResourceReferences: env.DisableResourceReferences.Value(),
+					DisableOutputValues:       env.DisableOutputValues.Value(),
+					GeneratePlan:              true,
+					Experimental:              env.Experimental.Value(),
+				},
+				Display: displayOpts,
+			},
+			StackConfiguration: cfg,
+			SecretsManager:     sm,
+			SecretsProvider:    stack.DefaultSecretsProvider,
+			Scopes:             backend.CancellationScopes,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("previewing the destination stack: %w", err)
+		}
+		return plan, nil
+	}
+}
+
+// adoptionProblem records why a moved resource can't be adopted by the destination stack's program.
+type adoptionProblem struct {
+	urn    urn.URN
+	reason string
+}
+
+// adoptResource checks that the destination program declares the given moved resource, whose URNs have already been
+// rewritten for the destination stack, with the inputs that it has. If it does, the resource is re-parented and
+// re-providered to match the registration. destResources holds the resources that were in the destination stack before
+// the move and moved the URNs of every resource being moved.
+func adoptResource(
+	res *resource.State, plan *deploy.Plan,
+	destResources map[urn.URN]*resource.State, moved map[urn.URN]struct{},
+) *adoptionProblem {
+	rp, ok := plan.ResourcePlans[res.URN]
+	if !ok || rp.Goal == nil {
+		return &adoptionProblem{res.URN, "is not declared by the destination program"}
+	}
+	goal := rp.Goal
+
+	// Moved resources are never in the destination stack already, so their plans diff against no old inputs.
+	if keys := mismatchedInputs(deploy.PlannedInputs(nil, rp), res.Inputs); len(keys) > 0 {
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = string(k)
+		}
+		return &adoptionProblem{res.URN, "is declared with different inputs: " + strings.Join(names, ", ")}
+	}
+
+	if goal.Parent != "" && goal.Parent != res.Parent {
+		_, inDest := destResources[goal.Parent]
+		_, isMoved := moved[goal.Parent]
+		if !inDest && !isMoved {
+			return &adoptionProblem{res.URN, fmt.Sprintf(
+				"is declared with parent %s, which isn't in the destination stack yet", goal.Parent)}
+		}
+		res.Parent = goal.Parent
+	}
+
+	if goal.Provider != "" {
+		ref, err := providers.ParseReference(goal.Provider)
+		if err != nil {
+			return &adoptionProblem{res.URN, fmt.Sprintf("is declared with an invalid provider: %v", err)}
+		}
+		if prov, ok := destResources[ref.URN()]; ok {
+			// Use the destination stack's provider, even if the provider copied from the source stack has a different
+			// configuration: that's the provider the destination program will manage the resource with.
+			destRef, err := providers.NewReference(prov.URN, prov.ID)
+			if err != nil {
+				return &adoptionProblem{res.URN, fmt.Sprintf("has an invalid provider: %v", err)}
+			}
+			res.Provider = destRef.String()
+		} else if current, err := providers.ParseReference(res.Provider); err != nil || current.URN() != ref.URN() {
+			return &adoptionProblem{res.URN, fmt.Sprintf(
+				"is declared with provider %s, which isn't in the destination stack yet", ref.URN())}
+		}
+	}
+
+	return nil
+}
+
+// mismatchedInputs returns the keys whose planned values don't match the actual inputs of a resource, in sorted order.
+// Planned values that are unknown during the preview match any actual value.
+func mismatchedInputs(planned, actual resource.PropertyMap) []resource.PropertyKey {
+	var keys []resource.PropertyKey
+	for k, v := range planned {
+		if !inputMatches(v, actual[k]) {
+			keys = append(keys, k)
+		}
+	}
+	for k, v := range actual {
+		if _, ok := planned[k]; !ok && !v.IsNull() {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func inputMatches(planned, actual resource.PropertyValue) bool {
+	switch {
+	case planned.ContainsUnknowns():
+		if planned.IsObject() && actual.IsObject() {
+			return len(mismatchedInputs(planned.ObjectValue(), actual.ObjectValue())) == 0
+		}
+		if planned.IsArray() && actual.IsArray() && len(planned.ArrayValue()) == len(actual.ArrayValue()) {
+			for i, v := range planned.ArrayValue() {
+				if !inputMatches(v, actual.ArrayValue()[i]) {
+					return false
+				}
+			}
+			return true
+		}
+		// Unknown values, and secrets or outputs wrapping them, match anything.
+		return !planned.IsObject() && !planned.IsArray()
+	default:
+		return planned.DeepEquals(actual)
+	}
+}
+
+// printAdoptionProblems writes the reasons that moved resources can't be adopted by the destination program.
+func (cmd *stateMoveCmd) printAdoptionProblems(problems []adoptionProblem) {
+	for _, p := range problems {
+		fmt.Fprintf(cmd.Stdout, "  - %s %s\n", p.urn, p.reason)
+	}
+}
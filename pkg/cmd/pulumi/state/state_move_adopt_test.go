@@ -0,0 +1,255 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+// runAdoptingMove moves the given URNs between stacks made from the given resources, adopting them with the given
+// plan for the destination stack.
+func runAdoptingMove(
+	t *testing.T, sourceResources, destResources []*resource.State, args []string, plan *deploy.Plan,
+) (*deploy.Snapshot, *deploy.Snapshot, string, error) {
+	ctx := context.Background()
+	url := "file://" + filepath.ToSlash(t.TempDir())
+	b, err := diy.New(ctx, diagtest.LogSink(t), url, nil)
+	require.NoError(t, err)
+
+	sourceStack := createStackWithResources(t, b, "organization/test/sourceStack", sourceResources)
+	destStack := createStackWithResources(t, b, "organization/test/destStack", destResources)
+
+	mp := &secrets.MockProvider{}
+	mp = mp.Add("b64", func(_ json.RawMessage) (secrets.Manager, error) {
+		return b64.NewBase64SecretsManager(), nil
+	})
+
+	var stdout bytes.Buffer
+	stateMoveCmd := stateMoveCmd{
+		Yes:       true,
+		Stdout:    &stdout,
+		Colorizer: colors.Never,
+		Adopt:     true,
+		previewDest: func(_ context.Context, s backend.Stack) (*deploy.Plan, error) {
+			assert.Equal(t, destStack.Ref(), s.Ref())
+			return plan, nil
+		},
+	}
+	moveErr := stateMoveCmd.Run(ctx, sourceStack, destStack, args, mp, mp)
+
+	// Read the stacks back from a new backend, so that we see what the move saved.
+	b, err = diy.New(ctx, diagtest.LogSink(t), url, nil)
+	require.NoError(t, err)
+	snapshot := func(s backend.Stack) *deploy.Snapshot {
+		s, err := b.GetStack(ctx, s.Ref())
+		require.NoError(t, err)
+		snap, err := s.Snapshot(ctx, mp)
+		require.NoError(t, err)
+		return snap
+	}
+	sourceSnapshot, destSnapshot := snapshot(sourceStack), snapshot(destStack)
+
+	return sourceSnapshot, destSnapshot, stdout.String(), moveErr
+}
+
+func TestMoveAdoptUsesDestinationProvider(t *testing.T) {
+	t.Parallel()
+
+	sourceProviderURN := resource.NewURN("sourceStack", "test", "", "pulumi:providers:a", "default_1_0_0")
+	sourceResources := []*resource.State{
+		{
+			URN:    sourceProviderURN,
+			Type:   "pulumi:providers:a",
+			ID:     "provider_id",
+			Custom: true,
+			Inputs: resource.PropertyMap{"region": resource.NewStringProperty("us-east-1")},
+		},
+		{
+			URN:      resource.NewURN("sourceStack", "test", "", "a:b:c", "name"),
+			Type:     "a:b:c",
+			ID:       "id",
+			Custom:   true,
+			Provider: string(sourceProviderURN) + "::provider_id",
+			Inputs:   resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+		},
+	}
+
+	destRootURN := resource.NewURN("destStack", "test", "", resource.RootStackType, "test-destStack")
+	destProviderURN := resource.NewURN("destStack", "test", "", "pulumi:providers:a", "default_1_0_0")
+	destResources := []*resource.State{
+		{URN: destRootURN, Type: resource.RootStackType},
+		{
+			URN:    destProviderURN,
+			Type:   "pulumi:providers:a",
+			ID:     "other_provider_id",
+			Custom: true,
+			Inputs: resource.PropertyMap{"region": resource.NewStringProperty("us-west-2")},
+		},
+	}
+
+	movedURN := resource.NewURN("destStack", "test", "", "a:b:c", "name")
+	plan := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		movedURN: {Goal: &deploy.GoalPlan{
+			Type:   "a:b:c",
+			Name:   "name",
+			Custom: true,
+			Parent: destRootURN,
+			// The destination program declares the resource with its own, differently configured, provider.
+			Provider: string(destProviderURN) + "::other_provider_id",
+			InputDiff: deploy.PlanDiff{
+				Adds: resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+			},
+		}},
+	}}
+
+	sourceSnapshot, destSnapshot, _, err := runAdoptingMove(
+		t, sourceResources, destResources, []string{string(sourceResources[1].URN)}, plan)
+	require.NoError(t, err)
+
+	assert.Len(t, sourceSnapshot.Resources, 1)
+	// The source stack's provider isn't copied, as nothing in the destination stack uses it.
+	require.Len(t, destSnapshot.Resources, 3)
+	assert.Equal(t, movedURN, destSnapshot.Resources[2].URN)
+	assert.Equal(t, destRootURN, destSnapshot.Resources[2].Parent)
+	assert.Equal(t, string(destProviderURN)+"::other_provider_id", destSnapshot.Resources[2].Provider)
+}
+
+func TestMoveAdoptRefusesUndeclaredResources(t *testing.T) {
+	t.Parallel()
+
+	providerURN := resource.NewURN("sourceStack", "test", "", "pulumi:providers:a", "default_1_0_0")
+	sourceResources := []*resource.State{
+		{
+			URN:    providerURN,
+			Type:   "pulumi:providers:a",
+			ID:     "provider_id",
+			Custom: true,
+		},
+		{
+			URN:      resource.NewURN("sourceStack", "test", "", "a:b:c", "declared"),
+			Type:     "a:b:c",
+			ID:       "id1",
+			Custom:   true,
+			Provider: string(providerURN) + "::provider_id",
+			Inputs:   resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+		},
+		{
+			URN:      resource.NewURN("sourceStack", "test", "", "a:b:c", "changed"),
+			Type:     "a:b:c",
+			ID:       "id2",
+			Custom:   true,
+			Provider: string(providerURN) + "::provider_id",
+			Inputs:   resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+		},
+		{
+			URN:      resource.NewURN("sourceStack", "test", "", "a:b:c", "undeclared"),
+			Type:     "a:b:c",
+			ID:       "id3",
+			Custom:   true,
+			Provider: string(providerURN) + "::provider_id",
+		},
+	}
+
+	destRootURN := resource.NewURN("destStack", "test", "", resource.RootStackType, "test-destStack")
+	destProvider := string(resource.NewURN("destStack", "test", "", "pulumi:providers:a", "default_1_0_0")) +
+		"::" + providers.UnknownID
+	plan := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		resource.NewURN("destStack", "test", "", "a:b:c", "declared"): {Goal: &deploy.GoalPlan{
+			Type:     "a:b:c",
+			Name:     "declared",
+			Parent:   destRootURN,
+			Provider: destProvider,
+			InputDiff: deploy.PlanDiff{
+				// Values that aren't known during the preview match anything.
+				Adds: resource.PropertyMap{"size": resource.MakeComputed(resource.NewStringProperty(""))},
+			},
+		}},
+		resource.NewURN("destStack", "test", "", "a:b:c", "changed"): {Goal: &deploy.GoalPlan{
+			Type:     "a:b:c",
+			Name:     "changed",
+			Parent:   destRootURN,
+			Provider: destProvider,
+			InputDiff: deploy.PlanDiff{
+				Adds: resource.PropertyMap{"size": resource.NewNumberProperty(2)},
+			},
+		}},
+	}}
+
+	args := []string{
+		string(sourceResources[1].URN), string(sourceResources[2].URN), string(sourceResources[3].URN),
+	}
+	destResources := []*resource.State{{URN: destRootURN, Type: resource.RootStackType}}
+	sourceSnapshot, destSnapshot, stdout, err := runAdoptingMove(t, sourceResources, destResources, args, plan)
+	assert.ErrorContains(t, err, "the destination program does not declare all of the moved resources")
+
+	assert.Contains(t, stdout, "The program for organization/test/destStack does not declare the following "+
+		"resources as they are in organization/test/sourceStack:\n\n"+
+		"  - urn:pulumi:destStack::test::a:b:c::changed is declared with different inputs: size\n"+
+		"  - urn:pulumi:destStack::test::a:b:c::undeclared is not declared by the destination program\n")
+
+	// Nothing is moved.
+	assert.Len(t, sourceSnapshot.Resources, 4)
+	assert.Len(t, destSnapshot.Resources, 1)
+}
+
+func TestMismatchedInputs(t *testing.T) {
+	t.Parallel()
+
+	unknown := resource.MakeComputed(resource.NewStringProperty(""))
+	planned := resource.PropertyMap{
+		"same":    resource.NewStringProperty("a"),
+		"unknown": unknown,
+		"nested": resource.NewObjectProperty(resource.PropertyMap{
+			"known":   resource.NewStringProperty("b"),
+			"unknown": unknown,
+		}),
+		"list":      resource.NewArrayProperty([]resource.PropertyValue{unknown, resource.NewStringProperty("c")}),
+		"different": resource.NewStringProperty("d"),
+		"missing":   resource.NewStringProperty("e"),
+	}
+	actual := resource.PropertyMap{
+		"same":    resource.NewStringProperty("a"),
+		"unknown": resource.NewNumberProperty(1),
+		"nested": resource.NewObjectProperty(resource.PropertyMap{
+			"known":   resource.NewStringProperty("b"),
+			"unknown": resource.NewStringProperty("x"),
+		}),
+		"list": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewBoolProperty(true), resource.NewStringProperty("z"),
+		}),
+		"different": resource.NewStringProperty("f"),
+		"extra":     resource.NewStringProperty("g"),
+		"null":      resource.NewNullProperty(),
+	}
+
+	assert.Equal(t, []resource.PropertyKey{"different", "extra", "list", "missing"}, mismatchedInputs(planned, actual))
+}
@@ -384,7 +384,13 @@ func TestStateUpgradeProjectNameWidget_notATerminal(t *testing.T) {
 type stubDIYBackend struct {
 	diy.Backend
 
-	UpgradeF func(context.Context, *diy.UpgradeOptions) error
+	UpgradeF    func(context.Context, *diy.UpgradeOptions) error
+	ListStacksF func(
+		context.Context, backend.ListStacksFilter, backend.ContinuationToken,
+	) ([]backend.StackSummary, backend.ContinuationToken, error)
+	CompactHistoryF func(
+		context.Context, backend.StackReference, diy.HistoryRetention,
+	) (diy.HistoryCompaction, error)
 }
 
 var _ diy.Backend = (*stubDIYBackend)(nil)
@@ -392,3 +398,15 @@ var _ diy.Backend = (*stubDIYBackend)(nil)
 func (f *stubDIYBackend) Upgrade(ctx context.Context, opts *diy.UpgradeOptions) error {
 	return f.UpgradeF(ctx, opts)
 }
+
+func (f *stubDIYBackend) ListStacks(
+	ctx context.Context, filter backend.ListStacksFilter, inContToken backend.ContinuationToken,
+) ([]backend.StackSummary, backend.ContinuationToken, error) {
+	return f.ListStacksF(ctx, filter, inContToken)
+}
+
+func (f *stubDIYBackend) CompactHistory(
+	ctx context.Context, stackRef backend.StackReference, retention diy.HistoryRetention,
+) (diy.HistoryCompaction, error) {
+	return f.CompactHistoryF(ctx, stackRef, retention)
+}
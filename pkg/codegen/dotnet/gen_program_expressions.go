@@ -339,6 +339,18 @@ var functionNamespaces = map[string][]string{
 	"toBase64":         {"System"},
 	"fromBase64":       {"System"},
 	"sha1":             {"System.Security.Cryptography", "System.Text"},
+	"md5":              {"System.Security.Cryptography", "System.Text"},
+	"sha256":           {"System.Security.Cryptography", "System.Text"},
+	"sha512":           {"System.Security.Cryptography", "System.Text"},
+	"base64sha256":     {"System", "System.Security.Cryptography", "System.Text"},
+	"cidrsubnet":       {"System", "System.Net"},
+	"format":           {"System", "System.Globalization", "System.Text.RegularExpressions"},
+	"min":              {"System"},
+	"max":              {"System"},
+	"abs":              {"System"},
+	"ceil":             {"System"},
+	"floor":            {"System"},
+	"urlEncode":        {"System"},
 	"singleOrNone":     {"System.Linq"},
 }
 
@@ -665,6 +677,44 @@ func (g *generator) GenFunctionCallExpression(w io.Writer, expr *model.FunctionC
 	case "sha1":
 		// Assuming the existence of the following helper method located earlier in the preamble
 		g.Fgenf(w, "ComputeSHA1(%v)", expr.Args[0])
+	case "md5":
+		g.Fgenf(w, "ComputeMD5(%v)", expr.Args[0])
+	case "sha256":
+		g.Fgenf(w, "ComputeSHA256(%v)", expr.Args[0])
+	case "sha512":
+		g.Fgenf(w, "ComputeSHA512(%v)", expr.Args[0])
+	case "base64sha256":
+		g.Fgenf(w, "ComputeBase64SHA256(%v)", expr.Args[0])
+	case "cidrsubnet":
+		g.Fgenf(w, "CidrSubnet(%v, %v, %v)", expr.Args[0], expr.Args[1], expr.Args[2])
+	case "format":
+		// Assuming the existence of the following helper method located earlier in the preamble, as .NET format
+		// strings don't use printf-style verbs.
+		g.Fgenf(w, "Format(%v", expr.Args[0])
+		for _, arg := range expr.Args[1:] {
+			g.Fgenf(w, ", %v", arg)
+		}
+		g.Fgen(w, ")")
+	case "upper":
+		g.Fgenf(w, "%.20v.ToUpperInvariant()", expr.Args[0])
+	case "lower":
+		g.Fgenf(w, "%.20v.ToLowerInvariant()", expr.Args[0])
+	case "trimSpace":
+		g.Fgenf(w, "%.20v.Trim()", expr.Args[0])
+	case "replace":
+		g.Fgenf(w, "%.20v.Replace(%v, %v)", expr.Args[0], expr.Args[1], expr.Args[2])
+	case "min":
+		g.genNestedCall(w, "Math.Min", expr.Args)
+	case "max":
+		g.genNestedCall(w, "Math.Max", expr.Args)
+	case "abs":
+		g.Fgenf(w, "Math.Abs(%v)", expr.Args[0])
+	case "ceil":
+		g.Fgenf(w, "Math.Ceiling(%v)", expr.Args[0])
+	case "floor":
+		g.Fgenf(w, "Math.Floor(%v)", expr.Args[0])
+	case "urlEncode":
+		g.Fgenf(w, "Uri.EscapeDataString(%v)", expr.Args[0])
 	case "stack":
 		g.Fgen(w, "Deployment.Instance.StackName")
 	case "project":
@@ -680,6 +730,18 @@ func (g *generator) GenFunctionCallExpression(w io.Writer, expr *model.FunctionC
 	}
 }
 
+// genNestedCall generates a call to a binary function over any number of arguments by nesting the calls, e.g.
+// Math.Min(a, Math.Min(b, c)).
+func (g *generator) genNestedCall(w io.Writer, fn string, args []model.Expression) {
+	if len(args) == 1 {
+		g.Fgenf(w, "%v", args[0])
+		return
+	}
+	g.Fgenf(w, "%s(%v, ", fn, args[0])
+	g.genNestedCall(w, fn, args[1:])
+	g.Fgen(w, ")")
+}
+
 func (g *generator) genDictionaryOrTuple(w io.Writer, expr model.Expression) {
 	switch expr := expr.(type) {
 	case *model.ObjectConsExpression:
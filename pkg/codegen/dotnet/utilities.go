@@ -146,6 +146,54 @@ func getHelperMethodIfNeeded(functionName string, indent string) (string, bool)
 %s    var hash = SHA1.Create().ComputeHash(Encoding.UTF8.GetBytes(input));
 %s    return BitConverter.ToString(hash).Replace("-","").ToLowerInvariant();
 %s}`, indent, indent, indent, indent, indent), true
+	case "md5", "sha256", "sha512":
+		name := strings.ToUpper(functionName)
+		return fmt.Sprintf(`
+%[1]sstring Compute%[2]s(string input) 
+%[1]s{
+%[1]s    var hash = %[2]s.Create().ComputeHash(Encoding.UTF8.GetBytes(input));
+%[1]s    return BitConverter.ToString(hash).Replace("-","").ToLowerInvariant();
+%[1]s}`, indent, name), true
+	case "base64sha256":
+		return fmt.Sprintf(`
+%[1]sstring ComputeBase64SHA256(string input) 
+%[1]s{
+%[1]s    var hash = SHA256.Create().ComputeHash(Encoding.UTF8.GetBytes(input));
+%[1]s    return Convert.ToBase64String(hash);
+%[1]s}`, indent), true
+	case "cidrsubnet":
+		return fmt.Sprintf(`
+%[1]sstring CidrSubnet(string prefix, int newBits, int netNum) 
+%[1]s{
+%[1]s    var parts = prefix.Split('/');
+%[1]s    var bytes = IPAddress.Parse(parts[0]).GetAddressBytes();
+%[1]s    var prefixLength = int.Parse(parts[1]);
+%[1]s    var bits = prefixLength + newBits;
+%[1]s    if (bits > bytes.Length * 8 || netNum < 0 || netNum >= Math.Pow(2, newBits))
+%[1]s    {
+%[1]s        throw new ArgumentException($"cidrsubnet: can't make subnet {netNum} with {newBits} more bits");
+%[1]s    }
+%[1]s    for (var bit = prefixLength; bit < bytes.Length * 8; bit++)
+%[1]s    {
+%[1]s        var mask = (byte)(1 << (7 - bit %% 8));
+%[1]s        var shift = bits - 1 - bit;
+%[1]s        var set = bit < bits && shift < 31 && (netNum >> shift & 1) == 1;
+%[1]s        bytes[bit / 8] = set ? (byte)(bytes[bit / 8] | mask) : (byte)(bytes[bit / 8] & ~mask);
+%[1]s    }
+%[1]s    return $"{new IPAddress(bytes)}/{bits}";
+%[1]s}`, indent), true
+	case "format":
+		return fmt.Sprintf(`
+%[1]sstring Format(string format, params object[] args) 
+%[1]s{
+%[1]s    var index = 0;
+%[1]s    return Regex.Replace(format, "%%[sdf%%]", match => match.Value switch
+%[1]s    {
+%[1]s        "%%%%" => "%%",
+%[1]s        "%%f" => Convert.ToDouble(args[index++], CultureInfo.InvariantCulture).ToString("F6", CultureInfo.InvariantCulture),
+%[1]s        _ => Convert.ToString(args[index++], CultureInfo.InvariantCulture) ?? "",
+%[1]s    });
+%[1]s}`, indent), true
 	case "notImplemented":
 		return fmt.Sprintf(`
 %sobject NotImplemented(string errorMessage) 
@@ -418,3 +418,12 @@ func testGenerateExpression(
 		assert.Equal(t, goCode, index.String())
 	})
 }
+
+func TestFormatVerbs(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "sdf", string(formatVerbs("%s has %d legs and weighs %f kg")))
+	assert.Equal(t, "d", string(formatVerbs("100%% of %03d")))
+	assert.Equal(t, "fs", string(formatVerbs("%-8.2f|%s")))
+	assert.Empty(t, formatVerbs("no verbs"))
+}
@@ -410,6 +410,51 @@ func (g *generator) GenFunctionCallExpression(w io.Writer, expr *model.FunctionC
 		g.Fgenf(w, "mime.TypeByExtension(path.Ext(%.v))", expr.Args[0])
 	case "sha1":
 		g.Fgenf(w, "sha1Hash(%v)", expr.Args[0])
+	case "md5":
+		g.Fgenf(w, "md5Hash(%v)", expr.Args[0])
+	case "sha256":
+		g.Fgenf(w, "sha256Hash(%v)", expr.Args[0])
+	case "sha512":
+		g.Fgenf(w, "sha512Hash(%v)", expr.Args[0])
+	case "base64sha256":
+		g.Fgenf(w, "base64sha256(%v)", expr.Args[0])
+	case "cidrsubnet":
+		// Assuming the existence of the following helper method
+		g.Fgenf(w, "cidrsubnet(%v, %v, %v)", expr.Args[0], expr.Args[1], expr.Args[2])
+	case "upper":
+		g.Fgenf(w, "strings.ToUpper(%v)", expr.Args[0])
+	case "lower":
+		g.Fgenf(w, "strings.ToLower(%v)", expr.Args[0])
+	case "trimSpace":
+		g.Fgenf(w, "strings.TrimSpace(%v)", expr.Args[0])
+	case "replace":
+		g.Fgenf(w, "strings.ReplaceAll(%v, %v, %v)", expr.Args[0], expr.Args[1], expr.Args[2])
+	case "format":
+		// PCL numbers are float64s in Go, which the %d verb won't print, so the numbers it formats are converted to
+		// integers first. Number literals are left alone, as they're untyped constants that already format as integers.
+		verbs := formatVerbs(pcl.LiteralValueString(expr.Args[0]))
+		g.Fgenf(w, "fmt.Sprintf(%v", expr.Args[0])
+		for i, arg := range expr.Args[1:] {
+			_, isLiteral := arg.(*model.LiteralValueExpression)
+			if i < len(verbs) && verbs[i] == 'd' && !isLiteral && model.NumberType.Equals(arg.Type()) {
+				g.Fgenf(w, ", int(%v)", arg)
+			} else {
+				g.Fgenf(w, ", %v", arg)
+			}
+		}
+		g.Fgen(w, ")")
+	case "min":
+		g.genNestedCall(w, "math.Min", expr.Args)
+	case "max":
+		g.genNestedCall(w, "math.Max", expr.Args)
+	case "abs":
+		g.Fgenf(w, "math.Abs(%v)", expr.Args[0])
+	case "ceil":
+		g.Fgenf(w, "math.Ceil(%v)", expr.Args[0])
+	case "floor":
+		g.Fgenf(w, "math.Floor(%v)", expr.Args[0])
+	case "urlEncode":
+		g.Fgenf(w, "url.QueryEscape(%v)", expr.Args[0])
 	case "goOptionalFloat64":
 		g.Fgenf(w, "pulumi.Float64Ref(%.v)", expr.Args[0])
 	case "goOptionalBool":
@@ -443,6 +488,39 @@ func (g *generator) GenFunctionCallExpression(w io.Writer, expr *model.FunctionC
 	}
 }
 
+// formatVerbs returns the verbs of a printf-style format string in the order in which they consume arguments, e.g.
+// "%s has %d legs" returns "sd". A %% doesn't consume an argument, so it isn't included.
+func formatVerbs(format string) []rune {
+	var verbs []rune
+	runes := []rune(format)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			continue
+		}
+		// Skip over any flags, width and precision to find the verb.
+		i++
+		for i < len(runes) && strings.ContainsRune("+-# 0123456789.", runes[i]) {
+			i++
+		}
+		if i < len(runes) && runes[i] != '%' {
+			verbs = append(verbs, runes[i])
+		}
+	}
+	return verbs
+}
+
+// genNestedCall generates a call to a binary function over any number of arguments by nesting the calls, e.g.
+// math.Min(a, math.Min(b, c)).
+func (g *generator) genNestedCall(w io.Writer, fn string, args []model.Expression) {
+	if len(args) == 1 {
+		g.Fgenf(w, "%v", args[0])
+		return
+	}
+	g.Fgenf(w, "%s(%v, ", fn, args[0])
+	g.genNestedCall(w, fn, args[1:])
+	g.Fgen(w, ")")
+}
+
 // Currently args type for output-versioned invokes are named
 // `FOutputArgs`, but this is not yet understood by `tokenToType`. Use
 // this function to compensate.
@@ -1290,6 +1368,22 @@ var functionPackages = map[string][]string{
 	"fromBase64":       {"encoding/base64"},
 	"toJSON":           {"encoding/json"},
 	"sha1":             {"crypto/sha1", "encoding/hex"},
+	"md5":              {"crypto/md5", "encoding/hex"},
+	"sha256":           {"crypto/sha256", "encoding/hex"},
+	"sha512":           {"crypto/sha512", "encoding/hex"},
+	"base64sha256":     {"crypto/sha256", "encoding/base64"},
+	"cidrsubnet":       {"fmt", "net/netip"},
+	"upper":            {"strings"},
+	"lower":            {"strings"},
+	"trimSpace":        {"strings"},
+	"replace":          {"strings"},
+	"format":           {"fmt"},
+	"min":              {"math"},
+	"max":              {"math"},
+	"abs":              {"math"},
+	"ceil":             {"math"},
+	"floor":            {"math"},
+	"urlEncode":        {"net/url"},
 	"filebase64sha256": {"crypto/sha256", "os"},
 	"cwd":              {"os"},
 	"singleOrNone":     {"fmt"},
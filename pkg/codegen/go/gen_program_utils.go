@@ -105,6 +105,46 @@ func getHelperMethodIfNeeded(functionName string, indent string) (string, bool)
 				hash := sha1.Sum([]byte(input))
 				return hex.EncodeToString(hash[:])
 			}`, true
+	case "md5":
+		return `func md5Hash(input string) string {
+				hash := md5.Sum([]byte(input))
+				return hex.EncodeToString(hash[:])
+			}`, true
+	case "sha256":
+		return `func sha256Hash(input string) string {
+				hash := sha256.Sum256([]byte(input))
+				return hex.EncodeToString(hash[:])
+			}`, true
+	case "sha512":
+		return `func sha512Hash(input string) string {
+				hash := sha512.Sum512([]byte(input))
+				return hex.EncodeToString(hash[:])
+			}`, true
+	case "base64sha256":
+		return `func base64sha256(input string) string {
+				hash := sha256.Sum256([]byte(input))
+				return base64.StdEncoding.EncodeToString(hash[:])
+			}`, true
+	case "cidrsubnet":
+		return `func cidrsubnet(prefix string, newbits int, netnum int) string {
+				p, err := netip.ParsePrefix(prefix)
+				if err != nil {
+					panic(err.Error())
+				}
+				bits := p.Bits() + newbits
+				if bits > p.Addr().BitLen() || netnum < 0 || netnum >= 1<<newbits {
+					panic(fmt.Sprintf("cidrsubnet: can't make subnet %d with %d more bits in %s", netnum, newbits, prefix))
+				}
+				addr := p.Masked().Addr().AsSlice()
+				for i := 0; i < newbits; i++ {
+					if netnum&(1<<i) != 0 {
+						bit := bits - 1 - i
+						addr[bit/8] |= 1 << (7 - bit%8)
+					}
+				}
+				subnet, _ := netip.AddrFromSlice(addr)
+				return netip.PrefixFrom(subnet, bits).String()
+			}`, true
 	case "notImplemented":
 		return fmt.Sprintf(`
 %sfunc notImplemented(message string) pulumi.AnyOutput {
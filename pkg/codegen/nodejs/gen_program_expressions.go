@@ -307,6 +307,10 @@ var functionImports = map[string][]string{
 	"readFile":           {"fs"},
 	"readDir":            {"fs"},
 	"sha1":               {"crypto"},
+	"md5":                {"crypto"},
+	"sha256":             {"crypto"},
+	"sha512":             {"crypto"},
+	"base64sha256":       {"crypto"},
 }
 
 func (g *generator) getFunctionImports(x *model.FunctionCallExpression) []string {
@@ -545,8 +549,43 @@ func (g *generator) GenFunctionCallExpression(w io.Writer, expr *model.FunctionC
 		} else {
 			g.Fgenf(w, "JSON.stringify(%v)", expr.Args[0])
 		}
-	case "sha1":
-		g.Fgenf(w, "crypto.createHash('sha1').update(%v).digest('hex')", expr.Args[0])
+	case "sha1", "md5", "sha256", "sha512":
+		g.Fgenf(w, "crypto.createHash('%s').update(%v).digest('hex')", expr.Name, expr.Args[0])
+	case "base64sha256":
+		g.Fgenf(w, "crypto.createHash('sha256').update(%v).digest('base64')", expr.Args[0])
+	case "cidrsubnet":
+		// Assuming the existence of the following helper method
+		g.Fgenf(w, "cidrsubnet(%v, %v, %v)", expr.Args[0], expr.Args[1], expr.Args[2])
+	case "upper":
+		g.Fgenf(w, "%.20v.toUpperCase()", expr.Args[0])
+	case "lower":
+		g.Fgenf(w, "%.20v.toLowerCase()", expr.Args[0])
+	case "trimSpace":
+		g.Fgenf(w, "%.20v.trim()", expr.Args[0])
+	case "replace":
+		// String.prototype.replaceAll isn't available in the ES2016 target that programs are compiled for.
+		g.Fgenf(w, "%.20v.split(%v).join(%v)", expr.Args[0], expr.Args[1], expr.Args[2])
+	case "format":
+		// Assuming the existence of the following helper method located earlier in the preamble, as util.format
+		// doesn't format %f with a fixed precision like the other languages do.
+		g.Fgenf(w, "format(%v", expr.Args[0])
+		for _, arg := range expr.Args[1:] {
+			g.Fgenf(w, ", %v", arg)
+		}
+		g.Fgen(w, ")")
+	case "min", "max":
+		g.Fgenf(w, "Math.%s(", expr.Name)
+		for i, arg := range expr.Args {
+			if i > 0 {
+				g.Fgen(w, ", ")
+			}
+			g.Fgenf(w, "%v", arg)
+		}
+		g.Fgen(w, ")")
+	case "abs", "ceil", "floor":
+		g.Fgenf(w, "Math.%s(%v)", expr.Name, expr.Args[0])
+	case "urlEncode":
+		g.Fgenf(w, "encodeURIComponent(%v)", expr.Args[0])
 	case "stack":
 		g.Fgenf(w, "pulumi.getStack()")
 	case "project":
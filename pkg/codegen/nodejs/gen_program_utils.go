@@ -48,6 +48,19 @@ func getHelperMethodIfNeeded(function *model.FunctionCallExpression, indent stri
 		return fmt.Sprintf(`%sfunction mimeType(path: string): string {
 %s    throw new Error("mimeType not implemented, use the mime or mime-types package instead");
 %s}`, indent, indent, indent), true
+	case "cidrsubnet":
+		return generateCidrsubnetFunction(indent), true
+	case "format":
+		return fmt.Sprintf(`%[1]sfunction format(format: string, ...args: any[]): string {
+%[1]s    let index = 0;
+%[1]s    return format.replace(/%%[sdf%%]/g, verb => {
+%[1]s        if (verb === "%%%%") {
+%[1]s            return "%%";
+%[1]s        }
+%[1]s        const arg = args[index++];
+%[1]s        return verb === "%%f" ? Number(arg).toFixed(6) : String(arg);
+%[1]s    });
+%[1]s}`, indent), true
 	case "try":
 		_, outputTry := function.Signature.ReturnType.(*model.OutputType)
 		return generateTryFunction(outputTry, indent), true
@@ -124,3 +137,60 @@ func generateOutputtyTryFunction(indent string) string {
 %[1]s}
 `, indent)
 }
+
+// The cidrsubnet helper works on arrays of bytes rather than BigInts, as BigInt isn't available in the ES2016 target
+// that programs are compiled for. IPv6 addresses are formatted in the canonical form of RFC 5952.
+func generateCidrsubnetFunction(indent string) string {
+	return fmt.Sprintf(`%[1]sfunction cidrsubnet(prefix: string, newbits: number, netnum: number): string {
+%[1]s    const [address, length] = prefix.split("/");
+%[1]s    let bytes: number[];
+%[1]s    if (address.includes(":")) {
+%[1]s        const [head, tail] = address.includes("::") ? address.split("::") : [address, undefined];
+%[1]s        const groups = (s: string | undefined) => s ? s.split(":").map(g => parseInt(g, 16)) : [];
+%[1]s        const headGroups = groups(head);
+%[1]s        const tailGroups = groups(tail);
+%[1]s        const zeros = new Array(8 - headGroups.length - tailGroups.length).fill(0);
+%[1]s        bytes = [];
+%[1]s        for (const w of [...headGroups, ...zeros, ...tailGroups]) {
+%[1]s            bytes.push(w >> 8, w & 0xff);
+%[1]s        }
+%[1]s    } else {
+%[1]s        bytes = address.split(".").map(b => parseInt(b, 10));
+%[1]s    }
+%[1]s    const bits = parseInt(length, 10) + newbits;
+%[1]s    if (bits > bytes.length * 8 || netnum < 0 || netnum >= Math.pow(2, newbits)) {
+%[1]s        throw new Error(`+"`"+`cidrsubnet: can't make subnet ${netnum} with ${newbits} more bits`+"`"+`);
+%[1]s    }
+%[1]s    for (let bit = bits - newbits; bit < bytes.length * 8; bit++) {
+%[1]s        const i = Math.floor(bit / 8);
+%[1]s        const mask = 1 << (7 - bit %% 8);
+%[1]s        const set = bit < bits && Math.floor(netnum / Math.pow(2, bits - 1 - bit)) %% 2 === 1;
+%[1]s        bytes[i] = set ? bytes[i] | mask : bytes[i] & ~mask;
+%[1]s    }
+%[1]s    if (bytes.length === 4) {
+%[1]s        return `+"`"+`${bytes.join(".")}/${bits}`+"`"+`;
+%[1]s    }
+%[1]s    const words = [];
+%[1]s    for (let i = 0; i < 16; i += 2) {
+%[1]s        words.push((bytes[i] << 8) | bytes[i + 1]);
+%[1]s    }
+%[1]s    // Compress the longest run of two or more zero groups.
+%[1]s    let start = -1, run = 1;
+%[1]s    for (let i = 0; i < 8; i++) {
+%[1]s        let j = i;
+%[1]s        while (j < 8 && words[j] === 0) {
+%[1]s            j++;
+%[1]s        }
+%[1]s        if (j - i > run) {
+%[1]s            start = i;
+%[1]s            run = j - i;
+%[1]s        }
+%[1]s    }
+%[1]s    const hex = (ws: number[]) => ws.map(w => w.toString(16)).join(":");
+%[1]s    if (start < 0) {
+%[1]s        return `+"`"+`${hex(words)}/${bits}`+"`"+`;
+%[1]s    }
+%[1]s    return `+"`"+`${hex(words.slice(0, start))}::${hex(words.slice(start + run))}/${bits}`+"`"+`;
+%[1]s}
+`, indent)
+}
@@ -344,6 +344,51 @@ func pulumiBuiltins(options bindOptions) map[string]*model.Function {
 			}},
 			ReturnType: model.StringType,
 		}),
+		"upper": stringFunction("value"),
+		"lower": stringFunction("value"),
+		// Removes leading and trailing whitespace from a string.
+		"trimSpace": stringFunction("value"),
+		// Replaces every occurrence of a substring with another string. The substring is matched literally.
+		"replace": stringFunction("value", "search", "replacement"),
+		// Formats its arguments according to a printf-style format string. The %s, %d, %f and %% verbs are
+		// supported in every language, %d is meant for whole numbers and %f prints six decimal places. Numbers are float64s in Go, so generated
+		// Go converts the numbers that %d formats to integers, which it can only do if the format string is a literal.
+		"format": newVariadicFunction("format", []model.Parameter{{
+			Name: "format",
+			Type: model.StringType,
+		}}, model.DynamicType, 0, model.StringType),
+		"min":   newVariadicFunction("min", nil, model.NumberType, 1, model.NumberType),
+		"max":   newVariadicFunction("max", nil, model.NumberType, 1, model.NumberType),
+		"abs":   numberFunction(),
+		"ceil":  numberFunction(),
+		"floor": numberFunction(),
+		// Percent-encodes a string for use in a URL query or path segment.
+		"urlEncode": stringFunction("value"),
+		// The hashing functions return the hex encoded digest of a UTF-8 string, except for base64sha256 which
+		// returns the base64 encoded digest.
+		"md5":          stringFunction("input"),
+		"sha256":       stringFunction("input"),
+		"sha512":       stringFunction("input"),
+		"base64sha256": stringFunction("input"),
+		// Calculates the address range of a subnet within an IPv4 or IPv6 CIDR prefix, where newbits is the number
+		// of bits to extend the prefix by and netnum is the number of the subnet.
+		"cidrsubnet": model.NewFunction(model.StaticFunctionSignature{
+			Parameters: []model.Parameter{
+				{
+					Name: "prefix",
+					Type: model.StringType,
+				},
+				{
+					Name: "newbits",
+					Type: model.IntType,
+				},
+				{
+					Name: "netnum",
+					Type: model.IntType,
+				},
+			},
+			ReturnType: model.StringType,
+		}),
 		// Returns the name of the current stack
 		"stack": model.NewFunction(model.StaticFunctionSignature{
 			ReturnType: model.StringType,
@@ -509,6 +554,63 @@ func pulumiBuiltins(options bindOptions) map[string]*model.Function {
 	}
 }
 
+// stringFunction returns a function that takes string parameters with the given names and returns a string.
+func stringFunction(parameterNames ...string) *model.Function {
+	parameters := make([]model.Parameter, len(parameterNames))
+	for i, name := range parameterNames {
+		parameters[i] = model.Parameter{
+			Name: name,
+			Type: model.StringType,
+		}
+	}
+	return model.NewFunction(model.StaticFunctionSignature{
+		Parameters: parameters,
+		ReturnType: model.StringType,
+	})
+}
+
+// numberFunction returns a function that takes a single number and returns a number.
+func numberFunction() *model.Function {
+	return model.NewFunction(model.StaticFunctionSignature{
+		Parameters: []model.Parameter{{
+			Name: "value",
+			Type: model.NumberType,
+		}},
+		ReturnType: model.NumberType,
+	})
+}
+
+// newVariadicFunction returns a function that takes the given parameters followed by at least minArgs further
+// arguments of argType. Rather than using a varargs parameter, every argument is given its own parameter in the bound
+// signature, as the apply rewriter and the program generators expect a parameter per argument.
+func newVariadicFunction(
+	functionName string, parameters []model.Parameter, argType model.Type, minArgs int, returnType model.Type,
+) *model.Function {
+	return model.NewFunction(model.GenericFunctionSignature(
+		func(args []model.Expression) (model.StaticFunctionSignature, hcl.Diagnostics) {
+			var diagnostics hcl.Diagnostics
+			if len(args) < len(parameters)+minArgs {
+				diagnostics = hcl.Diagnostics{&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("'%s' expects at least %d arguments", functionName, len(parameters)+minArgs),
+				}}
+			}
+
+			signature := model.StaticFunctionSignature{
+				Parameters: slices.Clone(parameters),
+				ReturnType: returnType,
+			}
+			for i := len(parameters); i < len(args); i++ {
+				signature.Parameters = append(signature.Parameters, model.Parameter{
+					Name: fmt.Sprintf("arg%d", i-len(parameters)),
+					Type: argType,
+				})
+			}
+			return signature, diagnostics
+		},
+	))
+}
+
 func newResourceFunction(functionName string) *model.Function {
 	return model.NewFunction(model.GenericFunctionSignature(
 		func(args []model.Expression) (model.StaticFunctionSignature, hcl.Diagnostics) {
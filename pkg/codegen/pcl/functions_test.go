@@ -344,3 +344,35 @@ func TestInvalidBindingPulumiResourceTypeName(t *testing.T) {
 		}
 	}
 }
+
+func TestMinWithoutArguments(t *testing.T) {
+	t.Parallel()
+
+	program, _, err := ParseAndBindProgram(t, "value = min()", "program.pp")
+	assert.Nil(t, program, "The program doesn't bind")
+	assert.ErrorContains(t, err, "'min' expects at least 1 arguments")
+}
+
+// Tests that format binds a parameter for each of its arguments, and that the arguments can be outputs.
+func TestFormatWithOutputArguments(t *testing.T) {
+	t.Parallel()
+
+	source := `value = format("%s-%d", secret("a"), 1)`
+	program, _, err := ParseAndBindProgram(t, source, "program.pp")
+	assert.NoError(t, err)
+
+	localVariable, ok := program.Nodes[0].(*pcl.LocalVariable)
+	assert.True(t, ok, "first node is a local variable variable")
+	call, ok := localVariable.Definition.Value.(*model.FunctionCallExpression)
+	assert.True(t, ok, "the value is a function call")
+	assert.Len(t, call.Signature.Parameters, 3)
+	assert.Equal(t, model.NewOutputType(model.StringType), call.Type())
+}
+
+func TestCidrsubnetRequiresIntegers(t *testing.T) {
+	t.Parallel()
+
+	program, _, err := ParseAndBindProgram(t, `value = cidrsubnet("10.0.0.0/16", [8], 2)`, "program.pp")
+	assert.Nil(t, program, "The program doesn't bind")
+	assert.Error(t, err)
+}
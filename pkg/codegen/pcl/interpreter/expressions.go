@@ -0,0 +1,496 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
+)
+
+// rangeVariable is the key that the value of a resource's `range` variable is bound to. The binder defines a new
+// `range` variable for each resource that it can't be found through, so the variable is looked up by name instead.
+const rangeVariable = "range"
+
+// scope binds the variables introduced by for expressions, splat expressions and resource ranges.
+type scope struct {
+	key   any
+	value cty.Value
+	next  *scope
+}
+
+func (s *scope) bind(key any, value cty.Value) *scope {
+	return &scope{key: key, value: value, next: s}
+}
+
+func (s *scope) lookup(key any) (cty.Value, bool) {
+	for ; s != nil; s = s.next {
+		if s.key == key {
+			return s.value, true
+		}
+	}
+	return cty.NilVal, false
+}
+
+// errorf returns an error diagnostic for the given expression.
+func errorf(expr model.Expression, format string, args ...interface{}) error {
+	rng := expr.SyntaxNode().Range()
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf(format, args...),
+		Subject:  &rng,
+	}
+}
+
+// evaluate evaluates an expression. Values that are not known yet evaluate to unknown values, and values computed
+// from secrets are marked as secret.
+func (e *evaluator) evaluate(expr model.Expression, s *scope) (cty.Value, error) {
+	switch expr := expr.(type) {
+	case *model.LiteralValueExpression:
+		return expr.Value, nil
+	case *model.TemplateExpression:
+		return e.evaluateTemplate(expr, s)
+	case *model.TemplateJoinExpression:
+		return e.evaluateTemplateJoin(expr, s)
+	case *model.ScopeTraversalExpression:
+		root, err := e.evaluateReference(expr, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return traverse(expr, root, expr.Traversal.SimpleSplit().Rel)
+	case *model.RelativeTraversalExpression:
+		source, err := e.evaluate(expr.Source, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return traverse(expr, source, expr.Traversal)
+	case *model.IndexExpression:
+		collection, err := e.evaluate(expr.Collection, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		key, err := e.evaluate(expr.Key, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return index(expr, collection, key)
+	case *model.FunctionCallExpression:
+		return e.evaluateCall(expr, s)
+	case *model.ConditionalExpression:
+		return e.evaluateConditional(expr, s)
+	case *model.BinaryOpExpression:
+		left, err := e.evaluate(expr.LeftOperand, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		right, err := e.evaluate(expr.RightOperand, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		result, err := expr.Operation.Impl.Call([]cty.Value{left, right})
+		if err != nil {
+			return cty.NilVal, errorf(expr, "%v", err)
+		}
+		return result, nil
+	case *model.UnaryOpExpression:
+		operand, err := e.evaluate(expr.Operand, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		result, err := expr.Operation.Impl.Call([]cty.Value{operand})
+		if err != nil {
+			return cty.NilVal, errorf(expr, "%v", err)
+		}
+		return result, nil
+	case *model.ObjectConsExpression:
+		return e.evaluateObjectCons(expr, s)
+	case *model.TupleConsExpression:
+		elements := make([]cty.Value, len(expr.Expressions))
+		for i, x := range expr.Expressions {
+			v, err := e.evaluate(x, s)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elements[i] = v
+		}
+		return cty.TupleVal(elements), nil
+	case *model.ForExpression:
+		return e.evaluateFor(expr, s)
+	case *model.SplatExpression:
+		return e.evaluateSplat(expr, s)
+	default:
+		return cty.NilVal, errorf(expr, "cannot evaluate an expression of type %T", expr)
+	}
+}
+
+// evaluateReference evaluates the root of a scope traversal.
+func (e *evaluator) evaluateReference(expr *model.ScopeTraversalExpression, s *scope) (cty.Value, error) {
+	switch root := expr.Parts[0].(type) {
+	case pcl.Node:
+		v, ok := e.value(root)
+		if !ok {
+			return cty.NilVal, errorf(expr, "%s has not been evaluated", root.Name())
+		}
+		return v, nil
+	case *model.Constant:
+		return root.ConstantValue, nil
+	case *model.Variable:
+		if v, ok := s.lookup(root); ok {
+			return v, nil
+		}
+		if root.Name == rangeVariable {
+			if v, ok := s.lookup(rangeVariable); ok {
+				return v, nil
+			}
+		}
+	default:
+		if v, ok := s.lookup(root); ok {
+			return v, nil
+		}
+	}
+	return cty.NilVal, errorf(expr, "undefined variable %s", expr.RootName)
+}
+
+func (e *evaluator) evaluateTemplate(expr *model.TemplateExpression, s *scope) (cty.Value, error) {
+	if len(expr.Parts) == 1 {
+		return e.evaluate(expr.Parts[0], s)
+	}
+
+	var b strings.Builder
+	var marks []cty.ValueMarks
+	known := true
+	for _, part := range expr.Parts {
+		v, err := e.evaluate(part, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		v, m := v.Unmark()
+		marks = append(marks, m)
+		if !v.IsKnown() {
+			known = false
+			continue
+		}
+		str, err := toString(part, v)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		b.WriteString(str)
+	}
+	if !known {
+		return cty.UnknownVal(cty.String).WithMarks(marks...), nil
+	}
+	return cty.StringVal(b.String()).WithMarks(marks...), nil
+}
+
+func (e *evaluator) evaluateTemplateJoin(expr *model.TemplateJoinExpression, s *scope) (cty.Value, error) {
+	tuple, err := e.evaluate(expr.Tuple, s)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	tuple, marks := tuple.UnmarkDeep()
+	if !tuple.IsWhollyKnown() {
+		return cty.UnknownVal(cty.String).WithMarks(marks), nil
+	}
+
+	var b strings.Builder
+	for it := tuple.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		str, err := toString(expr, v)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		b.WriteString(str)
+	}
+	return cty.StringVal(b.String()).WithMarks(marks), nil
+}
+
+// toString converts a known, unmarked value to a string for interpolation into a template.
+func toString(expr model.Expression, v cty.Value) (string, error) {
+	if v.IsNull() {
+		return "", errorf(expr, "cannot interpolate a null value into a string")
+	}
+	str, err := convert.Convert(v, cty.String)
+	if err != nil {
+		return "", errorf(expr, "cannot interpolate a value of type %s into a string", v.Type().FriendlyName())
+	}
+	return str.AsString(), nil
+}
+
+func (e *evaluator) evaluateConditional(expr *model.ConditionalExpression, s *scope) (cty.Value, error) {
+	condition, err := e.evaluate(expr.Condition, s)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	condition, marks := condition.Unmark()
+	if !condition.IsKnown() {
+		return cty.DynamicVal.WithMarks(marks), nil
+	}
+	condition, err = convert.Convert(condition, cty.Bool)
+	if err != nil || condition.IsNull() {
+		return cty.NilVal, errorf(expr.Condition, "the condition must be a boolean")
+	}
+
+	result := expr.FalseResult
+	if condition.True() {
+		result = expr.TrueResult
+	}
+	v, err := e.evaluate(result, s)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return v.WithMarks(marks), nil
+}
+
+func (e *evaluator) evaluateObjectCons(expr *model.ObjectConsExpression, s *scope) (cty.Value, error) {
+	attrs := make(map[string]cty.Value, len(expr.Items))
+	var marks []cty.ValueMarks
+	known := true
+	for _, item := range expr.Items {
+		key, err := e.evaluate(item.Key, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		key, m := key.Unmark()
+		marks = append(marks, m)
+		if !key.IsKnown() {
+			known = false
+			continue
+		}
+		str, err := toString(item.Key, key)
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		value, err := e.evaluate(item.Value, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		attrs[str] = value
+	}
+	if !known {
+		return cty.DynamicVal.WithMarks(marks...), nil
+	}
+	return cty.ObjectVal(attrs).WithMarks(marks...), nil
+}
+
+func (e *evaluator) evaluateFor(expr *model.ForExpression, s *scope) (cty.Value, error) {
+	collection, err := e.evaluate(expr.Collection, s)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	collection, marks := collection.Unmark()
+	if !collection.IsKnown() {
+		return cty.DynamicVal.WithMarks(marks), nil
+	}
+	if collection.IsNull() {
+		return cty.NilVal, errorf(expr.Collection, "cannot iterate over a null value")
+	}
+	if !collection.CanIterateElements() {
+		return cty.NilVal, errorf(expr.Collection, "cannot iterate over a value of type %s",
+			collection.Type().FriendlyName())
+	}
+
+	var elements []cty.Value
+	attrs := map[string]cty.Value{}
+	groups := map[string][]cty.Value{}
+	for it := collection.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		inner := s
+		if expr.KeyVariable != nil {
+			inner = inner.bind(expr.KeyVariable, k)
+		}
+		inner = inner.bind(expr.ValueVariable, v)
+
+		if expr.Condition != nil {
+			condition, err := e.evaluate(expr.Condition, inner)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			condition, m := condition.Unmark()
+			marks = mergeMarks(marks, m)
+			if !condition.IsKnown() {
+				return cty.DynamicVal.WithMarks(marks), nil
+			}
+			if condition.IsNull() || condition.Type() != cty.Bool {
+				return cty.NilVal, errorf(expr.Condition, "the condition must be a boolean")
+			}
+			if condition.False() {
+				continue
+			}
+		}
+
+		value, err := e.evaluate(expr.Value, inner)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		if expr.Key == nil {
+			elements = append(elements, value)
+			continue
+		}
+
+		key, err := e.evaluate(expr.Key, inner)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		key, m := key.Unmark()
+		marks = mergeMarks(marks, m)
+		if !key.IsKnown() {
+			return cty.DynamicVal.WithMarks(marks), nil
+		}
+		str, err := toString(expr.Key, key)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		switch {
+		case expr.Group:
+			groups[str] = append(groups[str], value)
+		case hasKey(attrs, str):
+			return cty.NilVal, errorf(expr.Key, "duplicate key %q", str)
+		default:
+			attrs[str] = value
+		}
+	}
+
+	switch {
+	case expr.Key == nil:
+		return cty.TupleVal(elements).WithMarks(marks), nil
+	case expr.Group:
+		for k, vs := range groups {
+			attrs[k] = cty.TupleVal(vs)
+		}
+	}
+	return cty.ObjectVal(attrs).WithMarks(marks), nil
+}
+
+func (e *evaluator) evaluateSplat(expr *model.SplatExpression, s *scope) (cty.Value, error) {
+	source, err := e.evaluate(expr.Source, s)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	source, marks := source.Unmark()
+	if !source.IsKnown() {
+		return cty.DynamicVal.WithMarks(marks), nil
+	}
+	if source.IsNull() {
+		return cty.EmptyTupleVal.WithMarks(marks), nil
+	}
+
+	items := []cty.Value{source}
+	if typ := source.Type(); typ.IsListType() || typ.IsTupleType() || typ.IsSetType() {
+		items = nil
+		for it := source.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			items = append(items, v)
+		}
+	}
+
+	elements := make([]cty.Value, len(items))
+	for i, item := range items {
+		v, err := e.evaluate(expr.Each, s.bind(expr.Item, item))
+		if err != nil {
+			return cty.NilVal, err
+		}
+		elements[i] = v
+	}
+	return cty.TupleVal(elements).WithMarks(marks), nil
+}
+
+func hasKey(attrs map[string]cty.Value, key string) bool {
+	_, ok := attrs[key]
+	return ok
+}
+
+// mergeMarks returns the union of two sets of marks.
+func mergeMarks(a, b cty.ValueMarks) cty.ValueMarks {
+	result := make(cty.ValueMarks, len(a)+len(b))
+	for m := range a {
+		result[m] = struct{}{}
+	}
+	for m := range b {
+		result[m] = struct{}{}
+	}
+	return result
+}
+
+// traverse applies the attribute and index steps of a traversal to a value.
+func traverse(expr model.Expression, v cty.Value, traversal hcl.Traversal) (cty.Value, error) {
+	for _, step := range traversal {
+		var err error
+		switch step := step.(type) {
+		case hcl.TraverseAttr:
+			v, err = index(expr, v, cty.StringVal(step.Name))
+		case hcl.TraverseIndex:
+			v, err = index(expr, v, step.Key)
+		default:
+			err = errorf(expr, "unsupported traversal step %T", step)
+		}
+		if err != nil {
+			return cty.NilVal, err
+		}
+	}
+	return v, nil
+}
+
+// index looks up a key in a collection. Looking up an attribute that an object doesn't have, or any key of a null
+// value, results in null. This matches the optional chaining that the code generators emit for these lookups.
+func index(expr model.Expression, collection, key cty.Value) (cty.Value, error) {
+	collection, marks := collection.Unmark()
+	key, keyMarks := key.Unmark()
+	marks = mergeMarks(marks, keyMarks)
+
+	if collection.IsNull() {
+		return cty.NullVal(cty.DynamicPseudoType).WithMarks(marks), nil
+	}
+	if !collection.IsKnown() || !key.IsKnown() {
+		return cty.DynamicVal.WithMarks(marks), nil
+	}
+
+	typ := collection.Type()
+	switch {
+	case typ.IsObjectType() || typ.IsMapType():
+		k, err := convert.Convert(key, cty.String)
+		if err != nil || k.IsNull() {
+			return cty.NilVal, errorf(expr, "objects must be indexed by strings")
+		}
+		if typ.IsObjectType() {
+			if !typ.HasAttribute(k.AsString()) {
+				return cty.NullVal(cty.DynamicPseudoType).WithMarks(marks), nil
+			}
+			return collection.GetAttr(k.AsString()).WithMarks(marks), nil
+		}
+		if collection.HasIndex(k).False() {
+			return cty.NullVal(cty.DynamicPseudoType).WithMarks(marks), nil
+		}
+		return collection.Index(k).WithMarks(marks), nil
+	case typ.IsListType() || typ.IsTupleType():
+		k, err := convert.Convert(key, cty.Number)
+		if err != nil || k.IsNull() {
+			return cty.NilVal, errorf(expr, "lists must be indexed by numbers")
+		}
+		if collection.HasIndex(k).False() {
+			return cty.NilVal, errorf(expr, "index %s is out of range for a list of %d elements",
+				k.AsBigFloat().String(), collection.LengthInt())
+		}
+		return collection.Index(k).WithMarks(marks), nil
+	default:
+		return cty.NilVal, errorf(expr, "cannot index a value of type %s", typ.FriendlyName())
+	}
+}
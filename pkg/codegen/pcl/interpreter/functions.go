@@ -0,0 +1,688 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interpreter
+
+import (
+	"crypto/md5"  //nolint:gosec // md5 is a PCL intrinsic, not used for security.
+	"crypto/sha1" //nolint:gosec // sha1 is a PCL intrinsic, not used for security.
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/archive"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/asset"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/urn"
+)
+
+// builtin implements a PCL function. Its arguments are always known and never marked: calls with unknown arguments
+// evaluate to unknown values without calling the builtin, and the marks of the arguments are applied to the result.
+type builtin func(e *evaluator, args []cty.Value) (cty.Value, error)
+
+var builtins map[string]builtin
+
+func init() {
+	builtins = map[string]builtin{
+		"element":            elementFunction,
+		"entries":            entriesFunction,
+		"fileArchive":        assetFunction(archive.FromPath),
+		"remoteArchive":      assetFunction(archive.FromURI),
+		"assetArchive":       assetArchiveFunction,
+		"fileAsset":          assetFunction(asset.FromPath),
+		"stringAsset":        assetFunction(asset.FromText),
+		"remoteAsset":        assetFunction(asset.FromURI),
+		"join":               joinFunction,
+		"length":             lengthFunction,
+		"lookup":             lookupFunction,
+		"mimeType":           mimeTypeFunction,
+		"range":              rangeFunction,
+		"readDir":            readDirFunction,
+		"readFile":           fileFunction(func(b []byte) string { return string(b) }),
+		"filebase64":         fileFunction(base64.StdEncoding.EncodeToString),
+		"filebase64sha256":   fileFunction(base64sha256),
+		"sha1":               stringFunction(sha1Hash),
+		"split":              splitFunction,
+		"toBase64":           stringFunction(func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }),
+		"fromBase64":         fromBase64Function,
+		"toJSON":             toJSONFunction,
+		"upper":              stringFunction(strings.ToUpper),
+		"lower":              stringFunction(strings.ToLower),
+		"trimSpace":          stringFunction(strings.TrimSpace),
+		"replace":            replaceFunction,
+		"format":             formatFunction,
+		"min":                numbersFunction(math.Min),
+		"max":                numbersFunction(math.Max),
+		"abs":                numberFunction(math.Abs),
+		"ceil":               numberFunction(math.Ceil),
+		"floor":              numberFunction(math.Floor),
+		"urlEncode":          stringFunction(url.QueryEscape),
+		"md5":                stringFunction(md5Hash),
+		"sha256":             stringFunction(sha256Hash),
+		"sha512":             stringFunction(sha512Hash),
+		"base64sha256":       stringFunction(func(s string) string { return base64sha256([]byte(s)) }),
+		"cidrsubnet":         cidrsubnetFunction,
+		"stack":              stackFunction,
+		"project":            projectFunction,
+		"organization":       organizationFunction,
+		"cwd":                cwdFunction,
+		"rootDirectory":      rootDirectoryFunction,
+		"notImplemented":     notImplementedFunction,
+		"singleOrNone":       singleOrNoneFunction,
+		"getOutput":          getOutputFunction,
+		"pulumiResourceType": resourceFunction(func(u urn.URN) string { return string(u.Type()) }),
+		"pulumiResourceName": resourceFunction(urn.URN.Name),
+	}
+}
+
+// evaluateCall evaluates a call to a PCL function.
+func (e *evaluator) evaluateCall(expr *model.FunctionCallExpression, s *scope) (cty.Value, error) {
+	// These functions need to see unevaluated, unknown or marked arguments.
+	switch expr.Name {
+	case pcl.Invoke:
+		return e.evaluateInvoke(expr, s)
+	case pcl.Call:
+		return cty.NilVal, errorf(expr, "method calls are not supported by the PCL interpreter")
+	case pcl.IntrinsicConvert:
+		return e.evaluateConvert(expr, s)
+	case "secret":
+		v, err := e.evaluate(expr.Args[0], s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return v.Mark(secretMark), nil
+	case "unsecret":
+		v, err := e.evaluate(expr.Args[0], s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		v, _ = v.UnmarkDeep()
+		return v, nil
+	case "try":
+		var last error
+		for _, arg := range expr.Args {
+			v, err := e.evaluate(arg, s)
+			if err == nil {
+				if !v.IsWhollyKnown() {
+					return cty.DynamicVal, nil
+				}
+				return v, nil
+			}
+			last = err
+		}
+		return cty.NilVal, errorf(expr, "none of the arguments to try could be evaluated: %v", last)
+	case "can":
+		v, err := e.evaluate(expr.Args[0], s)
+		switch {
+		case err != nil:
+			return cty.False, nil
+		case !v.IsWhollyKnown():
+			return cty.UnknownVal(cty.Bool), nil
+		default:
+			return cty.True, nil
+		}
+	}
+
+	fn, ok := builtins[expr.Name]
+	if !ok {
+		return cty.NilVal, errorf(expr, "unknown function %s", expr.Name)
+	}
+
+	args := make([]cty.Value, len(expr.Args))
+	marks := cty.NewValueMarks()
+	known := true
+	for i, arg := range expr.Args {
+		v, err := e.evaluate(arg, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		known = known && v.IsWhollyKnown()
+		v, m := v.UnmarkDeep()
+		args[i], marks = v, mergeMarks(marks, m)
+	}
+	if !known {
+		return cty.DynamicVal.WithMarks(marks), nil
+	}
+
+	result, err := fn(e, args)
+	if err != nil {
+		return cty.NilVal, errorf(expr, "%s: %v", expr.Name, err)
+	}
+	return result.WithMarks(marks), nil
+}
+
+// evaluateConvert evaluates a conversion that the binder inserted to make a value fit the type that it's used as,
+// e.g. a number passed as a string property.
+func (e *evaluator) evaluateConvert(expr *model.FunctionCallExpression, s *scope) (cty.Value, error) {
+	v, err := e.evaluate(expr.Args[0], s)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	var target cty.Type
+	switch pcl.UnwrapOption(model.ResolveOutputs(expr.Signature.ReturnType)) {
+	case model.StringType:
+		target = cty.String
+	case model.NumberType, model.IntType:
+		target = cty.Number
+	case model.BoolType:
+		target = cty.Bool
+	default:
+		return v, nil
+	}
+
+	unmarked, marks := v.Unmark()
+	if !unmarked.IsKnown() || unmarked.IsNull() || !unmarked.Type().IsPrimitiveType() {
+		return v, nil
+	}
+	if converted, err := convert.Convert(unmarked, target); err == nil {
+		return converted.WithMarks(marks), nil
+	}
+	return v, nil
+}
+
+func stringArg(v cty.Value) (string, error) {
+	if v.IsNull() {
+		return "", errors.New("expected a string, got null")
+	}
+	s, err := convert.Convert(v, cty.String)
+	if err != nil {
+		return "", fmt.Errorf("expected a string, got %s", v.Type().FriendlyName())
+	}
+	return s.AsString(), nil
+}
+
+func numberArg(v cty.Value) (float64, error) {
+	if v.IsNull() {
+		return 0, errors.New("expected a number, got null")
+	}
+	n, err := convert.Convert(v, cty.Number)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number, got %s", v.Type().FriendlyName())
+	}
+	f, _ := n.AsBigFloat().Float64()
+	return f, nil
+}
+
+func intArg(v cty.Value) (int, error) {
+	f, err := numberArg(v)
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("expected a whole number, got %v", f)
+	}
+	return int(f), nil
+}
+
+// elements returns the elements of a list, tuple or set.
+func elements(v cty.Value) ([]cty.Value, error) {
+	if v.IsNull() || !(v.Type().IsListType() || v.Type().IsTupleType() || v.Type().IsSetType()) {
+		return nil, fmt.Errorf("expected a list, got %s", v.Type().FriendlyName())
+	}
+	var result []cty.Value
+	for it := v.ElementIterator(); it.Next(); {
+		_, e := it.Element()
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+func stringFunction(f func(string) string) builtin {
+	return func(_ *evaluator, args []cty.Value) (cty.Value, error) {
+		s, err := stringArg(args[0])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.StringVal(f(s)), nil
+	}
+}
+
+func numberFunction(f func(float64) float64) builtin {
+	return func(_ *evaluator, args []cty.Value) (cty.Value, error) {
+		n, err := numberArg(args[0])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.NumberFloatVal(f(n)), nil
+	}
+}
+
+// numbersFunction returns a builtin that folds its arguments with the given function.
+func numbersFunction(f func(float64, float64) float64) builtin {
+	return func(_ *evaluator, args []cty.Value) (cty.Value, error) {
+		result, err := numberArg(args[0])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		for _, arg := range args[1:] {
+			n, err := numberArg(arg)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			result = f(result, n)
+		}
+		return cty.NumberFloatVal(result), nil
+	}
+}
+
+func md5Hash(s string) string {
+	hash := md5.Sum([]byte(s)) //nolint:gosec
+	return hex.EncodeToString(hash[:])
+}
+
+func sha1Hash(s string) string {
+	hash := sha1.Sum([]byte(s)) //nolint:gosec
+	return hex.EncodeToString(hash[:])
+}
+
+func sha256Hash(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(hash[:])
+}
+
+func sha512Hash(s string) string {
+	hash := sha512.Sum512([]byte(s))
+	return hex.EncodeToString(hash[:])
+}
+
+func base64sha256(b []byte) string {
+	hash := sha256.Sum256(b)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func elementFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	list, err := elements(args[0])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	i, err := intArg(args[1])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if len(list) == 0 {
+		return cty.NilVal, errors.New("cannot use element on an empty list")
+	}
+	if i < 0 {
+		return cty.NilVal, errors.New("the index must not be negative")
+	}
+	return list[i%len(list)], nil
+}
+
+func entriesFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	v := args[0]
+	if v.IsNull() || !v.CanIterateElements() {
+		return cty.NilVal, fmt.Errorf("expected a list or map, got %s", v.Type().FriendlyName())
+	}
+	var entries []cty.Value
+	for it := v.ElementIterator(); it.Next(); {
+		k, e := it.Element()
+		entries = append(entries, cty.ObjectVal(map[string]cty.Value{"key": k, "value": e}))
+	}
+	return cty.TupleVal(entries), nil
+}
+
+// assetFunction returns a builtin that makes an asset or archive from a string.
+func assetFunction[T *asset.Asset | *archive.Archive](f func(string) (T, error)) builtin {
+	return func(_ *evaluator, args []cty.Value) (cty.Value, error) {
+		s, err := stringArg(args[0])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		a, err := f(s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		switch a := any(a).(type) {
+		case *asset.Asset:
+			return assetVal(resource.NewAssetProperty(a)), nil
+		default:
+			return assetVal(resource.NewArchiveProperty(a.(*archive.Archive))), nil
+		}
+	}
+}
+
+func assetArchiveFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	v := args[0]
+	if v.IsNull() || !(v.Type().IsMapType() || v.Type().IsObjectType()) {
+		return cty.NilVal, fmt.Errorf("expected a map of assets, got %s", v.Type().FriendlyName())
+	}
+	assets := map[string]interface{}{}
+	for it := v.ElementIterator(); it.Next(); {
+		k, e := it.Element()
+		if e.IsNull() || e.Type() != assetType {
+			return cty.NilVal, fmt.Errorf("%s is not an asset or archive", k.AsString())
+		}
+		pv := e.EncapsulatedValue().(*resource.PropertyValue)
+		if pv.IsAsset() {
+			assets[k.AsString()] = pv.AssetValue()
+		} else {
+			assets[k.AsString()] = pv.ArchiveValue()
+		}
+	}
+	a, err := archive.FromAssets(assets)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return assetVal(resource.NewArchiveProperty(a)), nil
+}
+
+func joinFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	sep, err := stringArg(args[0])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	list, err := elements(args[1])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	strs := make([]string, len(list))
+	for i, v := range list {
+		if strs[i], err = stringArg(v); err != nil {
+			return cty.NilVal, err
+		}
+	}
+	return cty.StringVal(strings.Join(strs, sep)), nil
+}
+
+func lengthFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	v := args[0]
+	switch {
+	case v.IsNull():
+		return cty.NilVal, errors.New("cannot take the length of null")
+	case v.Type() == cty.String:
+		return cty.NumberIntVal(int64(len([]rune(v.AsString())))), nil
+	case v.CanIterateElements():
+		return cty.NumberIntVal(int64(v.LengthInt())), nil
+	default:
+		return cty.NilVal, fmt.Errorf("cannot take the length of a value of type %s", v.Type().FriendlyName())
+	}
+}
+
+func lookupFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	v := args[0]
+	if v.IsNull() || !(v.Type().IsMapType() || v.Type().IsObjectType()) {
+		return cty.NilVal, fmt.Errorf("expected a map, got %s", v.Type().FriendlyName())
+	}
+	key, err := stringArg(args[1])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	for it := v.ElementIterator(); it.Next(); {
+		k, e := it.Element()
+		if k.AsString() == key {
+			return e, nil
+		}
+	}
+	if len(args) > 2 {
+		return args[2], nil
+	}
+	return cty.NilVal, fmt.Errorf("no key %q in map", key)
+}
+
+func mimeTypeFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	path, err := stringArg(args[0])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	typ := mime.TypeByExtension(filepath.Ext(path))
+	if typ == "" {
+		typ = "application/octet-stream"
+	}
+	return cty.StringVal(typ), nil
+}
+
+func rangeFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	from, to := 0, 0
+	var err error
+	if len(args) == 1 {
+		to, err = intArg(args[0])
+	} else if from, err = intArg(args[0]); err == nil {
+		to, err = intArg(args[1])
+	}
+	if err != nil {
+		return cty.NilVal, err
+	}
+	var result []cty.Value
+	for i := from; i < to; i++ {
+		result = append(result, cty.NumberIntVal(int64(i)))
+	}
+	return cty.TupleVal(result), nil
+}
+
+// path resolves a path relative to the program's directory.
+func (e *evaluator) path(v cty.Value) (string, error) {
+	path, err := stringArg(v)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(e.opts.RootDirectory, path)
+	}
+	return path, nil
+}
+
+func readDirFunction(e *evaluator, args []cty.Value) (cty.Value, error) {
+	path, err := e.path(args[0])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	names := make([]cty.Value, len(entries))
+	for i, entry := range entries {
+		names[i] = cty.StringVal(entry.Name())
+	}
+	return cty.TupleVal(names), nil
+}
+
+// fileFunction returns a builtin that reads a file and converts its contents to a string.
+func fileFunction(f func([]byte) string) builtin {
+	return func(e *evaluator, args []cty.Value) (cty.Value, error) {
+		path, err := e.path(args[0])
+		if err != nil {
+			return cty.NilVal, err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.StringVal(f(b)), nil
+	}
+}
+
+func splitFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	sep, err := stringArg(args[0])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	s, err := stringArg(args[1])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	var parts []cty.Value
+	for _, p := range strings.Split(s, sep) {
+		parts = append(parts, cty.StringVal(p))
+	}
+	return cty.TupleVal(parts), nil
+}
+
+func fromBase64Function(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	s, err := stringArg(args[0])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return cty.StringVal(string(b)), nil
+}
+
+func toJSONFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	b, err := ctyjson.Marshal(args[0], args[0].Type())
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return cty.StringVal(string(b)), nil
+}
+
+func replaceFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		s, err := stringArg(arg)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		strs[i] = s
+	}
+	return cty.StringVal(strings.ReplaceAll(strs[0], strs[1], strs[2])), nil
+}
+
+func formatFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	return stdlib.FormatFunc.Call(args)
+}
+
+// cidrsubnet calculates a subnet address within the given IP network address prefix, as Terraform's function of the
+// same name does.
+func cidrsubnetFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	s, err := stringArg(args[0])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	newbits, err := intArg(args[1])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	netnum, err := intArg(args[2])
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	bits := prefix.Bits() + newbits
+	if newbits < 0 || bits > prefix.Addr().BitLen() || netnum < 0 || newbits < 63 && netnum >= 1<<newbits {
+		return cty.NilVal, fmt.Errorf("can't make subnet %d with %d more bits in %s", netnum, newbits, s)
+	}
+	addr := prefix.Masked().Addr().AsSlice()
+	for i := 0; i < newbits && i < 63; i++ {
+		if netnum&(1<<i) != 0 {
+			bit := bits - 1 - i
+			addr[bit/8] |= 1 << (7 - bit%8)
+		}
+	}
+	subnet, _ := netip.AddrFromSlice(addr)
+	return cty.StringVal(netip.PrefixFrom(subnet, bits).String()), nil
+}
+
+func stackFunction(e *evaluator, _ []cty.Value) (cty.Value, error) {
+	return cty.StringVal(e.opts.Stack), nil
+}
+
+func projectFunction(e *evaluator, _ []cty.Value) (cty.Value, error) {
+	return cty.StringVal(e.opts.Project), nil
+}
+
+func organizationFunction(e *evaluator, _ []cty.Value) (cty.Value, error) {
+	return cty.StringVal(e.opts.Organization), nil
+}
+
+func cwdFunction(_ *evaluator, _ []cty.Value) (cty.Value, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return cty.StringVal(cwd), nil
+}
+
+func rootDirectoryFunction(e *evaluator, _ []cty.Value) (cty.Value, error) {
+	return cty.StringVal(e.opts.RootDirectory), nil
+}
+
+func notImplementedFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	message, err := stringArg(args[0])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return cty.NilVal, errors.New(message)
+}
+
+func singleOrNoneFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	list, err := elements(args[0])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	switch len(list) {
+	case 0:
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	case 1:
+		return list[0], nil
+	default:
+		return cty.NilVal, fmt.Errorf("expected a list with a single element, got %d elements", len(list))
+	}
+}
+
+// getOutputFunction reads an output from the outputs of a stack reference.
+func getOutputFunction(_ *evaluator, args []cty.Value) (cty.Value, error) {
+	ref := args[0]
+	if ref.IsNull() || !ref.Type().IsObjectType() || !ref.Type().HasAttribute("outputs") {
+		return cty.NilVal, errors.New("expected a stack reference")
+	}
+	name, err := stringArg(args[1])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	outputs := ref.GetAttr("outputs")
+	if outputs.IsNull() || !(outputs.Type().IsObjectType() || outputs.Type().IsMapType()) {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+	return lookupFunction(nil, []cty.Value{outputs, cty.StringVal(name), cty.NullVal(cty.DynamicPseudoType)})
+}
+
+// resourceFunction returns a builtin that computes a string from the URN of a resource.
+func resourceFunction(f func(urn.URN) string) builtin {
+	return func(_ *evaluator, args []cty.Value) (cty.Value, error) {
+		res := args[0]
+		if res.IsNull() || !res.Type().IsObjectType() || !res.Type().HasAttribute("urn") {
+			return cty.NilVal, errors.New("expected a resource")
+		}
+		u, err := stringArg(res.GetAttr("urn"))
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.StringVal(f(urn.URN(u))), nil
+	}
+}
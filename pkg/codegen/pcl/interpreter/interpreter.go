@@ -0,0 +1,834 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interpreter evaluates bound PCL programs against a resource monitor, so that they can be run directly by
+// the engine rather than being converted to a language SDK program first.
+package interpreter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// Options configures the evaluation of a PCL program.
+type Options struct {
+	// The names of the project, stack and organization that the program is being run for.
+	Project      string
+	Stack        string
+	Organization string
+	// The directory containing the program. Relative file paths are resolved against it.
+	RootDirectory string
+	// The stack's configuration, keyed by fully qualified configuration key.
+	Config map[string]string
+	// The configuration keys whose values are secret.
+	ConfigSecretKeys []string
+	// True if the program is being run for a preview.
+	DryRun bool
+}
+
+// runner holds the state that is shared by a program and the programs of its components.
+type runner struct {
+	ctx      context.Context
+	monitor  pulumirpc.ResourceMonitorClient
+	opts     Options
+	packages map[string]packageInfo
+}
+
+// packageInfo records the schema, version and download URL of a package that the program uses.
+type packageInfo struct {
+	schema      schema.PackageReference
+	version     string
+	downloadURL string
+}
+
+// Run evaluates a bound program, registering its resources and invoking its functions through the given resource
+// monitor. The program's resources are parented to the stack, and its outputs become the stack's outputs.
+func Run(ctx context.Context, program *pcl.Program, monitor pulumirpc.ResourceMonitorClient, opts Options) error {
+	r := &runner{
+		ctx:      ctx,
+		monitor:  monitor,
+		opts:     opts,
+		packages: map[string]packageInfo{},
+	}
+	for _, pkg := range program.PackageReferences() {
+		info := packageInfo{schema: pkg}
+		if v := pkg.Version(); v != nil {
+			info.version = v.String()
+		}
+		if def, err := pkg.Definition(); err == nil {
+			info.downloadURL = def.PluginDownloadURL
+		}
+		r.packages[pkg.Name()] = info
+	}
+
+	stack, err := monitor.RegisterResource(ctx, &pulumirpc.RegisterResourceRequest{
+		Type: string(resource.RootStackType),
+		Name: opts.Project + "-" + opts.Stack,
+	})
+	if err != nil {
+		return fmt.Errorf("registering the stack: %w", err)
+	}
+
+	e := r.newEvaluator(program, stack.Urn, "", r.stackConfig)
+	outputs, err := e.run()
+	if err != nil {
+		return err
+	}
+	return r.registerOutputs(stack.Urn, outputs)
+}
+
+// stackConfig looks up the value of a configuration variable in the stack's configuration. Values of types other
+// than string are stored as JSON.
+func (r *runner) stackConfig(cv *pcl.ConfigVariable) (cty.Value, bool, error) {
+	key := cv.LogicalName()
+	if !strings.Contains(key, ":") {
+		key = r.opts.Project + ":" + key
+	}
+	raw, ok := r.opts.Config[key]
+	if !ok {
+		return cty.NilVal, false, nil
+	}
+
+	typ := pcl.UnwrapOption(cv.Type())
+	v := cty.StringVal(raw)
+	if typ != model.StringType {
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			v = fromPropertyValue(resource.NewPropertyValue(value), typ, false)
+		} else if typ != model.DynamicType {
+			return cty.NilVal, false, fmt.Errorf("configuration key %q is not a valid %v: %w", key, typ, err)
+		}
+	}
+	if slices.Contains(r.opts.ConfigSecretKeys, key) {
+		v = v.Mark(secretMark)
+	}
+	return v, true, nil
+}
+
+// registerOutputs registers the outputs of a stack or component resource.
+func (r *runner) registerOutputs(urn string, outputs map[string]cty.Value) error {
+	m, err := toPropertyMap(cty.ObjectVal(outputs))
+	if err != nil {
+		return fmt.Errorf("registering outputs: %w", err)
+	}
+	s, err := plugin.MarshalProperties(m, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	if err != nil {
+		return fmt.Errorf("registering outputs: %w", err)
+	}
+	_, err = r.monitor.RegisterResourceOutputs(r.ctx, &pulumirpc.RegisterResourceOutputsRequest{
+		Urn:     urn,
+		Outputs: s,
+	})
+	if err != nil {
+		return fmt.Errorf("registering outputs: %w", err)
+	}
+	return nil
+}
+
+// evaluator evaluates the nodes of a program, or of a component's program.
+type evaluator struct {
+	*runner
+
+	program *pcl.Program
+	// The URN that resources are parented to when they don't set a parent.
+	parent string
+	// A prefix for the names of the program's resources, used to keep the names of component children unique.
+	namePrefix string
+	// Looks up the value of a configuration variable, returning false if it isn't set.
+	config func(cv *pcl.ConfigVariable) (cty.Value, bool, error)
+
+	m      sync.Mutex
+	values map[pcl.Node]cty.Value
+	urns   map[pcl.Node][]string
+}
+
+func (r *runner) newEvaluator(
+	program *pcl.Program, parent, namePrefix string, config func(*pcl.ConfigVariable) (cty.Value, bool, error),
+) *evaluator {
+	return &evaluator{
+		runner:     r,
+		program:    program,
+		parent:     parent,
+		namePrefix: namePrefix,
+		config:     config,
+		values:     map[pcl.Node]cty.Value{},
+		urns:       map[pcl.Node][]string{},
+	}
+}
+
+func (e *evaluator) value(n pcl.Node) (cty.Value, bool) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	v, ok := e.values[n]
+	return v, ok
+}
+
+func (e *evaluator) setValue(n pcl.Node, v cty.Value, urns []string) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.values[n] = v
+	e.urns[n] = urns
+}
+
+// dependencies returns the nodes of the program that the given node refers to.
+func (e *evaluator) dependencies(n pcl.Node) []pcl.Node {
+	var deps []pcl.Node
+	seen := map[pcl.Node]bool{}
+	diags := n.VisitExpressions(model.IdentityVisitor, func(x model.Expression) (model.Expression, hcl.Diagnostics) {
+		if x, ok := x.(*model.ScopeTraversalExpression); ok {
+			if dep, ok := x.Parts[0].(pcl.Node); ok && dep != n && !seen[dep] {
+				seen[dep] = true
+				deps = append(deps, dep)
+			}
+		}
+		return x, nil
+	})
+	contract.Assertf(len(diags) == 0, "expected no diagnostics from VisitExpressions")
+	return deps
+}
+
+// referencedURNs returns the URNs of the resources and components that an expression refers to.
+func (e *evaluator) referencedURNs(expr model.Expression) []string {
+	var urns []string
+	_, diags := model.VisitExpression(expr, model.IdentityVisitor, func(x model.Expression) (model.Expression, hcl.Diagnostics) {
+		if x, ok := x.(*model.ScopeTraversalExpression); ok {
+			if dep, ok := x.Parts[0].(pcl.Node); ok {
+				e.m.Lock()
+				for _, u := range e.urns[dep] {
+					if !slices.Contains(urns, u) {
+						urns = append(urns, u)
+					}
+				}
+				e.m.Unlock()
+			}
+		}
+		return x, nil
+	})
+	contract.Assertf(len(diags) == 0, "expected no diagnostics from VisitExpression")
+	return urns
+}
+
+// run evaluates each node of the program once the nodes that it depends on have been evaluated, so that independent
+// resources are registered concurrently. It returns the program's outputs.
+func (e *evaluator) run() (map[string]cty.Value, error) {
+	done := make(map[pcl.Node]chan struct{}, len(e.program.Nodes))
+	for _, n := range e.program.Nodes {
+		done[n] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(e.program.Nodes))
+	failed := make(map[pcl.Node]bool)
+	var failedLock sync.Mutex
+	outputs := map[string]cty.Value{}
+	var outputsLock sync.Mutex
+	for i, n := range e.program.Nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[n])
+
+			for _, dep := range e.dependencies(n) {
+				if ch, ok := done[dep]; ok {
+					<-ch
+				}
+				failedLock.Lock()
+				depFailed := failed[dep]
+				failedLock.Unlock()
+				if depFailed {
+					// The dependency's error has already been reported.
+					failedLock.Lock()
+					failed[n] = true
+					failedLock.Unlock()
+					return
+				}
+			}
+
+			var err error
+			if o, ok := n.(*pcl.OutputVariable); ok {
+				var v cty.Value
+				if v, err = e.evaluate(o.Value, nil); err == nil {
+					outputsLock.Lock()
+					outputs[o.LogicalName()] = v
+					outputsLock.Unlock()
+				}
+			} else {
+				err = e.evaluateNode(n)
+			}
+			if err != nil {
+				errs[i] = err
+				failedLock.Lock()
+				failed[n] = true
+				failedLock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+func (e *evaluator) evaluateNode(n pcl.Node) error {
+	switch n := n.(type) {
+	case *pcl.ConfigVariable:
+		v, ok, err := e.config(n)
+		switch {
+		case err != nil:
+			return err
+		case ok:
+		case n.DefaultValue != nil:
+			if v, err = e.evaluate(n.DefaultValue, nil); err != nil {
+				return err
+			}
+		case n.Nullable:
+			v = cty.NullVal(cty.DynamicPseudoType)
+		default:
+			return fmt.Errorf("missing required configuration variable %q", n.LogicalName())
+		}
+		e.setValue(n, v, nil)
+		return nil
+	case *pcl.LocalVariable:
+		v, err := e.evaluate(n.Definition.Value, nil)
+		if err != nil {
+			return err
+		}
+		e.setValue(n, v, e.referencedURNs(n.Definition.Value))
+		return nil
+	case *pcl.Resource:
+		return e.instantiate(n, n.Options, n.LogicalName(), func(name string, s *scope) (cty.Value, string, error) {
+			return e.registerResource(n, name, s)
+		})
+	case *pcl.Component:
+		return e.instantiate(n, n.Options, n.LogicalName(), func(name string, s *scope) (cty.Value, string, error) {
+			return e.registerComponent(n, name, s)
+		})
+	default:
+		return fmt.Errorf("cannot evaluate a node of type %T", n)
+	}
+}
+
+// instantiate registers a resource or component once, or once for each element of its range. Each instance of a
+// ranged resource is named after its key in the range.
+func (e *evaluator) instantiate(
+	n pcl.Node, options *pcl.ResourceOptions, name string,
+	register func(name string, s *scope) (cty.Value, string, error),
+) error {
+	name = e.namePrefix + name
+	if options == nil || options.Range == nil {
+		v, urn, err := register(name, nil)
+		if err != nil {
+			return err
+		}
+		e.setValue(n, v, []string{urn})
+		return nil
+	}
+
+	rng, err := e.evaluate(options.Range, nil)
+	if err != nil {
+		return err
+	}
+	rng, _ = rng.UnmarkDeep()
+	if !rng.IsKnown() {
+		// The instances can't be known until the range is, which is only the case in a preview.
+		e.setValue(n, cty.DynamicVal, nil)
+		return nil
+	}
+	if rng.IsNull() {
+		return errorf(options.Range, "cannot range over a null value")
+	}
+
+	type instance struct {
+		name  string
+		scope *scope
+	}
+	var instances []instance
+	switch typ := rng.Type(); {
+	case typ == cty.Bool:
+		if rng.False() {
+			e.setValue(n, cty.NullVal(cty.DynamicPseudoType), nil)
+			return nil
+		}
+		v, urn, err := register(name, nil)
+		if err != nil {
+			return err
+		}
+		e.setValue(n, v, []string{urn})
+		return nil
+	case typ == cty.Number:
+		count, err := intArg(rng)
+		if err != nil {
+			return errorf(options.Range, "%v", err)
+		}
+		for i := 0; i < count; i++ {
+			v := cty.ObjectVal(map[string]cty.Value{"value": cty.NumberIntVal(int64(i))})
+			instances = append(instances, instance{fmt.Sprintf("%s-%d", name, i), (*scope)(nil).bind(rangeVariable, v)})
+		}
+	case rng.CanIterateElements():
+		for it := rng.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			key, err := toString(options.Range, k)
+			if err != nil {
+				return err
+			}
+			v = cty.ObjectVal(map[string]cty.Value{"key": k, "value": v})
+			instances = append(instances, instance{name + "-" + key, (*scope)(nil).bind(rangeVariable, v)})
+		}
+	default:
+		return errorf(options.Range, "cannot range over a value of type %s", typ.FriendlyName())
+	}
+
+	values := make([]cty.Value, len(instances))
+	urns := make([]string, len(instances))
+	for i, inst := range instances {
+		v, urn, err := register(inst.name, inst.scope)
+		if err != nil {
+			return err
+		}
+		values[i], urns[i] = v, urn
+	}
+	e.setValue(n, cty.TupleVal(values), urns)
+	return nil
+}
+
+// resourceOptions are the evaluated options of a resource or component.
+type resourceOptions struct {
+	parent         string
+	provider       string
+	dependsOn      []string
+	protect        *bool
+	retainOnDelete *bool
+	ignoreChanges  []string
+	version        string
+	downloadURL    string
+	deletedWith    string
+}
+
+func (e *evaluator) evaluateOptions(options *pcl.ResourceOptions, s *scope) (*resourceOptions, error) {
+	opts := &resourceOptions{parent: e.parent}
+	if options == nil {
+		return opts, nil
+	}
+
+	for _, opt := range []struct {
+		expr model.Expression
+		set  func(v cty.Value) error
+	}{
+		{options.Parent, func(v cty.Value) (err error) { opts.parent, err = urnOf(v); return }},
+		{options.Provider, func(v cty.Value) (err error) { opts.provider, err = providerReference(v); return }},
+		{options.DeletedWith, func(v cty.Value) (err error) { opts.deletedWith, err = urnOf(v); return }},
+		{options.DependsOn, func(v cty.Value) (err error) { opts.dependsOn, err = urnsOf(v); return }},
+		{options.Protect, func(v cty.Value) (err error) { opts.protect, err = boolOption(v); return }},
+		{options.RetainOnDelete, func(v cty.Value) (err error) { opts.retainOnDelete, err = boolOption(v); return }},
+		{options.Version, func(v cty.Value) (err error) { opts.version, err = stringArg(v); return }},
+		{options.PluginDownloadURL, func(v cty.Value) (err error) { opts.downloadURL, err = stringArg(v); return }},
+	} {
+		if opt.expr == nil {
+			continue
+		}
+		v, err := e.evaluate(opt.expr, s)
+		if err != nil {
+			return nil, err
+		}
+		v, _ = v.UnmarkDeep()
+		if !v.IsWhollyKnown() {
+			return nil, errorf(opt.expr, "resource options must be known")
+		}
+		if err := opt.set(v); err != nil {
+			return nil, errorf(opt.expr, "%v", err)
+		}
+	}
+
+	if ignoreChanges, ok := options.IgnoreChanges.(*model.TupleConsExpression); ok {
+		for _, x := range ignoreChanges.Expressions {
+			traversal, ok := x.(*model.ScopeTraversalExpression)
+			if !ok {
+				return nil, errorf(x, "ignoreChanges must list properties of the resource")
+			}
+			opts.ignoreChanges = append(opts.ignoreChanges, propertyPath(traversal.Traversal))
+		}
+	}
+	return opts, nil
+}
+
+// propertyPath formats a traversal of a resource's properties as a property path.
+func propertyPath(traversal hcl.Traversal) string {
+	var b strings.Builder
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			b.WriteString(step.Name)
+		case hcl.TraverseAttr:
+			b.WriteString("." + step.Name)
+		case hcl.TraverseIndex:
+			if step.Key.Type() == cty.String {
+				fmt.Fprintf(&b, "[%q]", step.Key.AsString())
+			} else {
+				fmt.Fprintf(&b, "[%s]", step.Key.AsBigFloat().String())
+			}
+		}
+	}
+	return b.String()
+}
+
+func urnOf(v cty.Value) (string, error) {
+	if v.IsNull() || !v.Type().IsObjectType() || !v.Type().HasAttribute("urn") {
+		return "", errors.New("expected a resource")
+	}
+	return stringArg(v.GetAttr("urn"))
+}
+
+// urnsOf returns the URNs of a resource or of a list of resources, such as a ranged resource.
+func urnsOf(v cty.Value) ([]string, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	if typ := v.Type(); !(typ.IsListType() || typ.IsTupleType() || typ.IsSetType()) {
+		u, err := urnOf(v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{u}, nil
+	}
+	var urns []string
+	for it := v.ElementIterator(); it.Next(); {
+		_, e := it.Element()
+		us, err := urnsOf(e)
+		if err != nil {
+			return nil, err
+		}
+		urns = append(urns, us...)
+	}
+	return urns, nil
+}
+
+// providerReference returns a reference to a provider resource, in the form that the engine expects.
+func providerReference(v cty.Value) (string, error) {
+	u, err := urnOf(v)
+	if err != nil {
+		return "", err
+	}
+	if !v.Type().HasAttribute("id") {
+		return "", errors.New("expected a provider resource")
+	}
+	id := v.GetAttr("id")
+	if !id.IsKnown() || id.IsNull() || id.AsString() == "" {
+		// The provider hasn't been created yet, which is only the case in a preview.
+		return u + "::" + plugin.UnknownStringValue, nil
+	}
+	return u + "::" + id.AsString(), nil
+}
+
+func boolOption(v cty.Value) (*bool, error) {
+	if v.IsNull() || v.Type() != cty.Bool {
+		return nil, errors.New("expected a boolean")
+	}
+	b := v.True()
+	return &b, nil
+}
+
+// packageFor returns the version and download URL to use for a resource or function token, unless they are set
+// explicitly.
+func (e *evaluator) packageFor(token, version, downloadURL string) (string, string) {
+	pkg, module, member, _ := pcl.DecomposeToken(token, hcl.Range{})
+	if pkg == "pulumi" && module == "providers" {
+		pkg = member
+	}
+	info := e.packages[pkg]
+	if version == "" {
+		version = info.version
+	}
+	if downloadURL == "" {
+		downloadURL = info.downloadURL
+	}
+	return version, downloadURL
+}
+
+// functionToken returns the token from the package's schema for a function token that has been canonicalized by the
+// binder.
+func (e *evaluator) functionToken(token string) string {
+	pkg, module, member, _ := pcl.DecomposeToken(token, hcl.Range{})
+	info, ok := e.packages[pkg]
+	if !ok {
+		return token
+	}
+	for it := info.schema.Functions().Range(); it.Next(); {
+		tok := it.Token()
+		_, _, m, _ := pcl.DecomposeToken(tok, hcl.Range{})
+		if tok == token || m == member && info.schema.TokenToModule(tok) == module {
+			return tok
+		}
+	}
+	return token
+}
+
+func (e *evaluator) registerResource(r *pcl.Resource, name string, s *scope) (cty.Value, string, error) {
+	opts, err := e.evaluateOptions(r.Options, s)
+	if err != nil {
+		return cty.NilVal, "", err
+	}
+
+	inputs := make(map[string]cty.Value, len(r.Inputs))
+	propertyDependencies := map[string]*pulumirpc.RegisterResourceRequest_PropertyDependencies{}
+	for _, attr := range r.Inputs {
+		v, err := e.evaluate(attr.Value, s)
+		if err != nil {
+			return cty.NilVal, "", err
+		}
+		inputs[attr.Name] = v
+		if urns := e.referencedURNs(attr.Value); len(urns) > 0 {
+			propertyDependencies[attr.Name] = &pulumirpc.RegisterResourceRequest_PropertyDependencies{Urns: urns}
+		}
+	}
+	props, err := toPropertyMap(cty.ObjectVal(inputs))
+	if err != nil {
+		return cty.NilVal, "", fmt.Errorf("resource %s: %w", name, err)
+	}
+	object, err := plugin.MarshalProperties(props, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	if err != nil {
+		return cty.NilVal, "", fmt.Errorf("resource %s: %w", name, err)
+	}
+
+	dependencies := opts.dependsOn
+	for _, dep := range e.dependencies(r) {
+		e.m.Lock()
+		for _, u := range e.urns[dep] {
+			if !slices.Contains(dependencies, u) {
+				dependencies = append(dependencies, u)
+			}
+		}
+		e.m.Unlock()
+	}
+
+	token := r.Token
+	if r.Schema != nil {
+		// The binder canonicalizes tokens, but the engine expects the token from the package's schema.
+		token = r.Schema.Token
+	}
+	custom := r.Schema == nil || !r.Schema.IsComponent
+	version, downloadURL := e.packageFor(token, opts.version, opts.downloadURL)
+	resp, err := e.monitor.RegisterResource(e.ctx, &pulumirpc.RegisterResourceRequest{
+		Type:                  token,
+		Name:                  name,
+		Parent:                opts.parent,
+		Custom:                custom,
+		Remote:                !custom,
+		Object:                object,
+		Protect:               opts.protect,
+		RetainOnDelete:        opts.retainOnDelete,
+		Dependencies:          dependencies,
+		PropertyDependencies:  propertyDependencies,
+		Provider:              opts.provider,
+		Version:               version,
+		PluginDownloadURL:     downloadURL,
+		IgnoreChanges:         opts.ignoreChanges,
+		DeletedWith:           opts.deletedWith,
+		AcceptSecrets:         true,
+		SupportsPartialValues: true,
+	})
+	if err != nil {
+		return cty.NilVal, "", fmt.Errorf("registering resource %s: %w", name, err)
+	}
+
+	outputs, err := plugin.UnmarshalProperties(resp.Object, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	if err != nil {
+		return cty.NilVal, "", fmt.Errorf("resource %s: %w", name, err)
+	}
+	attrs := objectAttributes(outputs, r.OutputType, e.opts.DryRun)
+	attrs["urn"] = cty.StringVal(resp.Urn)
+	if custom {
+		attrs["id"] = cty.StringVal(resp.Id)
+		if resp.Id == "" && e.opts.DryRun {
+			attrs["id"] = cty.UnknownVal(cty.String)
+		}
+	}
+	return cty.ObjectVal(attrs), resp.Urn, nil
+}
+
+// registerComponent registers a component resource and evaluates the component's program with the component's inputs
+// as its configuration. The component's value is an object of the outputs of its program.
+func (e *evaluator) registerComponent(c *pcl.Component, name string, s *scope) (cty.Value, string, error) {
+	opts, err := e.evaluateOptions(c.Options, s)
+	if err != nil {
+		return cty.NilVal, "", err
+	}
+
+	inputs := make(map[string]cty.Value, len(c.Inputs))
+	var dependencies []string
+	for _, attr := range c.Inputs {
+		v, err := e.evaluate(attr.Value, s)
+		if err != nil {
+			return cty.NilVal, "", err
+		}
+		inputs[attr.Name] = v
+		dependencies = append(dependencies, e.referencedURNs(attr.Value)...)
+	}
+
+	resp, err := e.monitor.RegisterResource(e.ctx, &pulumirpc.RegisterResourceRequest{
+		Type:           "components:index:" + c.DeclarationName(),
+		Name:           name,
+		Parent:         opts.parent,
+		Protect:        opts.protect,
+		RetainOnDelete: opts.retainOnDelete,
+		Dependencies:   append(dependencies, opts.dependsOn...),
+		Provider:       opts.provider,
+		DeletedWith:    opts.deletedWith,
+	})
+	if err != nil {
+		return cty.NilVal, "", fmt.Errorf("registering component %s: %w", name, err)
+	}
+
+	child := e.newEvaluator(c.Program, resp.Urn, name+"-", func(cv *pcl.ConfigVariable) (cty.Value, bool, error) {
+		v, ok := inputs[cv.Name()]
+		if !ok || v.IsNull() {
+			return cty.NilVal, false, nil
+		}
+		return v, true, nil
+	})
+	outputs, err := child.run()
+	if err != nil {
+		return cty.NilVal, "", fmt.Errorf("component %s: %w", name, err)
+	}
+	if err := e.registerOutputs(resp.Urn, outputs); err != nil {
+		return cty.NilVal, "", fmt.Errorf("component %s: %w", name, err)
+	}
+
+	attrs := map[string]cty.Value{"urn": cty.StringVal(resp.Urn)}
+	for k, v := range outputs {
+		attrs[k] = v
+	}
+	return cty.ObjectVal(attrs), resp.Urn, nil
+}
+
+// evaluateInvoke invokes a provider function. Invokes whose arguments aren't known yet are skipped, and evaluate to
+// unknown values.
+func (e *evaluator) evaluateInvoke(expr *model.FunctionCallExpression, s *scope) (cty.Value, error) {
+	args := make([]cty.Value, len(expr.Args))
+	for i, arg := range expr.Args {
+		v, err := e.evaluate(arg, s)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		args[i] = v
+	}
+	for len(args) < 3 {
+		args = append(args, cty.NullVal(cty.DynamicPseudoType))
+	}
+
+	token, err := stringArg(args[0])
+	if err != nil {
+		return cty.NilVal, errorf(expr, "%v", err)
+	}
+	if !args[1].IsWhollyKnown() || !args[2].IsWhollyKnown() {
+		return cty.DynamicVal, nil
+	}
+	secret := isSecret(args[1])
+
+	inputs, err := toPropertyValue(args[1])
+	if err != nil {
+		return cty.NilVal, errorf(expr.Args[1], "%v", err)
+	}
+	if inputs.IsSecret() {
+		inputs = inputs.SecretValue().Element
+	}
+	var m resource.PropertyMap
+	switch {
+	case inputs.IsObject():
+		m = inputs.ObjectValue()
+	case !inputs.IsNull():
+		return cty.NilVal, errorf(expr.Args[1], "invoke arguments must be an object")
+	}
+	object, err := plugin.MarshalProperties(m, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	if err != nil {
+		return cty.NilVal, errorf(expr, "%v", err)
+	}
+
+	var provider, version, downloadURL string
+	if invokeOpts, _ := args[2].UnmarkDeep(); !invokeOpts.IsNull() {
+		if !invokeOpts.Type().IsObjectType() {
+			return cty.NilVal, errorf(expr.Args[2], "invoke options must be an object")
+		}
+		for name, set := range map[string]func(v cty.Value) error{
+			"provider":          func(v cty.Value) (err error) { provider, err = providerReference(v); return },
+			"version":           func(v cty.Value) (err error) { version, err = stringArg(v); return },
+			"pluginDownloadUrl": func(v cty.Value) (err error) { downloadURL, err = stringArg(v); return },
+		} {
+			if invokeOpts.Type().HasAttribute(name) {
+				if v := invokeOpts.GetAttr(name); !v.IsNull() {
+					if err := set(v); err != nil {
+						return cty.NilVal, errorf(expr.Args[2], "%s: %v", name, err)
+					}
+				}
+			}
+		}
+	}
+	token = e.functionToken(token)
+	version, downloadURL = e.packageFor(token, version, downloadURL)
+
+	resp, err := e.monitor.Invoke(e.ctx, &pulumirpc.ResourceInvokeRequest{
+		Tok:               token,
+		Args:              object,
+		Provider:          provider,
+		Version:           version,
+		PluginDownloadURL: downloadURL,
+	})
+	if err != nil {
+		return cty.NilVal, errorf(expr, "invoking %s: %v", token, err)
+	}
+	if len(resp.Failures) > 0 {
+		reasons := make([]string, len(resp.Failures))
+		for i, f := range resp.Failures {
+			reasons[i] = fmt.Sprintf("%s (%s)", f.Reason, f.Property)
+		}
+		return cty.NilVal, errorf(expr, "invoking %s failed: %s", token, strings.Join(reasons, "; "))
+	}
+
+	ret, err := plugin.UnmarshalProperties(resp.Return, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	if err != nil {
+		return cty.NilVal, errorf(expr, "invoking %s: %v", token, err)
+	}
+
+	returnType := pcl.UnwrapOption(model.ResolveOutputs(expr.Signature.ReturnType))
+	var result cty.Value
+	if _, isObject := returnType.(*model.ObjectType); !isObject && len(ret) == 1 {
+		// Functions that don't return objects return their single value as the only property of the result.
+		for _, v := range ret {
+			result = fromPropertyValue(v, returnType, false)
+		}
+	} else {
+		result = cty.ObjectVal(objectAttributes(ret, returnType, false))
+	}
+	if secret {
+		result = result.Mark(secretMark)
+	}
+	return result, nil
+}
@@ -0,0 +1,398 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interpreter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/testing/utils"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+var testdataPath = filepath.Join("..", "..", "testing", "test", "testdata")
+
+// fakeMonitor is a resource monitor that records the resources registered with it. Resources echo their inputs as
+// their outputs, and are only given IDs outside of a preview.
+type fakeMonitor struct {
+	pulumirpc.ResourceMonitorClient
+
+	dryRun  bool
+	invoke  func(tok string, args resource.PropertyMap) resource.PropertyMap
+	m       sync.Mutex
+	byName  map[string]*pulumirpc.RegisterResourceRequest
+	outputs map[string]resource.PropertyMap
+}
+
+func newFakeMonitor(dryRun bool) *fakeMonitor {
+	return &fakeMonitor{
+		dryRun:  dryRun,
+		byName:  map[string]*pulumirpc.RegisterResourceRequest{},
+		outputs: map[string]resource.PropertyMap{},
+	}
+}
+
+func (m *fakeMonitor) urn(typ, name string) string {
+	return string(resource.NewURN("stack", "project", "", tokens.Type(typ), name))
+}
+
+func (m *fakeMonitor) RegisterResource(
+	_ context.Context, req *pulumirpc.RegisterResourceRequest, _ ...grpc.CallOption,
+) (*pulumirpc.RegisterResourceResponse, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.byName[req.Name] = req
+
+	id := "id-" + req.Name
+	if m.dryRun || !req.Custom {
+		id = ""
+	}
+	return &pulumirpc.RegisterResourceResponse{
+		Urn:    m.urn(req.Type, req.Name),
+		Id:     id,
+		Object: req.Object,
+	}, nil
+}
+
+func (m *fakeMonitor) RegisterResourceOutputs(
+	_ context.Context, req *pulumirpc.RegisterResourceOutputsRequest, _ ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	outputs, err := plugin.UnmarshalProperties(req.Outputs, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	if err != nil {
+		return nil, err
+	}
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.outputs[req.Urn] = outputs
+	return &emptypb.Empty{}, nil
+}
+
+func (m *fakeMonitor) Invoke(
+	_ context.Context, req *pulumirpc.ResourceInvokeRequest, _ ...grpc.CallOption,
+) (*pulumirpc.InvokeResponse, error) {
+	args, err := plugin.UnmarshalProperties(req.Args, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	if err != nil {
+		return nil, err
+	}
+	ret, err := plugin.MarshalProperties(m.invoke(req.Tok, args), plugin.MarshalOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.InvokeResponse{Return: ret}, nil
+}
+
+func (m *fakeMonitor) stackOutputs() resource.PropertyMap {
+	return m.outputs[m.urn(string(resource.RootStackType), "project-stack")]
+}
+
+func (m *fakeMonitor) inputs(t *testing.T, name string) resource.PropertyMap {
+	req, ok := m.byName[name]
+	require.True(t, ok, "resource %s was not registered", name)
+	inputs, err := plugin.UnmarshalProperties(req.Object, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	require.NoError(t, err)
+	return inputs
+}
+
+// bindProgram writes the given files to a temporary directory and binds them as a program.
+func bindProgram(t *testing.T, files map[string]string) (*pcl.Program, string) {
+	dir := t.TempDir()
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	}
+
+	loader := schema.NewPluginLoader(utils.NewHost(testdataPath))
+	program, diags, err := pcl.BindDirectory(dir, loader)
+	require.NoError(t, err)
+	require.False(t, diags.HasErrors(), "%v", diags)
+	return program, dir
+}
+
+func run(t *testing.T, monitor *fakeMonitor, files map[string]string, config map[string]string) error {
+	program, dir := bindProgram(t, files)
+	return Run(context.Background(), program, monitor, Options{
+		Project:       "project",
+		Stack:         "stack",
+		RootDirectory: dir,
+		Config:        config,
+		DryRun:        monitor.dryRun,
+	})
+}
+
+func TestRunResources(t *testing.T) {
+	t.Parallel()
+
+	monitor := newFakeMonitor(false)
+	err := run(t, monitor, map[string]string{"main.pp": `
+config prefix string {
+	default = "pet"
+}
+
+config length int {}
+
+names = ["a", "b"]
+
+resource pets "random:index/randomPet:RandomPet" {
+	options {
+		range = names
+	}
+	prefix = "${prefix}-${range.value}"
+	length = length
+}
+
+resource dependent "random:index/randomPet:RandomPet" {
+	prefix = pets[1].prefix
+	options {
+		dependsOn = pets
+		protect = true
+	}
+}
+
+output prefixes {
+	value = pets[*].prefix
+}
+
+output id {
+	value = dependent.id
+}
+`}, map[string]string{"project:length": "3"})
+	require.NoError(t, err)
+
+	assert.Equal(t, resource.PropertyMap{
+		"prefix": resource.NewStringProperty("pet-a"),
+		"length": resource.NewNumberProperty(3),
+	}, monitor.inputs(t, "pets-0"))
+	assert.Equal(t, resource.PropertyMap{
+		"prefix": resource.NewStringProperty("pet-b"),
+		"length": resource.NewNumberProperty(3),
+	}, monitor.inputs(t, "pets-1"))
+
+	dependent := monitor.byName["dependent"]
+	assert.Equal(t, "random:index/randomPet:RandomPet", dependent.Type)
+	assert.True(t, dependent.Custom)
+	assert.True(t, *dependent.Protect)
+	assert.Equal(t, "4.11.2", dependent.Version)
+	assert.Equal(t, monitor.urn(string(resource.RootStackType), "project-stack"), dependent.Parent)
+	pets := []string{monitor.urn(dependent.Type, "pets-0"), monitor.urn(dependent.Type, "pets-1")}
+	assert.ElementsMatch(t, pets, dependent.Dependencies)
+	assert.ElementsMatch(t, pets, dependent.PropertyDependencies["prefix"].Urns)
+
+	assert.Equal(t, resource.PropertyMap{
+		"prefixes": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewStringProperty("pet-a"),
+			resource.NewStringProperty("pet-b"),
+		}),
+		"id": resource.NewStringProperty("id-dependent"),
+	}, monitor.stackOutputs())
+}
+
+func TestRunPreview(t *testing.T) {
+	t.Parallel()
+
+	monitor := newFakeMonitor(true)
+	err := run(t, monitor, map[string]string{"main.pp": `
+resource pet "random:index/randomPet:RandomPet" {}
+
+resource other "random:index/randomPet:RandomPet" {
+	prefix = pet.id
+}
+
+output petId {
+	value = pet.id
+}
+
+output separator {
+	value = pet.separator
+}
+`}, nil)
+	require.NoError(t, err)
+
+	assert.True(t, monitor.inputs(t, "other")["prefix"].IsComputed())
+	outputs := monitor.stackOutputs()
+	assert.True(t, outputs["petId"].IsComputed())
+	assert.True(t, outputs["separator"].IsComputed())
+}
+
+func TestRunSecrets(t *testing.T) {
+	t.Parallel()
+
+	monitor := newFakeMonitor(false)
+	program, dir := bindProgram(t, map[string]string{"main.pp": `
+config password string {}
+
+resource pet "random:index/randomPet:RandomPet" {
+	prefix = "${password}!"
+	separator = unsecret(password)
+}
+
+output hidden {
+	value = secret("value")
+}
+`})
+	err := Run(context.Background(), program, monitor, Options{
+		Project:          "project",
+		Stack:            "stack",
+		RootDirectory:    dir,
+		Config:           map[string]string{"project:password": "hunter2"},
+		ConfigSecretKeys: []string{"project:password"},
+	})
+	require.NoError(t, err)
+
+	inputs := monitor.inputs(t, "pet")
+	assert.Equal(t, resource.MakeSecret(resource.NewStringProperty("hunter2!")), inputs["prefix"])
+	assert.Equal(t, resource.NewStringProperty("hunter2"), inputs["separator"])
+	assert.Equal(t, resource.MakeSecret(resource.NewStringProperty("value")), monitor.stackOutputs()["hidden"])
+}
+
+func TestRunInvoke(t *testing.T) {
+	t.Parallel()
+
+	monitor := newFakeMonitor(false)
+	monitor.invoke = func(tok string, args resource.PropertyMap) resource.PropertyMap {
+		assert.Equal(t, "std:index:Abs", tok)
+		return resource.PropertyMap{
+			"result": resource.NewNumberProperty(args["a"].NumberValue() + args["b"].NumberValue()),
+		}
+	}
+	err := run(t, monitor, map[string]string{"main.pp": `
+output result {
+	value = invoke("std:index:Abs", { a = 1, b = 2 }).result
+}
+`}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, resource.NewNumberProperty(3), monitor.stackOutputs()["result"])
+}
+
+func TestRunComponent(t *testing.T) {
+	t.Parallel()
+
+	monitor := newFakeMonitor(false)
+	err := run(t, monitor, map[string]string{
+		"main.pp": `
+component first "./pets" {
+	prefix = "first"
+}
+
+output separator {
+	value = first.separator
+}
+`,
+		"pets/main.pp": `
+config prefix string {}
+
+resource pet "random:index/randomPet:RandomPet" {
+	prefix = prefix
+	separator = "_"
+}
+
+output separator {
+	value = pet.separator
+}
+`,
+	}, nil)
+	require.NoError(t, err)
+
+	component := monitor.byName["first"]
+	assert.Equal(t, "components:index:Pets", component.Type)
+	assert.False(t, component.Custom)
+
+	pet := monitor.byName["first-pet"]
+	assert.Equal(t, monitor.urn(component.Type, "first"), pet.Parent)
+	assert.Equal(t, resource.NewStringProperty("first"), monitor.inputs(t, "first-pet")["prefix"])
+
+	assert.Equal(t, resource.PropertyMap{
+		"separator": resource.NewStringProperty("_"),
+	}, monitor.outputs[monitor.urn(component.Type, "first")])
+	assert.Equal(t, resource.NewStringProperty("_"), monitor.stackOutputs()["separator"])
+}
+
+func TestRunMissingConfig(t *testing.T) {
+	t.Parallel()
+
+	monitor := newFakeMonitor(false)
+	err := run(t, monitor, map[string]string{"main.pp": `
+config length int {}
+
+resource pet "random:index/randomPet:RandomPet" {
+	length = length
+}
+`}, nil)
+	assert.ErrorContains(t, err, `missing required configuration variable "length"`)
+	assert.NotContains(t, monitor.byName, "pet")
+}
+
+func TestRunFunctions(t *testing.T) {
+	t.Parallel()
+
+	monitor := newFakeMonitor(false)
+	err := run(t, monitor, map[string]string{
+		"main.pp": `
+output outputs {
+	value = {
+		join = join("-", ["a", "b"])
+		split = split(",", "a,b")
+		length = length([1, 2, 3])
+		element = element(["a", "b"], 3)
+		lookup = lookup({ a = "x" }, "b", "default")
+		json = toJSON({ a = [1, 2] })
+		base64 = fromBase64(toBase64("hello"))
+		file = readFile("file.txt")
+		subnet = cidrsubnet("10.0.0.0/16", 8, 2)
+		format = format("%s-%d", "a", 1)
+		replace = replace("a.b.c", ".", "/")
+		stack = stack()
+		sha1 = sha1("hello")
+		entries = [for e in entries({ a = 1 }) : e.key]
+		conditional = true ? "yes" : "no"
+	}
+}
+`,
+		"file.txt": "contents",
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, resource.NewPropertyValue(map[string]interface{}{
+		"join":        "a-b",
+		"split":       []interface{}{"a", "b"},
+		"length":      3,
+		"element":     "b",
+		"lookup":      "default",
+		"json":        `{"a":[1,2]}`,
+		"base64":      "hello",
+		"file":        "contents",
+		"subnet":      "10.0.2.0/24",
+		"format":      "a-1",
+		"replace":     "a/b/c",
+		"stack":       "stack",
+		"sha1":        "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+		"entries":     []interface{}{"a"},
+		"conditional": "yes",
+	}), monitor.stackOutputs()["outputs"])
+}
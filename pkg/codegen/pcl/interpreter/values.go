@@ -0,0 +1,213 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interpreter
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// secretMark marks values that are secret. Marks propagate through operations and function calls, so anything
+// computed from a secret is itself secret.
+type valueMark string
+
+const secretMark valueMark = "secret"
+
+// assetType is the type of asset and archive values. The capsule holds the resource.PropertyValue of the asset or
+// archive, so that it can be passed to the resource monitor as-is.
+var assetType = cty.Capsule("asset", reflect.TypeOf(resource.PropertyValue{}))
+
+func assetVal(v resource.PropertyValue) cty.Value {
+	return cty.CapsuleVal(assetType, &v)
+}
+
+// isSecret returns true if the value or any value nested within it is secret.
+func isSecret(v cty.Value) bool {
+	_, marks := v.UnmarkDeep()
+	_, ok := marks[secretMark]
+	return ok
+}
+
+// toPropertyValue converts an evaluated value to a property value that can be sent to the resource monitor.
+func toPropertyValue(v cty.Value) (resource.PropertyValue, error) {
+	v, marks := v.Unmark()
+	if _, ok := marks[secretMark]; ok {
+		pv, err := toPropertyValue(v)
+		if err != nil {
+			return resource.PropertyValue{}, err
+		}
+		return resource.MakeSecret(pv), nil
+	}
+
+	if !v.IsKnown() {
+		return resource.MakeComputed(resource.NewStringProperty("")), nil
+	}
+	if v.IsNull() {
+		return resource.NewNullProperty(), nil
+	}
+
+	typ := v.Type()
+	switch {
+	case typ == cty.Bool:
+		return resource.NewBoolProperty(v.True()), nil
+	case typ == cty.Number:
+		f, _ := v.AsBigFloat().Float64()
+		return resource.NewNumberProperty(f), nil
+	case typ == cty.String:
+		return resource.NewStringProperty(v.AsString()), nil
+	case typ == assetType:
+		return *v.EncapsulatedValue().(*resource.PropertyValue), nil
+	case typ.IsListType() || typ.IsTupleType() || typ.IsSetType():
+		elements := make([]resource.PropertyValue, 0, v.LengthInt())
+		for it := v.ElementIterator(); it.Next(); {
+			_, e := it.Element()
+			pv, err := toPropertyValue(e)
+			if err != nil {
+				return resource.PropertyValue{}, err
+			}
+			elements = append(elements, pv)
+		}
+		return resource.NewArrayProperty(elements), nil
+	case typ.IsMapType() || typ.IsObjectType():
+		m, err := toPropertyMap(v)
+		if err != nil {
+			return resource.PropertyValue{}, err
+		}
+		return resource.NewObjectProperty(m), nil
+	default:
+		return resource.PropertyValue{}, fmt.Errorf("cannot convert a value of type %s", typ.FriendlyName())
+	}
+}
+
+// toPropertyMap converts an evaluated object or map to a property map. Null values are omitted, as they are when
+// properties are left unset in the language SDKs.
+func toPropertyMap(v cty.Value) (resource.PropertyMap, error) {
+	m := resource.PropertyMap{}
+	for it := v.ElementIterator(); it.Next(); {
+		k, e := it.Element()
+		if e.IsNull() {
+			continue
+		}
+		pv, err := toPropertyValue(e)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k.AsString(), err)
+		}
+		m[resource.PropertyKey(k.AsString())] = pv
+	}
+	return m, nil
+}
+
+// fromPropertyValue converts a property value returned by the resource monitor to a value, using the given type to
+// fill in properties that are missing from objects. Missing properties are unknown during a preview, and null
+// otherwise.
+func fromPropertyValue(v resource.PropertyValue, t model.Type, dryRun bool) cty.Value {
+	t = pcl.UnwrapOption(model.ResolveOutputs(t))
+
+	switch {
+	case v.IsSecret():
+		return fromPropertyValue(v.SecretValue().Element, t, dryRun).Mark(secretMark)
+	case v.IsComputed():
+		return cty.DynamicVal
+	case v.IsOutput():
+		o := v.OutputValue()
+		var result cty.Value
+		if !o.Known {
+			result = cty.DynamicVal
+		} else {
+			result = fromPropertyValue(o.Element, t, dryRun)
+		}
+		if o.Secret {
+			result = result.Mark(secretMark)
+		}
+		return result
+	case v.IsNull():
+		return cty.NullVal(cty.DynamicPseudoType)
+	case v.IsBool():
+		return cty.BoolVal(v.BoolValue())
+	case v.IsNumber():
+		return cty.NumberVal(big.NewFloat(v.NumberValue()))
+	case v.IsString():
+		return cty.StringVal(v.StringValue())
+	case v.IsAsset(), v.IsArchive():
+		return assetVal(v)
+	case v.IsResourceReference():
+		ref := v.ResourceReferenceValue()
+		return cty.ObjectVal(map[string]cty.Value{
+			"urn": cty.StringVal(string(ref.URN)),
+			"id":  fromPropertyValue(ref.ID, model.StringType, dryRun),
+		})
+	case v.IsArray():
+		var elementType model.Type = model.DynamicType
+		if t, ok := t.(*model.ListType); ok {
+			elementType = t.ElementType
+		}
+		arr := v.ArrayValue()
+		elements := make([]cty.Value, len(arr))
+		for i, e := range arr {
+			typ := elementType
+			if t, ok := t.(*model.TupleType); ok && i < len(t.ElementTypes) {
+				typ = t.ElementTypes[i]
+			}
+			elements[i] = fromPropertyValue(e, typ, dryRun)
+		}
+		return cty.TupleVal(elements)
+	case v.IsObject():
+		return cty.ObjectVal(objectAttributes(v.ObjectValue(), t, dryRun))
+	default:
+		return cty.DynamicVal
+	}
+}
+
+// objectAttributes converts a property map returned by the resource monitor to the attributes of an object. See
+// fromPropertyValue.
+func objectAttributes(m resource.PropertyMap, t model.Type, dryRun bool) map[string]cty.Value {
+	t = pcl.UnwrapOption(model.ResolveOutputs(t))
+
+	attrs := map[string]cty.Value{}
+	switch t := t.(type) {
+	case *model.ObjectType:
+		for k, pt := range t.Properties {
+			if dryRun {
+				attrs[k] = cty.DynamicVal
+			} else {
+				attrs[k] = cty.NullVal(cty.DynamicPseudoType)
+			}
+			if v, ok := m[resource.PropertyKey(k)]; ok {
+				attrs[k] = fromPropertyValue(v, pt, dryRun)
+			}
+		}
+		for k, v := range m {
+			if _, ok := t.Properties[string(k)]; !ok {
+				attrs[string(k)] = fromPropertyValue(v, model.DynamicType, dryRun)
+			}
+		}
+	case *model.MapType:
+		for k, v := range m {
+			attrs[string(k)] = fromPropertyValue(v, t.ElementType, dryRun)
+		}
+	default:
+		for k, v := range m {
+			attrs[string(k)] = fromPropertyValue(v, model.DynamicType, dryRun)
+		}
+	}
+	return attrs
+}
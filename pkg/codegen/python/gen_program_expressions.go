@@ -83,8 +83,13 @@ func (g *generator) GetPrecedence(expr model.Expression) int {
 		}
 	case *model.UnaryOpExpression:
 		return 13
-	case *model.FunctionCallExpression, *model.IndexExpression, *model.RelativeTraversalExpression,
-		*model.TemplateJoinExpression:
+	case *model.FunctionCallExpression:
+		// format is generated as a use of the % operator rather than as a call.
+		if expr.Name == "format" {
+			return 12
+		}
+		return 16
+	case *model.IndexExpression, *model.RelativeTraversalExpression, *model.TemplateJoinExpression:
 		return 16
 	case *model.ForExpression, *model.ObjectConsExpression, *model.SplatExpression, *model.TupleConsExpression:
 		return 17
@@ -226,6 +231,14 @@ var functionImports = map[string][]string{
 	"fromBase64":       {"base64"},
 	"toJSON":           {"json"},
 	"sha1":             {"hashlib"},
+	"md5":              {"hashlib"},
+	"sha256":           {"hashlib"},
+	"sha512":           {"hashlib"},
+	"base64sha256":     {"base64", "hashlib"},
+	"cidrsubnet":       {"ipaddress"},
+	"ceil":             {"math"},
+	"floor":            {"math"},
+	"urlEncode":        {"urllib.parse"},
 	"stack":            {"pulumi"},
 	"project":          {"pulumi"},
 	"organization":     {"pulumi"},
@@ -496,8 +509,54 @@ func (g *generator) GenFunctionCallExpression(w io.Writer, expr *model.FunctionC
 		} else {
 			g.Fgenf(w, "json.dumps(%.v)", expr.Args[0])
 		}
-	case "sha1":
-		g.Fgenf(w, "hashlib.sha1(%v.encode()).hexdigest()", expr.Args[0])
+	case "sha1", "md5", "sha256", "sha512":
+		g.Fgenf(w, "hashlib.%s(%.16v.encode()).hexdigest()", expr.Name, expr.Args[0])
+	case "base64sha256":
+		g.Fgenf(w, "base64.b64encode(hashlib.sha256(%.16v.encode()).digest()).decode()", expr.Args[0])
+	case "cidrsubnet":
+		// Assuming the existence of the following helper method
+		g.Fgenf(w, "cidrsubnet(%.v, %.v, %.v)", expr.Args[0], expr.Args[1], expr.Args[2])
+	case "upper":
+		g.Fgenf(w, "%.16v.upper()", expr.Args[0])
+	case "lower":
+		g.Fgenf(w, "%.16v.lower()", expr.Args[0])
+	case "trimSpace":
+		g.Fgenf(w, "%.16v.strip()", expr.Args[0])
+	case "replace":
+		g.Fgenf(w, "%.16v.replace(%.v, %.v)", expr.Args[0], expr.Args[1], expr.Args[2])
+	case "format":
+		g.Fgenf(w, "%.13v %% (", expr.Args[0])
+		for i, arg := range expr.Args[1:] {
+			if i > 0 {
+				g.Fgen(w, ", ")
+			}
+			g.Fgenf(w, "%.v", arg)
+		}
+		// A single argument still needs to be passed as a tuple.
+		if len(expr.Args) == 2 {
+			g.Fgen(w, ",")
+		}
+		g.Fgen(w, ")")
+	case "min", "max":
+		// Python's min and max take an iterable when they're given a single argument.
+		if len(expr.Args) == 1 {
+			g.Fgenf(w, "%v", expr.Args[0])
+			return
+		}
+		g.Fgenf(w, "%s(", expr.Name)
+		for i, arg := range expr.Args {
+			if i > 0 {
+				g.Fgen(w, ", ")
+			}
+			g.Fgenf(w, "%.v", arg)
+		}
+		g.Fgen(w, ")")
+	case "abs":
+		g.Fgenf(w, "abs(%.v)", expr.Args[0])
+	case "ceil", "floor":
+		g.Fgenf(w, "math.%s(%.v)", expr.Name, expr.Args[0])
+	case "urlEncode":
+		g.Fgenf(w, "urllib.parse.quote(%.v, safe=\"\")", expr.Args[0])
 	case "project":
 		g.Fgen(w, "pulumi.get_project()")
 	case "stack":
@@ -27,6 +27,17 @@ func getHelperMethodIfNeeded(functionName string, indent string) (string, bool)
 	fileData = open(path).read().encode()
 	hashedData = hashlib.sha256(fileData.encode()).digest()
 	return base64.b64encode(hashedData).decode()`, true
+	case "cidrsubnet":
+		return fmt.Sprintf(`%[1]sdef cidrsubnet(prefix, newbits, netnum):
+%[1]s    network = ipaddress.ip_network(prefix, strict=False)
+%[1]s    prefixlen = network.prefixlen + newbits
+%[1]s    if prefixlen > network.max_prefixlen or netnum < 0 or netnum >= 2 ** newbits:
+%[1]s        raise ValueError(f"cidrsubnet: can't make subnet {netnum} with {newbits} more bits in {prefix}")
+%[1]s    address = int(network.network_address) + (netnum << (network.max_prefixlen - prefixlen))
+%[1]s    return str(type(network)((address, prefixlen)))
+`,
+			indent,
+		), true
 	case "notImplemented":
 		return fmt.Sprintf(`
 %sdef not_implemented(msg):
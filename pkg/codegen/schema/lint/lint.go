@@ -0,0 +1,171 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint checks bound package schemas for problems that aren't errors, but that make a package harder to use,
+// such as missing documentation or secrets that aren't marked as such.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+// Severity is the severity of a finding.
+type Severity string
+
+const (
+	// Warning findings are reported, but don't fail a check.
+	Warning Severity = "warning"
+	// Error findings fail a check.
+	Error Severity = "error"
+)
+
+// A Finding is a single problem reported by a rule.
+type Finding struct {
+	// Rule is the name of the rule that reported the problem.
+	Rule string
+	// Severity is the severity of the problem.
+	Severity Severity
+	// Token is the token of the resource, function or type that has the problem.
+	Token string
+	// Path is the path to the property or value within the token that has the problem, if any.
+	Path string
+	// Message describes the problem.
+	Message string
+}
+
+// Diagnostic returns the finding as a diagnostic.
+func (f Finding) Diagnostic() *hcl.Diagnostic {
+	severity := hcl.DiagWarning
+	if f.Severity == Error {
+		severity = hcl.DiagError
+	}
+	location := f.Token
+	if f.Path != "" {
+		location += " " + f.Path
+	}
+	return &hcl.Diagnostic{
+		Severity: severity,
+		Summary:  fmt.Sprintf("%s: %s [%s]", location, f.Message, f.Rule),
+	}
+}
+
+// A Reporter is called by a rule for each problem it finds.
+type Reporter func(token, path, message string)
+
+// A Rule checks a package for one kind of problem.
+type Rule struct {
+	// Name is the name that the rule is configured by.
+	Name string
+	// Description describes what the rule checks.
+	Description string
+	// Severity is the severity of the rule's findings, unless it is configured otherwise.
+	Severity Severity
+	// Check checks the package, calling report for each problem.
+	Check func(pkg *schema.Package, report Reporter)
+}
+
+// Config configures the rules that are run.
+type Config struct {
+	// Rules configures individual rules, keyed by name. Rules that aren't listed run with their defaults.
+	Rules map[string]RuleConfig `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// RuleConfig configures a single rule.
+type RuleConfig struct {
+	// Disabled turns the rule off.
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// Severity overrides the severity of the rule's findings.
+	Severity Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+	// Ignore suppresses the rule's findings for the given tokens. An entry that ends in "*" matches every token that
+	// starts with the rest of the entry.
+	Ignore []string `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+}
+
+func (c RuleConfig) ignores(token string) bool {
+	for _, pattern := range c.Ignore {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(token, prefix) {
+				return true
+			}
+		} else if token == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint runs the given rules against a package and returns their findings, sorted by token and path. An error is
+// returned if the configuration refers to a rule that doesn't exist or is otherwise invalid.
+func Lint(pkg *schema.Package, rules []Rule, config Config) ([]Finding, error) {
+	known := map[string]bool{}
+	for _, r := range rules {
+		known[r.Name] = true
+	}
+	for name, c := range config.Rules {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+		if c.Severity != "" && c.Severity != Warning && c.Severity != Error {
+			return nil, fmt.Errorf("invalid severity %q for lint rule %q, expected %q or %q",
+				c.Severity, name, Warning, Error)
+		}
+	}
+
+	var findings []Finding
+	for _, r := range rules {
+		c := config.Rules[r.Name]
+		if c.Disabled {
+			continue
+		}
+		severity := r.Severity
+		if c.Severity != "" {
+			severity = c.Severity
+		}
+		r.Check(pkg, func(token, path, message string) {
+			if c.ignores(token) {
+				return
+			}
+			findings = append(findings, Finding{
+				Rule:     r.Name,
+				Severity: severity,
+				Token:    token,
+				Path:     path,
+				Message:  message,
+			})
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Token != findings[j].Token {
+			return findings[i].Token < findings[j].Token
+		}
+		return findings[i].Path < findings[j].Path
+	})
+	return findings, nil
+}
+
+// Diagnostics converts findings to diagnostics.
+func Diagnostics(findings []Finding) hcl.Diagnostics {
+	diags := make(hcl.Diagnostics, len(findings))
+	for i, f := range findings {
+		diags[i] = f.Diagnostic()
+	}
+	return diags
+}
@@ -0,0 +1,166 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+func property(typ, description string) schema.PropertySpec {
+	return schema.PropertySpec{TypeSpec: schema.TypeSpec{Type: typ}, Description: description}
+}
+
+func bindPackage(t *testing.T) *schema.Package {
+	pkg, err := schema.ImportSpec(schema.PackageSpec{
+		Name: "pets",
+		Provider: schema.ResourceSpec{
+			ObjectTypeSpec: schema.ObjectTypeSpec{Description: "The provider for pets."},
+		},
+		Resources: map[string]schema.ResourceSpec{
+			"pets:index:Dog": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{
+					Description: "A dog.",
+					Properties: map[string]schema.PropertySpec{
+						"name":     property("string", "The dog's name."),
+						"owner_id": property("string", "The owner of the dog."),
+						"collar": {
+							TypeSpec:    schema.TypeSpec{Ref: "#/types/pets:index:Collar"},
+							Description: "The dog's collar.",
+						},
+					},
+				},
+				InputProperties: map[string]schema.PropertySpec{
+					"name":       property("string", "The dog's name."),
+					"apiToken":   property("string", "The token used to register the dog."),
+					"tokenCount": property("integer", "The number of tokens the dog has."),
+					"pageToken":  property("string", "The page of dogs to list from."),
+					"nextToken":  property("string", "The page of dogs to list next."),
+					"breed": {
+						TypeSpec:           schema.TypeSpec{Type: "string"},
+						Description:        "Deprecated: dogs no longer have breeds.",
+						DeprecationMessage: "",
+					},
+				},
+			},
+		},
+		Types: map[string]schema.ComplexTypeSpec{
+			"pets:index:Collar": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{
+					Type:       "object",
+					Properties: map[string]schema.PropertySpec{"color": property("string", "")},
+				},
+			},
+			"pets:index:Toy": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{
+					Type:        "object",
+					Description: "A toy.",
+				},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	return pkg
+}
+
+func TestDefaultRules(t *testing.T) {
+	t.Parallel()
+
+	findings, err := Lint(bindPackage(t), DefaultRules, Config{})
+	require.NoError(t, err)
+
+	type finding struct{ rule, location string }
+	actual := make([]finding, len(findings))
+	for i, f := range findings {
+		assert.Equal(t, Warning, f.Severity)
+		actual[i] = finding{f.Rule, f.Token + " " + f.Path}
+	}
+	assert.ElementsMatch(t, []finding{
+		{"missing-description", "pets:index:Collar "},
+		{"missing-description", "pets:index:Collar properties.color"},
+		{"deprecated-without-message", "pets:index:Dog inputs.breed"},
+		{"readable-outputs", "pets:index:Dog inputs.breed"},
+		{"readable-outputs", "pets:index:Dog inputs.apiToken"},
+		{"unmarked-secret", "pets:index:Dog inputs.apiToken"},
+		{"readable-outputs", "pets:index:Dog inputs.tokenCount"},
+		// Pagination tokens aren't secrets.
+		{"readable-outputs", "pets:index:Dog inputs.pageToken"},
+		{"readable-outputs", "pets:index:Dog inputs.nextToken"},
+		{"property-casing", "pets:index:Dog properties.owner_id"},
+		{"unreachable-type", "pets:index:Toy "},
+	}, actual)
+}
+
+func TestLintConfig(t *testing.T) {
+	t.Parallel()
+
+	pkg := bindPackage(t)
+
+	findings, err := Lint(pkg, DefaultRules, Config{
+		Rules: map[string]RuleConfig{
+			"readable-outputs":    {Disabled: true},
+			"missing-description": {Ignore: []string{"pets:index:Col*"}},
+			"unreachable-type":    {Ignore: []string{"pets:index:Toy"}},
+			"unmarked-secret":     {Severity: Error},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, findings, 3)
+	assert.Equal(t, "unmarked-secret", findings[0].Rule)
+	assert.Equal(t, Error, findings[0].Severity)
+	assert.Equal(t, "deprecated-without-message", findings[1].Rule)
+	assert.Equal(t, "property-casing", findings[2].Rule)
+
+	diag := findings[0].Diagnostic()
+	assert.Equal(t, hcl.DiagError, diag.Severity)
+	assert.Equal(t, "pets:index:Dog inputs.apiToken: property looks like a secret but is not marked secret "+
+		"[unmarked-secret]", diag.Summary)
+
+	_, err = Lint(pkg, DefaultRules, Config{Rules: map[string]RuleConfig{"no-such-rule": {}}})
+	assert.ErrorContains(t, err, `unknown lint rule "no-such-rule"`)
+
+	_, err = Lint(pkg, DefaultRules, Config{Rules: map[string]RuleConfig{"unmarked-secret": {Severity: "fatal"}}})
+	assert.ErrorContains(t, err, `invalid severity "fatal"`)
+}
+
+func TestCustomRule(t *testing.T) {
+	t.Parallel()
+
+	rule := Rule{
+		Name:     "no-dogs",
+		Severity: Error,
+		Check: func(pkg *schema.Package, report Reporter) {
+			for _, r := range pkg.Resources {
+				if r.Token == "pets:index:Dog" {
+					report(r.Token, "", "dogs are not allowed")
+				}
+			}
+		},
+	}
+
+	findings, err := Lint(bindPackage(t), []Rule{rule}, Config{})
+	require.NoError(t, err)
+	assert.Equal(t, []Finding{{
+		Rule:     "no-dogs",
+		Severity: Error,
+		Token:    "pets:index:Dog",
+		Message:  "dogs are not allowed",
+	}}, findings)
+}
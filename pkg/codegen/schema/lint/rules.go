@@ -0,0 +1,309 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+// DefaultRules are the rules that are run by `pulumi schema check --lint`.
+var DefaultRules = []Rule{
+	{
+		Name:        "deprecated-without-message",
+		Description: "Entries that are documented as deprecated must have a deprecation message",
+		Severity:    Warning,
+		Check:       checkDeprecations,
+	},
+	{
+		Name:        "missing-description",
+		Description: "Resources, functions, types and properties should have descriptions",
+		Severity:    Warning,
+		Check:       checkDescriptions,
+	},
+	{
+		Name:        "property-casing",
+		Description: "Property names should be camelCase",
+		Severity:    Warning,
+		Check:       checkPropertyCasing,
+	},
+	{
+		Name:        "readable-outputs",
+		Description: "The inputs of custom resources should also be outputs, so that they can be read back by ID",
+		Severity:    Warning,
+		Check:       checkReadableOutputs,
+	},
+	{
+		Name:        "unmarked-secret",
+		Description: "Properties that are named like secrets should be marked secret",
+		Severity:    Warning,
+		Check:       checkSecrets,
+	},
+	{
+		Name:        "unreachable-type",
+		Description: "Types should be used by a resource, function or configuration variable",
+		Severity:    Warning,
+		Check:       checkReachability,
+	},
+}
+
+// resources returns the resources of a package, including its provider.
+func resources(pkg *schema.Package) []*schema.Resource {
+	if pkg.Provider == nil {
+		return pkg.Resources
+	}
+	return append([]*schema.Resource{pkg.Provider}, pkg.Resources...)
+}
+
+// namedTypes returns the object types and enums of a package. The input shapes of object types are skipped, as they
+// share their token and properties with the plain shape.
+func namedTypes(pkg *schema.Package) (objects []*schema.ObjectType, enums []*schema.EnumType) {
+	for _, t := range pkg.Types {
+		switch t := t.(type) {
+		case *schema.ObjectType:
+			if t.PlainShape == nil {
+				objects = append(objects, t)
+			}
+		case *schema.EnumType:
+			enums = append(enums, t)
+		}
+	}
+	return objects, enums
+}
+
+// A propertyList is a list of properties in a package, along with the token and path prefix that locate them.
+type propertyList struct {
+	token      string
+	prefix     string
+	properties []*schema.Property
+	// isType is true if the properties belong to an object type.
+	isType bool
+}
+
+func propertyLists(pkg *schema.Package) []propertyList {
+	lists := []propertyList{{token: "pulumi:config", prefix: "config.", properties: pkg.Config}}
+	for _, r := range resources(pkg) {
+		lists = append(lists,
+			propertyList{token: r.Token, prefix: "inputs.", properties: r.InputProperties},
+			propertyList{token: r.Token, prefix: "properties.", properties: r.Properties})
+	}
+	for _, f := range pkg.Functions {
+		if f.Inputs != nil {
+			lists = append(lists, propertyList{token: f.Token, prefix: "inputs.", properties: f.Inputs.Properties})
+		}
+		if f.Outputs != nil {
+			lists = append(lists, propertyList{token: f.Token, prefix: "outputs.", properties: f.Outputs.Properties})
+		}
+	}
+	objects, _ := namedTypes(pkg)
+	for _, t := range objects {
+		lists = append(lists, propertyList{
+			token:      t.Token,
+			prefix:     "properties.",
+			properties: t.Properties,
+			isType:     true,
+		})
+	}
+	return lists
+}
+
+func checkDescriptions(pkg *schema.Package, report Reporter) {
+	for _, r := range resources(pkg) {
+		if r.Comment == "" {
+			report(r.Token, "", "resource has no description")
+		}
+	}
+	for _, f := range pkg.Functions {
+		if f.Comment == "" && !f.IsMethod {
+			report(f.Token, "", "function has no description")
+		}
+	}
+	objects, enums := namedTypes(pkg)
+	for _, t := range objects {
+		if t.Comment == "" {
+			report(t.Token, "", "type has no description")
+		}
+	}
+	for _, t := range enums {
+		if t.Comment == "" {
+			report(t.Token, "", "enum has no description")
+		}
+	}
+	for _, l := range propertyLists(pkg) {
+		for _, p := range l.properties {
+			if p.Comment == "" {
+				report(l.token, l.prefix+p.Name, "property has no description")
+			}
+		}
+	}
+}
+
+var camelCase = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+func checkPropertyCasing(pkg *schema.Package, report Reporter) {
+	for _, l := range propertyLists(pkg) {
+		for _, p := range l.properties {
+			if !camelCase.MatchString(p.Name) {
+				report(l.token, l.prefix+p.Name, fmt.Sprintf("property name %q is not camelCase", p.Name))
+			}
+		}
+	}
+}
+
+func checkReadableOutputs(pkg *schema.Package, report Reporter) {
+	for _, r := range pkg.Resources {
+		// Components can't be read, and overlays are implemented by hand.
+		if r.IsComponent || r.IsOverlay {
+			continue
+		}
+		outputs := map[string]bool{}
+		for _, p := range r.Properties {
+			outputs[p.Name] = true
+		}
+		for _, p := range r.InputProperties {
+			if !outputs[p.Name] {
+				report(r.Token, "inputs."+p.Name,
+					"input property is not an output, so it can't be read back when the resource is looked up by ID")
+			}
+		}
+	}
+}
+
+// secretSuffixes are the suffixes of the lower-cased names of properties that are likely to hold secrets. Tokens are
+// only matched with a qualifier, since names like nextToken and pageToken are pagination cursors rather than secrets.
+var secretSuffixes = []string{
+	"accesskey", "accesstoken", "apikey", "apitoken", "authtoken", "passphrase", "password", "privatekey",
+	"refreshtoken", "secret", "secretkey",
+}
+
+func isStringType(t schema.Type) bool {
+	switch t := t.(type) {
+	case *schema.InputType:
+		return isStringType(t.ElementType)
+	case *schema.OptionalType:
+		return isStringType(t.ElementType)
+	default:
+		return t == schema.StringType
+	}
+}
+
+func checkSecrets(pkg *schema.Package, report Reporter) {
+	for _, l := range propertyLists(pkg) {
+		for _, p := range l.properties {
+			if p.Secret || !isStringType(p.Type) {
+				continue
+			}
+			name := strings.ToLower(p.Name)
+			for _, suffix := range secretSuffixes {
+				if strings.HasSuffix(name, suffix) {
+					report(l.token, l.prefix+p.Name, "property looks like a secret but is not marked secret")
+					break
+				}
+			}
+		}
+	}
+}
+
+func checkReachability(pkg *schema.Package, report Reporter) {
+	reachable := map[string]bool{}
+	var visit func(t schema.Type)
+	visit = func(t schema.Type) {
+		switch t := t.(type) {
+		case *schema.InputType:
+			visit(t.ElementType)
+		case *schema.OptionalType:
+			visit(t.ElementType)
+		case *schema.ArrayType:
+			visit(t.ElementType)
+		case *schema.MapType:
+			visit(t.ElementType)
+		case *schema.UnionType:
+			for _, e := range t.ElementTypes {
+				visit(e)
+			}
+		case *schema.EnumType:
+			reachable[t.Token] = true
+		case *schema.ObjectType:
+			if reachable[t.Token] {
+				return
+			}
+			reachable[t.Token] = true
+			for _, p := range t.Properties {
+				visit(p.Type)
+			}
+		}
+	}
+
+	for _, l := range propertyLists(pkg) {
+		// Object types are only reachable if something else refers to them.
+		if l.isType {
+			continue
+		}
+		for _, p := range l.properties {
+			visit(p.Type)
+		}
+	}
+	for _, f := range pkg.Functions {
+		if f.ReturnType != nil {
+			visit(f.ReturnType)
+		}
+	}
+
+	objects, enums := namedTypes(pkg)
+	for _, t := range objects {
+		if !reachable[t.Token] {
+			report(t.Token, "", "type is not used by any resource, function or configuration variable")
+		}
+	}
+	for _, t := range enums {
+		if !reachable[t.Token] {
+			report(t.Token, "", "enum is not used by any resource, function or configuration variable")
+		}
+	}
+}
+
+var deprecatedComment = regexp.MustCompile(`(?im)^\s*(\*\*)?deprecated\b`)
+
+func checkDeprecations(pkg *schema.Package, report Reporter) {
+	check := func(token, path, comment, message string) {
+		switch {
+		case message != "" && strings.TrimSpace(message) == "":
+			report(token, path, "deprecation message is empty")
+		case message == "" && deprecatedComment.MatchString(comment):
+			report(token, path, "description says this is deprecated, but it has no deprecation message")
+		}
+	}
+
+	for _, r := range resources(pkg) {
+		check(r.Token, "", r.Comment, r.DeprecationMessage)
+	}
+	for _, f := range pkg.Functions {
+		check(f.Token, "", f.Comment, f.DeprecationMessage)
+	}
+	_, enums := namedTypes(pkg)
+	for _, t := range enums {
+		for _, e := range t.Elements {
+			check(t.Token, fmt.Sprintf("values.%v", e.Value), e.Comment, e.DeprecationMessage)
+		}
+	}
+	for _, l := range propertyLists(pkg) {
+		for _, p := range l.properties {
+			check(l.token, l.prefix+p.Name, p.Comment, p.DeprecationMessage)
+		}
+	}
+}
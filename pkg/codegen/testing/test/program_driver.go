@@ -228,6 +228,10 @@ var PulumiPulumiProgramTests = []ProgramTest{
 		Directory:   "functions",
 		Description: "Functions",
 	},
+	{
+		Directory:   "intrinsic-functions",
+		Description: "String, math, encoding, hashing and network functions",
+	},
 	{
 		Directory:   "output-funcs-aws",
 		Description: "Output Versioned Functions",
@@ -225,6 +225,8 @@ func newDeployment(
 		Parallel:                  opts.Parallel,
 		Refresh:                   opts.Refresh,
 		RefreshOnly:               opts.isRefresh,
+		Resume:                    opts.Resume,
+		RollbackOnFailure:         opts.RollbackOnFailure,
 		DestroyProgram:            opts.DestroyProgram,
 		ReplaceTargets:            opts.ReplaceTargets,
 		Targets:                   opts.Targets,
@@ -238,6 +240,8 @@ func newDeployment(
 		GeneratePlan:              opts.UpdateOptions.GeneratePlan,
 		ContinueOnError:           opts.ContinueOnError,
 		Autonamer:                 opts.Autonamer,
+		Waves:                     opts.Waves,
+		WaveApprover:              opts.WaveApprover,
 	}
 
 	var depl *deploy.Deployment
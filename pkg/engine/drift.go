@@ -0,0 +1,151 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// NewDriftReport builds a drift report from the events of a refresh preview. Each resource whose refresh found that
+// it had changed or no longer exists is reported along with the paths of the properties that have drifted.
+func NewDriftReport(events []Event) apitype.DriftReport {
+	report := apitype.DriftReport{Resources: []apitype.ResourceDrift{}}
+	for _, e := range events {
+		payload, ok := e.Payload().(ResourceOutputsEventPayload)
+		if !ok || payload.Internal {
+			continue
+		}
+
+		// The outputs of a refresh step are reported with the operation that the refresh amounts to.
+		metadata := payload.Metadata
+		switch metadata.Op {
+		case deploy.OpDelete:
+			report.Resources = append(report.Resources, apitype.ResourceDrift{
+				URN:  string(metadata.URN),
+				Type: string(metadata.Type),
+				Kind: apitype.DriftDeleted,
+			})
+		case deploy.OpUpdate:
+			report.Resources = append(report.Resources, apitype.ResourceDrift{
+				URN:        string(metadata.URN),
+				Type:       string(metadata.Type),
+				Kind:       apitype.DriftModified,
+				Properties: propertyDrift(&metadata),
+			})
+		}
+	}
+
+	slices.SortFunc(report.Resources, func(a, b apitype.ResourceDrift) int {
+		return strings.Compare(a.URN, b.URN)
+	})
+	return report
+}
+
+// propertyDrift returns the properties that have drifted according to the given refresh step.
+func propertyDrift(metadata *StepEventMetadata) []apitype.PropertyDrift {
+	if metadata.Old == nil || metadata.New == nil {
+		return nil
+	}
+
+	// Refreshes of external resources, and refreshes that use the legacy diff, don't ask the provider for a diff and
+	// only compare the resource's outputs.
+	var diff *resource.ObjectDiff
+	if metadata.DetailedDiff != nil {
+		diff = TranslateDetailedDiff(metadata, true /*refresh*/)
+	} else {
+		diff = metadata.Old.Outputs.Diff(metadata.New.Outputs)
+	}
+
+	var properties []apitype.PropertyDrift
+	addProperty := func(path resource.PropertyPath, kind apitype.DiffKind) {
+		properties = append(properties, apitype.PropertyDrift{
+			Path:      path.String(),
+			Kind:      kind,
+			InputDiff: isInputDiff(metadata, path),
+		})
+	}
+
+	var walkObject func(path resource.PropertyPath, diff *resource.ObjectDiff)
+	var walkValue func(path resource.PropertyPath, diff resource.ValueDiff)
+	walkObject = func(path resource.PropertyPath, diff *resource.ObjectDiff) {
+		for k := range diff.Adds {
+			addProperty(appendPath(path, string(k)), apitype.DiffAdd)
+		}
+		for k := range diff.Deletes {
+			addProperty(appendPath(path, string(k)), apitype.DiffDelete)
+		}
+		for k, update := range diff.Updates {
+			walkValue(appendPath(path, string(k)), update)
+		}
+	}
+	walkValue = func(path resource.PropertyPath, diff resource.ValueDiff) {
+		switch {
+		case diff.Object != nil:
+			walkObject(path, diff.Object)
+		case diff.Array != nil:
+			for i := range diff.Array.Adds {
+				addProperty(appendPath(path, i), apitype.DiffAdd)
+			}
+			for i := range diff.Array.Deletes {
+				addProperty(appendPath(path, i), apitype.DiffDelete)
+			}
+			for i, update := range diff.Array.Updates {
+				walkValue(appendPath(path, i), update)
+			}
+		default:
+			addProperty(path, apitype.DiffUpdate)
+		}
+	}
+	if diff != nil {
+		walkObject(nil, diff)
+	}
+
+	// If the provider reported changes without saying where they are, fall back to the keys that it reported.
+	if len(properties) == 0 {
+		for _, k := range metadata.Diffs {
+			addProperty(resource.PropertyPath{string(k)}, apitype.DiffUpdate)
+		}
+	}
+
+	slices.SortFunc(properties, func(a, b apitype.PropertyDrift) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return properties
+}
+
+// appendPath returns a new property path made of the given path followed by the given key.
+func appendPath(path resource.PropertyPath, key interface{}) resource.PropertyPath {
+	return append(slices.Clip(path), key)
+}
+
+// isInputDiff returns true if the entry of the step's detailed diff that covers the given path compares the resource's
+// inputs rather than its outputs.
+func isInputDiff(metadata *StepEventMetadata, path resource.PropertyPath) bool {
+	for p, diff := range metadata.DetailedDiff {
+		elements, err := resource.ParsePropertyPath(p)
+		if err != nil {
+			elements = resource.PropertyPath{p}
+		}
+		if elements.Contains(path) {
+			return diff.InputDiff
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+)
+
+func TestNewDriftReport(t *testing.T) {
+	t.Parallel()
+
+	refreshed := func(
+		op display.StepOp, name string, old, new resource.PropertyMap, detailedDiff map[string]plugin.PropertyDiff,
+	) Event {
+		urn := resource.NewURN("stack", "proj", "", "pkgA:m:typA", name)
+		metadata := StepEventMetadata{Op: op, URN: urn, Type: "pkgA:m:typA", DetailedDiff: detailedDiff}
+		if old != nil {
+			metadata.Old = &StepEventStateMetadata{URN: urn, Inputs: old, Outputs: old}
+		}
+		if new != nil {
+			metadata.New = &StepEventStateMetadata{URN: urn, Inputs: new, Outputs: new}
+		}
+		return NewEvent(ResourceOutputsEventPayload{Metadata: metadata, Planning: true})
+	}
+
+	old := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"size": 1,
+		"tags": map[string]interface{}{"env": "dev"},
+	})
+	new := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"size": 2,
+		"tags": map[string]interface{}{"env": "prod", "owner": "ops"},
+	})
+
+	report := NewDriftReport([]Event{
+		refreshed(deploy.OpSame, "same", old, old, nil),
+		refreshed(deploy.OpUpdate, "modified", old, new, map[string]plugin.PropertyDiff{
+			"size": {Kind: plugin.DiffUpdate, InputDiff: true},
+			"tags": {Kind: plugin.DiffUpdate},
+		}),
+		// Without a detailed diff, the resource's outputs are compared.
+		refreshed(deploy.OpUpdate, "external", old, new, nil),
+		refreshed(deploy.OpDelete, "deleted", old, nil, nil),
+	})
+
+	modified := []apitype.PropertyDrift{
+		{Path: "size", Kind: apitype.DiffUpdate, InputDiff: true},
+		{Path: "tags.env", Kind: apitype.DiffUpdate},
+		{Path: "tags.owner", Kind: apitype.DiffAdd},
+	}
+	external := []apitype.PropertyDrift{
+		{Path: "size", Kind: apitype.DiffUpdate},
+		{Path: "tags.env", Kind: apitype.DiffUpdate},
+		{Path: "tags.owner", Kind: apitype.DiffAdd},
+	}
+	assert.True(t, report.HasDrift())
+	assert.Equal(t, []apitype.ResourceDrift{
+		{
+			URN:  "urn:pulumi:stack::proj::pkgA:m:typA::deleted",
+			Type: "pkgA:m:typA",
+			Kind: apitype.DriftDeleted,
+		},
+		{
+			URN:        "urn:pulumi:stack::proj::pkgA:m:typA::external",
+			Type:       "pkgA:m:typA",
+			Kind:       apitype.DriftModified,
+			Properties: external,
+		},
+		{
+			URN:        "urn:pulumi:stack::proj::pkgA:m:typA::modified",
+			Type:       "pkgA:m:typA",
+			Kind:       apitype.DriftModified,
+			Properties: modified,
+		},
+	}, report.Resources)
+
+	assert.False(t, NewDriftReport(nil).HasDrift())
+}
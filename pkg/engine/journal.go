@@ -47,6 +47,11 @@ func (entries JournalEntries) Snap(base *deploy.Snapshot) (*deploy.Snapshot, err
 	// Build up a list of current resources by replaying the journal.
 	deletes := make(map[*resource.State]bool)
 	resources, dones := []*resource.State{}, make(map[*resource.State]bool)
+	written := make(map[*resource.State]int)
+	appendResource := func(res *resource.State) {
+		written[res] = len(resources)
+		resources = append(resources, res)
+	}
 	ops, doneOps := []resource.Operation{}, make(map[*resource.State]bool)
 	for _, e := range entries {
 		logging.V(7).Infof("%v %v (%v)", e.Step.Op(), e.Step.URN(), e.Kind)
@@ -87,14 +92,21 @@ func (entries JournalEntries) Snap(base *deploy.Snapshot) (*deploy.Snapshot, err
 				step, ok := e.Step.(*deploy.SameStep)
 				contract.Assertf(ok, "expected *deploy.SameStep, got %T", e.Step)
 				if !step.IsSkippedCreate() {
-					resources = append(resources, e.Step.New())
+					appendResource(e.Step.New())
 					dones[e.Step.Old()] = true
 				}
 			case deploy.OpUpdate:
-				resources = append(resources, e.Step.New())
-				dones[e.Step.Old()] = true
+				// An update of a state written earlier in this journal, as when a failed update is rolled back,
+				// takes the place of that state. See backend.SnapshotManager.markUpdated.
+				if i, has := written[e.Step.Old()]; has && e.Step.Old() != e.Step.New() {
+					resources[i] = e.Step.New()
+					written[e.Step.New()] = i
+				} else {
+					appendResource(e.Step.New())
+					dones[e.Step.Old()] = true
+				}
 			case deploy.OpCreate, deploy.OpCreateReplacement:
-				resources = append(resources, e.Step.New())
+				appendResource(e.Step.New())
 				if old := e.Step.Old(); old != nil && old.PendingReplacement {
 					dones[old] = true
 				}
@@ -109,14 +121,14 @@ func (entries JournalEntries) Snap(base *deploy.Snapshot) (*deploy.Snapshot, err
 			case deploy.OpReplace:
 				// do nothing.
 			case deploy.OpRead, deploy.OpReadReplacement:
-				resources = append(resources, e.Step.New())
+				appendResource(e.Step.New())
 				if e.Step.Old() != nil {
 					dones[e.Step.Old()] = true
 				}
 			case deploy.OpRemovePendingReplace:
 				dones[e.Step.Old()] = true
 			case deploy.OpImport, deploy.OpImportReplacement:
-				resources = append(resources, e.Step.New())
+				appendResource(e.Step.New())
 				dones[e.Step.New()] = true
 			}
 		}
@@ -0,0 +1,225 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycletest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
+	lt "github.com/pulumi/pulumi/pkg/v3/engine/lifecycletest/framework"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func TestConcurrencyLimits(t *testing.T) {
+	t.Parallel()
+
+	var lock sync.Mutex
+	running, maxRunning := map[tokens.Type]int{}, map[tokens.Type]int{}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(_ context.Context, req plugin.CreateRequest) (plugin.CreateResponse, error) {
+					lock.Lock()
+					running[req.Type]++
+					maxRunning[req.Type] = max(maxRunning[req.Type], running[req.Type])
+					lock.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					lock.Lock()
+					running[req.Type]--
+					lock.Unlock()
+					return plugin.CreateResponse{
+						ID:         resource.ID(req.URN.Name()),
+						Properties: req.Properties,
+						Status:     resource.StatusOK,
+					}, nil
+				},
+			}, nil
+		}),
+	}
+
+	// Register six resources of each type at once, as an SDK would for resources that don't depend on each other.
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		var wg sync.WaitGroup
+		for _, typ := range []string{"pkgA:m:typA", "pkgA:m:typB"} {
+			for i := 0; i < 6; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := monitor.RegisterResource(tokens.Type(typ), fmt.Sprintf("%s-%d", typ[len(typ)-4:], i), true)
+					assert.NoError(t, err)
+				}()
+			}
+		}
+		wg.Wait()
+		return nil
+	})
+	hostF := deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+
+	p := &lt.TestPlan{
+		Options: lt.TestUpdateOptions{
+			T:             t,
+			HostF:         hostF,
+			UpdateOptions: UpdateOptions{Parallel: 12},
+			// Skip display tests because the order in which the resources are created isn't deterministic.
+			SkipDisplayTests: true,
+		},
+		Config: config.Map{
+			config.MustMakeKey("pulumi", "concurrency-limits"): config.NewObjectValue(`{"pkgA:m:typA": 2}`),
+		},
+	}
+
+	project := p.GetProject()
+	snap, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient,
+		func(_ workspace.Project, _ deploy.Target, _ JournalEntries, events []Event, err error) error {
+			// Steps queued behind the limit are reported in the progress display, and the report is cleared once
+			// they run.
+			queued := map[resource.URN]bool{}
+			for _, e := range events {
+				if payload, ok := e.Payload().(DiagEventPayload); ok && payload.Ephemeral {
+					if strings.Contains(payload.Message, "concurrent operations allowed for type pkgA:m:typA") {
+						queued[payload.URN] = true
+					} else if strings.TrimSpace(colors.Never.Colorize(payload.Message)) == "" {
+						delete(queued, payload.URN)
+					}
+				}
+			}
+			assert.NotEmpty(t, events)
+			assert.Empty(t, queued, "expected the status messages of queued steps to be cleared")
+			return err
+		}, "0")
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 13)
+
+	assert.LessOrEqual(t, maxRunning["pkgA:m:typA"], 2)
+	assert.Equal(t, 0, running["pkgA:m:typA"])
+}
+
+// TestConcurrencyLimitsFreeWorkers checks that steps waiting on a concurrency limit don't take up the workers that
+// other steps could run on.
+func TestConcurrencyLimitsFreeWorkers(t *testing.T) {
+	t.Parallel()
+
+	var lock sync.Mutex
+	// The number of steps of typA that had finished when the first step of typB started.
+	finishedA, finishedAtB := 0, -1
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(_ context.Context, req plugin.CreateRequest) (plugin.CreateResponse, error) {
+					lock.Lock()
+					if req.Type == "pkgA:m:typB" && finishedAtB < 0 {
+						finishedAtB = finishedA
+					}
+					lock.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					lock.Lock()
+					if req.Type == "pkgA:m:typA" {
+						finishedA++
+					}
+					lock.Unlock()
+					return plugin.CreateResponse{
+						ID:         resource.ID(req.URN.Name()),
+						Properties: req.Properties,
+						Status:     resource.StatusOK,
+					}, nil
+				},
+			}, nil
+		}),
+	}
+
+	// Register the resources of the limited type first, so that with two workers both would be waiting on the limit
+	// when the other type is registered if waiting took up a worker.
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		var wg sync.WaitGroup
+		register := func(typ tokens.Type, name string) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := monitor.RegisterResource(typ, name, true)
+				assert.NoError(t, err)
+			}()
+		}
+		for i := 0; i < 4; i++ {
+			register("pkgA:m:typA", fmt.Sprintf("typA-%d", i))
+		}
+		time.Sleep(5 * time.Millisecond)
+		for i := 0; i < 4; i++ {
+			register("pkgA:m:typB", fmt.Sprintf("typB-%d", i))
+		}
+		wg.Wait()
+		return nil
+	})
+	hostF := deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+
+	p := &lt.TestPlan{
+		Options: lt.TestUpdateOptions{
+			T:                t,
+			HostF:            hostF,
+			UpdateOptions:    UpdateOptions{Parallel: 2},
+			SkipDisplayTests: true,
+		},
+		Config: config.Map{
+			config.MustMakeKey("pulumi", "concurrency-limits"): config.NewObjectValue(`{"pkgA:m:typA": 1}`),
+		},
+	}
+
+	project := p.GetProject()
+	snap, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil, "0")
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 9)
+	// If waiting took up a worker, typB would only start once all but one of the steps of typA had been dequeued.
+	assert.Less(t, finishedAtB, 2, "expected steps of typB to run while steps of typA were waiting on the limit")
+}
+
+func TestConcurrencyLimitsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		return nil
+	})
+	hostF := deploytest.NewPluginHostF(nil, nil, programF)
+
+	p := &lt.TestPlan{
+		Options: lt.TestUpdateOptions{T: t, HostF: hostF},
+		Config: config.Map{
+			config.MustMakeKey("pulumi", "concurrency-limits"): config.NewObjectValue(`{"pkgA": 0}`),
+		},
+	}
+
+	project := p.GetProject()
+	_, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil, "0")
+	assert.ErrorContains(t, err, `pulumi:concurrency-limits: the concurrency limit for "pkgA" must be at least 1, got 0`)
+}
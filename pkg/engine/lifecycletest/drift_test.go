@@ -0,0 +1,123 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycletest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
+	lt "github.com/pulumi/pulumi/pkg/v3/engine/lifecycletest/framework"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// Tests that a drift report built from the events of a refresh preview lists the resources that have drifted.
+func TestDriftReport(t *testing.T) {
+	t.Parallel()
+
+	drifted := false
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(_ context.Context, req plugin.CreateRequest) (plugin.CreateResponse, error) {
+					return plugin.CreateResponse{ID: "id", Properties: req.Properties, Status: resource.StatusOK}, nil
+				},
+				ReadF: func(_ context.Context, req plugin.ReadRequest) (plugin.ReadResponse, error) {
+					state := req.State
+					if drifted {
+						switch req.Name {
+						case "resA":
+							state = resource.PropertyMap{"size": resource.NewNumberProperty(2)}
+						case "resB":
+							return plugin.ReadResponse{}, nil
+						}
+					}
+					return plugin.ReadResponse{
+						ReadResult: plugin.ReadResult{ID: req.ID, Inputs: req.Inputs, Outputs: state},
+						Status:     resource.StatusOK,
+					}, nil
+				},
+				DiffF: func(_ context.Context, req plugin.DiffRequest) (plugin.DiffResponse, error) {
+					if req.OldOutputs["size"].DeepEquals(req.NewInputs["size"]) {
+						return plugin.DiffResponse{Changes: plugin.DiffNone}, nil
+					}
+					return plugin.DiffResponse{
+						Changes:      plugin.DiffSome,
+						DetailedDiff: map[string]plugin.PropertyDiff{"size": {Kind: plugin.DiffUpdate}},
+					}, nil
+				},
+			}, nil
+		}),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range []string{"resA", "resB", "resC"} {
+			_, err := monitor.RegisterResource("pkgA:m:typA", name, true, deploytest.ResourceOptions{
+				Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	p := &lt.TestPlan{}
+	project := p.GetProject()
+	opts := lt.TestUpdateOptions{
+		T:                t,
+		HostF:            deploytest.NewPluginHostF(nil, nil, programF, loaders...),
+		SkipDisplayTests: true,
+	}
+
+	snap, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), opts, false, p.BackendClient, nil, "0")
+	require.NoError(t, err)
+
+	// Without drift, a refresh preview reports nothing.
+	var report apitype.DriftReport
+	validate := func(_ workspace.Project, _ deploy.Target, _ JournalEntries, events []Event, err error) error {
+		report = NewDriftReport(events)
+		return err
+	}
+	_, err = lt.TestOp(Refresh).RunStep(project, p.GetTarget(t, snap), opts, true, p.BackendClient, validate, "1")
+	require.NoError(t, err)
+	assert.False(t, report.HasDrift())
+
+	drifted = true
+	_, err = lt.TestOp(Refresh).RunStep(project, p.GetTarget(t, snap), opts, true, p.BackendClient, validate, "2")
+	require.NoError(t, err)
+	assert.Equal(t, []apitype.ResourceDrift{
+		{
+			URN:        string(p.NewURN("pkgA:m:typA", "resA", "")),
+			Type:       "pkgA:m:typA",
+			Kind:       apitype.DriftModified,
+			Properties: []apitype.PropertyDrift{{Path: "size", Kind: apitype.DiffUpdate}},
+		},
+		{
+			URN:  string(p.NewURN("pkgA:m:typA", "resB", "")),
+			Type: "pkgA:m:typA",
+			Kind: apitype.DriftDeleted,
+		},
+	}, report.Resources)
+}
@@ -1374,3 +1374,89 @@ func TestImportStackReference(t *testing.T) {
 
 	assert.ErrorContains(t, err, "stack reference can not be imported")
 }
+
+func TestImportDiscover(t *testing.T) {
+	t.Parallel()
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				GetSchemaF: func(context.Context, plugin.GetSchemaRequest) (plugin.GetSchemaResponse, error) {
+					return plugin.GetSchemaResponse{Schema: []byte(importSchema)}, nil
+				},
+				DiffF: diffImportResource,
+				ReadF: func(_ context.Context, req plugin.ReadRequest) (plugin.ReadResponse, error) {
+					return plugin.ReadResponse{
+						ReadResult: plugin.ReadResult{
+							ID:      req.ID,
+							Inputs:  resource.PropertyMap{"foo": resource.NewStringProperty("bar")},
+							Outputs: resource.PropertyMap{"foo": resource.NewStringProperty("bar")},
+						},
+						Status: resource.StatusOK,
+					}, nil
+				},
+				DiscoverRelatedF: func(
+					_ context.Context, req plugin.DiscoverRelatedRequest,
+				) (plugin.DiscoverRelatedResponse, error) {
+					switch req.ID {
+					case "vpc-1":
+						return plugin.DiscoverRelatedResponse{Resources: []plugin.RelatedResource{
+							{Type: "pkgA:m:typA", ID: "subnet-1", Name: "subnet", Relationship: plugin.RelationshipChild},
+							{Type: "pkgA:m:typA", ID: "sg/1", Relationship: plugin.RelationshipDependent},
+							{Type: "pkgA:m:typA", ID: "dhcp-1", Name: "dhcp", Relationship: plugin.RelationshipDependency},
+							{Type: "pkgB:m:typB", ID: "other-1", Relationship: plugin.RelationshipChild},
+						}}, nil
+					case "subnet-1":
+						// Resources that have already been discovered are only imported once.
+						return plugin.DiscoverRelatedResponse{Resources: []plugin.RelatedResource{
+							{Type: "pkgA:m:typA", ID: "vpc-1", Relationship: plugin.RelationshipDependency},
+							{Type: "pkgA:m:typA", ID: "nic-1", Name: "subnet", Relationship: plugin.RelationshipChild},
+							{Type: "pkgA:m:typA", ID: "sg-2", Name: "subnet", Relationship: plugin.RelationshipDependent},
+						}}, nil
+					}
+					return plugin.DiscoverRelatedResponse{}, nil
+				},
+			}, nil
+		}),
+	}
+	programF := deploytest.NewLanguageRuntimeF(nil)
+	hostF := deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+
+	p := &lt.TestPlan{
+		// The discovered resources that don't depend on each other are imported in parallel, so the order of their
+		// events isn't deterministic.
+		Options: lt.TestUpdateOptions{T: t, HostF: hostF, SkipDisplayTests: true},
+	}
+
+	project := p.GetProject()
+	snap, err := lt.ImportOp([]deploy.Import{{
+		Type:     "pkgA:m:typA",
+		Name:     "vpc",
+		ID:       "vpc-1",
+		Discover: true,
+	}}).RunStep(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil, "0")
+	require.NoError(t, err)
+
+	byID := map[resource.ID]*resource.State{}
+	for _, r := range snap.Resources {
+		if r.Type == "pkgA:m:typA" {
+			byID[r.ID] = r
+		}
+	}
+	require.Len(t, byID, 6)
+
+	vpc := p.NewURN("pkgA:m:typA", "vpc", "")
+	subnet := p.NewURN("pkgA:m:typA", "subnet", vpc)
+	dhcp := p.NewURN("pkgA:m:typA", "dhcp", "")
+	assert.Equal(t, vpc, byID["vpc-1"].URN)
+	assert.Equal(t, []resource.URN{dhcp}, byID["vpc-1"].Dependencies)
+	assert.Equal(t, subnet, byID["subnet-1"].URN)
+	assert.Equal(t, vpc, byID["subnet-1"].Parent)
+	assert.Equal(t, p.NewURN("pkgA:m:typA", "sg-1", ""), byID["sg/1"].URN)
+	assert.Equal(t, []resource.URN{vpc}, byID["sg/1"].Dependencies)
+	assert.Equal(t, dhcp, byID["dhcp-1"].URN)
+	assert.Equal(t, subnet, byID["nic-1"].Parent)
+	// Names that clash with other imported resources are made unique.
+	assert.Equal(t, p.NewURN("pkgA:m:typA", "subnet-2", vpc), byID["sg-2"].URN)
+	assert.Equal(t, []resource.URN{subnet}, byID["sg-2"].Dependencies)
+}
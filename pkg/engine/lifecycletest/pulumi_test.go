@@ -798,6 +798,205 @@ func TestRefreshPreservesPendingCreateOperations(t *testing.T) {
 	assert.Equal(t, urnB, new.PendingOperations[0].Resource.URN)
 }
 
+// Tests that resuming an update reconciles the operations left pending by an interrupted update by reading their
+// resources, and carries on with the update.
+func TestResumeWithPendingOperations(t *testing.T) {
+	t.Parallel()
+
+	p := &lt.TestPlan{}
+
+	const resType = "pkgA:m:typA"
+	urnA := p.NewURN(resType, "resA", "")
+	urnB := p.NewURN(resType, "resB", "")
+	urnC := p.NewURN(resType, "resC", "")
+	urnD := p.NewURN(resType, "resD", "")
+
+	var reads []resource.URN
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(_ context.Context, req plugin.CreateRequest) (plugin.CreateResponse, error) {
+					return plugin.CreateResponse{
+						ID:         resource.ID(req.URN.Name()),
+						Properties: resource.PropertyMap{},
+						Status:     resource.StatusOK,
+					}, nil
+				},
+				ReadF: func(_ context.Context, req plugin.ReadRequest) (plugin.ReadResponse, error) {
+					reads = append(reads, req.URN)
+					if req.URN == urnB {
+						// The delete completed before the update was interrupted.
+						return plugin.ReadResponse{}, nil
+					}
+					return plugin.ReadResponse{
+						ReadResult: plugin.ReadResult{
+							ID:      req.ID,
+							Inputs:  resource.PropertyMap{},
+							Outputs: resource.PropertyMap{"live": resource.NewBoolProperty(true)},
+						},
+						Status: resource.StatusOK,
+					}, nil
+				},
+			}, nil
+		}),
+	}
+
+	names := []string{"resA", "resB"}
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range names {
+			_, err := monitor.RegisterResource(resType, name, true)
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+
+	op := lt.TestOp(Update)
+	options := lt.TestUpdateOptions{T: t, HostF: deploytest.NewPluginHostF(nil, nil, programF, loaders...)}
+	project := p.GetProject()
+
+	snap, err := op.RunStep(project, p.GetTarget(t, nil), options, false, nil, nil, "0")
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 3)
+	resA, resB := snap.Resources[1], snap.Resources[2]
+
+	// Interrupt an update while A was being updated, B deleted and D created. C was being imported from a pending
+	// create.
+	pending := func(res *resource.State, urn resource.URN, id resource.ID) *resource.State {
+		res = res.Copy()
+		res.URN, res.ID = urn, id
+		return res
+	}
+	snap.PendingOperations = []resource.Operation{
+		{Resource: pending(resA, urnA, resA.ID), Type: resource.OperationTypeUpdating},
+		{Resource: resB, Type: resource.OperationTypeDeleting},
+		{Resource: pending(resA, urnC, "resC"), Type: resource.OperationTypeImporting},
+		{Resource: pending(resA, urnD, ""), Type: resource.OperationTypeCreating},
+	}
+
+	names = []string{"resA", "resC"}
+	options.Resume = true
+	snap, err = op.RunStep(project, p.GetTarget(t, snap), options, false, nil, nil, "1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []resource.URN{urnA, urnB, urnC}, reads)
+
+	// The pending create can't be read, so it's left for a refresh to resolve.
+	require.Len(t, snap.PendingOperations, 1)
+	assert.Equal(t, resource.OperationTypeCreating, snap.PendingOperations[0].Type)
+	assert.Equal(t, urnD, snap.PendingOperations[0].Resource.URN)
+
+	urns := map[resource.URN]*resource.State{}
+	for _, res := range snap.Resources {
+		urns[res.URN] = res
+	}
+	assert.NotContains(t, urns, urnB)
+	require.Contains(t, urns, urnA)
+	assert.Equal(t, resource.NewBoolProperty(true), urns[urnA].Outputs["live"])
+	require.Contains(t, urns, urnC)
+	assert.Equal(t, resource.ID("resC"), urns[urnC].ID)
+}
+
+// Tests that an update run with RollbackOnFailure undoes the changes it made before it failed, and leaves the
+// changes it can't undo in place.
+func TestRollbackOnFailure(t *testing.T) {
+	t.Parallel()
+
+	p := &lt.TestPlan{}
+
+	const resType = "pkgA:m:typA"
+	urnA := p.NewURN(resType, "resA", "")
+	urnB := p.NewURN(resType, "resB", "")
+	urnC := p.NewURN(resType, "resC", "")
+	urnD := p.NewURN(resType, "resD", "")
+
+	var deletes []resource.URN
+	var updates []resource.PropertyMap
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(_ context.Context, req plugin.DiffRequest) (plugin.DiffResult, error) {
+					if req.OldInputs.DeepEquals(req.NewInputs) {
+						return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+					}
+					if req.URN == urnB {
+						return plugin.DiffResult{
+							Changes:             plugin.DiffSome,
+							ReplaceKeys:         []resource.PropertyKey{"foo"},
+							DeleteBeforeReplace: true,
+						}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffSome}, nil
+				},
+				CreateF: func(_ context.Context, req plugin.CreateRequest) (plugin.CreateResponse, error) {
+					if req.URN == urnD {
+						return plugin.CreateResponse{Status: resource.StatusUnknown}, errors.New("oh no")
+					}
+					return plugin.CreateResponse{
+						ID:         resource.ID(req.URN.Name()),
+						Properties: req.Properties,
+						Status:     resource.StatusOK,
+					}, nil
+				},
+				UpdateF: func(_ context.Context, req plugin.UpdateRequest) (plugin.UpdateResponse, error) {
+					updates = append(updates, req.NewInputs)
+					return plugin.UpdateResponse{Properties: req.NewInputs, Status: resource.StatusOK}, nil
+				},
+				DeleteF: func(_ context.Context, req plugin.DeleteRequest) (plugin.DeleteResponse, error) {
+					deletes = append(deletes, req.URN)
+					return plugin.DeleteResponse{}, nil
+				},
+			}, nil
+		}),
+	}
+
+	foo := "1"
+	names := []string{"resA", "resB"}
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		// Resources are registered one at a time, so D fails once the others are done.
+		for _, name := range names {
+			_, err := monitor.RegisterResource(resType, name, true, deploytest.ResourceOptions{
+				Inputs: resource.PropertyMap{"foo": resource.NewStringProperty(foo)},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	op := lt.TestOp(Update)
+	options := lt.TestUpdateOptions{T: t, HostF: deploytest.NewPluginHostF(nil, nil, programF, loaders...)}
+	project := p.GetProject()
+
+	snap, err := op.RunStep(project, p.GetTarget(t, nil), options, false, nil, nil, "0")
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 3)
+
+	// Update A, replace B, create C, and then fail to create D.
+	foo = "2"
+	names = []string{"resA", "resB", "resC", "resD"}
+	options.RollbackOnFailure = true
+	snap, err = op.RunStep(project, p.GetTarget(t, snap), options, false, nil, nil, "1")
+	assert.ErrorContains(t, err, "oh no")
+
+	// A is updated back to its old inputs and C is deleted, but B's original was deleted before its replacement was
+	// created, so B is left replaced.
+	require.Len(t, updates, 2)
+	assert.Equal(t, resource.PropertyMap{"foo": resource.NewStringProperty("1")}, updates[1])
+	assert.Equal(t, []resource.URN{urnB, urnC}, deletes)
+
+	urns := map[resource.URN]*resource.State{}
+	for _, res := range snap.Resources {
+		urns[res.URN] = res
+	}
+	require.Len(t, snap.Resources, 3)
+	require.Contains(t, urns, urnA)
+	assert.Equal(t, resource.NewStringProperty("1"), urns[urnA].Inputs["foo"])
+	require.Contains(t, urns, urnB)
+	assert.Equal(t, resource.NewStringProperty("2"), urns[urnB].Inputs["foo"])
+	assert.NotContains(t, urns, urnC)
+	assert.Empty(t, snap.PendingOperations)
+}
+
 func findPendingOperationsByType(opType resource.OperationType, snapshot *deploy.Snapshot) []resource.Operation {
 	var operations []resource.Operation
 	for _, operation := range snapshot.PendingOperations {
@@ -0,0 +1,179 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycletest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
+	lt "github.com/pulumi/pulumi/pkg/v3/engine/lifecycletest/framework"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// newRetryTest returns a plugin host for a program that registers a single resource with the given retry policy,
+// whose creation fails with the given errors before it succeeds. The returned function reports the number of times
+// that the provider was asked to create the resource.
+func newRetryTest(
+	policy *resource.RetryPolicy, failures ...string,
+) (deploytest.PluginHostFactory, func() int) {
+	var lock sync.Mutex
+	attempts := 0
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(_ context.Context, req plugin.CreateRequest) (plugin.CreateResponse, error) {
+					if req.Preview {
+						return plugin.CreateResponse{Properties: req.Properties, Status: resource.StatusOK}, nil
+					}
+
+					lock.Lock()
+					defer lock.Unlock()
+					attempts++
+					if attempts <= len(failures) {
+						return plugin.CreateResponse{Status: resource.StatusUnknown}, errors.New(failures[attempts-1])
+					}
+					return plugin.CreateResponse{
+						ID:         "created-id",
+						Properties: req.Properties,
+						Status:     resource.StatusOK,
+					}, nil
+				},
+			}, nil
+		}),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			RetryPolicy:             policy,
+			SupportsResultReporting: true,
+		})
+		return err
+	})
+
+	return deploytest.NewPluginHostF(nil, nil, programF, loaders...), func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return attempts
+	}
+}
+
+// retryWarnings returns the messages of the warnings that report the retries of failed operations.
+func retryWarnings(events []Event) []string {
+	var warnings []string
+	for _, e := range events {
+		if payload, ok := e.Payload().(DiagEventPayload); ok && payload.Severity == diag.Warning &&
+			strings.Contains(payload.Message, "retrying in") {
+			warnings = append(warnings, strings.TrimSpace(colors.Never.Colorize(payload.Message)))
+		}
+	}
+	return warnings
+}
+
+// Tests that a failed create is retried under the retry policy of the resource, and that each retry is reported.
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	hostF, attempts := newRetryTest(
+		&resource.RetryPolicy{MaxAttempts: 3, Delay: 0.001, Errors: []string{"throttled"}},
+		"request throttled", "request throttled")
+
+	p := &lt.TestPlan{}
+	project := p.GetProject()
+	opts := lt.TestUpdateOptions{T: t, HostF: hostF}
+
+	snap, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), opts, false, p.BackendClient,
+		func(_ workspace.Project, _ deploy.Target, _ JournalEntries, events []Event, err error) error {
+			assert.Equal(t, []string{
+				"create failed, retrying in 1ms (attempt 2 of 3): request throttled",
+				"create failed, retrying in 2ms (attempt 3 of 3): request throttled",
+			}, retryWarnings(events))
+			return err
+		}, "0")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts())
+	assert.Len(t, snap.Resources, 2)
+	assert.Equal(t, resource.ID("created-id"), snap.Resources[1].ID)
+}
+
+// Tests that retries stop once a policy's attempts have been used up, and that errors that a policy doesn't cover
+// aren't retried at all.
+func TestRetryPolicyGivesUp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("attempts", func(t *testing.T) {
+		t.Parallel()
+
+		hostF, attempts := newRetryTest(&resource.RetryPolicy{MaxAttempts: 2, Delay: 0.001},
+			"request throttled", "request throttled", "request throttled")
+
+		p := &lt.TestPlan{}
+		opts := lt.TestUpdateOptions{T: t, HostF: hostF, SkipDisplayTests: true}
+		_, err := lt.TestOp(Update).RunStep(p.GetProject(), p.GetTarget(t, nil), opts, false, p.BackendClient, nil, "0")
+		assert.Error(t, err)
+		assert.Equal(t, 2, attempts())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		hostF, attempts := newRetryTest(
+			&resource.RetryPolicy{MaxAttempts: 5, Delay: 0.001, Errors: []string{"throttled"}},
+			"access denied")
+
+		p := &lt.TestPlan{}
+		opts := lt.TestUpdateOptions{T: t, HostF: hostF, SkipDisplayTests: true}
+		_, err := lt.TestOp(Update).RunStep(p.GetProject(), p.GetTarget(t, nil), opts, false, p.BackendClient, nil, "0")
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts())
+	})
+}
+
+// Tests that a retry policy can be set for a type through the pulumi:retry-policies config key.
+func TestRetryPolicyConfig(t *testing.T) {
+	t.Parallel()
+
+	hostF, attempts := newRetryTest(nil, "request throttled")
+
+	p := &lt.TestPlan{
+		Config: config.Map{
+			config.MustMakeKey("pulumi", "retry-policies"): config.NewObjectValue(
+				`{"pkgA:m:typA": {"maxAttempts": 2, "delay": "1ms"}}`),
+		},
+	}
+	opts := lt.TestUpdateOptions{T: t, HostF: hostF, SkipDisplayTests: true}
+	snap, err := lt.TestOp(Update).RunStep(p.GetProject(), p.GetTarget(t, nil), opts, false, p.BackendClient,
+		func(_ workspace.Project, _ deploy.Target, _ JournalEntries, events []Event, err error) error {
+			assert.Len(t, retryWarnings(events), 1)
+			return err
+		}, "0")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts())
+	assert.Len(t, snap.Resources, 2)
+}
@@ -1844,3 +1844,54 @@ func TestStackOutputsWithTargetedPlan(t *testing.T) {
 	}, false, p.BackendClient, nil)
 	assert.NoError(t, err)
 }
+
+// Tests that the plan for a resource that is replaced because of a diff keeps the inputs that aren't changing.
+func TestPlannedInputsOfReplacement(t *testing.T) {
+	t.Parallel()
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(_ context.Context, req plugin.DiffRequest) (plugin.DiffResponse, error) {
+					if !req.OldInputs["foo"].DeepEquals(req.NewInputs["foo"]) {
+						return plugin.DiffResponse{Changes: plugin.DiffSome, ReplaceKeys: []resource.PropertyKey{"foo"}}, nil
+					}
+					return plugin.DiffResponse{}, nil
+				},
+			}, nil
+		}),
+	}
+
+	ins := resource.PropertyMap{"foo": resource.NewProperty("a"), "bar": resource.NewProperty("b")}
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: ins,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	hostF := deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+
+	p := &lt.TestPlan{
+		Options: lt.TestUpdateOptions{
+			T:                t,
+			HostF:            hostF,
+			SkipDisplayTests: true,
+			UpdateOptions:    UpdateOptions{GeneratePlan: true, Experimental: true},
+		},
+	}
+	project := p.GetProject()
+
+	snap, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil, "0")
+	assert.NoError(t, err)
+
+	ins = resource.PropertyMap{"foo": resource.NewProperty("c"), "bar": resource.NewProperty("b")}
+	plan, err := lt.TestOp(Update).Plan(project, p.GetTarget(t, snap), p.Options, p.BackendClient, nil)
+	assert.NoError(t, err)
+
+	urn := p.NewURN("pkgA:m:typA", "resA", "")
+	rp := plan.ResourcePlans[urn]
+	assert.Contains(t, rp.Ops, deploy.OpReplace)
+	assert.False(t, rp.Goal.FromEmptyInputs)
+	assert.Equal(t, ins, deploy.PlannedInputs(snap.Resources[1].Inputs, rp))
+}
@@ -0,0 +1,307 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycletest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
+	lt "github.com/pulumi/pulumi/pkg/v3/engine/lifecycletest/framework"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// newWavesTest returns the options for a test of a deployment with waves, along with a function that returns the
+// resources created so far, in the order in which they were created.
+func newWavesTest(t *testing.T, program func(monitor *deploytest.ResourceMonitor) error) (
+	lt.TestUpdateOptions, func() []string,
+) {
+	var mu sync.Mutex
+	var created []string
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(_ context.Context, req plugin.CreateRequest) (plugin.CreateResponse, error) {
+					if !req.Preview {
+						mu.Lock()
+						defer mu.Unlock()
+						created = append(created, req.URN.Name())
+					}
+					return plugin.CreateResponse{
+						ID:         resource.ID(req.URN.Name()),
+						Properties: req.Properties,
+						Status:     resource.StatusOK,
+					}, nil
+				},
+			}, nil
+		}),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		return program(monitor)
+	})
+	options := lt.TestUpdateOptions{T: t, HostF: deploytest.NewPluginHostF(nil, nil, programF, loaders...)}
+	return options, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return created
+	}
+}
+
+// withWavesPlan returns the given options with the plan made by a preview of the update, which deployments with waves
+// need.
+func withWavesPlan(t *testing.T, p *lt.TestPlan, options lt.TestUpdateOptions) lt.TestUpdateOptions {
+	options.GeneratePlan = true
+	options.Experimental = true
+	plan, err := lt.TestOp(Update).Plan(p.GetProject(), p.GetTarget(t, nil), options, p.BackendClient, nil)
+	require.NoError(t, err)
+	options.Plan = plan.Clone()
+	return options
+}
+
+func TestWaves(t *testing.T) {
+	t.Parallel()
+
+	p := &lt.TestPlan{}
+	project := p.GetProject()
+
+	options, created := newWavesTest(t, func(monitor *deploytest.ResourceMonitor) error {
+		// A is in no wave, so it's in the last one. B is in the first wave, and C is in the first wave but depends
+		// on A, so it's in the last wave too. Like the SDKs do, register A without waiting for it.
+		type result struct {
+			resp *deploytest.RegisterResourceResponse
+			err  error
+		}
+		resA := make(chan result)
+		go func() {
+			resp, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+			resA <- result{resp, err}
+		}()
+		if _, err := monitor.RegisterResource("pkgA:m:typB", "resB", true); err != nil {
+			return err
+		}
+		a := <-resA
+		if a.err != nil {
+			return a.err
+		}
+		_, err := monitor.RegisterResource("pkgA:m:typB", "resC", true, deploytest.ResourceOptions{
+			Dependencies: []resource.URN{a.resp.URN},
+		})
+		return err
+	})
+
+	// The waves are worked out from the plan made by a preview, so that every resource of a wave is deployed before
+	// the next wave starts.
+	options.Waves = []deploy.DeploymentWave{{Types: []tokens.Type{"pkgA:m:typB"}}}
+	options = withWavesPlan(t, p, options)
+
+	var approvals []int
+	var waiting [][]resource.URN
+	options.WaveApprover = deploy.WaveApproverFunc(
+		func(_ context.Context, wave int, resources []resource.URN) (bool, error) {
+			// Nothing from a later wave is created before the wave is approved.
+			assert.Equal(t, []string{"resB"}, created())
+			approvals = append(approvals, wave)
+			waiting = append(waiting, resources)
+			return true, nil
+		})
+
+	snap, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), options, false, nil, nil, "0")
+	require.NoError(t, err)
+	assert.Len(t, snap.Resources, 4)
+	assert.Equal(t, []string{"resB", "resA", "resC"}, created())
+	assert.Equal(t, []int{1}, approvals)
+	require.Len(t, waiting, 1)
+	// resC is expected in the same wave, even though it can't register until resA has been created.
+	assert.Equal(t, []resource.URN{
+		p.NewURN("pkgA:m:typA", "resA", ""),
+		p.NewURN("pkgA:m:typB", "resC", ""),
+	}, waiting[0])
+}
+
+// Tests that a wave is approved before its resources are deployed even if they're registered after the resources of
+// a later wave have been held back.
+func TestWavesApprovedInOrder(t *testing.T) {
+	t.Parallel()
+
+	p := &lt.TestPlan{}
+	project := p.GetProject()
+
+	planned := false
+	firstApproval := make(chan struct{})
+	options, created := newWavesTest(t, func(monitor *deploytest.ResourceMonitor) error {
+		// resC is in the last wave, and is held back before resB, the only resource of the second wave, registers.
+		resC := make(chan error)
+		go func() {
+			_, err := monitor.RegisterResource("pkgA:m:typC", "resC", true)
+			resC <- err
+		}()
+		if planned {
+			<-firstApproval
+		}
+		if _, err := monitor.RegisterResource("pkgA:m:typB", "resB", true); err != nil {
+			return err
+		}
+		return <-resC
+	})
+
+	options.Waves = []deploy.DeploymentWave{
+		{Types: []tokens.Type{"pkgA:m:typA"}},
+		{Types: []tokens.Type{"pkgA:m:typB"}},
+	}
+	options = withWavesPlan(t, p, options)
+	planned = true
+
+	var approvals []int
+	var waiting [][]resource.URN
+	options.WaveApprover = deploy.WaveApproverFunc(
+		func(_ context.Context, wave int, resources []resource.URN) (bool, error) {
+			if len(approvals) == 0 {
+				close(firstApproval)
+			}
+			approvals = append(approvals, wave)
+			waiting = append(waiting, resources)
+			return true, nil
+		})
+
+	_, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), options, false, nil, nil, "0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"resB", "resC"}, created())
+	assert.Equal(t, []int{1, 2}, approvals)
+	assert.Equal(t, [][]resource.URN{
+		{p.NewURN("pkgA:m:typB", "resB", "")},
+		{p.NewURN("pkgA:m:typC", "resC", "")},
+	}, waiting)
+}
+
+func TestWavesStopped(t *testing.T) {
+	t.Parallel()
+
+	p := &lt.TestPlan{}
+	project := p.GetProject()
+
+	options, created := newWavesTest(t, func(monitor *deploytest.ResourceMonitor) error {
+		if _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{
+				"tags": resource.NewObjectProperty(resource.PropertyMap{
+					"stage": resource.NewStringProperty("canary"),
+				}),
+			},
+		}); err != nil {
+			return err
+		}
+		_, err := monitor.RegisterResource("pkgA:m:typA", "resB", true)
+		return err
+	})
+
+	options.Waves = []deploy.DeploymentWave{{Tags: map[string]string{"stage": "canary"}}}
+	options = withWavesPlan(t, p, options)
+	options.WaveApprover = deploy.WaveApproverFunc(
+		func(context.Context, int, []resource.URN) (bool, error) {
+			return false, nil
+		})
+
+	snap, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), options, false, nil, nil, "0")
+	assert.ErrorContains(t, err, "the deployment was stopped before wave 2")
+	assert.Equal(t, []string{"resA"}, created())
+
+	// The canary wave is kept.
+	var names []string
+	for _, res := range snap.Resources {
+		names = append(names, res.URN.Name())
+	}
+	assert.Contains(t, names, "resA")
+	assert.NotContains(t, names, "resB")
+}
+
+func TestWavesSelectors(t *testing.T) {
+	t.Parallel()
+
+	p := &lt.TestPlan{}
+	project := p.GetProject()
+
+	options, created := newWavesTest(t, func(monitor *deploytest.ResourceMonitor) error {
+		// resA inherits the deploymentWave option of its parent, and resB has its tags in a list under "tagsAll".
+		comp, err := monitor.RegisterResource("pkgA:m:comp", "comp", false, deploytest.ResourceOptions{
+			DeploymentWave: "canary",
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Parent: comp.URN,
+		}); err != nil {
+			return err
+		}
+		if _, err := monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{
+				"tagsAll": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewObjectProperty(resource.PropertyMap{
+						"key":   resource.NewStringProperty("stage"),
+						"value": resource.NewStringProperty("beta"),
+					}),
+				}),
+			},
+		}); err != nil {
+			return err
+		}
+		_, err = monitor.RegisterResource("pkgA:m:typA", "resC", true)
+		return err
+	})
+
+	options.Waves = []deploy.DeploymentWave{
+		{Names: []string{"canary"}},
+		{Tags: map[string]string{"stage": "beta"}},
+	}
+	options = withWavesPlan(t, p, options)
+	var approvals []int
+	options.WaveApprover = deploy.WaveApproverFunc(
+		func(_ context.Context, wave int, _ []resource.URN) (bool, error) {
+			approvals = append(approvals, wave)
+			return wave == 1, nil
+		})
+
+	_, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), options, false, nil, nil, "0")
+	assert.ErrorContains(t, err, "the deployment was stopped before wave 3")
+	assert.Equal(t, []string{"resA", "resB"}, created())
+	assert.Equal(t, []int{1, 2}, approvals)
+}
+
+func TestWavesNeedPlan(t *testing.T) {
+	t.Parallel()
+
+	p := &lt.TestPlan{}
+	project := p.GetProject()
+
+	options, created := newWavesTest(t, func(monitor *deploytest.ResourceMonitor) error {
+		_, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+		return err
+	})
+
+	// Without a plan, the deployment can't tell when the program has registered every resource of a wave.
+	options.Waves = []deploy.DeploymentWave{{Types: []tokens.Type{"pkgA:m:typA"}}}
+	_, err := lt.TestOp(Update).RunStep(project, p.GetTarget(t, nil), options, false, nil, nil, "0")
+	assert.ErrorContains(t, err, "deploying in waves needs the plan made by a preview")
+	assert.Empty(t, created())
+}
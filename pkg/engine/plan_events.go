@@ -0,0 +1,198 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// NewPlanEvents returns the events of a preview that would produce the given plan, so that a saved plan can be shown
+// with the same displays as a preview. The events describe the changes that the plan makes to the resources in the
+// given snapshot, which should be the snapshot that the plan was made against, and end with a summary event.
+func NewPlanEvents(plan *deploy.Plan, snap *deploy.Snapshot, showSecrets bool) []Event {
+	olds := oldStates(snap)
+	news := plannedStates(plan, olds)
+
+	steps := map[resource.URN]StepEventMetadata{}
+	for urn, rp := range plan.ResourcePlans {
+		op := planOp(rp.Ops)
+		old, new := olds[urn], news[urn]
+		switch op {
+		case deploy.OpCreate:
+			old = nil
+		case deploy.OpDelete:
+			new = nil
+		}
+		if old == nil && new == nil {
+			// The plan deletes or reads a resource that the snapshot doesn't know of, so there's nothing to show.
+			continue
+		}
+		steps[urn] = newPlanStepMetadata(urn, op, old, new, showSecrets)
+	}
+	return planEvents(steps)
+}
+
+// NewPlanDiffEvents returns the events of a preview that would change the resource goals of plan a into those of plan
+// b. Resources that only b plans to register are shown as creates, those that only a plans to register as deletes and
+// those whose goals differ as updates. The planned inputs of both plans are worked out against the given snapshot.
+func NewPlanDiffEvents(a, b *deploy.Plan, snap *deploy.Snapshot, showSecrets bool) []Event {
+	olds := oldStates(snap)
+	as, bs := plannedStates(a, olds), plannedStates(b, olds)
+
+	steps := map[resource.URN]StepEventMetadata{}
+	for urn, old := range as {
+		if _, ok := bs[urn]; !ok {
+			steps[urn] = newPlanStepMetadata(urn, deploy.OpDelete, old, nil, showSecrets)
+		}
+	}
+	for urn, new := range bs {
+		old, ok := as[urn]
+		switch {
+		case !ok:
+			steps[urn] = newPlanStepMetadata(urn, deploy.OpCreate, nil, new, showSecrets)
+		case sameGoal(a.ResourcePlans[urn].Goal, b.ResourcePlans[urn].Goal) && old.Inputs.DeepEquals(new.Inputs):
+			steps[urn] = newPlanStepMetadata(urn, deploy.OpSame, old, new, showSecrets)
+		default:
+			steps[urn] = newPlanStepMetadata(urn, deploy.OpUpdate, old, new, showSecrets)
+		}
+	}
+	return planEvents(steps)
+}
+
+// oldStates returns the live resources of the given snapshot by URN.
+func oldStates(snap *deploy.Snapshot) map[resource.URN]*resource.State {
+	olds := map[resource.URN]*resource.State{}
+	if snap != nil {
+		for _, res := range snap.Resources {
+			if !res.Delete {
+				olds[res.URN] = res
+			}
+		}
+	}
+	return olds
+}
+
+// plannedStates returns the states that the given plan expects the resources it registers to have, as far as the
+// plan records them. Planned states have no outputs.
+func plannedStates(plan *deploy.Plan, olds map[resource.URN]*resource.State) map[resource.URN]*resource.State {
+	news := map[resource.URN]*resource.State{}
+	for urn, rp := range plan.ResourcePlans {
+		if rp.Goal == nil {
+			continue
+		}
+
+		goal := rp.Goal
+		var oldInputs resource.PropertyMap
+		var id resource.ID
+		if old, ok := olds[urn]; ok {
+			oldInputs, id = old.Inputs, old.ID
+		}
+		if goal.ID != "" {
+			id = goal.ID
+		}
+		news[urn] = &resource.State{
+			Type:                 goal.Type,
+			URN:                  urn,
+			Custom:               goal.Custom,
+			ID:                   id,
+			Inputs:               deploy.PlannedInputs(oldInputs, rp),
+			Parent:               goal.Parent,
+			Protect:              goal.Protect,
+			Dependencies:         goal.Dependencies,
+			Provider:             goal.Provider,
+			PropertyDependencies: goal.PropertyDependencies,
+			CustomTimeouts:       goal.CustomTimeouts,
+		}
+	}
+	return news
+}
+
+// planOp returns the operation that best describes the steps that a resource plan expects, in the same way that a
+// preview reports a replacement as a single replace.
+func planOp(ops []display.StepOp) display.StepOp {
+	for _, op := range []display.StepOp{
+		deploy.OpReplace, deploy.OpCreate, deploy.OpUpdate, deploy.OpDelete, deploy.OpImport, deploy.OpRead,
+	} {
+		if slices.Contains(ops, op) {
+			return op
+		}
+	}
+	return deploy.OpSame
+}
+
+// sameGoal returns true if the given goals agree on everything but the diffs that they were planned with.
+func sameGoal(a, b *deploy.GoalPlan) bool {
+	ac, bc := *a, *b
+	ac.InputDiff, bc.InputDiff = deploy.PlanDiff{}, deploy.PlanDiff{}
+	ac.OutputDiff, bc.OutputDiff = deploy.PlanDiff{}, deploy.PlanDiff{}
+	return reflect.DeepEqual(ac, bc)
+}
+
+func newPlanStepMetadata(
+	urn resource.URN, op display.StepOp, old, new *resource.State, showSecrets bool,
+) StepEventMetadata {
+	metadata := StepEventMetadata{
+		Op:  op,
+		URN: urn,
+		Old: makeStepEventStateMetadata(old, false, showSecrets),
+		New: makeStepEventStateMetadata(new, false, showSecrets),
+	}
+	metadata.Res = metadata.New
+	if metadata.Res == nil {
+		metadata.Res = metadata.Old
+	}
+	metadata.Type = metadata.Res.Type
+	metadata.Provider = metadata.Res.Provider
+	if old != nil && new != nil {
+		metadata.Diffs = old.Inputs.Diff(new.Inputs).ChangedKeys()
+	}
+	return metadata
+}
+
+// planEvents returns resource pre-events for the given steps followed by a summary of them. Parents are reported
+// before their children, as the displays expect.
+func planEvents(steps map[resource.URN]StepEventMetadata) []Event {
+	urns := make([]resource.URN, 0, len(steps))
+	for urn := range steps {
+		urns = append(urns, urn)
+	}
+	slices.Sort(urns)
+
+	var events []Event
+	changes := display.ResourceChanges{}
+	visited := map[resource.URN]bool{}
+	var visit func(urn resource.URN)
+	visit = func(urn resource.URN) {
+		step, ok := steps[urn]
+		if !ok || visited[urn] {
+			return
+		}
+		visited[urn] = true
+		visit(step.Res.Parent)
+
+		events = append(events, NewEvent(ResourcePreEventPayload{Metadata: step, Planning: true}))
+		changes[step.Op]++
+	}
+	for _, urn := range urns {
+		visit(urn)
+	}
+
+	return append(events, NewEvent(SummaryEventPayload{IsPreview: true, ResourceChanges: changes}))
+}
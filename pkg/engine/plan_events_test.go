@@ -0,0 +1,175 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestNewPlanEvents(t *testing.T) {
+	t.Parallel()
+
+	stackURN := resource.NewURN("stack", "proj", "", "pulumi:pulumi:Stack", "proj-stack")
+	updated := resource.NewURN("stack", "proj", "pulumi:pulumi:Stack", "pkgA:m:typA", "updated")
+	created := resource.NewURN("stack", "proj", "pulumi:pulumi:Stack", "pkgA:m:typA", "created")
+	deleted := resource.NewURN("stack", "proj", "pulumi:pulumi:Stack", "pkgA:m:typA", "deleted")
+
+	snap := &deploy.Snapshot{Resources: []*resource.State{
+		{URN: stackURN, Type: "pulumi:pulumi:Stack"},
+		{URN: updated, Type: "pkgA:m:typA", Parent: stackURN, Inputs: resource.PropertyMap{
+			"size": resource.NewNumberProperty(1),
+		}},
+		{URN: deleted, Type: "pkgA:m:typA", Parent: stackURN},
+	}}
+	plan := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		stackURN: {
+			Goal: &deploy.GoalPlan{Type: "pulumi:pulumi:Stack", Name: "proj-stack"},
+		},
+		updated: {
+			Goal: &deploy.GoalPlan{
+				Type:      "pkgA:m:typA",
+				Name:      "updated",
+				Parent:    stackURN,
+				InputDiff: deploy.PlanDiff{Updates: resource.PropertyMap{"size": resource.NewNumberProperty(2)}},
+			},
+			Ops: []display.StepOp{deploy.OpUpdate},
+		},
+		created: {
+			Goal: &deploy.GoalPlan{
+				Type:      "pkgA:m:typA",
+				Name:      "created",
+				Parent:    stackURN,
+				InputDiff: deploy.PlanDiff{Adds: resource.PropertyMap{"size": resource.NewNumberProperty(3)}},
+			},
+			Ops: []display.StepOp{deploy.OpCreate},
+		},
+		deleted: {
+			Ops: []display.StepOp{deploy.OpDelete},
+		},
+	}}
+
+	events := NewPlanEvents(plan, snap, false)
+	require.Len(t, events, 5)
+
+	// The root stack comes first, as it's the parent of every other resource.
+	steps := map[resource.URN]StepEventMetadata{}
+	for i, e := range events[:4] {
+		payload, ok := e.Payload().(ResourcePreEventPayload)
+		require.True(t, ok)
+		assert.True(t, payload.Planning)
+		if i == 0 {
+			assert.Equal(t, stackURN, payload.Metadata.URN)
+		}
+		steps[payload.Metadata.URN] = payload.Metadata
+	}
+
+	assert.Equal(t, deploy.OpSame, steps[stackURN].Op)
+
+	assert.Equal(t, deploy.OpUpdate, steps[updated].Op)
+	assert.Equal(t, resource.PropertyMap{"size": resource.NewNumberProperty(1)}, steps[updated].Old.Inputs)
+	assert.Equal(t, resource.PropertyMap{"size": resource.NewNumberProperty(2)}, steps[updated].New.Inputs)
+	assert.Equal(t, []resource.PropertyKey{"size"}, steps[updated].Diffs)
+
+	assert.Equal(t, deploy.OpCreate, steps[created].Op)
+	assert.Nil(t, steps[created].Old)
+	assert.Equal(t, resource.PropertyMap{"size": resource.NewNumberProperty(3)}, steps[created].New.Inputs)
+
+	assert.Equal(t, deploy.OpDelete, steps[deleted].Op)
+	assert.Nil(t, steps[deleted].New)
+	assert.Equal(t, deleted, steps[deleted].Res.URN)
+
+	summary, ok := events[4].Payload().(SummaryEventPayload)
+	require.True(t, ok)
+	assert.True(t, summary.IsPreview)
+	assert.Equal(t, display.ResourceChanges{
+		deploy.OpSame:   1,
+		deploy.OpUpdate: 1,
+		deploy.OpCreate: 1,
+		deploy.OpDelete: 1,
+	}, summary.ResourceChanges)
+}
+
+func TestNewPlanDiffEvents(t *testing.T) {
+	t.Parallel()
+
+	same := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "same")
+	changed := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "changed")
+	protected := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "protected")
+	onlyA := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "onlyA")
+	onlyB := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "onlyB")
+
+	snap := &deploy.Snapshot{Resources: []*resource.State{
+		{URN: same, Type: "pkgA:m:typA", Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(1)}},
+	}}
+	goal := func(name string, size float64) *deploy.ResourcePlan {
+		return &deploy.ResourcePlan{
+			Goal: &deploy.GoalPlan{
+				Type:      "pkgA:m:typA",
+				Name:      name,
+				InputDiff: deploy.PlanDiff{Adds: resource.PropertyMap{"size": resource.NewNumberProperty(size)}},
+			},
+			Ops: []display.StepOp{deploy.OpCreate},
+		}
+	}
+
+	a := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		// The same goal planned against different inputs is still the same.
+		same: {
+			Goal: &deploy.GoalPlan{Type: "pkgA:m:typA", Name: "same", InputDiff: deploy.PlanDiff{
+				Updates: resource.PropertyMap{"size": resource.NewNumberProperty(2)},
+			}},
+			Ops: []display.StepOp{deploy.OpUpdate},
+		},
+		changed:   goal("changed", 1),
+		protected: goal("protected", 1),
+		onlyA:     goal("onlyA", 1),
+	}}
+	b := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		same: {
+			Goal: &deploy.GoalPlan{Type: "pkgA:m:typA", Name: "same", InputDiff: deploy.PlanDiff{
+				Updates: resource.PropertyMap{"size": resource.NewNumberProperty(2)},
+			}},
+			Ops: []display.StepOp{deploy.OpUpdate},
+		},
+		changed:   goal("changed", 2),
+		protected: goal("protected", 1),
+		onlyB:     goal("onlyB", 1),
+	}}
+	b.ResourcePlans[protected].Goal.Protect = true
+
+	events := NewPlanDiffEvents(a, b, snap, false)
+	require.Len(t, events, 6)
+
+	ops := map[resource.URN]display.StepOp{}
+	for _, e := range events[:5] {
+		payload, ok := e.Payload().(ResourcePreEventPayload)
+		require.True(t, ok)
+		ops[payload.Metadata.URN] = payload.Metadata.Op
+	}
+	assert.Equal(t, map[resource.URN]display.StepOp{
+		same:      deploy.OpSame,
+		changed:   deploy.OpUpdate,
+		protected: deploy.OpUpdate,
+		onlyA:     deploy.OpDelete,
+		onlyB:     deploy.OpCreate,
+	}, ops)
+}
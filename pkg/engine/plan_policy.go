@@ -0,0 +1,145 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+)
+
+// AnalyzePlan runs the given local policy packs against the resource goals of a saved plan without running the
+// program that produced it. The planned inputs of each resource are rebuilt from the plan's input diffs and the
+// inputs recorded in the given snapshot, which should be the snapshot that the plan was made against.
+//
+// Only resource policies are run: a plan doesn't record the outputs that stack policies validate.
+func AnalyzePlan(plugctx *plugin.Context, plan *deploy.Plan, snap *deploy.Snapshot, packs []LocalPolicyPack,
+	analyzerOpts *plugin.PolicyAnalyzerOptions,
+) ([]plugin.AnalyzeDiagnostic, error) {
+	var allValidationErrors []string
+	for _, pack := range packs {
+		analyzerInfo, validationErrors, err := loadLocalPolicyPack(plugctx, pack, analyzerOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, validationError := range validationErrors {
+			allValidationErrors = append(allValidationErrors,
+				fmt.Sprintf("validating policy config: %s %s  %s",
+					analyzerInfo.Name, analyzerInfo.Version, validationError))
+		}
+	}
+	if len(allValidationErrors) > 0 {
+		sort.Strings(allValidationErrors)
+		for _, validationError := range allValidationErrors {
+			plugctx.Diag.Errorf(diag.Message("", validationError))
+		}
+		return nil, errors.New("validating policy config")
+	}
+
+	return analyzePlannedResources(plannedResources(plan, snap), plugctx.Host.ListAnalyzers())
+}
+
+// plannedResources returns the resources that the given plan expects to register, in URN order. Resources that the
+// plan only deletes or reads have no goal and are left out.
+func plannedResources(plan *deploy.Plan, snap *deploy.Snapshot) []plugin.AnalyzerResource {
+	olds := oldStates(snap)
+
+	// Work out the planned inputs of every resource first, so that providers that are themselves in the plan are
+	// passed to the analyzers with their planned inputs.
+	inputs := map[resource.URN]resource.PropertyMap{}
+	urns := make([]resource.URN, 0, len(plan.ResourcePlans))
+	for urn, rp := range plan.ResourcePlans {
+		if rp.Goal == nil {
+			continue
+		}
+		var oldInputs resource.PropertyMap
+		if old, ok := olds[urn]; ok {
+			oldInputs = old.Inputs
+		}
+		inputs[urn] = deploy.PlannedInputs(oldInputs, rp)
+		urns = append(urns, urn)
+	}
+	slices.Sort(urns)
+
+	resources := make([]plugin.AnalyzerResource, 0, len(urns))
+	for _, urn := range urns {
+		goal := plan.ResourcePlans[urn].Goal
+		r := plugin.AnalyzerResource{
+			URN:        urn,
+			Type:       goal.Type,
+			Name:       goal.Name,
+			Properties: inputs[urn],
+			Options: plugin.AnalyzerResourceOptions{
+				Protect:                 goal.Protect,
+				IgnoreChanges:           goal.IgnoreChanges,
+				DeleteBeforeReplace:     goal.DeleteBeforeReplace,
+				AdditionalSecretOutputs: goal.AdditionalSecretOutputs,
+				Aliases:                 goal.Aliases,
+				CustomTimeouts:          goal.CustomTimeouts,
+				Parent:                  goal.Parent,
+			},
+		}
+		if goal.Provider != "" {
+			if ref, err := providers.ParseReference(goal.Provider); err == nil {
+				providerInputs, ok := inputs[ref.URN()]
+				if !ok {
+					if old, has := olds[ref.URN()]; has {
+						providerInputs = old.Inputs
+					}
+				}
+				r.Provider = &plugin.AnalyzerProviderResource{
+					URN:        ref.URN(),
+					Type:       ref.URN().Type(),
+					Name:       ref.URN().Name(),
+					Properties: providerInputs,
+				}
+			}
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// analyzePlannedResources runs each analyzer against each of the given resources and returns the policy violations
+// that they report. As a plan can't be remediated, remediation policies are reported as mandatory violations.
+func analyzePlannedResources(
+	resources []plugin.AnalyzerResource, analyzers []plugin.Analyzer,
+) ([]plugin.AnalyzeDiagnostic, error) {
+	var violations []plugin.AnalyzeDiagnostic
+	for _, r := range resources {
+		for _, analyzer := range analyzers {
+			diagnostics, err := analyzer.Analyze(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to run policy: %w", err)
+			}
+			for _, d := range diagnostics {
+				if d.EnforcementLevel == apitype.Remediate {
+					d.EnforcementLevel = apitype.Mandatory
+				}
+				d.URN = r.URN
+				violations = append(violations, d)
+			}
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func TestPlannedResources(t *testing.T) {
+	t.Parallel()
+
+	urn := func(typ tokens.Type, name string) resource.URN {
+		return resource.NewURN("stack", "proj", "", typ, name)
+	}
+	providerURN := urn("pulumi:providers:pkgA", "default")
+	updated := urn("pkgA:m:typA", "updated")
+	created := urn("pkgA:m:typA", "created")
+	replaced := urn("pkgA:m:typA", "replaced")
+	deleted := urn("pkgA:m:typA", "deleted")
+
+	snap := &deploy.Snapshot{Resources: []*resource.State{
+		{URN: providerURN, Type: "pulumi:providers:pkgA", Inputs: resource.PropertyMap{
+			"region": resource.NewStringProperty("us-west-2"),
+		}},
+		{URN: updated, Type: "pkgA:m:typA", Inputs: resource.PropertyMap{
+			"size": resource.NewNumberProperty(1),
+			"tags": resource.NewStringProperty("old"),
+			"keep": resource.NewBoolProperty(true),
+		}},
+		{URN: replaced, Type: "pkgA:m:typA", Inputs: resource.PropertyMap{
+			"zone": resource.NewStringProperty("a"),
+			"name": resource.NewStringProperty("x"),
+		}},
+		{URN: deleted, Type: "pkgA:m:typA"},
+	}}
+
+	provider := string(providerURN) + "::id"
+	plan := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		providerURN: {
+			Goal: &deploy.GoalPlan{Type: "pulumi:providers:pkgA", Name: "default", Custom: true},
+			Ops:  []display.StepOp{deploy.OpSame},
+		},
+		updated: {
+			Goal: &deploy.GoalPlan{
+				Type:     "pkgA:m:typA",
+				Name:     "updated",
+				Custom:   true,
+				Provider: provider,
+				Protect:  true,
+				InputDiff: deploy.PlanDiff{
+					Adds:    resource.PropertyMap{"owner": resource.NewStringProperty("ops")},
+					Updates: resource.PropertyMap{"size": resource.NewNumberProperty(2)},
+					Deletes: []resource.PropertyKey{"tags"},
+				},
+			},
+			Ops: []display.StepOp{deploy.OpUpdate},
+		},
+		created: {
+			Goal: &deploy.GoalPlan{
+				Type:      "pkgA:m:typA",
+				Name:      "created",
+				Custom:    true,
+				Provider:  provider,
+				InputDiff: deploy.PlanDiff{Adds: resource.PropertyMap{"size": resource.NewNumberProperty(3)}},
+			},
+			Ops: []display.StepOp{deploy.OpCreate},
+		},
+		// A replacement caused by a diff is still diffed against the old inputs of the resource.
+		replaced: {
+			Goal: &deploy.GoalPlan{
+				Type:      "pkgA:m:typA",
+				Name:      "replaced",
+				Custom:    true,
+				InputDiff: deploy.PlanDiff{Updates: resource.PropertyMap{"zone": resource.NewStringProperty("b")}},
+			},
+			Ops: []display.StepOp{deploy.OpCreateReplacement, deploy.OpReplace, deploy.OpDeleteReplaced},
+		},
+		deleted: {
+			Ops: []display.StepOp{deploy.OpDelete},
+		},
+	}}
+
+	resources := plannedResources(plan, snap)
+	require.Len(t, resources, 4)
+
+	byURN := map[resource.URN]plugin.AnalyzerResource{}
+	for _, r := range resources {
+		byURN[r.URN] = r
+	}
+	assert.NotContains(t, byURN, deleted)
+
+	assert.Equal(t, resource.PropertyMap{
+		"size":  resource.NewNumberProperty(2),
+		"keep":  resource.NewBoolProperty(true),
+		"owner": resource.NewStringProperty("ops"),
+	}, byURN[updated].Properties)
+	assert.True(t, byURN[updated].Options.Protect)
+	assert.Equal(t, &plugin.AnalyzerProviderResource{
+		URN:        providerURN,
+		Type:       "pulumi:providers:pkgA",
+		Name:       "default",
+		Properties: resource.PropertyMap{"region": resource.NewStringProperty("us-west-2")},
+	}, byURN[updated].Provider)
+
+	assert.Equal(t, resource.PropertyMap{"size": resource.NewNumberProperty(3)}, byURN[created].Properties)
+	assert.Equal(t, resource.PropertyMap{
+		"zone": resource.NewStringProperty("b"),
+		"name": resource.NewStringProperty("x"),
+	}, byURN[replaced].Properties)
+	assert.Nil(t, byURN[replaced].Provider)
+}
+
+func TestAnalyzePlannedResources(t *testing.T) {
+	t.Parallel()
+
+	small := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "small")
+	large := resource.NewURN("stack", "proj", "", "pkgA:m:typA", "large")
+	analyzer := &deploytest.Analyzer{
+		AnalyzeF: func(r plugin.AnalyzerResource) ([]plugin.AnalyzeDiagnostic, error) {
+			if r.Properties["size"].NumberValue() <= 10 {
+				return nil, nil
+			}
+			return []plugin.AnalyzeDiagnostic{
+				{PolicyName: "max-size", PolicyPackName: "pack", EnforcementLevel: apitype.Remediate},
+				{PolicyName: "size-warning", PolicyPackName: "pack", EnforcementLevel: apitype.Advisory},
+			}, nil
+		},
+	}
+
+	violations, err := analyzePlannedResources([]plugin.AnalyzerResource{
+		{URN: small, Properties: resource.PropertyMap{"size": resource.NewNumberProperty(1)}},
+		{URN: large, Properties: resource.PropertyMap{"size": resource.NewNumberProperty(100)}},
+	}, []plugin.Analyzer{analyzer})
+	require.NoError(t, err)
+	assert.Equal(t, []plugin.AnalyzeDiagnostic{
+		{PolicyName: "max-size", PolicyPackName: "pack", EnforcementLevel: apitype.Mandatory, URN: large},
+		{PolicyName: "size-warning", PolicyPackName: "pack", EnforcementLevel: apitype.Advisory, URN: large},
+	}, violations)
+}
@@ -136,6 +136,21 @@ type UpdateOptions struct {
 	// true if the plan should refresh before executing.
 	Refresh bool
 
+	// true if the plan should reconcile the operations left pending by an interrupted update before executing, by
+	// reading the resources they were operating on from their providers.
+	Resume bool
+
+	// true if the plan should undo the changes made by an update that fails, bringing the resources that it created,
+	// updated or replaced back to their state before the update.
+	RollbackOnFailure bool
+
+	// The waves in which to deploy resources during an update, in order. The steps of each wave wait until the steps
+	// of the previous waves have completed.
+	Waves []deploy.DeploymentWave
+
+	// Approves each wave of an update with Waves before it is deployed. If nil, every wave is approved.
+	WaveApprover deploy.WaveApprover
+
 	// true if the plan should run the program as part of destroy.
 	DestroyProgram bool
 
@@ -199,6 +214,12 @@ type UpdateOptions struct {
 	ShowSecrets bool
 }
 
+// UsePreviewPlan returns true if an update should be constrained to the plan made by its preview. This is the case in
+// experimental mode, and for updates deployed in waves, whose waves are worked out from the plan.
+func (opts UpdateOptions) UsePreviewPlan() bool {
+	return opts.Experimental || len(opts.Waves) > 0
+}
+
 // HasChanges returns true if there are any non-same changes in the resulting summary.
 func HasChanges(changes display.ResourceChanges) bool {
 	var c int
@@ -373,23 +394,7 @@ func installAndLoadPolicyPlugins(ctx context.Context, plugctx *plugin.Context,
 		go func(i int, pack LocalPolicyPack) {
 			defer wg.Done()
 			deployOpts.Events.PolicyLoadEvent()
-			abs, err := filepath.Abs(pack.Path)
-			if err != nil {
-				errs <- err
-				return
-			}
-
-			analyzer, err := plugctx.Host.PolicyAnalyzer(tokens.QName(abs), pack.Path, analyzerOpts)
-			if err != nil {
-				errs <- err
-				return
-			} else if analyzer == nil {
-				errs <- fmt.Errorf("policy analyzer could not be loaded from path %q", pack.Path)
-				return
-			}
-
-			// Update the Policy Pack names now that we have loaded the plugins and can access the name.
-			analyzerInfo, err := analyzer.GetAnalyzerInfo()
+			analyzerInfo, validationErrors, err := loadLocalPolicyPack(plugctx, pack, analyzerOpts)
 			if err != nil {
 				errs <- err
 				return
@@ -398,34 +403,7 @@ func installAndLoadPolicyPlugins(ctx context.Context, plugctx *plugin.Context,
 			// Read and store the name and version since it won't have been supplied by anyone else yet.
 			deployOpts.LocalPolicyPacks[i].Name = analyzerInfo.Name
 			deployOpts.LocalPolicyPacks[i].Version = analyzerInfo.Version
-
-			// Load config, reconcile & validate it, and pass it to the policy pack.
-			if !analyzerInfo.SupportsConfig {
-				if pack.Config != "" {
-					errs <- fmt.Errorf("policy pack %q at %q does not support config", analyzerInfo.Name, pack.Path)
-					return
-				}
-				return
-			}
-			var configFromFile map[string]plugin.AnalyzerPolicyConfig
-			if pack.Config != "" {
-				configFromFile, err = resourceanalyzer.LoadPolicyPackConfigFromFile(pack.Config)
-				if err != nil {
-					errs <- err
-					return
-				}
-			}
-			config, validationErrors, err := resourceanalyzer.ReconcilePolicyPackConfig(
-				analyzerInfo.Policies, analyzerInfo.InitialConfig, configFromFile)
-			if err != nil {
-				errs <- fmt.Errorf("reconciling policy config for %q at %q: %w", analyzerInfo.Name, pack.Path, err)
-				return
-			}
 			appendValidationErrors(analyzerInfo.Name, analyzerInfo.Version, validationErrors)
-			if err = analyzer.Configure(config); err != nil {
-				errs <- fmt.Errorf("configuring policy pack %q at %q: %w", analyzerInfo.Name, pack.Path, err)
-				return
-			}
 		}(i, pack)
 	}
 
@@ -449,6 +427,55 @@ func installAndLoadPolicyPlugins(ctx context.Context, plugctx *plugin.Context,
 	return nil
 }
 
+// loadLocalPolicyPack loads the policy analyzer for the given local policy pack and configures it with the pack's
+// config file, if any. It returns the analyzer's info along with any errors found validating the pack's config.
+func loadLocalPolicyPack(plugctx *plugin.Context, pack LocalPolicyPack, analyzerOpts *plugin.PolicyAnalyzerOptions,
+) (plugin.AnalyzerInfo, []string, error) {
+	abs, err := filepath.Abs(pack.Path)
+	if err != nil {
+		return plugin.AnalyzerInfo{}, nil, err
+	}
+
+	analyzer, err := plugctx.Host.PolicyAnalyzer(tokens.QName(abs), pack.Path, analyzerOpts)
+	if err != nil {
+		return plugin.AnalyzerInfo{}, nil, err
+	} else if analyzer == nil {
+		return plugin.AnalyzerInfo{}, nil, fmt.Errorf("policy analyzer could not be loaded from path %q", pack.Path)
+	}
+
+	// Update the Policy Pack names now that we have loaded the plugins and can access the name.
+	analyzerInfo, err := analyzer.GetAnalyzerInfo()
+	if err != nil {
+		return plugin.AnalyzerInfo{}, nil, err
+	}
+
+	// Load config, reconcile & validate it, and pass it to the policy pack.
+	if !analyzerInfo.SupportsConfig {
+		if pack.Config != "" {
+			return analyzerInfo, nil, fmt.Errorf("policy pack %q at %q does not support config", analyzerInfo.Name, pack.Path)
+		}
+		return analyzerInfo, nil, nil
+	}
+	var configFromFile map[string]plugin.AnalyzerPolicyConfig
+	if pack.Config != "" {
+		configFromFile, err = resourceanalyzer.LoadPolicyPackConfigFromFile(pack.Config)
+		if err != nil {
+			return analyzerInfo, nil, err
+		}
+	}
+	config, validationErrors, err := resourceanalyzer.ReconcilePolicyPackConfig(
+		analyzerInfo.Policies, analyzerInfo.InitialConfig, configFromFile)
+	if err != nil {
+		return analyzerInfo, nil, fmt.Errorf(
+			"reconciling policy config for %q at %q: %w", analyzerInfo.Name, pack.Path, err)
+	}
+	if err = analyzer.Configure(config); err != nil {
+		return analyzerInfo, nil, fmt.Errorf(
+			"configuring policy pack %q at %q: %w", analyzerInfo.Name, pack.Path, err)
+	}
+	return analyzerInfo, validationErrors, nil
+}
+
 func newUpdateSource(ctx context.Context,
 	client deploy.BackendClient, opts *deploymentOptions, proj *workspace.Project, pwd, main, projectRoot string,
 	target *deploy.Target, plugctx *plugin.Context,
@@ -0,0 +1,313 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// An InvokeFunc answers the function invocations made by a program that is being captured.
+type InvokeFunc func(ctx context.Context, req *pulumirpc.ResourceInvokeRequest) (*pulumirpc.InvokeResponse, error)
+
+// A CaptureMonitor is a resource monitor that records the resources a program registers instead of creating them. Each
+// custom resource is given a placeholder ID and echoes its inputs as its outputs, so that the program sees known values
+// throughout. The recorded resources can then be turned back into a PCL program with GenerateCapturedProgram.
+type CaptureMonitor struct {
+	pulumirpc.UnimplementedResourceMonitorServer
+
+	project tokens.PackageName
+	stack   tokens.StackName
+	invoke  InvokeFunc
+
+	m         sync.Mutex
+	stackURN  resource.URN
+	resources []*resource.State
+	outputs   resource.PropertyMap
+	// The default providers that have been synthesized for resources that don't set a provider, keyed by package and
+	// version.
+	defaultProviders map[string]*resource.State
+}
+
+// NewCaptureMonitor creates a monitor that captures the resources of a program in the given project and stack. Invokes
+// are answered by the given function; if it is nil, invokes return no results.
+func NewCaptureMonitor(project tokens.PackageName, stack tokens.StackName, invoke InvokeFunc) *CaptureMonitor {
+	return &CaptureMonitor{
+		project:          project,
+		stack:            stack,
+		invoke:           invoke,
+		defaultProviders: map[string]*resource.State{},
+	}
+}
+
+// Resources returns the resources that have been captured in the order they were registered, including the default
+// providers that were synthesized for them.
+func (m *CaptureMonitor) Resources() []*resource.State {
+	m.m.Lock()
+	defer m.m.Unlock()
+	return append([]*resource.State(nil), m.resources...)
+}
+
+// Outputs returns the stack outputs that have been captured.
+func (m *CaptureMonitor) Outputs() resource.PropertyMap {
+	m.m.Lock()
+	defer m.m.Unlock()
+	return m.outputs
+}
+
+var captureMarshalOptions = plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true}
+
+func (m *CaptureMonitor) SupportsFeature(
+	ctx context.Context, req *pulumirpc.SupportsFeatureRequest,
+) (*pulumirpc.SupportsFeatureResponse, error) {
+	// Resource references and output values are left disabled so that programs send plain IDs and values, which can
+	// be matched back to the resources they came from.
+	switch req.Id {
+	case "secrets", "aliasSpecs", "deletedWith":
+		return &pulumirpc.SupportsFeatureResponse{HasSupport: true}, nil
+	default:
+		return &pulumirpc.SupportsFeatureResponse{HasSupport: false}, nil
+	}
+}
+
+func (m *CaptureMonitor) newURN(parent resource.URN, typ, name string) resource.URN {
+	var parentType tokens.Type
+	if parent != "" && parent.QualifiedType() != resource.RootStackType {
+		parentType = parent.QualifiedType()
+	}
+	return resource.NewURN(m.stack.Q(), m.project, parentType, tokens.Type(typ), name)
+}
+
+// defaultProvider returns a reference to the default provider for the given package, synthesizing it if it doesn't
+// exist yet. Callers must hold the monitor's lock.
+func (m *CaptureMonitor) defaultProvider(pkg tokens.Package, version, downloadURL string) (string, error) {
+	key := string(pkg) + "@" + version
+	p, ok := m.defaultProviders[key]
+	if !ok {
+		name := "default"
+		inputs := resource.PropertyMap{}
+		if version != "" {
+			v, err := semver.ParseTolerant(version)
+			if err != nil {
+				return "", fmt.Errorf("invalid version %q for package %s: %w", version, pkg, err)
+			}
+			providers.SetProviderVersion(inputs, &v)
+			name += "_" + strings.NewReplacer(".", "_", "-", "_", "+", "_").Replace(v.String())
+		}
+		if downloadURL != "" {
+			providers.SetProviderURL(inputs, downloadURL)
+		}
+		typ := string(providers.MakeProviderType(pkg))
+		p = &resource.State{
+			Type:    tokens.Type(typ),
+			URN:     m.newURN("", typ, name),
+			ID:      resource.ID(name + "-id"),
+			Custom:  true,
+			Inputs:  inputs,
+			Outputs: inputs,
+		}
+		m.defaultProviders[key] = p
+		m.resources = append(m.resources, p)
+	}
+	ref, err := providers.NewReference(p.URN, p.ID)
+	if err != nil {
+		return "", err
+	}
+	return ref.String(), nil
+}
+
+func (m *CaptureMonitor) RegisterResource(
+	ctx context.Context, req *pulumirpc.RegisterResourceRequest,
+) (*pulumirpc.RegisterResourceResponse, error) {
+	inputs, err := plugin.UnmarshalProperties(req.Object, captureMarshalOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	parent := resource.URN(req.Parent)
+	if req.Type == string(resource.RootStackType) {
+		m.stackURN = m.newURN("", req.Type, req.Name)
+		return &pulumirpc.RegisterResourceResponse{Urn: string(m.stackURN)}, nil
+	}
+	if parent == "" {
+		parent = m.stackURN
+	}
+
+	state := &resource.State{
+		Type:    tokens.Type(req.Type),
+		URN:     m.newURN(parent, req.Type, req.Name),
+		Custom:  req.Custom,
+		Inputs:  inputs,
+		Outputs: inputs,
+		Parent:  parent,
+		Protect: req.GetProtect(),
+	}
+	for _, d := range req.Dependencies {
+		state.Dependencies = append(state.Dependencies, resource.URN(d))
+	}
+
+	if req.Custom || req.Remote {
+		if req.Custom {
+			state.ID = resource.ID(req.Name + "-id")
+		}
+		state.Provider = req.Provider
+		if state.Provider == "" {
+			pkg := state.Type.Package()
+			if providers.IsProviderType(state.Type) {
+				pkg = tokens.Package(state.Type.Name())
+			}
+			state.Provider, err = m.defaultProvider(pkg, req.Version, req.PluginDownloadURL)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	m.resources = append(m.resources, state)
+
+	object, err := plugin.MarshalProperties(inputs, captureMarshalOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.RegisterResourceResponse{
+		Urn:    string(state.URN),
+		Id:     string(state.ID),
+		Object: object,
+	}, nil
+}
+
+func (m *CaptureMonitor) RegisterResourceOutputs(
+	ctx context.Context, req *pulumirpc.RegisterResourceOutputsRequest,
+) (*emptypb.Empty, error) {
+	outputs, err := plugin.UnmarshalProperties(req.Outputs, captureMarshalOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+	if resource.URN(req.Urn) == m.stackURN {
+		m.outputs = outputs
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (m *CaptureMonitor) ReadResource(
+	ctx context.Context, req *pulumirpc.ReadResourceRequest,
+) (*pulumirpc.ReadResourceResponse, error) {
+	// Resources that are read rather than registered aren't part of the captured program, but the program still needs
+	// a value for them.
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	parent := resource.URN(req.Parent)
+	if parent == "" {
+		parent = m.stackURN
+	}
+	return &pulumirpc.ReadResourceResponse{
+		Urn:        string(m.newURN(parent, req.Type, req.Name)),
+		Properties: req.Properties,
+	}, nil
+}
+
+func (m *CaptureMonitor) Invoke(
+	ctx context.Context, req *pulumirpc.ResourceInvokeRequest,
+) (*pulumirpc.InvokeResponse, error) {
+	if m.invoke == nil {
+		return &pulumirpc.InvokeResponse{}, nil
+	}
+	return m.invoke(ctx, req)
+}
+
+// GenerateCapturedProgram writes a PCL program that recreates the resources and stack outputs captured by the given
+// monitor. Values are generated as literals, except where they match the ID, name or ARN of another resource, in which
+// case they are generated as references to that resource. Component resources that the program defined itself have no
+// equivalent in PCL, so their children are generated in their place.
+func GenerateCapturedProgram(w io.Writer, loader schema.Loader, monitor *CaptureMonitor) error {
+	snapshot := monitor.Resources()
+
+	components := map[resource.URN]*resource.State{}
+	for _, s := range snapshot {
+		if !s.Custom && s.Provider == "" {
+			components[s.URN] = s
+		}
+	}
+
+	var states []*resource.State
+	names := NameTable{}
+	taken := map[string]bool{}
+	for _, s := range snapshot {
+		if components[s.URN] != nil || providers.IsDefaultProvider(s.URN) {
+			continue
+		}
+
+		s = s.Copy()
+		for components[s.Parent] != nil {
+			s.Parent = components[s.Parent].Parent
+		}
+		deps := s.Dependencies[:0:0]
+		for _, d := range s.Dependencies {
+			if components[d] == nil {
+				deps = append(deps, d)
+			}
+		}
+		s.Dependencies = deps
+
+		name := s.URN.Name()
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s-%d", s.URN.Name(), n)
+		}
+		taken[name] = true
+		names[s.URN] = name
+		states = append(states, s)
+	}
+
+	program, diags, err := generateProgram(loader, states, snapshot, names, monitor.Outputs())
+	if err != nil {
+		return err
+	}
+	if program == nil {
+		return errors.New("the program's resources refer to each other in a way that can't be expressed in PCL")
+	}
+	if diags.HasErrors() {
+		return &DiagnosticsError{
+			diagnostics:         diags,
+			newDiagnosticWriter: program.NewDiagnosticWriter,
+		}
+	}
+
+	for _, source := range program.Source() {
+		if _, err := io.WriteString(w, source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
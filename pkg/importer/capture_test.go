@@ -0,0 +1,165 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/testing/utils"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+func marshalCaptureInputs(t *testing.T, m resource.PropertyMap) *structpb.Struct {
+	s, err := plugin.MarshalProperties(m, captureMarshalOptions)
+	require.NoError(t, err)
+	return s
+}
+
+func TestGenerateCapturedProgram(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	monitor := NewCaptureMonitor("project", tokens.MustParseStackName("dev"), nil)
+
+	stack, err := monitor.RegisterResource(ctx, &pulumirpc.RegisterResourceRequest{
+		Type: string(resource.RootStackType),
+		Name: "project-dev",
+	})
+	require.NoError(t, err)
+
+	pet, err := monitor.RegisterResource(ctx, &pulumirpc.RegisterResourceRequest{
+		Type:    "random:index/randomPet:RandomPet",
+		Name:    "pet",
+		Custom:  true,
+		Version: "4.11.2",
+		Object: marshalCaptureInputs(t, resource.PropertyMap{
+			"length": resource.NewNumberProperty(3),
+		}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "pet-id", pet.Id)
+
+	// A component defined by the program itself is flattened into its children.
+	component, err := monitor.RegisterResource(ctx, &pulumirpc.RegisterResourceRequest{
+		Type: "my:index:Component",
+		Name: "component",
+	})
+	require.NoError(t, err)
+
+	_, err = monitor.RegisterResource(ctx, &pulumirpc.RegisterResourceRequest{
+		Type:         "random:index/randomPet:RandomPet",
+		Name:         "child",
+		Custom:       true,
+		Parent:       component.Urn,
+		Version:      "4.11.2",
+		Dependencies: []string{pet.Urn},
+		Object: marshalCaptureInputs(t, resource.PropertyMap{
+			"prefix": resource.NewStringProperty(pet.Id),
+		}),
+	})
+	require.NoError(t, err)
+
+	_, err = monitor.RegisterResourceOutputs(ctx, &pulumirpc.RegisterResourceOutputsRequest{
+		Urn: stack.Urn,
+		Outputs: marshalCaptureInputs(t, resource.PropertyMap{
+			"petId":       resource.NewStringProperty(pet.Id),
+			"secret name": resource.MakeSecret(resource.NewStringProperty("hidden")),
+		}),
+	})
+	require.NoError(t, err)
+
+	loader := schema.NewPluginLoader(utils.NewHost(testdataPath))
+	var b strings.Builder
+	err = GenerateCapturedProgram(&b, loader, monitor)
+	require.NoError(t, err)
+
+	expected := `package random {
+    baseProviderName = "random"
+    baseProviderVersion = "4.11.2"
+
+}
+
+resource pet "random:index/randomPet:RandomPet" {
+    length = 3
+
+}
+
+resource child "random:index/randomPet:RandomPet" {
+    prefix = pet.id
+
+}
+
+output petId {
+    value = pet.id
+
+}
+
+output secret_name {
+    __logicalName = "secret name"
+    value = secret("hidden")
+
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestCaptureMonitorProviders(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	monitor := NewCaptureMonitor("project", tokens.MustParseStackName("dev"), nil)
+
+	provider, err := monitor.RegisterResource(ctx, &pulumirpc.RegisterResourceRequest{
+		Type:   "pulumi:providers:random",
+		Name:   "explicit",
+		Custom: true,
+	})
+	require.NoError(t, err)
+
+	_, err = monitor.RegisterResource(ctx, &pulumirpc.RegisterResourceRequest{
+		Type:     "random:index/randomPet:RandomPet",
+		Name:     "pet",
+		Custom:   true,
+		Provider: provider.Urn + "::" + provider.Id,
+	})
+	require.NoError(t, err)
+
+	_, err = monitor.RegisterResource(ctx, &pulumirpc.RegisterResourceRequest{
+		Type:    "random:index/randomPet:RandomPet",
+		Name:    "other",
+		Custom:  true,
+		Version: "4.11.2",
+	})
+	require.NoError(t, err)
+
+	resources := monitor.Resources()
+	require.Len(t, resources, 5)
+	// The explicit provider and the resource without a provider each get a default provider for their version.
+	assert.Equal(t, resource.URN("urn:pulumi:dev::project::pulumi:providers:random::default"), resources[0].URN)
+	assert.Equal(t, "urn:pulumi:dev::project::pulumi:providers:random::default::default-id", resources[1].Provider)
+	assert.Equal(t, provider.Urn+"::"+provider.Id, resources[2].Provider)
+	assert.Equal(t, resource.URN("urn:pulumi:dev::project::pulumi:providers:random::default_4_11_2"), resources[3].URN)
+	assert.Equal(t, string(resources[3].URN)+"::default_4_11_2-id", resources[4].Provider)
+}
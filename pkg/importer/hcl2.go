@@ -19,7 +19,11 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
+	"unicode"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 
 	"github.com/pulumi/pulumi/pkg/v3/codegen"
 	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
@@ -27,6 +31,8 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/archive"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/asset"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/slice"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -162,7 +168,7 @@ func GenerateHCL2Definition(
 	name := sanitizeName(state.URN.Name())
 	// Check if _this_ urn is in the name table, if so we need to set logicalName and use the mapped name for
 	// the resource block.
-	if mappedName, ok := importState.Names[state.URN]; ok {
+	if mappedName, ok := importState.Names[state.URN]; ok && mappedName != state.URN.Name() {
 		items = append(items, &model.Attribute{
 			Name: "__logicalName",
 			Value: &model.TemplateExpression{
@@ -217,6 +223,43 @@ func GenerateHCL2Definition(
 	}, pkgDesc, nil
 }
 
+// generateOutput generates an output block for the given stack output. Outputs whose names aren't valid identifiers
+// are given a sanitized name, and keep their original name as their logical name.
+func generateOutput(name string, value resource.PropertyValue, importState ImportState) (*model.Block, error) {
+	x, err := generateValue(schema.AnyType, value, importState, func(string) {})
+	if err != nil {
+		return nil, fmt.Errorf("output %s: %w", name, err)
+	}
+
+	var items []model.BodyItem
+	identifier := name
+	if !hclsyntax.ValidIdentifier(identifier) {
+		identifier = strings.Map(func(r rune) rune {
+			if r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return '_'
+		}, name)
+		if identifier == "" || !unicode.IsLetter(rune(identifier[0])) {
+			identifier = "_" + identifier
+		}
+		items = append(items, &model.Attribute{
+			Name: "__logicalName",
+			Value: &model.TemplateExpression{
+				Parts: []model.Expression{&model.LiteralValueExpression{Value: cty.StringVal(name)}},
+			},
+		})
+	}
+	items = append(items, &model.Attribute{Name: "value", Value: x})
+
+	return &model.Block{
+		Tokens: syntax.NewBlockTokens("output", identifier),
+		Type:   "output",
+		Labels: []string{identifier},
+		Body:   &model.Body{Items: items},
+	}, nil
+}
+
 func newVariableReference(name string) model.Expression {
 	return model.VariableReference(&model.Variable{
 		Name:         name,
@@ -277,10 +320,12 @@ func makeResourceOptions(state *resource.State, names NameTable, addedRefs map[s
 			}
 		}
 
-		resourceOptions = appendResourceOption(resourceOptions, "dependsOn", &model.TupleConsExpression{
-			Tokens:      syntax.NewTupleConsTokens(len(deps)),
-			Expressions: deps,
-		})
+		if len(deps) != 0 {
+			resourceOptions = appendResourceOption(resourceOptions, "dependsOn", &model.TupleConsExpression{
+				Tokens:      syntax.NewTupleConsTokens(len(deps)),
+				Expressions: deps,
+			})
+		}
 	}
 	if state.Protect {
 		resourceOptions = appendResourceOption(resourceOptions, "protect", &model.LiteralValueExpression{
@@ -734,7 +779,7 @@ func generateValue(
 
 	switch {
 	case value.IsArchive():
-		return nil, errors.New("NYI: archives")
+		return generateArchive(value.ArchiveValue())
 	case value.IsArray():
 		elementType := schema.AnyType
 		if typ, ok := typ.(*schema.ArrayType); ok {
@@ -755,7 +800,7 @@ func generateValue(
 			Expressions: exprs,
 		}, nil
 	case value.IsAsset():
-		return nil, errors.New("NYI: assets")
+		return generateAsset(value.AssetValue())
 	case value.IsBool():
 		return &model.LiteralValueExpression{
 			Value: cty.BoolVal(value.BoolValue()),
@@ -871,3 +916,80 @@ func generateValue(
 		return nil, nil
 	}
 }
+
+// generateAsset generates a call to the PCL function that constructs the given asset.
+func generateAsset(a *asset.Asset) (model.Expression, error) {
+	var name, arg string
+	switch {
+	case a.IsText():
+		name, arg = "stringAsset", a.Text
+	case a.IsPath():
+		name, arg = "fileAsset", a.Path
+	case a.IsURI():
+		name, arg = "remoteAsset", a.URI
+	default:
+		return nil, errors.New("cannot generate an asset without contents")
+	}
+	return &model.FunctionCallExpression{
+		Name: name,
+		Args: []model.Expression{&model.TemplateExpression{
+			Parts: []model.Expression{&model.LiteralValueExpression{Value: cty.StringVal(arg)}},
+		}},
+	}, nil
+}
+
+// generateArchive generates a call to the PCL function that constructs the given archive.
+func generateArchive(a *archive.Archive) (model.Expression, error) {
+	switch {
+	case a.IsAssets():
+		keys := make([]string, 0, len(a.Assets))
+		for k := range a.Assets {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		items := slice.Prealloc[model.ObjectConsItem](len(keys))
+		for _, k := range keys {
+			var x model.Expression
+			var err error
+			switch v := a.Assets[k].(type) {
+			case *asset.Asset:
+				x, err = generateAsset(v)
+			case *archive.Archive:
+				x, err = generateArchive(v)
+			default:
+				err = fmt.Errorf("unexpected archive element %v", v)
+			}
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, model.ObjectConsItem{
+				Key:   &model.LiteralValueExpression{Value: cty.StringVal(fmt.Sprintf("%q", k))},
+				Value: x,
+			})
+		}
+		return &model.FunctionCallExpression{
+			Name: "assetArchive",
+			Args: []model.Expression{&model.ObjectConsExpression{
+				Tokens: syntax.NewObjectConsTokens(len(items)),
+				Items:  items,
+			}},
+		}, nil
+	case a.IsPath():
+		return &model.FunctionCallExpression{
+			Name: "fileArchive",
+			Args: []model.Expression{&model.TemplateExpression{
+				Parts: []model.Expression{&model.LiteralValueExpression{Value: cty.StringVal(a.Path)}},
+			}},
+		}, nil
+	case a.IsURI():
+		return &model.FunctionCallExpression{
+			Name: "remoteArchive",
+			Args: []model.Expression{&model.TemplateExpression{
+				Parts: []model.Expression{&model.LiteralValueExpression{Value: cty.StringVal(a.URI)}},
+			}},
+		}, nil
+	default:
+		return nil, errors.New("cannot generate an archive without contents")
+	}
+}
@@ -130,6 +130,9 @@ func createImportState(states []*resource.State, snapshot []*resource.State, nam
 		}
 
 		name := sanitizeName(state.URN.Name())
+		if mappedName, ok := names[state.URN]; ok {
+			name = sanitizeName(mappedName)
+		}
 		pathedLiteralValues = append(pathedLiteralValues, PathedLiteralValue{
 			Root:  name,
 			Value: resourceID,
@@ -171,6 +174,32 @@ func GenerateLanguageDefinitions(
 	snapshot []*resource.State,
 	names NameTable,
 ) error {
+	program, diags, err := generateProgram(loader, states, snapshot, names, nil)
+	if err != nil || program == nil {
+		return err
+	}
+
+	if diags.HasErrors() {
+		// It is possible that the provided states do not contain appropriately-shaped inputs, so this may be user
+		// error.
+		return &DiagnosticsError{
+			diagnostics:         diags,
+			newDiagnosticWriter: program.NewDiagnosticWriter,
+		}
+	}
+
+	return gen(w, program)
+}
+
+// generateProgram generates and binds a program that defines the given resources and stack outputs. It returns a nil
+// program if the resources reference each other in a way that can't be expressed.
+func generateProgram(
+	loader schema.Loader,
+	states []*resource.State,
+	snapshot []*resource.State,
+	names NameTable,
+	outputs resource.PropertyMap,
+) (*pcl.Program, hcl.Diagnostics, error) {
 	generateProgramText := func(importState ImportState) (*pcl.Program, hcl.Diagnostics, error) {
 		var hcl2Text bytes.Buffer
 
@@ -265,6 +294,15 @@ func GenerateLanguageDefinitions(
 			contract.IgnoreError(err)
 		}
 
+		for _, k := range outputs.StableKeys() {
+			output, err := generateOutput(string(k), outputs[k], importState)
+			if err != nil {
+				return nil, nil, err
+			}
+			_, err = fmt.Fprintf(&hcl2Text, "\n%v", output)
+			contract.IgnoreError(err)
+		}
+
 		parser := syntax.NewParser()
 		if err := parser.ParseFile(&hcl2Text, "anonymous.pp"); err != nil {
 			return nil, nil, err
@@ -300,21 +338,11 @@ func GenerateLanguageDefinitions(
 			// and instead just generate the code with the outputs as literals
 			program, diags, err = generateProgramText(ImportState{Names: names, Snapshot: snapshot})
 			if err != nil {
-				return nil
+				return nil, nil, nil
 			}
 		} else {
-			return err
-		}
-	}
-
-	if diags.HasErrors() {
-		// It is possible that the provided states do not contain appropriately-shaped inputs, so this may be user
-		// error.
-		return &DiagnosticsError{
-			diagnostics:         diags,
-			newDiagnosticWriter: program.NewDiagnosticWriter,
+			return nil, nil, err
 		}
 	}
-
-	return gen(w, program)
+	return program, diags, nil
 }
@@ -99,6 +99,12 @@ func (p *builtinProvider) GetMappings(context.Context, plugin.GetMappingsRequest
 	return plugin.GetMappingsResponse{}, nil
 }
 
+func (p *builtinProvider) DiscoverRelated(
+	context.Context, plugin.DiscoverRelatedRequest,
+) (plugin.DiscoverRelatedResponse, error) {
+	return plugin.DiscoverRelatedResponse{}, nil
+}
+
 // CheckConfig validates the configuration for this resource provider.
 func (p *builtinProvider) CheckConfig(context.Context, plugin.CheckConfigRequest) (plugin.CheckConfigResponse, error) {
 	return plugin.CheckConfigResponse{}, nil
@@ -0,0 +1,257 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// concurrencyLimitsConfigKey is the configuration key under which a project or stack can set concurrency limits.
+const concurrencyLimitsConfigKey = "concurrency-limits"
+
+// ConcurrencyLimits caps the number of steps that may run at the same time against a provider package, a provider
+// instance, or a resource type. These limits apply on top of the deployment's degree of parallelism, so that a highly
+// parallel deployment doesn't overwhelm the API behind any one provider.
+type ConcurrencyLimits struct {
+	// Packages limits the steps on resources whose types belong to the given packages.
+	Packages map[tokens.Package]int
+	// Providers limits the steps on resources managed by the provider instances with the given URNs.
+	Providers map[resource.URN]int
+	// Types limits the steps on resources of the given types.
+	Types map[tokens.Type]int
+}
+
+// IsEmpty returns true if there are no concurrency limits.
+func (l ConcurrencyLimits) IsEmpty() bool {
+	return len(l.Packages) == 0 && len(l.Providers) == 0 && len(l.Types) == 0
+}
+
+// ParseConcurrencyLimits parses concurrency limits from a JSON object that maps keys to the number of steps allowed
+// to run at once. Keys that are URNs name provider instances, keys that contain a colon name resource types, and any
+// other key names a package, e.g.
+//
+//	{"aws": 16, "aws:s3/bucket:Bucket": 4, "urn:pulumi:dev::proj::pulumi:providers:aws::east": 8}
+func ParseConcurrencyLimits(value string) (ConcurrencyLimits, error) {
+	var raw map[string]int
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return ConcurrencyLimits{}, fmt.Errorf("concurrency limits must be an object mapping keys to numbers: %w", err)
+	}
+
+	var limits ConcurrencyLimits
+	for key, limit := range raw {
+		if limit < 1 {
+			return ConcurrencyLimits{}, fmt.Errorf("the concurrency limit for %q must be at least 1, got %d", key, limit)
+		}
+		switch {
+		case resource.URN(key).IsValid():
+			if limits.Providers == nil {
+				limits.Providers = map[resource.URN]int{}
+			}
+			limits.Providers[resource.URN(key)] = limit
+		case strings.Contains(key, ":"):
+			if limits.Types == nil {
+				limits.Types = map[tokens.Type]int{}
+			}
+			limits.Types[tokens.Type(key)] = limit
+		default:
+			if limits.Packages == nil {
+				limits.Packages = map[tokens.Package]int{}
+			}
+			limits.Packages[tokens.Package(key)] = limit
+		}
+	}
+	return limits, nil
+}
+
+// concurrencyLimitsFromConfig reads the concurrency limits set by the pulumi:concurrency-limits configuration key of
+// the given target, if any.
+func concurrencyLimitsFromConfig(target *Target) (ConcurrencyLimits, error) {
+	v, ok := target.Config[config.MustMakeKey("pulumi", concurrencyLimitsConfigKey)]
+	if !ok {
+		return ConcurrencyLimits{}, nil
+	}
+	value, err := v.Value(target.Decrypter)
+	if err != nil {
+		return ConcurrencyLimits{}, err
+	}
+	limits, err := ParseConcurrencyLimits(value)
+	if err != nil {
+		return ConcurrencyLimits{}, fmt.Errorf("pulumi:%s: %w", concurrencyLimitsConfigKey, err)
+	}
+	return limits, nil
+}
+
+// concurrencySlot is a limited number of steps that may run at once, and the description of the limit used to report
+// steps that are waiting on it.
+type concurrencySlot struct {
+	tokens      chan struct{}
+	description string
+}
+
+// concurrencyLimiter holds steps back until they can run within the deployment's concurrency limits. A step must
+// acquire a slot under each limit that applies to it. Slots are always acquired in the same order: package, then
+// provider instance, then type, so steps waiting on each other's slots can't deadlock.
+type concurrencyLimiter struct {
+	limits ConcurrencyLimits
+
+	lock  sync.Mutex
+	slots map[string]*concurrencySlot
+}
+
+func newConcurrencyLimiter(limits ConcurrencyLimits) *concurrencyLimiter {
+	return &concurrencyLimiter{
+		limits: limits,
+		slots:  make(map[string]*concurrencySlot),
+	}
+}
+
+// slot returns the slot for the given key, creating it with the given limit on first use.
+func (cl *concurrencyLimiter) slot(key string, limit int, description string) *concurrencySlot {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	s, ok := cl.slots[key]
+	if !ok {
+		s = &concurrencySlot{tokens: make(chan struct{}, limit), description: description}
+		cl.slots[key] = s
+	}
+	return s
+}
+
+// slotsFor returns the slots that the given step must acquire, in the order in which to acquire them.
+func (cl *concurrencyLimiter) slotsFor(step Step) []*concurrencySlot {
+	// Steps that don't call their provider, and the steps that manage the providers themselves, aren't limited.
+	switch step.Op() {
+	case OpSame, OpRemovePendingReplace:
+		return nil
+	}
+	if _, isDiff := step.(*DiffStep); isDiff {
+		return nil
+	}
+	res := step.Res()
+	if res == nil || !res.Custom || providers.IsProviderType(res.Type) {
+		return nil
+	}
+
+	var slots []*concurrencySlot
+	pkg := res.Type.Package()
+	if limit, ok := cl.limits.Packages[pkg]; ok {
+		slots = append(slots, cl.slot("package:"+string(pkg), limit, fmt.Sprintf("package %s", pkg)))
+	}
+	if res.Provider != "" {
+		if ref, err := providers.ParseReference(res.Provider); err == nil {
+			if limit, ok := cl.limits.Providers[ref.URN()]; ok {
+				slots = append(slots, cl.slot("provider:"+string(ref.URN()), limit,
+					fmt.Sprintf("provider %s", ref.URN().Name())))
+			}
+		}
+	}
+	if limit, ok := cl.limits.Types[res.Type]; ok {
+		slots = append(slots, cl.slot("type:"+string(res.Type), limit, fmt.Sprintf("type %s", res.Type)))
+	}
+	return slots
+}
+
+// tryAcquire takes the slots of the given step if it can run within the concurrency limits right away, and returns a
+// function that releases them once it has run. If the step would have to wait, it returns false and holds no slots.
+func (cl *concurrencyLimiter) tryAcquire(step Step) (func(), bool) {
+	slots := cl.slotsFor(step)
+	release := func(n int) {
+		for i := n - 1; i >= 0; i-- {
+			<-slots[i].tokens
+		}
+	}
+
+	for i, s := range slots {
+		select {
+		case s.tokens <- struct{}{}:
+		default:
+			release(i)
+			return nil, false
+		}
+	}
+	return func() { release(len(slots)) }, true
+}
+
+// acquire blocks until the given step can run within the concurrency limits, calling waiting with the description
+// of each limit that the step has to wait for. It returns a function that releases the step's slots once it has run,
+// or an error if the context is canceled while the step is waiting.
+func (cl *concurrencyLimiter) acquire(
+	ctx context.Context, step Step, waiting func(description string, limit int),
+) (func(), error) {
+	slots := cl.slotsFor(step)
+	release := func(n int) {
+		for i := n - 1; i >= 0; i-- {
+			<-slots[i].tokens
+		}
+	}
+
+	for i, s := range slots {
+		select {
+		case s.tokens <- struct{}{}:
+			continue
+		default:
+		}
+
+		waiting(s.description, cap(s.tokens))
+		select {
+		case s.tokens <- struct{}{}:
+		case <-ctx.Done():
+			release(i)
+			return nil, ctx.Err()
+		}
+	}
+	return func() { release(len(slots)) }, nil
+}
+
+// reportQueued reports that a step is waiting behind a concurrency limit, as a status message that shows up in the
+// progress display.
+func (se *stepExecutor) reportQueued(step Step) func(description string, limit int) {
+	return func(description string, limit int) {
+		se.log(synchronousWorkerID, "step %v on %v queued behind the concurrency limit for %s",
+			step.Op(), step.URN(), description)
+		if sink := se.deployment.ctx.StatusDiag; sink != nil {
+			sink.Infof(diag.RawMessage(step.URN(),
+				fmt.Sprintf("waiting for one of the %d concurrent operations allowed for %s", limit, description)))
+		}
+	}
+}
+
+// acquireQueued blocks until the given step fits within the concurrency limits, reporting it as queued while it waits
+// and clearing that status once it can run.
+func (se *stepExecutor) acquireQueued(step Step) (func(), error) {
+	queued := false
+	report := se.reportQueued(step)
+	release, err := se.limiter.acquire(se.ctx, step, func(description string, limit int) {
+		queued = true
+		report(description, limit)
+	})
+	if queued {
+		if sink := se.deployment.ctx.StatusDiag; sink != nil {
+			sink.Infof(diag.RawMessage(step.URN(), ""))
+		}
+	}
+	return release, err
+}
@@ -0,0 +1,110 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func TestParseConcurrencyLimits(t *testing.T) {
+	t.Parallel()
+
+	limits, err := ParseConcurrencyLimits(
+		`{"aws": 16, "aws:s3/bucket:Bucket": 4, "urn:pulumi:dev::proj::pulumi:providers:aws::east": 8}`)
+	require.NoError(t, err)
+	assert.Equal(t, ConcurrencyLimits{
+		Packages:  map[tokens.Package]int{"aws": 16},
+		Providers: map[resource.URN]int{"urn:pulumi:dev::proj::pulumi:providers:aws::east": 8},
+		Types:     map[tokens.Type]int{"aws:s3/bucket:Bucket": 4},
+	}, limits)
+
+	_, err = ParseConcurrencyLimits(`{"aws": 0}`)
+	assert.ErrorContains(t, err, `the concurrency limit for "aws" must be at least 1, got 0`)
+
+	_, err = ParseConcurrencyLimits(`["aws"]`)
+	assert.ErrorContains(t, err, "concurrency limits must be an object mapping keys to numbers")
+
+	assert.True(t, ConcurrencyLimits{}.IsEmpty())
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := newConcurrencyLimiter(ConcurrencyLimits{
+		Packages: map[tokens.Package]int{"pkgA": 2},
+		Types:    map[tokens.Type]int{"pkgA:m:typA": 1},
+	})
+	newStep := func(typ tokens.Type, name string) Step {
+		return NewCreateStep(nil, noopEvent(0), &resource.State{
+			URN:      resource.NewURN("stack", "proj", "", typ, name),
+			Type:     typ,
+			Custom:   true,
+			Provider: "urn:pulumi:stack::proj::pulumi:providers:pkgA::default::id",
+		})
+	}
+	var waitedOn []string
+	waiting := func(description string, limit int) { waitedOn = append(waitedOn, description) }
+
+	// The first step of a type takes the only slot for that type.
+	releaseA, err := limiter.acquire(context.Background(), newStep("pkgA:m:typA", "a1"), waiting)
+	require.NoError(t, err)
+	assert.Empty(t, waitedOn)
+
+	// Steps of another type are only limited by the package.
+	releaseB, err := limiter.acquire(context.Background(), newStep("pkgA:m:typB", "b1"), waiting)
+	require.NoError(t, err)
+	assert.Empty(t, waitedOn)
+
+	// Both of the package's slots are taken, so the next step waits until it is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = limiter.acquire(ctx, newStep("pkgA:m:typB", "b2"), waiting)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"package pkgA"}, waitedOn)
+
+	// Once a step of typB has run, a second step of typA still waits on its type.
+	releaseB()
+	waitedOn = nil
+	_, err = limiter.acquire(ctx, newStep("pkgA:m:typA", "a2"), waiting)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"type pkgA:m:typA"}, waitedOn)
+
+	// Giving up on the type's slot gave back the package slot, so there is room for another step of typB.
+	waitedOn = nil
+	releaseB, err = limiter.acquire(context.Background(), newStep("pkgA:m:typB", "b3"), waiting)
+	require.NoError(t, err)
+	assert.Empty(t, waitedOn)
+	releaseB()
+
+	// Trying to take slots that are in use fails without holding on to any of them.
+	_, ok := limiter.tryAcquire(newStep("pkgA:m:typA", "a3"))
+	assert.False(t, ok)
+	releaseB, ok = limiter.tryAcquire(newStep("pkgA:m:typB", "b4"))
+	require.True(t, ok)
+	releaseB()
+	releaseA()
+
+	// Steps that don't call their provider are never held back.
+	same := newStep("pkgA:m:typA", "a1").New()
+	same.ID = "a1"
+	assert.Empty(t, limiter.slotsFor(NewSameStep(nil, noopEvent(0), same, same)))
+}
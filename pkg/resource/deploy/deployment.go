@@ -16,6 +16,7 @@ package deploy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"regexp"
@@ -66,6 +67,11 @@ type Options struct {
 	// whether or not to exit after refreshing (i.e. this is specifically a
 	// refresh operation).
 	RefreshOnly bool
+	// whether or not to reconcile the operations left pending by an interrupted
+	// deployment before executing.
+	Resume bool
+	// whether or not to undo the changes made by a deployment that fails.
+	RollbackOnFailure bool
 	// true if the plan should run the program as part of destroy.
 	DestroyProgram bool
 	// if specified, only operate on the specified resources.
@@ -94,6 +100,10 @@ type Options struct {
 	ContinueOnError bool
 	// Autonamer can resolve user's preference for custom autonaming options for a given resource.
 	Autonamer autonaming.Autonamer
+	// if specified, the waves in which to deploy resources, in order.
+	Waves []DeploymentWave
+	// if specified, approves each wave of a deployment with waves before it is deployed.
+	WaveApprover WaveApprover
 }
 
 // DegreeOfParallelism returns the degree of parallelism that should be used during the
@@ -312,6 +322,10 @@ type Deployment struct {
 	newPlans *resourcePlans
 	// the set of resources read as part of the deployment
 	reads *gsync.Map[resource.URN, *resource.State]
+	// the limits on the number of steps that may run at once against each provider package, provider or type.
+	concurrencyLimits ConcurrencyLimits
+	// the retry policies that apply to the resources of each package or type.
+	retryPolicies retryPolicies
 }
 
 // addDefaultProviders adds any necessary default provider definitions and references to the given snapshot. Version
@@ -469,6 +483,12 @@ func NewDeployment(
 	contract.Requiref(target != nil, "target", "must not be nil")
 	contract.Requiref(source != nil, "source", "must not be nil")
 
+	// Without a plan, the resources of a wave that the program hasn't registered yet can't be told apart from those
+	// that it won't register at all, so a later wave could start before an earlier one is done.
+	if len(opts.Waves) > 0 && !opts.DryRun && plan == nil {
+		return nil, errors.New("deploying in waves needs the plan made by a preview of the update")
+	}
+
 	if err := migrateProviders(target, prev, source); err != nil {
 		return nil, err
 	}
@@ -485,6 +505,15 @@ func NewDeployment(
 	// Build the dependency graph for the old resources.
 	depGraph := graph.NewDependencyGraph(oldResources)
 
+	concurrencyLimits, err := concurrencyLimitsFromConfig(target)
+	if err != nil {
+		return nil, err
+	}
+	retryPolicies, err := retryPoliciesFromConfig(target)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a goal map for the deployment.
 	newGoals := &gsync.Map[resource.URN, *resource.Goal]{}
 
@@ -517,6 +546,8 @@ func NewDeployment(
 		news:                 newResources,
 		newPlans:             newResourcePlan(target.Config),
 		reads:                reads,
+		concurrencyLimits:    concurrencyLimits,
+		retryPolicies:        retryPolicies,
 	}, nil
 }
 
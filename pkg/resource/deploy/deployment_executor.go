@@ -43,6 +43,8 @@ type deploymentExecutor struct {
 
 	skipped mapset.Set[urn.URN] // The set of resources that have failed
 
+	waves *waveScheduler // The scheduler of the deployment's waves, if it has any
+
 	// The number of expected events remaining from step generaton, this tells us we're still expecting events
 	// to be posted back to us from async work such as DiffSteps.
 	asyncEventsExpected int32
@@ -101,6 +103,9 @@ func (ex *deploymentExecutor) printPendingOperationsWarning() {
 		"using `pulumi refresh` which will refresh the state from the provider you are using and " +
 		"clear the pending operations if there are any.\n" +
 		"\n" +
+		"Alternatively, `pulumi up --resume` reconciles pending operations other than CREATE operations by " +
+		"reading their resources from the provider, and carries on with the update.\n" +
+		"\n" +
 		"Note that `pulumi refresh` will need to be run interactively to clear pending CREATE operations."
 
 	warning := "Attempting to deploy or update resources " +
@@ -153,6 +158,14 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context) (*Plan, error)
 		return ex.importResources(callerCtx)
 	}
 
+	// If we're resuming an interrupted deployment, reconcile the operations it left pending first.
+	if ex.deployment.opts.Resume && !ex.deployment.opts.DryRun &&
+		ex.deployment.prev != nil && len(ex.deployment.prev.PendingOperations) > 0 {
+		if err := ex.resume(callerCtx); err != nil {
+			return nil, err
+		}
+	}
+
 	// Before doing anything else, optionally refresh each resource in the base checkpoint.
 	if ex.deployment.opts.Refresh {
 		if err := ex.refresh(callerCtx); err != nil {
@@ -197,6 +210,15 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context) (*Plan, error)
 	// Set up a step generator and executor for this deployment.
 	ex.stepExec = newStepExecutor(ctx, cancel, ex.deployment, false)
 
+	// If the deployment is staged, hold back the steps of each wave until the previous waves are done.
+	var waveCompleted <-chan struct{}
+	if len(ex.deployment.opts.Waves) > 0 && !ex.deployment.opts.DryRun {
+		ex.waves = newWaveScheduler(ex.deployment, done, func(c chain) <-chan bool {
+			return ex.stepExec.ExecuteSerial(c).channel
+		})
+		waveCompleted = ex.waves.completed
+	}
+
 	// We iterate the source in its own goroutine because iteration is blocking and we want the main loop to be able to
 	// respond to cancellation requests promptly.
 	type nextEvent struct {
@@ -275,6 +297,12 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context) (*Plan, error)
 						return false, result.BailError(err)
 					}
 				}
+			case <-waveCompleted:
+				if err := ex.waves.chainCompleted(ctx); err != nil {
+					ex.reportError("", err)
+					cancel()
+					return false, result.BailError(err)
+				}
 			case <-ctx.Done():
 				logging.V(4).Infof("deploymentExecutor.Execute(...): context finished: %v", ctx.Err())
 
@@ -322,6 +350,13 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context) (*Plan, error)
 
 	logging.V(4).Infof("deploymentExecutor.Execute(...): step executor has completed")
 
+	// If the deployment failed and we've been asked to, undo the changes that it made before carrying on to report
+	// the failure. There's no point trying if the caller has cancelled the deployment.
+	failed := err != nil || stepExecutorError != nil || ex.stepGen.Errored()
+	if failed && ex.deployment.opts.RollbackOnFailure && !ex.deployment.opts.DryRun && callerCtx.Err() == nil {
+		ex.rollback(callerCtx)
+	}
+
 	// Check that we did operations for everything expected in the plan. We mutate ResourcePlan.Ops as we run
 	// so by the time we get here everything in the map should have an empty ops list (except for unneeded
 	// deletes). We skip this check if we already have an error, chances are if the deployment failed lots of
@@ -536,6 +571,9 @@ func (ex *deploymentExecutor) handleSingleEvent(event SourceEvent) error {
 		return nil
 	}
 
+	if ex.waves != nil {
+		return ex.waves.schedule(ex.stepExec.ctx, newSteps)
+	}
 	ex.stepExec.ExecuteSerial(newSteps)
 	return nil
 }
@@ -681,6 +719,62 @@ func (ex *deploymentExecutor) refresh(callerCtx context.Context) error {
 		}
 	}
 
+	return ex.runRefreshSteps(callerCtx, steps, resourceToStep)
+}
+
+// resume reconciles the operations that an interrupted deployment left pending, by reading the resources they were
+// operating on from their providers, so that this deployment can carry on from where the interrupted one stopped.
+//
+// The resources of pending updates and deletes are refreshed, which removes them from the base state if they no longer
+// exist. The resources of pending imports are added to the base state and refreshed, so that they're only kept if
+// they exist. Pending reads are dropped, since the program will read their resources again. Pending creates can't be
+// read, because the IDs of their resources aren't known, and are left for `pulumi refresh` to resolve.
+func (ex *deploymentExecutor) resume(callerCtx context.Context) error {
+	prev := ex.deployment.prev
+
+	var remaining []resource.Operation
+	pending := make(map[resource.URN]bool)
+	for _, op := range prev.PendingOperations {
+		switch op.Type {
+		case resource.OperationTypeUpdating, resource.OperationTypeDeleting:
+			pending[op.Resource.URN] = true
+		case resource.OperationTypeImporting:
+			if op.Resource.ID == "" {
+				remaining = append(remaining, op)
+				continue
+			}
+			// The resource was registered after everything it depends on, so it can go at the end of the base state.
+			prev.Resources = append(prev.Resources, op.Resource)
+			pending[op.Resource.URN] = true
+		case resource.OperationTypeReading:
+			logging.V(7).Infof("Dropping pending read of %v", op.Resource.URN)
+		default:
+			remaining = append(remaining, op)
+		}
+	}
+	prev.PendingOperations = remaining
+
+	steps := []Step{}
+	resourceToStep := map[*resource.State]Step{}
+	for _, res := range prev.Resources {
+		if !pending[res.URN] || !res.Custom || providers.IsProviderType(res.Type) {
+			continue
+		}
+		if err := ex.deployment.EnsureProvider(res.Provider); err != nil {
+			return fmt.Errorf("could not load provider for resource %v: %w", res.URN, err)
+		}
+		step := NewRefreshStep(ex.deployment, res)
+		steps = append(steps, step)
+		resourceToStep[res] = step
+	}
+
+	return ex.runRefreshSteps(callerCtx, steps, resourceToStep)
+}
+
+// runRefreshSteps executes the given refresh steps, and rebuilds the base state from their results.
+func (ex *deploymentExecutor) runRefreshSteps(
+	callerCtx context.Context, steps []Step, resourceToStep map[*resource.State]Step,
+) error {
 	// Fire up a worker pool and issue each refresh in turn.
 	ctx, cancel := context.WithCancel(callerCtx)
 
@@ -59,6 +59,8 @@ type Provider struct {
 	CallF         func(context.Context, plugin.CallRequest, *ResourceMonitor) (plugin.CallResponse, error)
 	GetMappingF   func(context.Context, plugin.GetMappingRequest) (plugin.GetMappingResponse, error)
 	GetMappingsF  func(context.Context, plugin.GetMappingsRequest) (plugin.GetMappingsResponse, error)
+
+	DiscoverRelatedF func(context.Context, plugin.DiscoverRelatedRequest) (plugin.DiscoverRelatedResponse, error)
 }
 
 func (prov *Provider) Handshake(
@@ -266,3 +268,13 @@ func (prov *Provider) GetMappings(
 	}
 	return prov.GetMappingsF(ctx, req)
 }
+
+func (prov *Provider) DiscoverRelated(
+	ctx context.Context,
+	req plugin.DiscoverRelatedRequest,
+) (plugin.DiscoverRelatedResponse, error) {
+	if prov.DiscoverRelatedF == nil {
+		return plugin.DiscoverRelatedResponse{}, nil
+	}
+	return prov.DiscoverRelatedF(ctx, req)
+}
@@ -139,6 +139,8 @@ type ResourceOptions struct {
 	Aliases                 []*pulumirpc.Alias
 	ImportID                resource.ID
 	CustomTimeouts          *resource.CustomTimeouts
+	RetryPolicy             *resource.RetryPolicy
+	DeploymentWave          string
 	RetainOnDelete          *bool
 	DeletedWith             resource.URN
 	SupportsPartialValues   *bool
@@ -232,6 +234,16 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		}
 	}
 
+	var retryPolicy *pulumirpc.RegisterResourceRequest_RetryPolicy
+	if opts.RetryPolicy != nil {
+		retryPolicy = &pulumirpc.RegisterResourceRequest_RetryPolicy{
+			MaxAttempts: int32(opts.RetryPolicy.MaxAttempts), //nolint:gosec // attempts never come close to overflowing int32
+			Delay:       prepareTestTimeout(opts.RetryPolicy.Delay),
+			MaxDelay:    prepareTestTimeout(opts.RetryPolicy.MaxDelay),
+			Errors:      opts.RetryPolicy.Errors,
+		}
+	}
+
 	deleteBeforeReplace := false
 	if opts.DeleteBeforeReplace != nil {
 		deleteBeforeReplace = *opts.DeleteBeforeReplace
@@ -272,6 +284,8 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		AliasURNs:                  aliasStrings,
 		ImportId:                   string(opts.ImportID),
 		CustomTimeouts:             timeouts,
+		RetryPolicy:                retryPolicy,
+		DeploymentWave:             opts.DeploymentWave,
 		SupportsPartialValues:      supportsPartialValues,
 		Remote:                     opts.Remote,
 		ReplaceOnChanges:           opts.ReplaceOnChanges,
@@ -20,11 +20,14 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/blang/semver"
 	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/v3/util/gsync"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/slice"
@@ -79,6 +82,11 @@ type Import struct {
 	Component bool
 	// True if this is a remote component resource. Component must be true if this is true.
 	Remote bool
+
+	// True if the resources related to this resource should be discovered from its provider and imported with it.
+	Discover bool
+	// The resources that this resource depends on, if any.
+	Dependencies []resource.URN
 }
 
 // ImportOptions controls the import process.
@@ -119,6 +127,15 @@ func NewImportDeployment(
 		return nil, err
 	}
 
+	concurrencyLimits, err := concurrencyLimitsFromConfig(target)
+	if err != nil {
+		return nil, err
+	}
+	retryPolicies, err := retryPoliciesFromConfig(target)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a goal map for the deployment.
 	newGoals := &gsync.Map[resource.URN, *resource.Goal]{}
 
@@ -148,6 +165,9 @@ func NewImportDeployment(
 		providers:    reg,
 		newPlans:     newResourcePlan(target.Config),
 		news:         &gsync.Map[resource.URN, *resource.State]{},
+
+		concurrencyLimits: concurrencyLimits,
+		retryPolicies:     retryPolicies,
 	}, nil
 }
 
@@ -371,6 +391,10 @@ func (i *importer) importResources(ctx context.Context) error {
 		return err
 	}
 
+	if err := i.discoverImports(ctx, stackURN, urnToReference); err != nil {
+		return err
+	}
+
 	// Create a step per resource to import and execute them in parallel batches which don't depend on each other.
 	// If there are duplicates, fail the import.
 	urns := map[resource.URN]struct{}{}
@@ -380,7 +404,7 @@ func (i *importer) importResources(ctx context.Context) error {
 		if parent == "" {
 			parent = stackURN
 		}
-		urn := i.deployment.generateURN(parent, imp.Type, imp.Name)
+		urn := i.importURN(stackURN, imp)
 
 		// Check for duplicate imports.
 		if _, has := urns[urn]; has {
@@ -406,16 +430,9 @@ func (i *importer) importResources(ctx context.Context) error {
 			}
 		}
 
-		providerURN := imp.Provider
-		if providerURN == "" && (!imp.Component || imp.Remote) {
-			pkg, version, parameterization, err := imp.Parameterization.ToProviderParameterization(imp.Type, imp.Version)
-			if err != nil {
-				return err
-			}
-			req := providers.NewProviderRequest(
-				pkg, version, imp.PluginDownloadURL, imp.PluginChecksums, parameterization)
-			typ, name := providers.MakeProviderType(req.Package()), req.DefaultName()
-			providerURN = i.deployment.generateURN("", typ, name)
+		providerURN, err := i.importProviderURN(imp)
+		if err != nil {
+			return err
 		}
 
 		var provider string
@@ -428,7 +445,7 @@ func (i *importer) importResources(ctx context.Context) error {
 		// Create the new desired state. Note that the resource is protected. Provider might be "" at this point.
 		new := resource.NewState(
 			urn.Type(), urn, !imp.Component, false, imp.ID, resource.PropertyMap{}, nil, parent, imp.Protect,
-			false, nil, nil, provider, nil, false, nil, nil, nil, "", false, "", nil, nil, "", nil)
+			false, imp.Dependencies, nil, provider, nil, false, nil, nil, nil, "", false, "", nil, nil, "", nil)
 		// Set a dummy goal so the resource is tracked as managed.
 		i.deployment.goals.Store(urn, &resource.Goal{})
 
@@ -469,11 +486,25 @@ func (i *importer) importResources(ctx context.Context) error {
 				continue
 			}
 
-			// If the step has no dependencies (we actually only need to look at parent), it can be executed in parallel
-			if _, ok := urns[step.New().Parent]; !ok {
+			// If neither the step's parent nor any of its dependencies are still to be imported, it can be executed in
+			// parallel.
+			if _, ok := urns[step.New().Parent]; ok {
+				continue
+			}
+			ready := true
+			for _, dep := range step.New().Dependencies {
+				if _, ok := urns[dep]; ok {
+					ready = false
+					break
+				}
+			}
+			if ready {
 				parallelSteps = append(parallelSteps, step)
 			}
 		}
+		if len(parallelSteps) == 0 {
+			return errors.New("the resources to import have circular dependencies")
+		}
 
 		// Remove all the urns we're about to import
 		for _, step := range parallelSteps {
@@ -491,3 +522,145 @@ func (i *importer) importResources(ctx context.Context) error {
 
 	return nil
 }
+
+// importURN returns the URN that the given import will be imported with.
+func (i *importer) importURN(stackURN resource.URN, imp Import) resource.URN {
+	parent := imp.Parent
+	if parent == "" {
+		parent = stackURN
+	}
+	return i.deployment.generateURN(parent, imp.Type, imp.Name)
+}
+
+// importProviderURN returns the URN of the provider that the given import will be imported with, which is the default
+// provider for its package if it doesn't specify one. Local components have no provider.
+func (i *importer) importProviderURN(imp Import) (resource.URN, error) {
+	if imp.Provider != "" || (imp.Component && !imp.Remote) {
+		return imp.Provider, nil
+	}
+
+	pkg, version, parameterization, err := imp.Parameterization.ToProviderParameterization(imp.Type, imp.Version)
+	if err != nil {
+		return "", err
+	}
+	req := providers.NewProviderRequest(
+		pkg, version, imp.PluginDownloadURL, imp.PluginChecksums, parameterization)
+	typ, name := providers.MakeProviderType(req.Package()), req.DefaultName()
+	return i.deployment.generateURN("", typ, name), nil
+}
+
+// discoverImports expands each import that asks for discovery with the resources that its provider reports as related
+// to it, and in turn with the resources related to those. Discovered resources are imported using the same provider as
+// the resource they were discovered from, so related resources from other packages are skipped with a warning.
+func (i *importer) discoverImports(
+	ctx context.Context, stackURN resource.URN, urnToReference map[resource.URN]string,
+) error {
+	type resourceKey struct {
+		typ tokens.Type
+		id  resource.ID
+	}
+
+	imports := i.deployment.imports
+	seen := map[resourceKey]struct{}{}
+	urns := map[resource.URN]struct{}{}
+	for _, imp := range imports {
+		seen[resourceKey{imp.Type, imp.ID}] = struct{}{}
+		urns[i.importURN(stackURN, imp)] = struct{}{}
+	}
+
+	// Discovered resources are appended to the list of imports, so this walks each tree breadth first.
+	for idx := 0; idx < len(imports); idx++ {
+		imp := imports[idx]
+		if !imp.Discover || imp.Component {
+			continue
+		}
+		urn := i.importURN(stackURN, imp)
+
+		providerURN, err := i.importProviderURN(imp)
+		if err != nil {
+			return err
+		}
+		ref, err := providers.ParseReference(urnToReference[providerURN])
+		if err != nil {
+			return fmt.Errorf("discovering resources related to %v: %w", urn, err)
+		}
+		prov, ok := i.deployment.providers.GetProvider(ref)
+		if !ok {
+			return fmt.Errorf("discovering resources related to %v: unknown provider '%v'", urn, ref)
+		}
+
+		resp, err := prov.DiscoverRelated(ctx, plugin.DiscoverRelatedRequest{URN: urn, ID: imp.ID})
+		if err != nil {
+			return fmt.Errorf("discovering resources related to %v: %w", urn, err)
+		}
+
+		for _, r := range resp.Resources {
+			key := resourceKey{r.Type, r.ID}
+			if _, has := seen[key]; has {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			if r.Type.Package() != imp.Type.Package() {
+				i.deployment.Diag().Warningf(diag.RawMessage(urn, fmt.Sprintf(
+					"skipping discovered resource %v of type %v: only resources of package %v can be imported with %v",
+					r.ID, r.Type, imp.Type.Package(), urn)))
+				continue
+			}
+
+			related := Import{
+				Type:              r.Type,
+				ID:                r.ID,
+				Parent:            imp.Parent,
+				Provider:          imp.Provider,
+				Version:           imp.Version,
+				PluginDownloadURL: imp.PluginDownloadURL,
+				PluginChecksums:   imp.PluginChecksums,
+				Protect:           imp.Protect,
+				Parameterization:  imp.Parameterization,
+				Discover:          true,
+			}
+			switch r.Relationship {
+			case plugin.RelationshipChild:
+				related.Parent = urn
+			case plugin.RelationshipDependent:
+				related.Dependencies = []resource.URN{urn}
+			}
+
+			// Pick a name that doesn't clash with any other resource being imported.
+			name := discoveredName(r)
+			related.Name = name
+			for n := 2; ; n++ {
+				if _, has := urns[i.importURN(stackURN, related)]; !has {
+					break
+				}
+				related.Name = name + "-" + strconv.Itoa(n)
+			}
+			relatedURN := i.importURN(stackURN, related)
+			urns[relatedURN] = struct{}{}
+
+			if r.Relationship == plugin.RelationshipDependency {
+				imports[idx].Dependencies = append(imports[idx].Dependencies, relatedURN)
+			}
+			imports = append(imports, related)
+		}
+	}
+
+	i.deployment.imports = imports
+	return nil
+}
+
+// discoveredName returns the name to import a discovered resource with: the name suggested by its provider, or
+// otherwise its ID with any characters that aren't valid in a resource name replaced.
+func discoveredName(r plugin.RelatedResource) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return strings.Map(func(c rune) rune {
+		if c == '_' || c == '-' || c == '.' ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			return c
+		}
+		return '-'
+	}, string(r.ID))
+}
@@ -129,6 +129,8 @@ type GoalPlan struct {
 	Custom bool
 	// the resource's checked input properties we expect to change.
 	InputDiff PlanDiff
+	// true if InputDiff was taken against no inputs at all, rather than against the resource's old inputs.
+	FromEmptyInputs bool
 	// the resource's output properties we expect to change (only set for RegisterResourceOutputs)
 	OutputDiff PlanDiff
 	// an optional parent URN for this resource.
@@ -222,6 +224,30 @@ type ResourcePlan struct {
 	Seed []byte
 }
 
+// PlannedInputs applies the input diff of a resource's plan to the inputs that the resource had before the plan was
+// made. Plans whose goals were diffed against no inputs at all, such as those for recreated or external resources,
+// don't start from the old inputs.
+func PlannedInputs(olds resource.PropertyMap, rp *ResourcePlan) resource.PropertyMap {
+	inputs := resource.PropertyMap{}
+	if !rp.Goal.FromEmptyInputs {
+		for k, v := range olds {
+			inputs[k] = v
+		}
+	}
+
+	diff := rp.Goal.InputDiff
+	for _, k := range diff.Deletes {
+		delete(inputs, k)
+	}
+	for k, v := range diff.Adds {
+		inputs[k] = v
+	}
+	for k, v := range diff.Updates {
+		inputs[k] = v
+	}
+	return inputs
+}
+
 func (rp *ResourcePlan) diffURNs(a, b []resource.URN) (message string, changed bool) {
 	stringsA := make([]string, len(a))
 	for i, urn := range a {
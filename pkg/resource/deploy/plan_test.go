@@ -18,6 +18,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pulumi/pulumi/pkg/v3/display"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/stretchr/testify/assert"
 )
@@ -540,6 +541,34 @@ func TestResourcePlan(t *testing.T) {
 	})
 }
 
+func TestPlannedInputs(t *testing.T) {
+	t.Parallel()
+
+	olds := resource.PropertyMap{"kept": resource.NewProperty("a"), "deleted": resource.NewProperty("b")}
+	rp := &ResourcePlan{
+		Goal: &GoalPlan{InputDiff: PlanDiff{
+			Adds:    resource.PropertyMap{"added": resource.NewProperty("c")},
+			Deletes: []resource.PropertyKey{"deleted"},
+		}},
+		Ops: []display.StepOp{OpUpdate},
+	}
+	assert.Equal(t, resource.PropertyMap{
+		"kept":  resource.NewProperty("a"),
+		"added": resource.NewProperty("c"),
+	}, PlannedInputs(olds, rp))
+
+	// A replacement caused by a diff is still diffed against the old inputs.
+	rp.Ops = []display.StepOp{OpCreateReplacement, OpReplace, OpDeleteReplaced}
+	assert.Equal(t, resource.PropertyMap{
+		"kept":  resource.NewProperty("a"),
+		"added": resource.NewProperty("c"),
+	}, PlannedInputs(olds, rp))
+
+	// A goal that was diffed against no inputs, such as a create-before-delete recreate, starts from no inputs at all.
+	rp.Goal.FromEmptyInputs = true
+	assert.Equal(t, resource.PropertyMap{"added": resource.NewProperty("c")}, PlannedInputs(olds, rp))
+}
+
 func TestCheckDiff(t *testing.T) {
 	t.Parallel()
 	t.Run("planDiff.Deletes", func(t *testing.T) {
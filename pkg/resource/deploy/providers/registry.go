@@ -479,6 +479,14 @@ func (r *Registry) GetMappings(context.Context, plugin.GetMappingsRequest) (plug
 	return plugin.GetMappingsResponse{}, errors.New("the provider registry has no mappings")
 }
 
+func (r *Registry) DiscoverRelated(
+	context.Context, plugin.DiscoverRelatedRequest,
+) (plugin.DiscoverRelatedResponse, error) {
+	contract.Failf("DiscoverRelated must not be called on the provider registry")
+
+	return plugin.DiscoverRelatedResponse{}, errors.New("the provider registry has no related resources")
+}
+
 // CheckConfig validates the configuration for this resource provider.
 func (r *Registry) CheckConfig(context.Context, plugin.CheckConfigRequest) (plugin.CheckConfigResponse, error) {
 	contract.Failf("CheckConfig must not be called on the provider registry")
@@ -0,0 +1,212 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// retryPoliciesConfigKey is the configuration key under which a project or stack can set retry policies.
+const retryPoliciesConfigKey = "retry-policies"
+
+// retryPolicySpec is the form of a retry policy in a RegisterResource request or in configuration, with its delays
+// given as duration strings.
+type retryPolicySpec struct {
+	MaxAttempts int      `json:"maxAttempts"`
+	Delay       string   `json:"delay"`
+	MaxDelay    string   `json:"maxDelay"`
+	Errors      []string `json:"errors"`
+}
+
+func (spec retryPolicySpec) policy() (*resource.RetryPolicy, error) {
+	if spec.MaxAttempts < 0 {
+		return nil, fmt.Errorf("maxAttempts must not be negative, got %d", spec.MaxAttempts)
+	}
+	parse := func(name, value string) (float64, error) {
+		if value == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse %s value %s", name, value)
+		}
+		return d.Seconds(), nil
+	}
+
+	delay, err := parse("delay", spec.Delay)
+	if err != nil {
+		return nil, err
+	}
+	maxDelay, err := parse("maxDelay", spec.MaxDelay)
+	if err != nil {
+		return nil, err
+	}
+	return &resource.RetryPolicy{
+		MaxAttempts: spec.MaxAttempts,
+		Delay:       delay,
+		MaxDelay:    maxDelay,
+		Errors:      spec.Errors,
+	}, nil
+}
+
+// parseRetryPolicy returns the retry policy of a RegisterResource request, if it has one.
+func parseRetryPolicy(rpc *pulumirpc.RegisterResourceRequest_RetryPolicy) (*resource.RetryPolicy, error) {
+	if rpc == nil {
+		return nil, nil
+	}
+	return retryPolicySpec{
+		MaxAttempts: int(rpc.GetMaxAttempts()),
+		Delay:       rpc.GetDelay(),
+		MaxDelay:    rpc.GetMaxDelay(),
+		Errors:      rpc.GetErrors(),
+	}.policy()
+}
+
+// retryPolicies holds the retry policies that apply to all the resources of a package or type.
+type retryPolicies struct {
+	packages map[tokens.Package]*resource.RetryPolicy
+	types    map[tokens.Type]*resource.RetryPolicy
+}
+
+// retryPoliciesFromConfig reads the retry policies set by the pulumi:retry-policies configuration key of the given
+// target, if any. The key's value is an object that maps type tokens or package names to policies, e.g.
+//
+//	{"aws:s3/bucket:Bucket": {"maxAttempts": 5, "delay": "1s", "maxDelay": "30s", "errors": ["Throttling"]}}
+func retryPoliciesFromConfig(target *Target) (retryPolicies, error) {
+	v, ok := target.Config[config.MustMakeKey("pulumi", retryPoliciesConfigKey)]
+	if !ok {
+		return retryPolicies{}, nil
+	}
+	value, err := v.Value(target.Decrypter)
+	if err != nil {
+		return retryPolicies{}, err
+	}
+
+	var specs map[string]retryPolicySpec
+	if err := json.Unmarshal([]byte(value), &specs); err != nil {
+		return retryPolicies{}, fmt.Errorf("pulumi:%s must be an object mapping types or packages to retry policies: %w",
+			retryPoliciesConfigKey, err)
+	}
+	var policies retryPolicies
+	for key, spec := range specs {
+		policy, err := spec.policy()
+		if err != nil {
+			return retryPolicies{}, fmt.Errorf("pulumi:%s: %s: %w", retryPoliciesConfigKey, key, err)
+		}
+		if strings.Contains(key, ":") {
+			if policies.types == nil {
+				policies.types = map[tokens.Type]*resource.RetryPolicy{}
+			}
+			policies.types[tokens.Type(key)] = policy
+		} else {
+			if policies.packages == nil {
+				policies.packages = map[tokens.Package]*resource.RetryPolicy{}
+			}
+			policies.packages[tokens.Package(key)] = policy
+		}
+	}
+	return policies, nil
+}
+
+// retryable returns true if a failed operation may be retried under the given policy.
+func retryable(policy *resource.RetryPolicy, status resource.Status, err error) bool {
+	// Operations that partially succeeded have changed the resource, and deleting a protected resource is never
+	// going to succeed.
+	if status == resource.StatusPartialFailure || errors.As(err, &deleteProtectedError{}) {
+		return false
+	}
+	if len(policy.Errors) == 0 {
+		return true
+	}
+	for _, e := range policy.Errors {
+		if strings.Contains(err.Error(), e) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before the given retry of an operation under the given policy, starting from 1.
+func retryDelay(policy *resource.RetryPolicy, retry int) time.Duration {
+	seconds := policy.Delay * math.Pow(2, float64(retry-1))
+	if policy.MaxDelay > 0 && seconds > policy.MaxDelay {
+		seconds = policy.MaxDelay
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// retryPolicy returns the retry policy that applies to the given step, if any. A policy set on the resource itself
+// takes precedence over one set for its type, which takes precedence over one set for its package.
+func (se *stepExecutor) retryPolicy(step Step) *resource.RetryPolicy {
+	if se.deployment.opts.DryRun {
+		return nil
+	}
+	switch step.Op() {
+	case OpCreate, OpUpdate, OpDelete, OpCreateReplacement, OpDeleteReplaced:
+	default:
+		return nil
+	}
+	// Only custom resources call their provider, and component types need not have a module to take a package from.
+	if res := step.Res(); res == nil || !res.Custom {
+		return nil
+	}
+
+	if se.deployment.goals != nil {
+		if goal, ok := se.deployment.goals.Load(step.URN()); ok && goal.RetryPolicy != nil {
+			return goal.RetryPolicy
+		}
+	}
+	if policy, ok := se.deployment.retryPolicies.types[step.Type()]; ok {
+		return policy
+	}
+	if policy, ok := se.deployment.retryPolicies.packages[step.Type().Package()]; ok {
+		return policy
+	}
+	return nil
+}
+
+// applyStep applies a step, retrying it with backoff under its retry policy for as long as it fails.
+func (se *stepExecutor) applyStep(workerID int, step Step) (resource.Status, StepCompleteFunc, error) {
+	policy := se.retryPolicy(step)
+	for attempt := 1; ; attempt++ {
+		status, complete, err := step.Apply()
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts || !retryable(policy, status, err) {
+			return status, complete, err
+		}
+
+		delay := retryDelay(policy, attempt)
+		se.log(workerID, "step %v on %v failed, retrying in %v: %v", step.Op(), step.URN(), delay, err)
+		se.deployment.Diag().Warningf(diag.RawMessage(step.URN(), fmt.Sprintf(
+			"%s failed, retrying in %v (attempt %d of %d): %v",
+			step.Op(), delay, attempt+1, policy.MaxAttempts, err)))
+
+		select {
+		case <-time.After(delay):
+		case <-se.ctx.Done():
+			return status, complete, err
+		}
+	}
+}
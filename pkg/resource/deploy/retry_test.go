@@ -0,0 +1,112 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+func TestParseRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := parseRetryPolicy(nil)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	policy, err = parseRetryPolicy(&pulumirpc.RegisterResourceRequest_RetryPolicy{
+		MaxAttempts: 5,
+		Delay:       "500ms",
+		MaxDelay:    "1m",
+		Errors:      []string{"Throttling"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &resource.RetryPolicy{
+		MaxAttempts: 5,
+		Delay:       0.5,
+		MaxDelay:    60,
+		Errors:      []string{"Throttling"},
+	}, policy)
+
+	_, err = parseRetryPolicy(&pulumirpc.RegisterResourceRequest_RetryPolicy{MaxAttempts: 2, Delay: "soon"})
+	assert.ErrorContains(t, err, "unable to parse delay value soon")
+}
+
+func TestRetryPoliciesFromConfig(t *testing.T) {
+	t.Parallel()
+
+	target := &Target{Config: config.Map{
+		config.MustMakeKey("pulumi", "retry-policies"): config.NewObjectValue(
+			`{"aws": {"maxAttempts": 3}, "aws:s3/bucket:Bucket": {"maxAttempts": 5, "delay": "1s"}}`),
+	}}
+	policies, err := retryPoliciesFromConfig(target)
+	require.NoError(t, err)
+	assert.Equal(t, &resource.RetryPolicy{MaxAttempts: 3}, policies.packages["aws"])
+	assert.Equal(t, &resource.RetryPolicy{MaxAttempts: 5, Delay: 1}, policies.types["aws:s3/bucket:Bucket"])
+
+	target.Config[config.MustMakeKey("pulumi", "retry-policies")] = config.NewObjectValue(`{"aws": {"maxAttempts": -1}}`)
+	_, err = retryPoliciesFromConfig(target)
+	assert.ErrorContains(t, err, "pulumi:retry-policies: aws: maxAttempts must not be negative, got -1")
+}
+
+func TestRetryDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := &resource.RetryPolicy{MaxAttempts: 10, Delay: 1, MaxDelay: 5}
+	assert.Equal(t, time.Second, retryDelay(policy, 1))
+	assert.Equal(t, 2*time.Second, retryDelay(policy, 2))
+	assert.Equal(t, 4*time.Second, retryDelay(policy, 3))
+	assert.Equal(t, 5*time.Second, retryDelay(policy, 4))
+}
+
+func TestRetryable(t *testing.T) {
+	t.Parallel()
+
+	throttled := errors.New("Throttling: rate exceeded")
+	assert.True(t, retryable(&resource.RetryPolicy{}, resource.StatusUnknown, throttled))
+	assert.True(t, retryable(&resource.RetryPolicy{Errors: []string{"Throttling"}}, resource.StatusOK, throttled))
+	assert.False(t, retryable(&resource.RetryPolicy{Errors: []string{"NotFound"}}, resource.StatusOK, throttled))
+	// Operations that partially succeeded are never retried.
+	assert.False(t, retryable(&resource.RetryPolicy{}, resource.StatusPartialFailure, throttled))
+	assert.False(t, retryable(&resource.RetryPolicy{}, resource.StatusOK, deleteProtectedError{}))
+}
+
+func TestRetryPolicyFor(t *testing.T) {
+	t.Parallel()
+
+	se := &stepExecutor{deployment: &Deployment{
+		opts:          &Options{},
+		retryPolicies: retryPolicies{packages: map[tokens.Package]*resource.RetryPolicy{"pkgA": {MaxAttempts: 3}}},
+	}}
+	newStep := func(typ tokens.Type, custom bool) Step {
+		state := &resource.State{URN: resource.NewURN("stack", "proj", "", typ, "name"), Type: typ, Custom: custom}
+		if custom {
+			state.Provider = "urn:pulumi:stack::proj::pulumi:providers:pkgA::default::id"
+		}
+		return NewCreateStep(nil, noopEvent(0), state)
+	}
+	assert.Equal(t, &resource.RetryPolicy{MaxAttempts: 3}, se.retryPolicy(newStep("pkgA:m:typA", true)))
+	// Components don't call a provider, and their types need not have a package to look up.
+	assert.Nil(t, se.retryPolicy(newStep("pkgA:component", false)))
+}
@@ -0,0 +1,143 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// restoreReplacedStep deletes the replacement of a resource that was replaced create-before-delete, and once the
+// replacement is gone, unmarks the resource it replaced for deletion so that it is kept.
+type restoreReplacedStep struct {
+	Step                     // the step that deletes the replacement.
+	replaced *resource.State // the resource that was replaced.
+}
+
+func (s *restoreReplacedStep) Apply() (resource.Status, StepCompleteFunc, error) {
+	status, complete, err := s.Step.Apply()
+	if err == nil {
+		s.replaced.Lock.Lock()
+		s.replaced.Delete = false
+		s.replaced.Lock.Unlock()
+	}
+	return status, complete, err
+}
+
+// irreversibleChange is a change made by a failed deployment that rolling back can't undo.
+type irreversibleChange struct {
+	urn    resource.URN
+	reason string
+}
+
+// rollbackSteps returns the steps that undo the changes made by the given applied steps, along with the changes that
+// can't be undone. Steps complete only after the steps they depend on, so undoing them in the reverse order respects
+// the dependencies between resources.
+func (ex *deploymentExecutor) rollbackSteps(applied []Step) ([]Step, []irreversibleChange) {
+	// Create-before-delete replacements can only be undone if the resources they replaced haven't been deleted yet.
+	deleted := make(map[*resource.State]bool)
+	for _, step := range applied {
+		if step.Op() == OpDeleteReplaced {
+			deleted[step.Old()] = true
+		}
+	}
+
+	var steps []Step
+	var irreversible []irreversibleChange
+	report := func(step Step, reason string) {
+		irreversible = append(irreversible, irreversibleChange{step.URN(), reason})
+	}
+	for i := len(applied) - 1; i >= 0; i-- {
+		step := applied[i]
+		switch step.Op() {
+		case OpCreate:
+			if step.New().Protect {
+				report(step, "it was created, but is protected")
+				continue
+			}
+			steps = append(steps, NewDeleteStep(ex.deployment, map[resource.URN]bool{}, step.New()))
+		case OpUpdate:
+			restored := step.Old().Copy()
+			restored.ID = ""
+			steps = append(steps, NewUpdateStep(ex.deployment, noopEvent(0), step.New(), restored, nil, nil, nil, nil))
+		case OpCreateReplacement:
+			if deleted[step.Old()] {
+				// The deletion of the replaced resource is reported below.
+				continue
+			}
+			if step.New().Protect {
+				report(step, "it was replaced, but the replacement is protected")
+				continue
+			}
+			steps = append(steps, &restoreReplacedStep{
+				Step:     NewDeleteStep(ex.deployment, map[resource.URN]bool{}, step.New()),
+				replaced: step.Old(),
+			})
+		case OpDeleteReplaced:
+			report(step, "it was replaced, and the original has been deleted")
+		case OpDelete:
+			report(step, "it has been deleted")
+		case OpImport, OpImportReplacement:
+			report(step, "it was imported, and is left in the stack")
+		default:
+			logging.V(7).Infof("Nothing to roll back for %v step on %v", step.Op(), step.URN())
+		}
+	}
+	return steps, irreversible
+}
+
+// rollback undoes the changes made by the steps that this deployment applied before it failed, bringing the resources
+// it created, updated and replaced back to their state before the deployment. Changes that can't be undone, such as
+// deletions, are reported as warnings.
+func (ex *deploymentExecutor) rollback(callerCtx context.Context) {
+	steps, irreversible := ex.rollbackSteps(ex.stepExec.GetAppliedSteps())
+	if len(steps) == 0 && len(irreversible) == 0 {
+		return
+	}
+	ex.deployment.Diag().Infof(diag.Message("", "rolling back %d change(s) made by the failed update"), len(steps))
+
+	ctx, cancel := context.WithCancel(callerCtx)
+	defer cancel()
+	stepExec := newStepExecutor(ctx, cancel, ex.deployment, false)
+	if len(steps) > 0 {
+		stepExec.ExecuteSerial(steps)
+	}
+	stepExec.SignalCompletion()
+	stepExec.WaitForCompletion()
+
+	// Steps run in order, so the first step that fails stops those that come after it.
+	rolledBack := make(map[Step]bool)
+	for _, step := range stepExec.GetAppliedSteps() {
+		rolledBack[step] = true
+	}
+	for _, step := range steps {
+		if !rolledBack[step] {
+			irreversible = append(irreversible, irreversibleChange{step.URN(), "undoing its changes failed"})
+		}
+	}
+
+	for _, change := range irreversible {
+		ex.deployment.Diag().Warningf(diag.RawMessage(change.urn,
+			fmt.Sprintf("could not roll back %s: %s", change.urn.Name(), change.reason)))
+	}
+	if len(irreversible) > 0 {
+		ex.deployment.Diag().Warningf(diag.Message("",
+			"%d change(s) made by the failed update could not be rolled back"), len(irreversible))
+	}
+}
@@ -1576,7 +1576,7 @@ func (rm *resmon) RegisterStackInvokeTransform(ctx context.Context, cb *pulumirp
 }
 
 // inheritFromParent returns a new goal that inherits from the given parent goal.
-// Currently only inherits DeletedWith, Protect, and RetainOnDelete from parent.
+// Currently only inherits DeletedWith, Protect, RetainOnDelete, and DeploymentWave from parent.
 func inheritFromParent(child resource.Goal, parent resource.Goal) *resource.Goal {
 	goal := child
 	if goal.DeletedWith == "" {
@@ -1588,6 +1588,9 @@ func inheritFromParent(child resource.Goal, parent resource.Goal) *resource.Goal
 	if goal.RetainOnDelete == nil {
 		goal.RetainOnDelete = parent.RetainOnDelete
 	}
+	if goal.DeploymentWave == "" {
+		goal.DeploymentWave = parent.DeploymentWave
+	}
 	return &goal
 }
 
@@ -2188,6 +2191,10 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 		return nil, rpcerror.New(codes.InvalidArgument, fmt.Sprintf("invalid DeletedWith URN: %s", err))
 	}
 	customTimeouts := opts.CustomTimeouts
+	retryPolicy, err := parseRetryPolicy(req.GetRetryPolicy())
+	if err != nil {
+		return nil, rpcerror.New(codes.InvalidArgument, fmt.Sprintf("invalid retryPolicy: %s", err))
+	}
 
 	additionalSecretOutputs := opts.GetAdditionalSecretOutputs()
 
@@ -2322,6 +2329,8 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 			additionalSecretKeys, parsedAliases, id, &timeouts, replaceOnChanges, retainOnDelete, deletedWith,
 			sourcePosition,
 		)
+		goal.RetryPolicy = retryPolicy
+		goal.DeploymentWave = req.GetDeploymentWave()
 
 		if goal.Parent != "" {
 			rm.resGoalsLock.Lock()
@@ -2444,6 +2453,9 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 		rm.checkComponentOption(result.State.URN, "deletedWith", func() bool {
 			return deletedWith != ""
 		})
+		rm.checkComponentOption(result.State.URN, "retryPolicy", func() bool {
+			return retryPolicy != nil
+		})
 	}
 
 	logging.V(5).Infof(
@@ -84,6 +84,9 @@ func (c completionToken) Wait(ctx context.Context) {
 type incomingChain struct {
 	Chain          chain     // The chain we intend to execute
 	CompletionChan chan bool // A completion channel to be closed when the chain has completed execution
+	// Releases the concurrency limit slots already taken for the first step of the chain, if it was requeued after
+	// waiting on a limit.
+	Release func()
 }
 
 // stepExecutor is the component of the engine responsible for taking steps and executing
@@ -112,6 +115,14 @@ type stepExecutor struct {
 	workers        sync.WaitGroup     // WaitGroup tracking the worker goroutines that are owned by this step executor.
 	incomingChains chan incomingChain // Incoming chains that we are to execute
 
+	// Holds back steps that would exceed the deployment's concurrency limits, if it has any.
+	limiter *concurrencyLimiter
+	// Chains that are waiting on a concurrency limit outside of any worker, to be requeued once their next step can
+	// run. Parking a chain is not allowed once completion has been signalled, as the incoming channel is then closing.
+	parkLock sync.Mutex
+	parked   sync.WaitGroup
+	closing  bool
+
 	ctx    context.Context    // cancellation context for the current deployment.
 	cancel context.CancelFunc // CancelFunc that cancels the above context.
 
@@ -122,6 +133,11 @@ type stepExecutor struct {
 	erroredStepLock sync.RWMutex
 	erroredSteps    []Step
 
+	// The steps that have been applied successfully, in the order in which they completed. These are only recorded
+	// if the deployment is to be rolled back on failure.
+	appliedStepLock sync.Mutex
+	appliedSteps    []Step
+
 	// ExecuteRegisterResourceOutputs will save the event for the stack resource so that the stack outputs
 	// can be finalized at the end of the deployment. We do this so we can determine whether or not the
 	// deployment succeeded. If there were errors, we update any stack outputs that were updated, but don't delete
@@ -167,6 +183,13 @@ func (se *stepExecutor) GetErroredSteps() []Step {
 	return se.erroredSteps
 }
 
+// GetAppliedSteps returns the steps that have been applied successfully, in the order in which they completed.
+func (se *stepExecutor) GetAppliedSteps() []Step {
+	se.appliedStepLock.Lock()
+	defer se.appliedStepLock.Unlock()
+	return se.appliedSteps
+}
+
 // ExecuteParallel submits an antichain for parallel execution. All of the steps within the antichain are submitted for
 // concurrent execution.
 func (se *stepExecutor) ExecuteParallel(antichain antichain) completionToken {
@@ -308,6 +331,12 @@ func (se *stepExecutor) Errored() error {
 // SignalCompletion signals to the stepExecutor that there are no more chains left to execute. All worker
 // threads will terminate as soon as they retire all of the work they are currently executing.
 func (se *stepExecutor) SignalCompletion() {
+	// Chains waiting on a concurrency limit still need to be requeued, so wait for them before closing the channel.
+	se.parkLock.Lock()
+	se.closing = true
+	se.parkLock.Unlock()
+	se.parked.Wait()
+
 	close(se.incomingChains)
 }
 
@@ -326,22 +355,50 @@ func (se *stepExecutor) WaitForCompletion() {
 //
 
 // executeChain executes a chain, one step at a time. If any step in the chain fails to execute, or if the
-// context is canceled, the chain stops execution.
-func (se *stepExecutor) executeChain(workerID int, chain chain) {
-	for _, step := range chain {
+// context is canceled, the chain stops execution. If a step has to wait on a concurrency limit, the rest of the chain
+// is parked until it can run and then requeued, so that the worker is free to run other chains in the meantime. In
+// that case executeChain returns true and the chain's completion channel is left to the requeued chain.
+func (se *stepExecutor) executeChain(workerID int, request incomingChain) bool {
+	for i, step := range request.Chain {
 		select {
 		case <-se.ctx.Done():
 			se.log(workerID, "step %v on %v canceled", step.Op(), step.URN())
-			return
+			if i == 0 && request.Release != nil {
+				request.Release()
+			}
+			return false
 		default:
 		}
 
+		// Take the step's slots within the concurrency limits before taking the work lock, so that steps waiting on
+		// a limit don't hold up anything that needs the write side of the lock.
+		release := func() {}
+		if i == 0 && request.Release != nil {
+			release = request.Release
+		} else if se.limiter != nil {
+			var ok bool
+			release, ok = se.limiter.tryAcquire(step)
+			if !ok {
+				if se.park(workerID, request.Chain[i:], request.CompletionChan) {
+					return true
+				}
+
+				var err error
+				release, err = se.acquireQueued(step)
+				if err != nil {
+					se.log(workerID, "step %v on %v canceled while waiting on a concurrency limit", step.Op(), step.URN())
+					return false
+				}
+			}
+		}
+
 		// Take the work lock before executing the step, this uses the "read" side of the lock because we're ok with as
 		// many workers as possible executing steps in parallel.
 		se.workerLock.RLock()
 		err := se.executeStep(workerID, step)
 		// Regardless of error we need to release the lock here.
 		se.workerLock.RUnlock()
+		release()
 
 		if err != nil {
 			se.log(workerID, "step %v on %v failed, signalling cancellation", step.Op(), step.URN())
@@ -357,9 +414,43 @@ func (se *stepExecutor) executeChain(workerID int, chain chain) {
 				diagMsg := diag.RawMessage(step.URN(), err.Error())
 				se.deployment.Diag().Errorf(diagMsg)
 			}
-			return
+			return false
 		}
 	}
+	return false
+}
+
+// park waits for the first step of the given chain to fit within the concurrency limits outside of any worker, and
+// then requeues the chain with the step's slots already taken. It returns false if the chain can't be parked because
+// completion has already been signalled, in which case the caller has to wait for the slots itself.
+func (se *stepExecutor) park(workerID int, rest chain, completion chan bool) bool {
+	se.parkLock.Lock()
+	defer se.parkLock.Unlock()
+	if se.closing {
+		return false
+	}
+
+	step := rest[0]
+	se.log(workerID, "parking step %v on %v until it fits within the concurrency limits", step.Op(), step.URN())
+	se.parked.Add(1)
+	go func() {
+		defer se.parked.Done()
+
+		release, err := se.acquireQueued(step)
+		if err != nil {
+			se.log(workerID, "step %v on %v canceled while waiting on a concurrency limit", step.Op(), step.URN())
+			close(completion)
+			return
+		}
+
+		select {
+		case se.incomingChains <- incomingChain{Chain: rest, CompletionChan: completion, Release: release}:
+		case <-se.ctx.Done():
+			release()
+			close(completion)
+		}
+	}()
+	return true
 }
 
 func (se *stepExecutor) cancelDueToError(err error, step Step) {
@@ -422,7 +513,7 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	}
 
 	se.log(workerID, "applying step %v on %v (preview %v)", step.Op(), step.URN(), se.deployment.opts.DryRun)
-	status, stepComplete, err := step.Apply()
+	status, stepComplete, err := se.applyStep(workerID, step)
 	if isDiff {
 		return nil
 	}
@@ -530,6 +621,12 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 		return StepApplyFailed{err}
 	}
 
+	if se.deployment.opts.RollbackOnFailure {
+		se.appliedStepLock.Lock()
+		se.appliedSteps = append(se.appliedSteps, step)
+		se.appliedStepLock.Unlock()
+	}
+
 	return nil
 }
 
@@ -575,8 +672,9 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 
 			se.log(workerID, "worker received chain for execution")
 			if !launchAsync {
-				se.executeChain(workerID, request.Chain)
-				close(request.CompletionChan)
+				if !se.executeChain(workerID, request) {
+					close(request.CompletionChan)
+				}
 				continue
 			}
 
@@ -587,8 +685,9 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 			go func() {
 				defer se.workers.Done()
 				se.log(newWorkerID, "launching oneshot worker")
-				se.executeChain(newWorkerID, request.Chain)
-				close(request.CompletionChan)
+				if !se.executeChain(newWorkerID, request) {
+					close(request.CompletionChan)
+				}
 			}()
 
 			oneshotWorkerID++
@@ -612,6 +711,9 @@ func newStepExecutor(
 		ctx:            ctx,
 		cancel:         cancel,
 	}
+	if !deployment.concurrencyLimits.IsEmpty() {
+		exec.limiter = newConcurrencyLimiter(deployment.concurrencyLimits)
+	}
 
 	// If we're being asked to run as parallel as possible, spawn a single worker that launches chain executions
 	// asynchronously.
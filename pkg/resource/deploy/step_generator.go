@@ -867,10 +867,13 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, boo
 
 	// If the resource is valid and we're generating plans then generate a plan
 	if !invalid && sg.deployment.opts.GeneratePlan {
-		if recreating || wasExternal || sg.isTargetedReplace(urn) || !hasOld {
+		fromEmptyInputs := recreating || wasExternal || sg.isTargetedReplace(urn) || !hasOld
+		if fromEmptyInputs {
 			oldInputs = nil
 		}
 		inputDiff := oldInputs.Diff(inputs)
+		goalPlan := NewGoalPlan(inputDiff, goal)
+		goalPlan.FromEmptyInputs = fromEmptyInputs
 
 		// Generate the output goal plan, if we're recreating this it should already exist
 		if recreating {
@@ -881,11 +884,11 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, boo
 			// The plan will have had it's Ops already partially filled in for the delete operation, but we
 			// now have the information needed to fill in Seed and Goal.
 			plan.Seed = randomSeed
-			plan.Goal = NewGoalPlan(inputDiff, goal)
+			plan.Goal = goalPlan
 		} else {
 			newResourcePlan := &ResourcePlan{
 				Seed: randomSeed,
-				Goal: NewGoalPlan(inputDiff, goal),
+				Goal: goalPlan,
 			}
 			sg.deployment.newPlans.set(urn, newResourcePlan)
 		}
@@ -0,0 +1,383 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// DeploymentWave selects the resources that make up one wave of a staged deployment. A resource is in the wave if
+// any of the wave's selectors matches it.
+type DeploymentWave struct {
+	// Names selects resources whose deploymentWave resource option is one of the given names.
+	Names []string
+	// Types selects resources by their type.
+	Types []tokens.Type
+	// Tags selects resources that are tagged with any of the given key and value pairs.
+	Tags map[string]string
+	// Targets selects resources by their URN.
+	Targets UrnTargets
+}
+
+// tagProperties are the properties that providers commonly keep a resource's tags in, e.g. "tagsAll" for the tags
+// that an AWS resource inherits from its provider, or "labels" for Google Cloud and Kubernetes resources.
+var tagProperties = []resource.PropertyKey{"tags", "tagsAll", "labels"}
+
+// Contains returns true if the wave selects the given resource, which was registered with the given deploymentWave
+// resource option.
+func (w DeploymentWave) Contains(res *resource.State, name string) bool {
+	if name != "" && slices.Contains(w.Names, name) {
+		return true
+	}
+	if slices.Contains(w.Types, res.Type) {
+		return true
+	}
+	if w.Targets.IsConstrained() && w.Targets.Contains(res.URN) {
+		return true
+	}
+	if len(w.Tags) > 0 {
+		for _, props := range []resource.PropertyMap{res.Inputs, res.Outputs} {
+			for _, k := range tagProperties {
+				if tags, ok := props[k]; ok && w.hasTag(tags) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasTag returns true if the given tags hold any of the wave's tags. Tags are either a map from key to value, or a
+// list of objects with key and value properties, as some providers use for resources that have more to say about each
+// tag.
+func (w DeploymentWave) hasTag(tags resource.PropertyValue) bool {
+	matches := func(k, v resource.PropertyValue) bool {
+		if !k.IsString() || !v.IsString() {
+			return false
+		}
+		want, ok := w.Tags[k.StringValue()]
+		return ok && want == v.StringValue()
+	}
+
+	switch {
+	case tags.IsObject():
+		for k, v := range tags.ObjectValue() {
+			if matches(resource.NewStringProperty(string(k)), v) {
+				return true
+			}
+		}
+	case tags.IsArray():
+		for _, tag := range tags.ArrayValue() {
+			if !tag.IsObject() {
+				continue
+			}
+			obj := tag.ObjectValue()
+			if matches(obj["key"], obj["value"]) || matches(obj["Key"], obj["Value"]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WaveApprover decides whether a staged deployment may go on to its next wave.
+type WaveApprover interface {
+	// ApproveWave is called once every step of the waves before the given one has completed, with the resources
+	// whose steps are waiting on the wave, followed by those that the plan expects in the wave but that the program
+	// hasn't registered yet. It returns false to stop the deployment.
+	ApproveWave(ctx context.Context, wave int, resources []resource.URN) (bool, error)
+}
+
+// WaveApproverFunc is a function that implements WaveApprover.
+type WaveApproverFunc func(ctx context.Context, wave int, resources []resource.URN) (bool, error)
+
+func (f WaveApproverFunc) ApproveWave(ctx context.Context, wave int, resources []resource.URN) (bool, error) {
+	return f(ctx, wave, resources)
+}
+
+// waveScheduler holds back the steps of a deployment that belong to later waves until the steps of earlier waves have
+// completed and the next wave has been approved.
+//
+// Every resource belongs to the first wave that selects it. Resources that no wave selects belong to an implicit last
+// wave, except for providers and component resources, which don't change anything themselves and so belong to the
+// first wave. Steps that don't change their resource are never held back. Whatever its own wave, a resource is never
+// deployed before the resources that it depends on, so the wave of a resource is the latest of its own wave and the
+// waves of its dependencies.
+//
+// A wave is done once none of its steps are running and the steps of all the resources that the deployment's plan
+// expects to change in the wave have been seen, as the program may not have registered them all yet. Deployments with
+// waves must have a plan, which the CLI makes by previewing the update first.
+//
+// The scheduler is only used from the deployment executor's main loop, so it needs no locking.
+type waveScheduler struct {
+	deployment *Deployment
+	waves      []DeploymentWave
+	approver   WaveApprover
+
+	current   int                    // the latest wave whose steps may run.
+	of        map[resource.URN]int   // the wave of each resource seen so far.
+	expected  map[resource.URN]int   // the waves of the resources the plan expects to change, until they're seen.
+	running   int                    // the number of chains that are running.
+	parked    map[int][]chain        // the chains held back, by wave.
+	urns      map[int][]resource.URN // the resources of the chains held back, by wave.
+	completed chan struct{}          // signalled each time a running chain completes.
+	done      <-chan bool            // closed when the deployment's main loop exits.
+
+	submit func(chain) <-chan bool // submits a chain to the step executor, returning its completion channel.
+}
+
+func newWaveScheduler(deployment *Deployment, done <-chan bool, submit func(chain) <-chan bool) *waveScheduler {
+	ws := &waveScheduler{
+		deployment: deployment,
+		waves:      deployment.opts.Waves,
+		approver:   deployment.opts.WaveApprover,
+		of:         make(map[resource.URN]int),
+		expected:   make(map[resource.URN]int),
+		parked:     make(map[int][]chain),
+		urns:       make(map[int][]resource.URN),
+		completed:  make(chan struct{}),
+		done:       done,
+		submit:     submit,
+	}
+	ws.expectPlan(deployment.plan)
+	return ws
+}
+
+// expectPlan records the waves of the resources that the given plan expects to change. This must be called before the
+// deployment starts to carry out the plan, as it consumes the plan's operations.
+func (ws *waveScheduler) expectPlan(plan *Plan) {
+	planned := make(map[resource.URN]int)
+	var waveOf func(urn resource.URN) int
+	waveOf = func(urn resource.URN) int {
+		if wave, ok := planned[urn]; ok {
+			return wave
+		}
+		rp, ok := plan.ResourcePlans[urn]
+		if !ok || rp.Goal == nil {
+			return 0
+		}
+		// Guard against cycles while we work out the waves of the dependencies.
+		planned[urn] = 0
+
+		var oldInputs resource.PropertyMap
+		if old := ws.deployment.olds[urn]; old != nil {
+			oldInputs = old.Inputs
+		}
+		res := &resource.State{
+			URN:                  urn,
+			Type:                 rp.Goal.Type,
+			Custom:               rp.Goal.Custom,
+			Inputs:               PlannedInputs(oldInputs, rp),
+			Parent:               rp.Goal.Parent,
+			Dependencies:         rp.Goal.Dependencies,
+			PropertyDependencies: rp.Goal.PropertyDependencies,
+			Provider:             rp.Goal.Provider,
+		}
+		wave := 0
+		if plannedChanges(rp.Ops) {
+			// Plans don't record the deploymentWave option, so only the wave's other selectors apply.
+			wave = ws.ownWave(res, "")
+		}
+		for _, dep := range resourceDependencies(res) {
+			wave = max(wave, waveOf(dep))
+		}
+		planned[urn] = wave
+		return wave
+	}
+
+	for urn, rp := range plan.ResourcePlans {
+		if rp.Goal != nil && plannedChanges(rp.Ops) {
+			ws.expected[urn] = waveOf(urn)
+		}
+	}
+}
+
+// plannedChanges returns true if any of the given operations changes its resource.
+func plannedChanges(ops []display.StepOp) bool {
+	for _, op := range ops {
+		switch op {
+		case OpSame, OpRead, OpReadReplacement:
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// resourceDependencies returns the URNs of all the resources that a resource depends on.
+func resourceDependencies(res *resource.State) []resource.URN {
+	deps := slices.Clone(res.Dependencies)
+	if res.Parent != "" {
+		deps = append(deps, res.Parent)
+	}
+	if res.DeletedWith != "" {
+		deps = append(deps, res.DeletedWith)
+	}
+	for _, propDeps := range res.PropertyDependencies {
+		deps = append(deps, propDeps...)
+	}
+	if res.Provider != "" {
+		if ref, err := providers.ParseReference(res.Provider); err == nil {
+			deps = append(deps, ref.URN())
+		}
+	}
+	return deps
+}
+
+// ownWave returns the wave that a resource belongs to, regardless of its dependencies. name is the deploymentWave
+// resource option that the resource was registered with, if any.
+func (ws *waveScheduler) ownWave(res *resource.State, name string) int {
+	for i, wave := range ws.waves {
+		if wave.Contains(res, name) {
+			return i
+		}
+	}
+	if !res.Custom || providers.IsProviderType(res.Type) {
+		return 0
+	}
+	return len(ws.waves)
+}
+
+// waveOf returns the wave of the resource that the given chain operates on.
+func (ws *waveScheduler) waveOf(steps chain) int {
+	res := steps[0].Res()
+
+	ops := make([]display.StepOp, len(steps))
+	for i, step := range steps {
+		ops[i] = step.Op()
+	}
+	wave := 0
+	if plannedChanges(ops) {
+		name := ""
+		if ws.deployment.goals != nil {
+			if goal, ok := ws.deployment.goals.Load(res.URN); ok && goal != nil {
+				name = goal.DeploymentWave
+			}
+		}
+		wave = ws.ownWave(res, name)
+	}
+	for _, dep := range resourceDependencies(res) {
+		if depWave, ok := ws.of[dep]; ok && depWave > wave {
+			wave = depWave
+		}
+	}
+
+	ws.of[res.URN] = wave
+	return wave
+}
+
+// schedule runs the given chain if its wave has been reached, and holds it back otherwise.
+func (ws *waveScheduler) schedule(ctx context.Context, steps chain) error {
+	if len(steps) == 0 {
+		ws.submit(steps)
+		return nil
+	}
+	if _, isDiff := steps[0].(*DiffStep); isDiff {
+		// Diffs don't change anything, and the steps they lead to are scheduled once they're done.
+		ws.submit(steps)
+		return nil
+	}
+
+	wave := ws.waveOf(steps)
+	delete(ws.expected, steps[0].URN())
+	if wave <= ws.current {
+		ws.run(steps)
+		return nil
+	}
+
+	logging.V(7).Infof("Holding back the steps of %v until wave %d", steps[0].URN(), wave)
+	ws.parked[wave] = append(ws.parked[wave], steps)
+	ws.urns[wave] = append(ws.urns[wave], steps[0].URN())
+	return ws.advance(ctx)
+}
+
+func (ws *waveScheduler) run(steps chain) {
+	ws.running++
+	completion := ws.submit(steps)
+	go func() {
+		<-completion
+		select {
+		case ws.completed <- struct{}{}:
+		case <-ws.done:
+		}
+	}()
+}
+
+// chainCompleted records the completion of a running chain, and goes on to the next wave if the current one is done.
+func (ws *waveScheduler) chainCompleted(ctx context.Context) error {
+	ws.running--
+	return ws.advance(ctx)
+}
+
+// advance goes on to the next wave if no steps are running, once the wave is approved. Waves are approved one at a
+// time and in order, including waves whose steps are all still expected from the program, so that no resource is
+// ever deployed without its wave having been approved. Waves that nothing is expected in are skipped.
+func (ws *waveScheduler) advance(ctx context.Context) error {
+	if ws.running > 0 || len(ws.parked) == 0 {
+		return nil
+	}
+
+	next := -1
+	for wave := range ws.parked {
+		if next == -1 || wave < next {
+			next = wave
+		}
+	}
+	var expected []resource.URN
+	for urn, wave := range ws.expected {
+		if wave <= ws.current {
+			// The current wave isn't done until all the resources expected in it have been seen.
+			return nil
+		}
+		if wave < next {
+			next, expected = wave, nil
+		}
+		if wave == next {
+			expected = append(expected, urn)
+		}
+	}
+	slices.Sort(expected)
+	urns := append(slices.Clone(ws.urns[next]), expected...)
+
+	ws.deployment.Diag().Infof(diag.Message("", "wave %d of %d is ready to deploy %d resource(s)"),
+		next+1, len(ws.waves)+1, len(urns))
+	if ws.approver != nil {
+		approved, err := ws.approver.ApproveWave(ctx, next, urns)
+		if err != nil {
+			return err
+		}
+		if !approved {
+			return fmt.Errorf("the deployment was stopped before wave %d", next+1)
+		}
+	}
+
+	ws.current = next
+	chains := ws.parked[next]
+	delete(ws.parked, next)
+	delete(ws.urns, next)
+	for _, steps := range chains {
+		ws.run(steps)
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// EncodeCheckpoint writes a checkpoint to w as a versioned checkpoint, in the same format that encoding.JSON uses.
+// Unlike marshalling the checkpoint, the resources of its deployment are encoded and written one at a time, so the
+// encoded checkpoint is never held in memory as a whole.
+func EncodeCheckpoint(w io.Writer, chk *apitype.CheckpointV3) error {
+	var dep *apitype.DeploymentV3
+	n := 0
+	var resources func(yield func(*apitype.ResourceV3) error) error
+	if chk.Latest != nil {
+		rest := *chk.Latest
+		rest.Resources = nil
+		dep = &rest
+		n = len(chk.Latest.Resources)
+		resources = func(yield func(*apitype.ResourceV3) error) error {
+			for i := range chk.Latest.Resources {
+				if err := yield(&chk.Latest.Resources[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	rest := *chk
+	rest.Latest = nil
+	return encodeCheckpoint(w, &rest, dep, n, resources)
+}
+
+// snapshotEncodeBatchSize is how many resources EncodeSnapshotCheckpoint serializes at a time. Secrets are encrypted
+// a batch at a time too, so this is the batch size of batching secrets managers.
+const snapshotEncodeBatchSize = DefaultMaxBatchEncryptCount
+
+// EncodeSnapshotCheckpoint writes the checkpoint of a stack whose latest deployment is the given snapshot to w, in
+// the same format as EncodeCheckpoint. The snapshot's resources are serialized in batches as they are written, so
+// neither the serialized deployment nor the encoded checkpoint is ever held in memory as a whole. The Latest field of
+// chk is ignored. If snap is nil, the checkpoint has no deployment.
+func EncodeSnapshotCheckpoint(
+	ctx context.Context, w io.Writer, chk *apitype.CheckpointV3, snap *deploy.Snapshot, showSecrets bool,
+) error {
+	rest := *chk
+	rest.Latest = nil
+	if snap == nil {
+		return encodeCheckpoint(w, &rest, nil, 0, nil)
+	}
+
+	// Serialize everything but the resources up front; it's small next to them.
+	noResources := *snap
+	noResources.Resources = nil
+	dep, err := SerializeDeployment(ctx, &noResources, showSecrets)
+	if err != nil {
+		return err
+	}
+
+	resources := func(yield func(*apitype.ResourceV3) error) error {
+		batch := make([]apitype.ResourceV3, 0, min(len(snap.Resources), snapshotEncodeBatchSize))
+		for start := 0; start < len(snap.Resources); start += snapshotEncodeBatchSize {
+			enc, completeBatch := beginEncryption(snap.SecretsManager)
+			batch = batch[:0]
+			for _, res := range snap.Resources[start:min(start+snapshotEncodeBatchSize, len(snap.Resources))] {
+				sres, err := SerializeResource(ctx, res, enc, showSecrets)
+				if err != nil {
+					return fmt.Errorf("serializing resources: %w", err)
+				}
+				batch = append(batch, sres)
+			}
+			if completeBatch != nil {
+				if err := completeBatch(ctx); err != nil {
+					return err
+				}
+			}
+			for i := range batch {
+				if err := yield(&batch[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return encodeCheckpoint(w, &rest, dep, len(snap.Resources), resources)
+}
+
+// encodeCheckpoint writes a checkpoint whose latest deployment is dep, if dep isn't nil. chk and dep must not hold
+// the deployment or its resources: the deployment's n resources are written one at a time by calling resources.
+func encodeCheckpoint(
+	w io.Writer, chk *apitype.CheckpointV3, dep *apitype.DeploymentV3,
+	n int, resources func(yield func(*apitype.ResourceV3) error) error,
+) error {
+	sw := &jsonStreamWriter{w: bufio.NewWriter(w)}
+
+	// Everything but the resources is encoded by marshalling the checkpoint and deployment with the streamed field
+	// left out, so that the encoding keeps up with fields that are added to them.
+	var latest *jsonField
+	if dep != nil {
+		var resourcesField *jsonField
+		if n > 0 {
+			resourcesField = &jsonField{"resources", func(depth int) {
+				sw.stream(depth, func(elem func(write func(depth int))) error {
+					return resources(func(res *apitype.ResourceV3) error {
+						elem(func(depth int) { sw.value(depth, res) })
+						return sw.err
+					})
+				})
+			}}
+		}
+		fields, err := sw.structFields(dep, resourcesField)
+		if err != nil {
+			return err
+		}
+		latest = &jsonField{"latest", func(depth int) { sw.object(depth, fields) }}
+	}
+	checkpointFields, err := sw.structFields(chk, latest)
+	if err != nil {
+		return err
+	}
+
+	sw.object(0, []jsonField{
+		{"version", func(depth int) { sw.value(depth, apitype.DeploymentSchemaVersionCurrent) }},
+		{"checkpoint", func(depth int) { sw.object(depth, checkpointFields) }},
+	})
+	sw.write("\n")
+
+	if sw.err != nil {
+		return sw.err
+	}
+	return sw.w.Flush()
+}
+
+// jsonField is a field of an object written by a jsonStreamWriter.
+type jsonField struct {
+	key   string
+	write func(depth int)
+}
+
+// jsonStreamWriter writes indented JSON piece by piece. Once a write fails, all further writes are skipped and the
+// error is kept in err.
+type jsonStreamWriter struct {
+	w   *bufio.Writer
+	buf bytes.Buffer
+	err error
+}
+
+func jsonIndent(depth int) string {
+	return strings.Repeat("    ", depth)
+}
+
+func (sw *jsonStreamWriter) write(s string) {
+	if sw.err == nil {
+		_, sw.err = sw.w.WriteString(s)
+	}
+}
+
+// value writes a JSON value that starts on a line indented to the given depth.
+func (sw *jsonStreamWriter) value(depth int, v interface{}) {
+	if sw.err != nil {
+		return
+	}
+	sw.buf.Reset()
+	enc := json.NewEncoder(&sw.buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(jsonIndent(depth), "    ")
+	if sw.err = enc.Encode(v); sw.err != nil {
+		return
+	}
+	// Encode terminates each value with a newline, which the caller decides on.
+	_, sw.err = sw.w.Write(bytes.TrimSuffix(sw.buf.Bytes(), []byte("\n")))
+}
+
+// structFields marshals v, a pointer to a struct, and returns the fields that it marshals to. If streamed isn't nil,
+// it's a field that was left out of v to be written separately, and it's placed where the struct declares it.
+func (sw *jsonStreamWriter) structFields(v interface{}, streamed *jsonField) ([]jsonField, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	var fields []jsonField
+	dec := json.NewDecoder(&buf)
+	err := decodeObject(dec, func(key string) error {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		fields = append(fields, jsonField{key, func(depth int) { sw.value(depth, raw) }})
+		return nil
+	})
+	if err != nil || streamed == nil {
+		return fields, err
+	}
+
+	order := jsonFieldOrder(reflect.TypeOf(v).Elem())
+	pos, ok := order[streamed.key]
+	contract.Assertf(ok, "%v has no field %q", reflect.TypeOf(v).Elem(), streamed.key)
+	i := 0
+	for i < len(fields) && order[fields[i].key] < pos {
+		i++
+	}
+	return slices.Insert(fields, i, *streamed), nil
+}
+
+// jsonFieldOrder returns the position of each of the JSON fields of a struct type.
+func jsonFieldOrder(t reflect.Type) map[string]int {
+	order := map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		order[name] = i
+	}
+	return order
+}
+
+func (sw *jsonStreamWriter) object(depth int, fields []jsonField) {
+	sw.write("{\n")
+	for i, f := range fields {
+		sw.write(fmt.Sprintf("%s%q: ", jsonIndent(depth+1), f.key))
+		f.write(depth + 1)
+		if i < len(fields)-1 {
+			sw.write(",")
+		}
+		sw.write("\n")
+	}
+	sw.write(jsonIndent(depth) + "}")
+}
+
+// stream writes an array whose elements are produced by elems, which calls elem to write each one. Any error from
+// elems that isn't a write error is kept in err too.
+func (sw *jsonStreamWriter) stream(depth int, elems func(elem func(write func(depth int))) error) {
+	sw.write("[")
+	first := true
+	err := elems(func(write func(depth int)) {
+		if !first {
+			sw.write(",")
+		}
+		first = false
+		sw.write("\n" + jsonIndent(depth+1))
+		write(depth + 1)
+	})
+	if sw.err == nil {
+		sw.err = err
+	}
+	sw.write("\n" + jsonIndent(depth) + "]")
+}
+
+// DecodeCheckpoint reads a versioned checkpoint of any version from r, migrating it to the latest version. The
+// resources of version 3 checkpoints are decoded one at a time, so the encoded checkpoint is never held in memory as
+// a whole. Older checkpoints, and checkpoints whose version comes after their content, are decoded in one go.
+func DecodeCheckpoint(r io.Reader) (*apitype.CheckpointV3, error) {
+	dec := json.NewDecoder(r)
+
+	var chk *apitype.CheckpointV3
+	version := -1
+	// fields holds everything that wasn't streamed, in case we need to fall back to decoding in one go.
+	fields := map[string]json.RawMessage{}
+	err := decodeObject(dec, func(key string) error {
+		switch {
+		case strings.EqualFold(key, "version"):
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			fields[key] = raw
+			return json.Unmarshal(raw, &version)
+		case strings.EqualFold(key, "checkpoint") && version == apitype.DeploymentSchemaVersionCurrent:
+			var err error
+			chk, err = decodeCheckpointV3(dec)
+			return err
+		default:
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			fields[key] = raw
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decoding checkpoint: %w", err)
+	}
+	if chk != nil {
+		return chk, nil
+	}
+
+	byts, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalVersionedCheckpointToLatestCheckpoint(encoding.JSON, byts)
+}
+
+// decodeCheckpointV3 decodes a version 3 checkpoint, streaming the resources of its deployment.
+func decodeCheckpointV3(dec *json.Decoder) (*apitype.CheckpointV3, error) {
+	var latest *apitype.DeploymentV3
+	fields := map[string]json.RawMessage{}
+	err := decodeObject(dec, func(key string) error {
+		if !strings.EqualFold(key, "latest") {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			fields[key] = raw
+			return nil
+		}
+
+		var err error
+		latest, err = decodeDeploymentV3(dec)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var chk apitype.CheckpointV3
+	if err := unmarshalFields(fields, &chk); err != nil {
+		return nil, err
+	}
+	chk.Latest = latest
+	return &chk, nil
+}
+
+// decodeDeploymentV3 decodes a deployment one resource at a time. It returns nil if the deployment is null.
+func decodeDeploymentV3(dec *json.Decoder) (*apitype.DeploymentV3, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, nil
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected deployment to be an object, got %v", tok)
+	}
+
+	var resources []apitype.ResourceV3
+	fields := map[string]json.RawMessage{}
+	err = decodeObjectFields(dec, func(key string) error {
+		if !strings.EqualFold(key, "resources") {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			fields[key] = raw
+			return nil
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok == nil {
+			return nil
+		}
+		if tok != json.Delim('[') {
+			return fmt.Errorf("expected resources to be an array, got %v", tok)
+		}
+		for dec.More() {
+			var res apitype.ResourceV3
+			if err := dec.Decode(&res); err != nil {
+				return err
+			}
+			resources = append(resources, res)
+		}
+		_, err = dec.Token()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var dep apitype.DeploymentV3
+	if err := unmarshalFields(fields, &dep); err != nil {
+		return nil, err
+	}
+	dep.Resources = resources
+	return &dep, nil
+}
+
+// decodeObject decodes a JSON object, calling field for each of its keys to decode the value that follows.
+func decodeObject(dec *json.Decoder, field func(key string) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("expected an object, got %v", tok)
+	}
+	return decodeObjectFields(dec, field)
+}
+
+// decodeObjectFields decodes the fields of a JSON object whose opening brace has already been read.
+func decodeObjectFields(dec *json.Decoder, field func(key string) error) error {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected an object key, got %v", tok)
+		}
+		if err := field(key); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('}') {
+		return errors.New("expected the end of an object")
+	}
+	return nil
+}
+
+// unmarshalFields unmarshals the fields of an object that weren't streamed, the same way json.Unmarshal would have
+// unmarshalled them as part of the whole object.
+func unmarshalFields(fields map[string]json.RawMessage, v interface{}) error {
+	byts, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(byts, v)
+}
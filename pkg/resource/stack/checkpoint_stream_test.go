@@ -0,0 +1,178 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+)
+
+func TestDecodeCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	for _, file := range []string{
+		"testdata/checkpoint-v0.json",
+		"testdata/checkpoint-v1.json",
+		"testdata/checkpoint-v3.json",
+	} {
+		file := file
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+
+			byts, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			want, err := UnmarshalVersionedCheckpointToLatestCheckpoint(encoding.JSON, byts)
+			require.NoError(t, err)
+			got, err := DecodeCheckpoint(bytes.NewReader(byts))
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestEncodeCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	byts, err := os.ReadFile("testdata/checkpoint-v3.json")
+	require.NoError(t, err)
+	chk, err := UnmarshalVersionedCheckpointToLatestCheckpoint(encoding.JSON, byts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		desc string
+		give *apitype.CheckpointV3
+	}{
+		{"empty", &apitype.CheckpointV3{Stack: "organization/project/dev"}},
+		{"config", &apitype.CheckpointV3{
+			Stack:  "organization/project/dev",
+			Config: config.Map{config.MustMakeKey("project", "key"): config.NewValue("<value>")},
+		}},
+		{"deployment", chk},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The streamed encoding is the same as marshalling the whole versioned checkpoint.
+			inner, err := encoding.JSON.Marshal(tt.give)
+			require.NoError(t, err)
+			want, err := encoding.JSON.Marshal(apitype.VersionedCheckpoint{
+				Version:    apitype.DeploymentSchemaVersionCurrent,
+				Checkpoint: json.RawMessage(inner),
+			})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, EncodeCheckpoint(&buf, tt.give))
+			assert.Equal(t, string(want), buf.String())
+
+			got, err := DecodeCheckpoint(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.give, got)
+		})
+	}
+}
+
+func TestEncodeSnapshotCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	byts, err := os.ReadFile("testdata/checkpoint-v3.json")
+	require.NoError(t, err)
+	chk, err := UnmarshalVersionedCheckpointToLatestCheckpoint(encoding.JSON, byts)
+	require.NoError(t, err)
+	snap, err := DeserializeCheckpoint(ctx, b64.Base64SecretsProvider, chk)
+	require.NoError(t, err)
+
+	// Streaming the snapshot is the same as serializing it and then encoding the checkpoint.
+	dep, err := SerializeDeployment(ctx, snap, false /* showSecrets */)
+	require.NoError(t, err)
+	var want bytes.Buffer
+	require.NoError(t, EncodeCheckpoint(&want, &apitype.CheckpointV3{Stack: chk.Stack, Latest: dep}))
+
+	var got bytes.Buffer
+	require.NoError(t, EncodeSnapshotCheckpoint(ctx, &got, &apitype.CheckpointV3{Stack: chk.Stack}, snap, false))
+	assert.Equal(t, want.String(), got.String())
+
+	got.Reset()
+	require.NoError(t, EncodeSnapshotCheckpoint(ctx, &got, &apitype.CheckpointV3{Stack: chk.Stack}, nil, false))
+	decoded, err := DecodeCheckpoint(&got)
+	require.NoError(t, err)
+	assert.Equal(t, &apitype.CheckpointV3{Stack: chk.Stack}, decoded)
+}
+
+// TestEncodeCheckpoint_allFields checks that every field of a checkpoint and its deployment is encoded, so that the
+// streamed encoding can't silently drop fields that are added to them.
+func TestEncodeCheckpoint_allFields(t *testing.T) {
+	t.Parallel()
+
+	chk := &apitype.CheckpointV3{
+		Stack:  "organization/project/dev",
+		Config: config.Map{config.MustMakeKey("project", "key"): config.NewValue("value")},
+		Latest: &apitype.DeploymentV3{
+			Manifest:         apitype.ManifestV1{Version: "3.0.0"},
+			SecretsProviders: &apitype.SecretsProvidersV1{Type: "passphrase"},
+			Resources:        []apitype.ResourceV3{{URN: "urn:pulumi:dev::project::a:b:c::d", Type: "a:b:c"}},
+			PendingOperations: []apitype.OperationV2{{
+				Resource: apitype.ResourceV3{URN: "urn:pulumi:dev::project::a:b:c::e", Type: "a:b:c"},
+				Type:     apitype.OperationTypeCreating,
+			}},
+			Metadata: apitype.SnapshotMetadataV1{
+				IntegrityErrorMetadata: &apitype.SnapshotIntegrityErrorMetadataV1{Version: "3.0.0"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, EncodeCheckpoint(&buf, chk))
+	var encoded struct {
+		Checkpoint map[string]json.RawMessage `json:"checkpoint"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &encoded))
+	var latest map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(encoded.Checkpoint["latest"], &latest))
+
+	checkFields := func(v interface{}, encoded map[string]json.RawMessage) {
+		rv := reflect.ValueOf(v).Elem()
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			// If this fails, a field has been added: set it above so that its encoding is checked.
+			require.False(t, rv.Field(i).IsZero(), "%v.%v is not set", rv.Type(), field.Name)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			assert.Contains(t, encoded, name, "%v.%v is not encoded", rv.Type(), field.Name)
+		}
+	}
+	checkFields(chk, encoded.Checkpoint)
+	checkFields(chk.Latest, latest)
+
+	got, err := DecodeCheckpoint(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, chk, got)
+}
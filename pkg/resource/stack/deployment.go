@@ -108,18 +108,7 @@ func SerializeDeployment(ctx context.Context, snap *deploy.Snapshot, showSecrets
 	manifest := snap.Manifest.Serialize()
 
 	sm := snap.SecretsManager
-	var enc config.Encrypter
-	var completeBatch CompleteCrypterBatch
-	if sm != nil {
-		// If the secrets manager supports batching, start the batch operation.
-		if batchingSecretsManager, ok := sm.(BatchingSecretsManager); ok {
-			enc, completeBatch = batchingSecretsManager.BeginBatchEncryption()
-		} else {
-			enc = sm.Encrypter()
-		}
-	} else {
-		enc = config.NewPanicCrypter()
-	}
+	enc, completeBatch := beginEncryption(sm)
 
 	// Serialize all vertices and only include a vertex section if non-empty.
 	resources := slice.Prealloc[apitype.ResourceV3](len(snap.Resources))
@@ -172,6 +161,18 @@ func SerializeDeployment(ctx context.Context, snap *deploy.Snapshot, showSecrets
 	}, nil
 }
 
+// beginEncryption returns the encrypter to serialize secrets with. If the secrets manager supports batching, it
+// starts a batch, and the returned CompleteCrypterBatch must be called to finish encrypting the serialized secrets.
+func beginEncryption(sm secrets.Manager) (config.Encrypter, CompleteCrypterBatch) {
+	if sm == nil {
+		return config.NewPanicCrypter(), nil
+	}
+	if batchingSecretsManager, ok := sm.(BatchingSecretsManager); ok {
+		return batchingSecretsManager.BeginBatchEncryption()
+	}
+	return sm.Encrypter(), nil
+}
+
 // UnmarshalUntypedDeployment unmarshals a raw untyped deployment into an up to date deployment object.
 func UnmarshalUntypedDeployment(
 	ctx context.Context,
@@ -111,6 +111,7 @@ func SerializeResourcePlan(
 			Name:                    plan.Goal.Name,
 			Custom:                  plan.Goal.Custom,
 			InputDiff:               inputDiff,
+			FromEmptyInputs:         plan.Goal.FromEmptyInputs,
 			OutputDiff:              outputDiff,
 			Parent:                  plan.Goal.Parent,
 			Protect:                 plan.Goal.Protect,
@@ -172,6 +173,7 @@ func DeserializeResourcePlan(
 			Name:                    plan.Goal.Name,
 			Custom:                  plan.Goal.Custom,
 			InputDiff:               inputDiff,
+			FromEmptyInputs:         plan.Goal.FromEmptyInputs,
 			OutputDiff:              outputDiff,
 			Parent:                  plan.Goal.Parent,
 			Protect:                 plan.Goal.Protect,
@@ -17,7 +17,9 @@
 package optup
 
 import (
+	"context"
 	"io"
+	"strings"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/debug"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
@@ -157,6 +159,31 @@ func ContinueOnError() Option {
 	})
 }
 
+// Wave deploys the resources matched by the given selectors in a wave of their own, after the resources of earlier
+// waves and before the resources that no wave selects. Selectors are type=<type>, tag=<key>=<value>, wave=<name> or
+// resource URNs. Waves can't be approved at a prompt from the Automation API, so they must be approved with
+// ApproveWave or AutoApproveWaves.
+func Wave(selectors ...string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Waves = append(opts.Waves, strings.Join(selectors, ","))
+	})
+}
+
+// AutoApproveWaves deploys every wave of the update without asking for approval.
+func AutoApproveWaves() Option {
+	return optionFunc(func(opts *Options) {
+		opts.AutoApproveWaves = true
+	})
+}
+
+// ApproveWave calls approve before each wave of the update is deployed, with the number of the wave, starting from 1,
+// and the URNs of the resources that are waiting on it. The update stops if approve returns false or an error.
+func ApproveWave(approve func(ctx context.Context, wave int, resources []string) (bool, error)) Option {
+	return optionFunc(func(opts *Options) {
+		opts.ApproveWave = approve
+	})
+}
+
 // AttachDebugger will run the process under a debugger, and pause until a debugger is attached
 func AttachDebugger() Option {
 	return optionFunc(func(opts *Options) {
@@ -223,6 +250,12 @@ type Options struct {
 	SuppressOutputs bool
 	// ContinueOnError will continue to perform the update operation despite the occurrence of errors.
 	ContinueOnError bool
+	// Deploy the resources matched by each comma-separated list of selectors in a wave of their own
+	Waves []string
+	// Deploy every wave without asking for approval
+	AutoApproveWaves bool
+	// Called to approve each wave before it is deployed
+	ApproveWave func(ctx context.Context, wave int, resources []string) (bool, error)
 	// AttachDebugger will run the process under a debugger, and pause until a debugger is attached
 	AttachDebugger bool
 	// Run using the configuration values in the specified file rather than detecting the file name
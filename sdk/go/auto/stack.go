@@ -118,6 +118,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/debug"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdestroy"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdrift"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/opthistory"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optrefresh"
@@ -419,6 +420,21 @@ func (s *Stack) Up(ctx context.Context, opts ...optup.Option) (UpResult, error)
 	if upOpts.ContinueOnError {
 		sharedArgs = append(sharedArgs, "--continue-on-error")
 	}
+	for _, wave := range upOpts.Waves {
+		sharedArgs = append(sharedArgs, "--wave="+wave)
+	}
+	if upOpts.AutoApproveWaves {
+		sharedArgs = append(sharedArgs, "--auto-approve-waves")
+	}
+	var approvals *waveApprovals
+	if upOpts.ApproveWave != nil {
+		var err error
+		if approvals, err = watchWaveApprovals(ctx, upOpts.ApproveWave); err != nil {
+			return res, err
+		}
+		defer func() { contract.IgnoreError(approvals.Close()) }()
+		sharedArgs = append(sharedArgs, "--wave-approval-dir="+approvals.dir)
+	}
 	if upOpts.AttachDebugger {
 		sharedArgs = append(sharedArgs, "--attach-debugger")
 	}
@@ -430,6 +446,10 @@ func (s *Stack) Up(ctx context.Context, opts ...optup.Option) (UpResult, error)
 	sharedArgs = append(sharedArgs, s.remoteArgs()...)
 
 	kind, args := constant.ExecKindAutoLocal, []string{"up", "--yes", "--skip-preview"}
+	if len(upOpts.Waves) > 0 {
+		// Waves are worked out from the plan made by the preview of the update.
+		args = []string{"up", "--yes"}
+	}
 	args = debug.AddArgs(&upOpts.DebugLogOpts, args)
 
 	if program := s.Workspace().Program(); program != nil {
@@ -455,6 +475,9 @@ func (s *Stack) Up(ctx context.Context, opts ...optup.Option) (UpResult, error)
 
 	args = append(args, sharedArgs...)
 	stdout, stderr, code, err := s.runPulumiCmdSync(ctx, upOpts.ProgressStreams, upOpts.ErrorProgressStreams, args...)
+	if approveErr := approvals.Close(); approveErr != nil {
+		err = errors.Join(err, approveErr)
+	}
 	if err != nil {
 		return res, newAutoError(fmt.Errorf("failed to run update: %w", err), stdout, stderr, code)
 	}
@@ -789,6 +812,82 @@ func refreshOptsToCmd(o *optrefresh.Options, s *Stack, isPreview bool) []string
 	return args
 }
 
+// driftExitCode is the exit code of `pulumi drift` when it finds that resources have drifted.
+const driftExitCode = 2
+
+// DetectDrift reads the current state of every resource in the stack from its provider and reports the resources that
+// have drifted from the stack's state. Unlike Refresh, it never changes the stack's state.
+func (s *Stack) DetectDrift(ctx context.Context, opts ...optdrift.Option) (DriftResult, error) {
+	var res DriftResult
+
+	if minVer := (semver.Version{Major: 3, Minor: 159}); s.Workspace().PulumiCommand().Version().LT(minVer) {
+		return res, fmt.Errorf("DetectDrift requires Pulumi CLI version >= %s", minVer)
+	}
+
+	driftOpts := &optdrift.Options{}
+	for _, o := range opts {
+		o.ApplyOption(driftOpts)
+	}
+
+	args := driftOptsToCmd(driftOpts, s)
+
+	// The drift report is written to stdout, and the progress of drift detection to stderr.
+	stdout, stderr, code, err := s.runPulumiCmdSync(
+		ctx,
+		nil,                       /* additionalOutputs */
+		driftOpts.ProgressStreams, /* additionalErrorOutputs */
+		args...,
+	)
+	if err != nil && code != driftExitCode {
+		return res, newAutoError(fmt.Errorf("failed to detect drift: %w", err), stdout, stderr, code)
+	}
+
+	var report apitype.DriftReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		return res, fmt.Errorf("unable to unmarshal drift report: %w", err)
+	}
+
+	res = DriftResult{
+		StdOut: stdout,
+		StdErr: stderr,
+		Report: report,
+	}
+	return res, nil
+}
+
+func driftOptsToCmd(o *optdrift.Options, s *Stack) []string {
+	args := slice.Prealloc[string](len(o.Target) + len(o.Exclude))
+
+	args = append(args, "drift", "--json")
+	args = debug.AddArgs(&o.DebugLogOpts, args)
+	for _, tURN := range o.Target {
+		args = append(args, "--target="+tURN)
+	}
+	for _, eURN := range o.Exclude {
+		args = append(args, "--exclude="+eURN)
+	}
+	if o.Parallel > 0 {
+		args = append(args, fmt.Sprintf("--parallel=%d", o.Parallel))
+	}
+	if o.UserAgent != "" {
+		args = append(args, "--exec-agent="+o.UserAgent)
+	}
+	if o.Color != "" {
+		args = append(args, "--color="+o.Color)
+	}
+	if o.ConfigFile != "" {
+		args = append(args, "--config-file="+o.ConfigFile)
+	}
+
+	execKind := constant.ExecKindAutoLocal
+	if s.Workspace().Program() != nil {
+		execKind = constant.ExecKindAutoInline
+	}
+	args = append(args, "--exec-kind="+execKind)
+
+	return args
+}
+
 func (s *Stack) PreviewDestroy(ctx context.Context, opts ...optdestroy.Option) (PreviewResult, error) {
 	var res PreviewResult
 
@@ -1359,6 +1458,19 @@ func (rr *RefreshResult) GetPermalink() (string, error) {
 	return GetPermalink(rr.StdOut)
 }
 
+// DriftResult is the output of a successful Stack.DetectDrift operation
+type DriftResult struct {
+	StdOut string
+	StdErr string
+	// Report describes the resources that have drifted.
+	Report apitype.DriftReport
+}
+
+// HasDrift returns true if any of the stack's resources have drifted.
+func (dr *DriftResult) HasDrift() bool {
+	return dr.Report.HasDrift()
+}
+
 // RenameResult is the output of a successful Stack.Rename operation
 type RenameResult struct {
 	StdOut  string
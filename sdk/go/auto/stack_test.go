@@ -24,6 +24,7 @@ import (
 
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdestroy"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdrift"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optimport"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optrefresh"
@@ -338,6 +339,27 @@ func TestRefreshOptsClearPendingCreates(t *testing.T) {
 	assert.Contains(t, args, "--clear-pending-creates")
 }
 
+func TestDriftOpts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pDir := filepath.Join(".", "test", "testproj")
+
+	stack, err := NewStackLocalSource(ctx, ptesting.RandomStackName(), pDir)
+	require.NoError(t, err)
+
+	args := driftOptsToCmd(&optdrift.Options{
+		Target:   []string{"urn:a"},
+		Exclude:  []string{"urn:b"},
+		Parallel: 4,
+	}, &stack)
+
+	assert.Equal(t, []string{"drift", "--json"}, args[:2])
+	assert.Contains(t, args, "--target=urn:a")
+	assert.Contains(t, args, "--exclude=urn:b")
+	assert.Contains(t, args, "--parallel=4")
+}
+
 func TestRename(t *testing.T) {
 	t.Parallel()
 
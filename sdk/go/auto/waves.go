@@ -0,0 +1,128 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+// waveApprovalPollInterval is how often waveApprovals looks for new requests to approve a wave.
+const waveApprovalPollInterval = 250 * time.Millisecond
+
+// waveApprovals answers the requests to approve the waves of an update, which the CLI writes to a directory when it's
+// run with --wave-approval-dir, by calling the update's approval callback.
+type waveApprovals struct {
+	dir    string
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+func watchWaveApprovals(
+	ctx context.Context, approve func(ctx context.Context, wave int, resources []string) (bool, error),
+) (*waveApprovals, error) {
+	dir, err := os.MkdirTemp("", "automation-waves-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wave approval dir: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	wa := &waveApprovals{dir: dir, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(wa.done)
+
+		ticker := time.NewTicker(waveApprovalPollInterval)
+		defer ticker.Stop()
+		// Waves are approved in order, so only the request for the next wave needs looking for.
+		for wave := 1; ; {
+			approved, found, err := wa.answer(ctx, wave, approve)
+			if err != nil {
+				wa.err = err
+				return
+			}
+			if found {
+				if !approved {
+					return
+				}
+				wave++
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return wa, nil
+}
+
+// answer answers the request to approve the given wave, if it has been written. If the callback fails, the wave is
+// not approved, so that the update stops, and the callback's error is returned.
+func (wa *waveApprovals) answer(
+	ctx context.Context, wave int, approve func(ctx context.Context, wave int, resources []string) (bool, error),
+) (approved bool, found bool, _ error) {
+	byts, err := os.ReadFile(filepath.Join(wa.dir, apitype.WaveApprovalRequestFile(wave)))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to read approval request for wave %d: %w", wave, err)
+	}
+	var req apitype.WaveApprovalRequest
+	if err := json.Unmarshal(byts, &req); err != nil {
+		return false, false, fmt.Errorf("failed to read approval request for wave %d: %w", wave, err)
+	}
+
+	approved, approveErr := approve(ctx, req.Wave, req.Resources)
+	if approveErr != nil {
+		approved = false
+		approveErr = fmt.Errorf("failed to approve wave %d: %w", wave, approveErr)
+	}
+
+	byts, err = json.Marshal(apitype.WaveApprovalResponse{Approved: approved})
+	if err != nil {
+		return false, true, err
+	}
+	// Write the response under a temporary name first, so that it's never read before it's complete.
+	respPath := filepath.Join(wa.dir, apitype.WaveApprovalResponseFile(wave))
+	if err := os.WriteFile(respPath+".tmp", byts, 0o600); err != nil {
+		return false, true, fmt.Errorf("failed to write approval of wave %d: %w", wave, err)
+	}
+	if err := os.Rename(respPath+".tmp", respPath); err != nil {
+		return false, true, fmt.Errorf("failed to write approval of wave %d: %w", wave, err)
+	}
+	return approved, true, approveErr
+}
+
+// Close stops answering requests and removes the directory they're exchanged through. It returns the error of the
+// approval callback, if it failed.
+func (wa *waveApprovals) Close() error {
+	if wa == nil {
+		return nil
+	}
+	wa.cancel()
+	<-wa.done
+	os.RemoveAll(wa.dir)
+	return wa.err
+}
@@ -0,0 +1,68 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+// requestWaveApproval plays the part of the CLI, asking for a wave to be approved and waiting for the answer.
+func requestWaveApproval(t *testing.T, dir string, wave int, resources []string) apitype.WaveApprovalResponse {
+	byts, err := json.Marshal(apitype.WaveApprovalRequest{Wave: wave, Resources: resources})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, apitype.WaveApprovalRequestFile(wave)), byts, 0o600))
+
+	var resp apitype.WaveApprovalResponse
+	require.Eventually(t, func() bool {
+		byts, err := os.ReadFile(filepath.Join(dir, apitype.WaveApprovalResponseFile(wave)))
+		return err == nil && json.Unmarshal(byts, &resp) == nil
+	}, 10*time.Second, 10*time.Millisecond)
+	return resp
+}
+
+func TestWaveApprovals(t *testing.T) {
+	t.Parallel()
+
+	var approved []int
+	approvals, err := watchWaveApprovals(context.Background(),
+		func(_ context.Context, wave int, resources []string) (bool, error) {
+			approved = append(approved, wave)
+			if wave == 2 {
+				assert.Equal(t, []string{"urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs"}, resources)
+				return false, errors.New("not today")
+			}
+			return true, nil
+		})
+	require.NoError(t, err)
+
+	assert.True(t, requestWaveApproval(t, approvals.dir, 1, nil).Approved)
+	assert.False(t, requestWaveApproval(t, approvals.dir, 2,
+		[]string{"urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs"}).Approved)
+
+	assert.ErrorContains(t, approvals.Close(), "not today")
+	assert.Equal(t, []int{1, 2}, approved)
+	assert.NoDirExists(t, approvals.dir)
+}
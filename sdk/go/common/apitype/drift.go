@@ -0,0 +1,57 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitype
+
+// DriftReport is the machine-readable report written by `pulumi drift --json`. It describes how the resources of a
+// stack have drifted from the state that Pulumi has recorded for them.
+type DriftReport struct {
+	// Resources lists the resources that have drifted. Resources that haven't drifted are not listed.
+	Resources []ResourceDrift `json:"resources"`
+}
+
+// HasDrift returns true if any of the stack's resources have drifted.
+func (r DriftReport) HasDrift() bool {
+	return len(r.Resources) > 0
+}
+
+// DriftKind describes how a resource has drifted.
+type DriftKind string
+
+const (
+	// DriftModified indicates that some of the resource's properties no longer match its recorded state.
+	DriftModified DriftKind = "modified"
+	// DriftDeleted indicates that the resource no longer exists.
+	DriftDeleted DriftKind = "deleted"
+)
+
+// ResourceDrift describes how a single resource has drifted.
+type ResourceDrift struct {
+	URN  string    `json:"urn"`
+	Type string    `json:"type"`
+	Kind DriftKind `json:"kind"`
+	// Properties lists the properties that have drifted. It is empty for resources that have been deleted.
+	Properties []PropertyDrift `json:"properties,omitempty"`
+}
+
+// PropertyDrift describes how a single property of a resource has drifted.
+type PropertyDrift struct {
+	// Path is the path of the property, e.g. `tags.env` or `rules[0].port`.
+	Path string `json:"path"`
+	// Kind is the kind of difference between the property's recorded and actual values.
+	Kind DiffKind `json:"diffKind"`
+	// InputDiff is true if the property's actual value differs from its recorded inputs rather than its recorded
+	// outputs.
+	InputDiff bool `json:"inputDiff"`
+}
@@ -16,6 +16,7 @@ package apitype
 
 import (
 	"io"
+	"time"
 
 	"github.com/blang/semver"
 )
@@ -74,3 +75,23 @@ type PackagePublishOp struct {
 	// This is optional, and if omitted, the package will not have installation documentation.
 	InstallDocs io.Reader
 }
+
+// PackageMetadata describes a version of a package published to a package registry.
+type PackageMetadata struct {
+	// Name is the URL-safe name of the package.
+	Name string `json:"name"`
+	// Publisher is the organization that published the package.
+	Publisher string `json:"publisher"`
+	// Source is the source of the package, see PackagePublishOp.
+	Source string `json:"source"`
+	// Version is the semantic version of the package.
+	Version semver.Version `json:"version"`
+	// Title is the display name of the package, if it has one.
+	Title string `json:"title,omitempty"`
+	// Description is the description of the package, if it has one.
+	Description string `json:"description,omitempty"`
+	// RepoURL is the URL of the package's source repository, if it has one.
+	RepoURL string `json:"repoUrl,omitempty"`
+	// CreatedAt is when the package version was published.
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -42,6 +42,8 @@ type GoalV1 struct {
 	Custom bool `json:"custom"`
 	// the resource properties that will be changed.
 	InputDiff PlanDiffV1 `json:"inputDiff,omitempty"`
+	// true if the input diff was taken against no inputs at all, rather than against the resource's old inputs.
+	FromEmptyInputs bool `json:"fromEmptyInputs,omitempty"`
 	// the resource outputs that will be changed.
 	OutputDiff PlanDiffV1 `json:"outputDiff,omitempty"`
 	// an optional parent URN for this resource.
@@ -0,0 +1,44 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitype
+
+import "fmt"
+
+// WaveApprovalRequest asks for a wave of an update deployed with `pulumi up --wave` to be approved. When approvals are
+// exchanged through a directory with `--wave-approval-dir`, the CLI writes each request to the file named by
+// WaveApprovalRequestFile, and waits for a WaveApprovalResponse in the file named by WaveApprovalResponseFile.
+type WaveApprovalRequest struct {
+	// Wave is the number of the wave, starting from 1.
+	Wave int `json:"wave"`
+	// Resources are the URNs of the resources whose steps are waiting on the wave.
+	Resources []string `json:"resources"`
+}
+
+// WaveApprovalResponse answers a WaveApprovalRequest.
+type WaveApprovalResponse struct {
+	// Approved is true if the wave may be deployed, and false to stop the update.
+	Approved bool `json:"approved"`
+}
+
+// WaveApprovalRequestFile returns the name of the file that the request to approve the given wave is written to.
+func WaveApprovalRequestFile(wave int) string {
+	return fmt.Sprintf("wave-%d.request.json", wave)
+}
+
+// WaveApprovalResponseFile returns the name of the file that the response to the request to approve the given wave is
+// written to.
+func WaveApprovalResponseFile(wave int) string {
+	return fmt.Sprintf("wave-%d.response.json", wave)
+}
@@ -45,6 +45,10 @@ var SkipUpdateCheck = env.Bool("SKIP_UPDATE_CHECK", "Disable checking for a new
 
 var Dev = env.Bool("DEV", "Enable features for hacking on pulumi itself.")
 
+var ServiceBackendJournal = env.Bool("SERVICE_BACKEND_JOURNAL", "If set, updates to stacks in the Pulumi Cloud "+
+	"backend, including self-hosted installations, replay each change onto the last checkpoint saved instead of "+
+	"serializing the whole checkpoint again.")
+
 var SkipCheckpoints = env.Bool("SKIP_CHECKPOINTS", "Skip saving state checkpoints and only save "+
 	"the final deployment. See #10668.")
 
@@ -150,6 +154,25 @@ var (
 
 	DIYBackendParallel = env.Int("DIY_BACKEND_PARALLEL",
 		"Number of parallel operations when fetching stacks and resources from the DIY backend.")
+
+	DIYBackendLockTimeout = env.Int("DIY_BACKEND_LOCK_TIMEOUT",
+		"Number of seconds without a heartbeat after which a DIY backend stack lock is considered stale "+
+			"and can be taken over. Defaults to 300.")
+
+	DIYBackendHistoryKeepUpdates = env.Int("DIY_BACKEND_HISTORY_KEEP_UPDATES",
+		"If set, only the given number of most recent updates are kept in a DIY backend stack's history. "+
+			"Older updates are removed after every update.")
+
+	DIYBackendHistoryKeepDays = env.Int("DIY_BACKEND_HISTORY_KEEP_DAYS",
+		"If set, only updates from the given number of most recent days are kept in a DIY backend stack's "+
+			"history. Older updates are removed after every update.")
+
+	DIYBackendHistoryCompress = env.Bool("DIY_BACKEND_HISTORY_COMPRESS",
+		"If set, uncompressed updates in a DIY backend stack's history are gzipped after every update.")
+
+	DIYBackendJournal = env.Bool("DIY_BACKEND_JOURNAL",
+		"If set, updates to DIY backend stacks append each change to a journal instead of rewriting the whole "+
+			"checkpoint, and compact the journal into the checkpoint when they finish.")
 )
 
 // Environment variables which affect Pulumi AI integrations
@@ -174,6 +174,7 @@ type MockProvider struct {
 	SignalCancellationF func(context.Context) error
 	GetMappingF         func(context.Context, GetMappingRequest) (GetMappingResponse, error)
 	GetMappingsF        func(context.Context, GetMappingsRequest) (GetMappingsResponse, error)
+	DiscoverRelatedF    func(context.Context, DiscoverRelatedRequest) (DiscoverRelatedResponse, error)
 }
 
 var _ Provider = (*MockProvider)(nil)
@@ -333,3 +334,12 @@ func (m *MockProvider) GetMappings(ctx context.Context, req GetMappingsRequest)
 	}
 	return GetMappingsResponse{}, errors.New("GetMappings not implemented")
 }
+
+func (m *MockProvider) DiscoverRelated(
+	ctx context.Context, req DiscoverRelatedRequest,
+) (DiscoverRelatedResponse, error) {
+	if m.DiscoverRelatedF != nil {
+		return m.DiscoverRelatedF(ctx, req)
+	}
+	return DiscoverRelatedResponse{}, errors.New("DiscoverRelated not implemented")
+}
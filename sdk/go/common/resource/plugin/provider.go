@@ -344,6 +344,35 @@ type GetMappingsResponse struct {
 	Keys []string
 }
 
+type DiscoverRelatedRequest struct {
+	URN resource.URN
+	ID  resource.ID
+}
+
+// Relationship describes how a resource returned by DiscoverRelated is related to the requested resource.
+type Relationship int
+
+const (
+	// RelationshipChild means the resource is a child of the requested resource.
+	RelationshipChild Relationship = 0
+	// RelationshipDependent means the resource depends on the requested resource.
+	RelationshipDependent Relationship = 1
+	// RelationshipDependency means the requested resource depends on the resource.
+	RelationshipDependency Relationship = 2
+)
+
+// RelatedResource is a resource returned by DiscoverRelated.
+type RelatedResource struct {
+	Type         tokens.Type
+	ID           resource.ID
+	Name         string
+	Relationship Relationship
+}
+
+type DiscoverRelatedResponse struct {
+	Resources []RelatedResource
+}
+
 // Provider presents a simple interface for orchestrating resource create, read, update, and delete operations.  Each
 // provider understands how to handle all of the resource types within a single package.
 //
@@ -436,6 +465,10 @@ type Provider interface {
 	// If a provider implements this method GetMapping will be called using the results from this method.
 	GetMappings(context.Context, GetMappingsRequest) (GetMappingsResponse, error)
 
+	// DiscoverRelated returns the resources that are directly related to the given existing resource, for importing
+	// whole trees of resources. A provider should return an empty list (not an error) if it can't discover any.
+	DiscoverRelated(context.Context, DiscoverRelatedRequest) (DiscoverRelatedResponse, error)
+
 	// mustEmbed *requires* that implementers make an explicit choice about forward compatibility.
 	//
 	// If [UnimplementedProvider] is embedded, then the struct will be forward compatible.
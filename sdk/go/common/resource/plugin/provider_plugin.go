@@ -2304,3 +2304,37 @@ func (p *provider) GetMappings(ctx context.Context, req GetMappingsRequest) (Get
 	}
 	return GetMappingsResponse{resp.Providers}, nil
 }
+
+func (p *provider) DiscoverRelated(ctx context.Context, req DiscoverRelatedRequest) (DiscoverRelatedResponse, error) {
+	label := fmt.Sprintf("%s.DiscoverRelated(%s, %s)", p.label(), req.URN, req.ID)
+	logging.V(7).Infof("%s executing", label)
+
+	resp, err := p.clientRaw.DiscoverRelated(p.requestContext(), &pulumirpc.DiscoverRelatedRequest{
+		Type: string(req.URN.Type()),
+		Id:   string(req.ID),
+		Urn:  string(req.URN),
+	})
+	if err != nil {
+		rpcError := rpcerror.Convert(err)
+		code := rpcError.Code()
+		if code == codes.Unimplemented {
+			// Providers that don't implement discovery don't know of any related resources.
+			logging.V(7).Infof("%s unimplemented", label)
+			return DiscoverRelatedResponse{}, nil
+		}
+		logging.V(7).Infof("%s failed: %v", label, rpcError)
+		return DiscoverRelatedResponse{}, err
+	}
+
+	resources := make([]RelatedResource, len(resp.GetResources()))
+	for i, r := range resp.GetResources() {
+		resources[i] = RelatedResource{
+			Type:         tokens.Type(r.GetType()),
+			ID:           resource.ID(r.GetId()),
+			Name:         r.GetName(),
+			Relationship: Relationship(r.GetRelationship()),
+		}
+	}
+	logging.V(7).Infof("%s success: #resources=%d", label, len(resources))
+	return DiscoverRelatedResponse{Resources: resources}, nil
+}
@@ -984,3 +984,26 @@ func (p *providerServer) GetMappings(ctx context.Context,
 	}
 	return &pulumirpc.GetMappingsResponse{Providers: providers.Keys}, nil
 }
+
+func (p *providerServer) DiscoverRelated(ctx context.Context,
+	req *pulumirpc.DiscoverRelatedRequest,
+) (*pulumirpc.DiscoverRelatedResponse, error) {
+	resp, err := p.provider.DiscoverRelated(ctx, DiscoverRelatedRequest{
+		URN: resource.URN(req.GetUrn()),
+		ID:  resource.ID(req.GetId()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*pulumirpc.RelatedResource, len(resp.Resources))
+	for i, r := range resp.Resources {
+		resources[i] = &pulumirpc.RelatedResource{
+			Type:         string(r.Type),
+			Id:           string(r.ID),
+			Name:         r.Name,
+			Relationship: pulumirpc.RelatedResource_Relationship(r.Relationship),
+		}
+	}
+	return &pulumirpc.DiscoverRelatedResponse{Resources: resources}, nil
+}
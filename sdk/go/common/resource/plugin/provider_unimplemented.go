@@ -126,6 +126,12 @@ func (p *UnimplementedProvider) GetMappings(context.Context, GetMappingsRequest)
 	return GetMappingsResponse{}, status.Error(codes.Unimplemented, "GetMappings is not yet implemented")
 }
 
+func (p *UnimplementedProvider) DiscoverRelated(
+	context.Context, DiscoverRelatedRequest,
+) (DiscoverRelatedResponse, error) {
+	return DiscoverRelatedResponse{}, status.Error(codes.Unimplemented, "DiscoverRelated is not yet implemented")
+}
+
 func (p NotForwardCompatibleProvider) mustEmbedAForwardCompatibilityOption(
 	UnimplementedProvider, NotForwardCompatibleProvider) {
 }
@@ -44,6 +44,10 @@ type Goal struct {
 	// if specified resource is being deleted as well.
 	DeletedWith    URN
 	SourcePosition string // If set, the source location of the resource registration
+	// if set, how the engine retries the resource's create, update and delete operations when they fail.
+	RetryPolicy *RetryPolicy
+	// if set, the name of the deployment wave that the resource is deployed in.
+	DeploymentWave string
 }
 
 // NewGoal allocates a new resource goal state.
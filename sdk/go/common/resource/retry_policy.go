@@ -0,0 +1,27 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+// RetryPolicy describes how the engine retries the create, update and delete operations of a resource that fail.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts at an operation, including the first one.
+	MaxAttempts int `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+	// Delay is the number of seconds to wait before the first retry. It doubles after each retry.
+	Delay float64 `json:"delay,omitempty" yaml:"delay,omitempty"`
+	// MaxDelay is the longest number of seconds to wait between attempts, if any.
+	MaxDelay float64 `json:"maxDelay,omitempty" yaml:"maxDelay,omitempty"`
+	// Errors, if set, limits retries to errors whose messages contain one of these strings.
+	Errors []string `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
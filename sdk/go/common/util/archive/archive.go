@@ -115,7 +115,11 @@ func ExtractTGZ(r io.Reader, dir string) error {
 	if err != nil {
 		return fmt.Errorf("uncompressing: %w", err)
 	}
-	tr := tar.NewReader(gzr)
+	return ExtractTar(tar.NewReader(gzr), dir)
+}
+
+// ExtractTar extracts the contents of a tar archive into a specific directory.
+func ExtractTar(tr *tar.Reader, dir string) error {
 	for {
 		header, err := tr.Next()
 		if err != nil {
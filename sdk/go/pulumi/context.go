@@ -1558,6 +1558,8 @@ func (ctx *Context) registerResource(
 				DeleteBeforeReplaceDefined: inputs.deleteBeforeReplace != nil,
 				ImportId:                   inputs.importID,
 				CustomTimeouts:             inputs.customTimeouts,
+				RetryPolicy:                inputs.retryPolicy,
+				DeploymentWave:             inputs.deploymentWave,
 				IgnoreChanges:              inputs.ignoreChanges,
 				AliasURNs:                  aliasURNs,
 				Aliases:                    aliases,
@@ -2025,6 +2027,8 @@ type resourceInputs struct {
 	deleteBeforeReplace     *bool
 	importID                string
 	customTimeouts          *pulumirpc.RegisterResourceRequest_CustomTimeouts
+	retryPolicy             *pulumirpc.RegisterResourceRequest_RetryPolicy
+	deploymentWave          string
 	ignoreChanges           []string
 	aliases                 []*pulumirpc.Alias
 	additionalSecretOutputs []string
@@ -2298,6 +2302,8 @@ func (ctx *Context) prepareResourceInputs(res Resource, props Input, t string, o
 		deleteBeforeReplace:     resOpts.deleteBeforeReplace,
 		importID:                string(resOpts.importID),
 		customTimeouts:          getTimeouts(opts.CustomTimeouts),
+		retryPolicy:             getRetryPolicy(opts.RetryPolicy),
+		deploymentWave:          opts.DeploymentWave,
 		ignoreChanges:           resOpts.ignoreChanges,
 		aliases:                 aliases,
 		additionalSecretOutputs: resOpts.additionalSecretOutputs,
@@ -2319,6 +2325,18 @@ func getTimeouts(custom *CustomTimeouts) *pulumirpc.RegisterResourceRequest_Cust
 	return &timeouts
 }
 
+func getRetryPolicy(policy *RetryPolicy) *pulumirpc.RegisterResourceRequest_RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &pulumirpc.RegisterResourceRequest_RetryPolicy{
+		MaxAttempts: int32(policy.MaxAttempts), //nolint:gosec // attempts never come close to overflowing int32
+		Delay:       policy.Delay,
+		MaxDelay:    policy.MaxDelay,
+		Errors:      policy.Errors,
+	}
+}
+
 // Helper struct for the return type of `getOpts`.
 type resourceOpts struct {
 	parentURN               URN
@@ -327,6 +327,25 @@ type CustomTimeouts struct {
 	Delete string
 }
 
+// RetryPolicy specifies how the engine retries the provisioning operations of a resource
+// that fail with transient errors, such as throttling or eventual consistency errors.
+// Use it with the [Retry] option when creating new resources.
+//
+// Delays are specified as duration strings in the same format as [CustomTimeouts].
+//
+// Retry policies can only be set on resources by the Go SDK for now. Policies for whole types or
+// packages can be set with the pulumi:retry-policies config instead, whatever the language of the program.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts at an operation, including the first one.
+	MaxAttempts int
+	// Delay is how long to wait before the first retry. It doubles after each retry.
+	Delay string
+	// MaxDelay is the longest time to wait between attempts.
+	MaxDelay string
+	// Errors, if set, limits retries to errors whose messages contain one of these strings.
+	Errors []string
+}
+
 // ResourceOptions is a snapshot of one or more [ResourceOption]s.
 //
 // You cannot pass a ResourceOptions struct to a resource constructor.
@@ -421,6 +440,14 @@ type ResourceOptions struct {
 	// DeletedWith holds a container resource that, if deleted,
 	// also deletes this resource.
 	DeletedWith Resource
+
+	// RetryPolicy, if set, has the engine retry resource CRUD operations
+	// that fail with transient errors.
+	RetryPolicy *RetryPolicy
+
+	// DeploymentWave is the name of the deployment wave
+	// that the resource is deployed in, if any.
+	DeploymentWave string
 }
 
 // NewResourceOptions builds a preview of the effect of the provided options.
@@ -457,6 +484,8 @@ type resourceOptions struct {
 	RetainOnDelete          *bool
 	DeletedWith             Resource
 	Parameterization        []byte
+	RetryPolicy             *RetryPolicy
+	DeploymentWave          string
 }
 
 func resourceOptionsSnapshot(ro *resourceOptions) *ResourceOptions {
@@ -520,6 +549,8 @@ func resourceOptionsSnapshot(ro *resourceOptions) *ResourceOptions {
 		PluginDownloadURL:       ro.PluginDownloadURL,
 		RetainOnDelete:          flatten(ro.RetainOnDelete),
 		DeletedWith:             ro.DeletedWith,
+		RetryPolicy:             ro.RetryPolicy,
+		DeploymentWave:          ro.DeploymentWave,
 	}
 }
 
@@ -923,6 +954,21 @@ func Timeouts(o *CustomTimeouts) ResourceOption {
 	})
 }
 
+// Retry has the engine retry the CRUD operations of the resource that fail with transient errors.
+func Retry(o *RetryPolicy) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.RetryPolicy = o
+	})
+}
+
+// DeploymentWave puts the resource in the named wave of a staged deployment. Waves are selected with the `--wave
+// wave=<name>` flag of `pulumi up`. Children of the resource are deployed in the same wave unless they set their own.
+func DeploymentWave(name string) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.DeploymentWave = name
+	})
+}
+
 // Transformations is an optional list of transformations to be applied to the resource.
 func Transformations(o []ResourceTransformation) ResourceOption {
 	return resourceOption(func(ro *resourceOptions) {
@@ -1109,6 +1109,13 @@ func TestNewResourceOptions(t *testing.T) {
 				ReplaceOnChanges: []string{"foo", "bar"},
 			},
 		},
+		{
+			desc: "Retry",
+			give: Retry(&RetryPolicy{MaxAttempts: 3, Delay: "1s"}),
+			want: ResourceOptions{
+				RetryPolicy: &RetryPolicy{MaxAttempts: 3, Delay: "1s"},
+			},
+		},
 		{
 			desc: "Timeouts",
 			give: Timeouts(&CustomTimeouts{Create: "10s"}),
@@ -1141,6 +1148,11 @@ func TestNewResourceOptions(t *testing.T) {
 			give: DeletedWith(&testRes{foo: "a"}),
 			want: ResourceOptions{DeletedWith: &testRes{foo: "a"}},
 		},
+		{
+			desc: "DeploymentWave",
+			give: DeploymentWave("canary"),
+			want: ResourceOptions{DeploymentWave: "canary"},
+		},
 	}
 
 	for _, tt := range tests {
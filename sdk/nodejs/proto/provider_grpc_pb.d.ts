@@ -32,6 +32,7 @@ interface IResourceProviderService extends grpc.ServiceDefinition<grpc.UntypedSe
     attach: IResourceProviderService_IAttach;
     getMapping: IResourceProviderService_IGetMapping;
     getMappings: IResourceProviderService_IGetMappings;
+    discoverRelated: IResourceProviderService_IDiscoverRelated;
 }
 
 interface IResourceProviderService_IHandshake extends grpc.MethodDefinition<pulumi_provider_pb.ProviderHandshakeRequest, pulumi_provider_pb.ProviderHandshakeResponse> {
@@ -223,6 +224,15 @@ interface IResourceProviderService_IGetMappings extends grpc.MethodDefinition<pu
     responseSerialize: grpc.serialize<pulumi_provider_pb.GetMappingsResponse>;
     responseDeserialize: grpc.deserialize<pulumi_provider_pb.GetMappingsResponse>;
 }
+interface IResourceProviderService_IDiscoverRelated extends grpc.MethodDefinition<pulumi_provider_pb.DiscoverRelatedRequest, pulumi_provider_pb.DiscoverRelatedResponse> {
+    path: "/pulumirpc.ResourceProvider/DiscoverRelated";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<pulumi_provider_pb.DiscoverRelatedRequest>;
+    requestDeserialize: grpc.deserialize<pulumi_provider_pb.DiscoverRelatedRequest>;
+    responseSerialize: grpc.serialize<pulumi_provider_pb.DiscoverRelatedResponse>;
+    responseDeserialize: grpc.deserialize<pulumi_provider_pb.DiscoverRelatedResponse>;
+}
 
 export const ResourceProviderService: IResourceProviderService;
 
@@ -248,6 +258,7 @@ export interface IResourceProviderServer extends grpc.UntypedServiceImplementati
     attach: grpc.handleUnaryCall<pulumi_plugin_pb.PluginAttach, google_protobuf_empty_pb.Empty>;
     getMapping: grpc.handleUnaryCall<pulumi_provider_pb.GetMappingRequest, pulumi_provider_pb.GetMappingResponse>;
     getMappings: grpc.handleUnaryCall<pulumi_provider_pb.GetMappingsRequest, pulumi_provider_pb.GetMappingsResponse>;
+    discoverRelated: grpc.handleUnaryCall<pulumi_provider_pb.DiscoverRelatedRequest, pulumi_provider_pb.DiscoverRelatedResponse>;
 }
 
 export interface IResourceProviderClient {
@@ -313,6 +324,9 @@ export interface IResourceProviderClient {
     getMappings(request: pulumi_provider_pb.GetMappingsRequest, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.GetMappingsResponse) => void): grpc.ClientUnaryCall;
     getMappings(request: pulumi_provider_pb.GetMappingsRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.GetMappingsResponse) => void): grpc.ClientUnaryCall;
     getMappings(request: pulumi_provider_pb.GetMappingsRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.GetMappingsResponse) => void): grpc.ClientUnaryCall;
+    discoverRelated(request: pulumi_provider_pb.DiscoverRelatedRequest, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.DiscoverRelatedResponse) => void): grpc.ClientUnaryCall;
+    discoverRelated(request: pulumi_provider_pb.DiscoverRelatedRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.DiscoverRelatedResponse) => void): grpc.ClientUnaryCall;
+    discoverRelated(request: pulumi_provider_pb.DiscoverRelatedRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.DiscoverRelatedResponse) => void): grpc.ClientUnaryCall;
 }
 
 export class ResourceProviderClient extends grpc.Client implements IResourceProviderClient {
@@ -379,4 +393,7 @@ export class ResourceProviderClient extends grpc.Client implements IResourceProv
     public getMappings(request: pulumi_provider_pb.GetMappingsRequest, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.GetMappingsResponse) => void): grpc.ClientUnaryCall;
     public getMappings(request: pulumi_provider_pb.GetMappingsRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.GetMappingsResponse) => void): grpc.ClientUnaryCall;
     public getMappings(request: pulumi_provider_pb.GetMappingsRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.GetMappingsResponse) => void): grpc.ClientUnaryCall;
+    public discoverRelated(request: pulumi_provider_pb.DiscoverRelatedRequest, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.DiscoverRelatedResponse) => void): grpc.ClientUnaryCall;
+    public discoverRelated(request: pulumi_provider_pb.DiscoverRelatedRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.DiscoverRelatedResponse) => void): grpc.ClientUnaryCall;
+    public discoverRelated(request: pulumi_provider_pb.DiscoverRelatedRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: pulumi_provider_pb.DiscoverRelatedResponse) => void): grpc.ClientUnaryCall;
 }
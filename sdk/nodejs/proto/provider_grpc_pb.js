@@ -176,6 +176,28 @@ function deserialize_pulumirpc_DiffResponse(buffer_arg) {
   return pulumi_provider_pb.DiffResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_pulumirpc_DiscoverRelatedRequest(arg) {
+  if (!(arg instanceof pulumi_provider_pb.DiscoverRelatedRequest)) {
+    throw new Error('Expected argument of type pulumirpc.DiscoverRelatedRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_DiscoverRelatedRequest(buffer_arg) {
+  return pulumi_provider_pb.DiscoverRelatedRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_DiscoverRelatedResponse(arg) {
+  if (!(arg instanceof pulumi_provider_pb.DiscoverRelatedResponse)) {
+    throw new Error('Expected argument of type pulumirpc.DiscoverRelatedResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_DiscoverRelatedResponse(buffer_arg) {
+  return pulumi_provider_pb.DiscoverRelatedResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_pulumirpc_GetMappingRequest(arg) {
   if (!(arg instanceof pulumi_provider_pb.GetMappingRequest)) {
     throw new Error('Expected argument of type pulumirpc.GetMappingRequest');
@@ -785,6 +807,24 @@ getMappings: {
     responseSerialize: serialize_pulumirpc_GetMappingsResponse,
     responseDeserialize: deserialize_pulumirpc_GetMappingsResponse,
   },
+  // `DiscoverRelated` is an optional method designed to aid in importing whole trees of existing resources. Given the
+// type and ID of a resource that exists in the provider's cloud, `DiscoverRelated` returns the resources that are
+// related to it, such as the subnets, route tables and security groups of a VPC, along with how each of them is
+// related to the given resource. The engine calls `DiscoverRelated` for each resource that it discovers in turn, so
+// a provider only needs to return the resources that are directly related to the given one.
+//
+// If a provider does not implement `DiscoverRelated`, the engine will assume that no related resources exist.
+discoverRelated: {
+    path: '/pulumirpc.ResourceProvider/DiscoverRelated',
+    requestStream: false,
+    responseStream: false,
+    requestType: pulumi_provider_pb.DiscoverRelatedRequest,
+    responseType: pulumi_provider_pb.DiscoverRelatedResponse,
+    requestSerialize: serialize_pulumirpc_DiscoverRelatedRequest,
+    requestDeserialize: deserialize_pulumirpc_DiscoverRelatedRequest,
+    responseSerialize: serialize_pulumirpc_DiscoverRelatedResponse,
+    responseDeserialize: deserialize_pulumirpc_DiscoverRelatedResponse,
+  },
 };
 
 exports.ResourceProviderClient = grpc.makeGenericClientConstructor(ResourceProviderService);
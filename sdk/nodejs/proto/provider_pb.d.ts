@@ -1460,3 +1460,87 @@ export namespace GetMappingsResponse {
         providersList: Array<string>,
     }
 }
+
+export class DiscoverRelatedRequest extends jspb.Message { 
+    getType(): string;
+    setType(value: string): DiscoverRelatedRequest;
+    getId(): string;
+    setId(value: string): DiscoverRelatedRequest;
+    getUrn(): string;
+    setUrn(value: string): DiscoverRelatedRequest;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DiscoverRelatedRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: DiscoverRelatedRequest): DiscoverRelatedRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DiscoverRelatedRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DiscoverRelatedRequest;
+    static deserializeBinaryFromReader(message: DiscoverRelatedRequest, reader: jspb.BinaryReader): DiscoverRelatedRequest;
+}
+
+export namespace DiscoverRelatedRequest {
+    export type AsObject = {
+        type: string,
+        id: string,
+        urn: string,
+    }
+}
+
+export class DiscoverRelatedResponse extends jspb.Message { 
+    clearResourcesList(): void;
+    getResourcesList(): Array<RelatedResource>;
+    setResourcesList(value: Array<RelatedResource>): DiscoverRelatedResponse;
+    addResources(value?: RelatedResource, index?: number): RelatedResource;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DiscoverRelatedResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: DiscoverRelatedResponse): DiscoverRelatedResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DiscoverRelatedResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DiscoverRelatedResponse;
+    static deserializeBinaryFromReader(message: DiscoverRelatedResponse, reader: jspb.BinaryReader): DiscoverRelatedResponse;
+}
+
+export namespace DiscoverRelatedResponse {
+    export type AsObject = {
+        resourcesList: Array<RelatedResource.AsObject>,
+    }
+}
+
+export class RelatedResource extends jspb.Message { 
+    getType(): string;
+    setType(value: string): RelatedResource;
+    getId(): string;
+    setId(value: string): RelatedResource;
+    getName(): string;
+    setName(value: string): RelatedResource;
+    getRelationship(): RelatedResource.Relationship;
+    setRelationship(value: RelatedResource.Relationship): RelatedResource;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): RelatedResource.AsObject;
+    static toObject(includeInstance: boolean, msg: RelatedResource): RelatedResource.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: RelatedResource, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): RelatedResource;
+    static deserializeBinaryFromReader(message: RelatedResource, reader: jspb.BinaryReader): RelatedResource;
+}
+
+export namespace RelatedResource {
+    export type AsObject = {
+        type: string,
+        id: string,
+        name: string,
+        relationship: RelatedResource.Relationship,
+    }
+
+    export enum Relationship {
+    CHILD = 0,
+    DEPENDENT = 1,
+    DEPENDENCY = 2,
+    }
+
+}
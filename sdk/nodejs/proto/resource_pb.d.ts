@@ -252,6 +252,13 @@ export class RegisterResourceRequest extends jspb.Message {
     getPackageref(): string;
     setPackageref(value: string): RegisterResourceRequest;
 
+    hasRetrypolicy(): boolean;
+    clearRetrypolicy(): void;
+    getRetrypolicy(): RegisterResourceRequest.RetryPolicy | undefined;
+    setRetrypolicy(value?: RegisterResourceRequest.RetryPolicy): RegisterResourceRequest;
+    getDeploymentwave(): string;
+    setDeploymentwave(value: string): RegisterResourceRequest;
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): RegisterResourceRequest.AsObject;
     static toObject(includeInstance: boolean, msg: RegisterResourceRequest): RegisterResourceRequest.AsObject;
@@ -300,6 +307,8 @@ export namespace RegisterResourceRequest {
         transformsList: Array<pulumi_callback_pb.Callback.AsObject>,
         supportsresultreporting: boolean,
         packageref: string,
+        retrypolicy?: RegisterResourceRequest.RetryPolicy.AsObject,
+        deploymentwave: string,
     }
 
 
@@ -351,6 +360,37 @@ export namespace RegisterResourceRequest {
         }
     }
 
+    export class RetryPolicy extends jspb.Message { 
+        getMaxattempts(): number;
+        setMaxattempts(value: number): RetryPolicy;
+        getDelay(): string;
+        setDelay(value: string): RetryPolicy;
+        getMaxdelay(): string;
+        setMaxdelay(value: string): RetryPolicy;
+        clearErrorsList(): void;
+        getErrorsList(): Array<string>;
+        setErrorsList(value: Array<string>): RetryPolicy;
+        addErrors(value: string, index?: number): string;
+
+        serializeBinary(): Uint8Array;
+        toObject(includeInstance?: boolean): RetryPolicy.AsObject;
+        static toObject(includeInstance: boolean, msg: RetryPolicy): RetryPolicy.AsObject;
+        static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+        static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+        static serializeBinaryToWriter(message: RetryPolicy, writer: jspb.BinaryWriter): void;
+        static deserializeBinary(bytes: Uint8Array): RetryPolicy;
+        static deserializeBinaryFromReader(message: RetryPolicy, reader: jspb.BinaryReader): RetryPolicy;
+    }
+
+    export namespace RetryPolicy {
+        export type AsObject = {
+            maxattempts: number,
+            delay: string,
+            maxdelay: string,
+            errorsList: Array<string>,
+        }
+    }
+
 }
 
 export class RegisterResourceResponse extends jspb.Message { 
@@ -215,6 +215,14 @@ type ResourceProviderClient interface {
 	// If a provider does not implement `GetMappings`, the engine will fall back to calling `GetMapping` blindly without
 	// a source provider name (that is, with the value `""`).
 	GetMappings(ctx context.Context, in *GetMappingsRequest, opts ...grpc.CallOption) (*GetMappingsResponse, error)
+	// `DiscoverRelated` is an optional method designed to aid in importing whole trees of existing resources. Given the
+	// type and ID of a resource that exists in the provider's cloud, `DiscoverRelated` returns the resources that are
+	// related to it, such as the subnets, route tables and security groups of a VPC, along with how each of them is
+	// related to the given resource. The engine calls `DiscoverRelated` for each resource that it discovers in turn, so
+	// a provider only needs to return the resources that are directly related to the given one.
+	//
+	// If a provider does not implement `DiscoverRelated`, the engine will assume that no related resources exist.
+	DiscoverRelated(ctx context.Context, in *DiscoverRelatedRequest, opts ...grpc.CallOption) (*DiscoverRelatedResponse, error)
 }
 
 type resourceProviderClient struct {
@@ -437,6 +445,15 @@ func (c *resourceProviderClient) GetMappings(ctx context.Context, in *GetMapping
 	return out, nil
 }
 
+func (c *resourceProviderClient) DiscoverRelated(ctx context.Context, in *DiscoverRelatedRequest, opts ...grpc.CallOption) (*DiscoverRelatedResponse, error) {
+	out := new(DiscoverRelatedResponse)
+	err := c.cc.Invoke(ctx, "/pulumirpc.ResourceProvider/DiscoverRelated", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ResourceProviderServer is the server API for ResourceProvider service.
 // All implementations must embed UnimplementedResourceProviderServer
 // for forward compatibility
@@ -633,6 +650,14 @@ type ResourceProviderServer interface {
 	// If a provider does not implement `GetMappings`, the engine will fall back to calling `GetMapping` blindly without
 	// a source provider name (that is, with the value `""`).
 	GetMappings(context.Context, *GetMappingsRequest) (*GetMappingsResponse, error)
+	// `DiscoverRelated` is an optional method designed to aid in importing whole trees of existing resources. Given the
+	// type and ID of a resource that exists in the provider's cloud, `DiscoverRelated` returns the resources that are
+	// related to it, such as the subnets, route tables and security groups of a VPC, along with how each of them is
+	// related to the given resource. The engine calls `DiscoverRelated` for each resource that it discovers in turn, so
+	// a provider only needs to return the resources that are directly related to the given one.
+	//
+	// If a provider does not implement `DiscoverRelated`, the engine will assume that no related resources exist.
+	DiscoverRelated(context.Context, *DiscoverRelatedRequest) (*DiscoverRelatedResponse, error)
 	mustEmbedUnimplementedResourceProviderServer()
 }
 
@@ -703,6 +728,9 @@ func (UnimplementedResourceProviderServer) GetMapping(context.Context, *GetMappi
 func (UnimplementedResourceProviderServer) GetMappings(context.Context, *GetMappingsRequest) (*GetMappingsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMappings not implemented")
 }
+func (UnimplementedResourceProviderServer) DiscoverRelated(context.Context, *DiscoverRelatedRequest) (*DiscoverRelatedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscoverRelated not implemented")
+}
 func (UnimplementedResourceProviderServer) mustEmbedUnimplementedResourceProviderServer() {}
 
 // UnsafeResourceProviderServer may be embedded to opt out of forward compatibility for this service.
@@ -1097,6 +1125,24 @@ func _ResourceProvider_GetMappings_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResourceProvider_DiscoverRelated_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverRelatedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceProviderServer).DiscoverRelated(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.ResourceProvider/DiscoverRelated",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceProviderServer).DiscoverRelated(ctx, req.(*DiscoverRelatedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ResourceProvider_ServiceDesc is the grpc.ServiceDesc for ResourceProvider service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1184,6 +1230,10 @@ var ResourceProvider_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMappings",
 			Handler:    _ResourceProvider_GetMappings_Handler,
 		},
+		{
+			MethodName: "DiscoverRelated",
+			Handler:    _ResourceProvider_DiscoverRelated_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
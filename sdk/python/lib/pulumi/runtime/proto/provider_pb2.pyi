@@ -1920,3 +1920,105 @@ class GetMappingsResponse(google.protobuf.message.Message):
     def ClearField(self, field_name: typing_extensions.Literal["providers", b"providers"]) -> None: ...
 
 global___GetMappingsResponse = GetMappingsResponse
+
+@typing_extensions.final
+class DiscoverRelatedRequest(google.protobuf.message.Message):
+    """`DiscoverRelatedRequest` is the type of requests sent as part of a [](pulumirpc.ResourceProvider.DiscoverRelated)
+    call.
+    """
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    TYPE_FIELD_NUMBER: builtins.int
+    ID_FIELD_NUMBER: builtins.int
+    URN_FIELD_NUMBER: builtins.int
+    type: builtins.str
+    """The type of the resource to discover related resources for."""
+    id: builtins.str
+    """The ID of the resource to discover related resources for."""
+    urn: builtins.str
+    """The URN that the resource will be imported with."""
+    def __init__(
+        self,
+        *,
+        type: builtins.str = ...,
+        id: builtins.str = ...,
+        urn: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing_extensions.Literal["id", b"id", "type", b"type", "urn", b"urn"]) -> None: ...
+
+global___DiscoverRelatedRequest = DiscoverRelatedRequest
+
+@typing_extensions.final
+class DiscoverRelatedResponse(google.protobuf.message.Message):
+    """`DiscoverRelatedResponse` is the type of responses sent by a [](pulumirpc.ResourceProvider.DiscoverRelated) call."""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RESOURCES_FIELD_NUMBER: builtins.int
+    @property
+    def resources(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___RelatedResource]:
+        """The resources that are directly related to the requested resource."""
+    def __init__(
+        self,
+        *,
+        resources: collections.abc.Iterable[global___RelatedResource] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing_extensions.Literal["resources", b"resources"]) -> None: ...
+
+global___DiscoverRelatedResponse = DiscoverRelatedResponse
+
+@typing_extensions.final
+class RelatedResource(google.protobuf.message.Message):
+    """`RelatedResource` describes a resource returned by a [](pulumirpc.ResourceProvider.DiscoverRelated) call."""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    class _Relationship:
+        ValueType = typing.NewType("ValueType", builtins.int)
+        V: typing_extensions.TypeAlias = ValueType
+
+    class _RelationshipEnumTypeWrapper(google.protobuf.internal.enum_type_wrapper._EnumTypeWrapper[RelatedResource._Relationship.ValueType], builtins.type):  # noqa: F821
+        DESCRIPTOR: google.protobuf.descriptor.EnumDescriptor
+        CHILD: RelatedResource._Relationship.ValueType  # 0
+        """The resource is a child of the requested resource, and will be imported with it as its parent."""
+        DEPENDENT: RelatedResource._Relationship.ValueType  # 1
+        """The resource depends on the requested resource."""
+        DEPENDENCY: RelatedResource._Relationship.ValueType  # 2
+        """The requested resource depends on the resource."""
+
+    class Relationship(_Relationship, metaclass=_RelationshipEnumTypeWrapper):
+        """The ways in which a resource can be related to the requested resource."""
+
+    CHILD: RelatedResource.Relationship.ValueType  # 0
+    """The resource is a child of the requested resource, and will be imported with it as its parent."""
+    DEPENDENT: RelatedResource.Relationship.ValueType  # 1
+    """The resource depends on the requested resource."""
+    DEPENDENCY: RelatedResource.Relationship.ValueType  # 2
+    """The requested resource depends on the resource."""
+
+    TYPE_FIELD_NUMBER: builtins.int
+    ID_FIELD_NUMBER: builtins.int
+    NAME_FIELD_NUMBER: builtins.int
+    RELATIONSHIP_FIELD_NUMBER: builtins.int
+    type: builtins.str
+    """The type of the related resource."""
+    id: builtins.str
+    """The ID of the related resource."""
+    name: builtins.str
+    """An optional suggested name for the related resource. If this is empty, the engine will derive a name from the
+    resource's ID.
+    """
+    relationship: global___RelatedResource.Relationship.ValueType
+    """How the resource is related to the requested resource."""
+    def __init__(
+        self,
+        *,
+        type: builtins.str = ...,
+        id: builtins.str = ...,
+        name: builtins.str = ...,
+        relationship: global___RelatedResource.Relationship.ValueType = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing_extensions.Literal["id", b"id", "name", b"name", "relationship", b"relationship", "type", b"type"]) -> None: ...
+
+global___RelatedResource = RelatedResource
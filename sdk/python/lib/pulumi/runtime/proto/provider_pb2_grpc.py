@@ -125,6 +125,11 @@ class ResourceProviderStub(object):
                 request_serializer=pulumi_dot_provider__pb2.GetMappingsRequest.SerializeToString,
                 response_deserializer=pulumi_dot_provider__pb2.GetMappingsResponse.FromString,
                 )
+        self.DiscoverRelated = channel.unary_unary(
+                '/pulumirpc.ResourceProvider/DiscoverRelated',
+                request_serializer=pulumi_dot_provider__pb2.DiscoverRelatedRequest.SerializeToString,
+                response_deserializer=pulumi_dot_provider__pb2.DiscoverRelatedResponse.FromString,
+                )
 
 
 class ResourceProviderServicer(object):
@@ -434,6 +439,19 @@ class ResourceProviderServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def DiscoverRelated(self, request, context):
+        """`DiscoverRelated` is an optional method designed to aid in importing whole trees of existing resources. Given the
+        type and ID of a resource that exists in the provider's cloud, `DiscoverRelated` returns the resources that are
+        related to it, such as the subnets, route tables and security groups of a VPC, along with how each of them is
+        related to the given resource. The engine calls `DiscoverRelated` for each resource that it discovers in turn, so
+        a provider only needs to return the resources that are directly related to the given one.
+
+        If a provider does not implement `DiscoverRelated`, the engine will assume that no related resources exist.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_ResourceProviderServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -542,6 +560,11 @@ def add_ResourceProviderServicer_to_server(servicer, server):
                     request_deserializer=pulumi_dot_provider__pb2.GetMappingsRequest.FromString,
                     response_serializer=pulumi_dot_provider__pb2.GetMappingsResponse.SerializeToString,
             ),
+            'DiscoverRelated': grpc.unary_unary_rpc_method_handler(
+                    servicer.DiscoverRelated,
+                    request_deserializer=pulumi_dot_provider__pb2.DiscoverRelatedRequest.FromString,
+                    response_serializer=pulumi_dot_provider__pb2.DiscoverRelatedResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'pulumirpc.ResourceProvider', rpc_method_handlers)
@@ -912,3 +935,20 @@ class ResourceProvider(object):
             pulumi_dot_provider__pb2.GetMappingsResponse.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def DiscoverRelated(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pulumirpc.ResourceProvider/DiscoverRelated',
+            pulumi_dot_provider__pb2.DiscoverRelatedRequest.SerializeToString,
+            pulumi_dot_provider__pb2.DiscoverRelatedResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
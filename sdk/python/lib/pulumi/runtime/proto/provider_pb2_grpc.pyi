@@ -305,6 +305,18 @@ class ResourceProviderStub:
     If a provider does not implement `GetMappings`, the engine will fall back to calling `GetMapping` blindly without
     a source provider name (that is, with the value `""`).
     """
+    DiscoverRelated: grpc.UnaryUnaryMultiCallable[
+        pulumi.provider_pb2.DiscoverRelatedRequest,
+        pulumi.provider_pb2.DiscoverRelatedResponse,
+    ]
+    """`DiscoverRelated` is an optional method designed to aid in importing whole trees of existing resources. Given the
+    type and ID of a resource that exists in the provider's cloud, `DiscoverRelated` returns the resources that are
+    related to it, such as the subnets, route tables and security groups of a VPC, along with how each of them is
+    related to the given resource. The engine calls `DiscoverRelated` for each resource that it discovers in turn, so
+    a provider only needs to return the resources that are directly related to the given one.
+
+    If a provider does not implement `DiscoverRelated`, the engine will assume that no related resources exist.
+    """
 
 class ResourceProviderServicer(metaclass=abc.ABCMeta):
     """The ResourceProvider service defines a standard interface for [resource providers](providers). A resource provider
@@ -628,5 +640,19 @@ class ResourceProviderServicer(metaclass=abc.ABCMeta):
         If a provider does not implement `GetMappings`, the engine will fall back to calling `GetMapping` blindly without
         a source provider name (that is, with the value `""`).
         """
+    
+    def DiscoverRelated(
+        self,
+        request: pulumi.provider_pb2.DiscoverRelatedRequest,
+        context: grpc.ServicerContext,
+    ) -> pulumi.provider_pb2.DiscoverRelatedResponse:
+        """`DiscoverRelated` is an optional method designed to aid in importing whole trees of existing resources. Given the
+        type and ID of a resource that exists in the provider's cloud, `DiscoverRelated` returns the resources that are
+        related to it, such as the subnets, route tables and security groups of a VPC, along with how each of them is
+        related to the given resource. The engine calls `DiscoverRelated` for each resource that it discovers in turn, so
+        a provider only needs to return the resources that are directly related to the given one.
+
+        If a provider does not implement `DiscoverRelated`, the engine will assume that no related resources exist.
+        """
 
 def add_ResourceProviderServicer_to_server(servicer: ResourceProviderServicer, server: typing.Union[grpc.Server, grpc.aio.Server]) -> None: ...
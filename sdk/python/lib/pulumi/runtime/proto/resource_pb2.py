@@ -19,7 +19,7 @@ from . import source_pb2 as pulumi_dot_source__pb2
 from . import callback_pb2 as pulumi_dot_callback__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x15pulumi/resource.proto\x12\tpulumirpc\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x15pulumi/provider.proto\x1a\x12pulumi/alias.proto\x1a\x13pulumi/source.proto\x1a\x15pulumi/callback.proto\"$\n\x16SupportsFeatureRequest\x12\n\n\x02id\x18\x01 \x01(\t\"-\n\x17SupportsFeatureResponse\x12\x12\n\nhasSupport\x18\x01 \x01(\x08\"\xfb\x03\n\x13ReadResourceRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04type\x18\x02 \x01(\t\x12\x0c\n\x04name\x18\x03 \x01(\t\x12\x0e\n\x06parent\x18\x04 \x01(\t\x12+\n\nproperties\x18\x05 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x14\n\x0c\x64\x65pendencies\x18\x06 \x03(\t\x12\x10\n\x08provider\x18\x07 \x01(\t\x12\x0f\n\x07version\x18\x08 \x01(\t\x12\x15\n\racceptSecrets\x18\t \x01(\x08\x12\x1f\n\x17\x61\x64\x64itionalSecretOutputs\x18\n \x03(\t\x12\x17\n\x0f\x61\x63\x63\x65ptResources\x18\x0c \x01(\x08\x12\x19\n\x11pluginDownloadURL\x18\r \x01(\t\x12L\n\x0fpluginChecksums\x18\x0f \x03(\x0b\x32\x33.pulumirpc.ReadResourceRequest.PluginChecksumsEntry\x12\x31\n\x0esourcePosition\x18\x0e \x01(\x0b\x32\x19.pulumirpc.SourcePosition\x12\x12\n\npackageRef\x18\x10 \x01(\t\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01J\x04\x08\x0b\x10\x0cR\x07\x61liases\"P\n\x14ReadResourceResponse\x12\x0b\n\x03urn\x18\x01 \x01(\t\x12+\n\nproperties\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\"\x9f\x0b\n\x17RegisterResourceRequest\x12\x0c\n\x04type\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0e\n\x06parent\x18\x03 \x01(\t\x12\x0e\n\x06\x63ustom\x18\x04 \x01(\x08\x12\'\n\x06object\x18\x05 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x14\n\x07protect\x18\x06 \x01(\x08H\x00\x88\x01\x01\x12\x14\n\x0c\x64\x65pendencies\x18\x07 \x03(\t\x12\x10\n\x08provider\x18\x08 \x01(\t\x12Z\n\x14propertyDependencies\x18\t \x03(\x0b\x32<.pulumirpc.RegisterResourceRequest.PropertyDependenciesEntry\x12\x1b\n\x13\x64\x65leteBeforeReplace\x18\n \x01(\x08\x12\x0f\n\x07version\x18\x0b \x01(\t\x12\x15\n\rignoreChanges\x18\x0c \x03(\t\x12\x15\n\racceptSecrets\x18\r \x01(\x08\x12\x1f\n\x17\x61\x64\x64itionalSecretOutputs\x18\x0e \x03(\t\x12\x11\n\taliasURNs\x18\x0f \x03(\t\x12\x10\n\x08importId\x18\x10 \x01(\t\x12I\n\x0e\x63ustomTimeouts\x18\x11 \x01(\x0b\x32\x31.pulumirpc.RegisterResourceRequest.CustomTimeouts\x12\"\n\x1a\x64\x65leteBeforeReplaceDefined\x18\x12 \x01(\x08\x12\x1d\n\x15supportsPartialValues\x18\x13 \x01(\x08\x12\x0e\n\x06remote\x18\x14 \x01(\x08\x12\x17\n\x0f\x61\x63\x63\x65ptResources\x18\x15 \x01(\x08\x12\x44\n\tproviders\x18\x16 \x03(\x0b\x32\x31.pulumirpc.RegisterResourceRequest.ProvidersEntry\x12\x18\n\x10replaceOnChanges\x18\x17 \x03(\t\x12\x19\n\x11pluginDownloadURL\x18\x18 \x01(\t\x12P\n\x0fpluginChecksums\x18\x1e \x03(\x0b\x32\x37.pulumirpc.RegisterResourceRequest.PluginChecksumsEntry\x12\x1b\n\x0eretainOnDelete\x18\x19 \x01(\x08H\x01\x88\x01\x01\x12!\n\x07\x61liases\x18\x1a \x03(\x0b\x32\x10.pulumirpc.Alias\x12\x13\n\x0b\x64\x65letedWith\x18\x1b \x01(\t\x12\x12\n\naliasSpecs\x18\x1c \x01(\x08\x12\x31\n\x0esourcePosition\x18\x1d \x01(\x0b\x32\x19.pulumirpc.SourcePosition\x12\'\n\ntransforms\x18\x1f \x03(\x0b\x32\x13.pulumirpc.Callback\x12\x1f\n\x17supportsResultReporting\x18  \x01(\x08\x12\x12\n\npackageRef\x18! \x01(\t\x1a$\n\x14PropertyDependencies\x12\x0c\n\x04urns\x18\x01 \x03(\t\x1a@\n\x0e\x43ustomTimeouts\x12\x0e\n\x06\x63reate\x18\x01 \x01(\t\x12\x0e\n\x06update\x18\x02 \x01(\t\x12\x0e\n\x06\x64\x65lete\x18\x03 \x01(\t\x1at\n\x19PropertyDependenciesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x46\n\x05value\x18\x02 \x01(\x0b\x32\x37.pulumirpc.RegisterResourceRequest.PropertyDependencies:\x02\x38\x01\x1a\x30\n\x0eProvidersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\x42\n\n\x08_protectB\x11\n\x0f_retainOnDelete\"\x9a\x03\n\x18RegisterResourceResponse\x12\x0b\n\x03urn\x18\x01 \x01(\t\x12\n\n\x02id\x18\x02 \x01(\t\x12\'\n\x06object\x18\x03 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x0e\n\x06stable\x18\x04 \x01(\x08\x12\x0f\n\x07stables\x18\x05 \x03(\t\x12[\n\x14propertyDependencies\x18\x06 \x03(\x0b\x32=.pulumirpc.RegisterResourceResponse.PropertyDependenciesEntry\x12!\n\x06result\x18\x07 \x01(\x0e\x32\x11.pulumirpc.Result\x1a$\n\x14PropertyDependencies\x12\x0c\n\x04urns\x18\x01 \x03(\t\x1au\n\x19PropertyDependenciesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12G\n\x05value\x18\x02 \x01(\x0b\x32\x38.pulumirpc.RegisterResourceResponse.PropertyDependencies:\x02\x38\x01\"W\n\x1eRegisterResourceOutputsRequest\x12\x0b\n\x03urn\x18\x01 \x01(\t\x12(\n\x07outputs\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\"\xf1\x02\n\x15ResourceInvokeRequest\x12\x0b\n\x03tok\x18\x01 \x01(\t\x12%\n\x04\x61rgs\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x10\n\x08provider\x18\x03 \x01(\t\x12\x0f\n\x07version\x18\x04 \x01(\t\x12\x17\n\x0f\x61\x63\x63\x65ptResources\x18\x05 \x01(\x08\x12\x19\n\x11pluginDownloadURL\x18\x06 \x01(\t\x12N\n\x0fpluginChecksums\x18\x08 \x03(\x0b\x32\x35.pulumirpc.ResourceInvokeRequest.PluginChecksumsEntry\x12\x31\n\x0esourcePosition\x18\x07 \x01(\x0b\x32\x19.pulumirpc.SourcePosition\x12\x12\n\npackageRef\x18\t \x01(\t\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\"\xc0\x05\n\x13ResourceCallRequest\x12\x0b\n\x03tok\x18\x01 \x01(\t\x12%\n\x04\x61rgs\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\x12L\n\x0f\x61rgDependencies\x18\x03 \x03(\x0b\x32\x33.pulumirpc.ResourceCallRequest.ArgDependenciesEntry\x12\x10\n\x08provider\x18\x04 \x01(\t\x12\x0f\n\x07version\x18\x05 \x01(\t\x12\x19\n\x11pluginDownloadURL\x18\r \x01(\t\x12L\n\x0fpluginChecksums\x18\x10 \x03(\x0b\x32\x33.pulumirpc.ResourceCallRequest.PluginChecksumsEntry\x12\x31\n\x0esourcePosition\x18\x0f \x01(\x0b\x32\x19.pulumirpc.SourcePosition\x12\x12\n\npackageRef\x18\x11 \x01(\t\x1a$\n\x14\x41rgumentDependencies\x12\x0c\n\x04urns\x18\x01 \x03(\t\x1ak\n\x14\x41rgDependenciesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x42\n\x05value\x18\x02 \x01(\x0b\x32\x33.pulumirpc.ResourceCallRequest.ArgumentDependencies:\x02\x38\x01\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01J\x04\x08\x06\x10\x07J\x04\x08\x07\x10\x08J\x04\x08\x08\x10\tJ\x04\x08\t\x10\nJ\x04\x08\n\x10\x0bJ\x04\x08\x0b\x10\x0cJ\x04\x08\x0c\x10\rJ\x04\x08\x0e\x10\x0fR\x07projectR\x05stackR\x06\x63onfigR\x10\x63onfigSecretKeysR\x06\x64ryRunR\x08parallelR\x0fmonitorEndpointR\x0corganization\"\xe3\x05\n\x18TransformResourceOptions\x12\x12\n\ndepends_on\x18\x01 \x03(\t\x12\x14\n\x07protect\x18\x02 \x01(\x08H\x00\x88\x01\x01\x12\x16\n\x0eignore_changes\x18\x03 \x03(\t\x12\x1a\n\x12replace_on_changes\x18\x04 \x03(\t\x12\x0f\n\x07version\x18\x05 \x01(\t\x12!\n\x07\x61liases\x18\x06 \x03(\x0b\x32\x10.pulumirpc.Alias\x12\x10\n\x08provider\x18\x07 \x01(\t\x12J\n\x0f\x63ustom_timeouts\x18\x08 \x01(\x0b\x32\x31.pulumirpc.RegisterResourceRequest.CustomTimeouts\x12\x1b\n\x13plugin_download_url\x18\t \x01(\t\x12\x1d\n\x10retain_on_delete\x18\n \x01(\x08H\x01\x88\x01\x01\x12\x14\n\x0c\x64\x65leted_with\x18\x0b \x01(\t\x12\"\n\x15\x64\x65lete_before_replace\x18\x0c \x01(\x08H\x02\x88\x01\x01\x12!\n\x19\x61\x64\x64itional_secret_outputs\x18\r \x03(\t\x12\x45\n\tproviders\x18\x0e \x03(\x0b\x32\x32.pulumirpc.TransformResourceOptions.ProvidersEntry\x12R\n\x10plugin_checksums\x18\x0f \x03(\x0b\x32\x38.pulumirpc.TransformResourceOptions.PluginChecksumsEntry\x1a\x30\n\x0eProvidersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\x42\n\n\x08_protectB\x13\n\x11_retain_on_deleteB\x18\n\x16_delete_before_replace\"\xb1\x01\n\x10TransformRequest\x12\x0c\n\x04type\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0e\n\x06\x63ustom\x18\x03 \x01(\x08\x12\x0e\n\x06parent\x18\x04 \x01(\t\x12+\n\nproperties\x18\x05 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x34\n\x07options\x18\x06 \x01(\x0b\x32#.pulumirpc.TransformResourceOptions\"v\n\x11TransformResponse\x12+\n\nproperties\x18\x01 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x34\n\x07options\x18\x02 \x01(\x0b\x32#.pulumirpc.TransformResourceOptions\"\x82\x01\n\x16TransformInvokeRequest\x12\r\n\x05token\x18\x01 \x01(\t\x12%\n\x04\x61rgs\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x32\n\x07options\x18\x03 \x01(\x0b\x32!.pulumirpc.TransformInvokeOptions\"t\n\x17TransformInvokeResponse\x12%\n\x04\x61rgs\x18\x01 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x32\n\x07options\x18\x02 \x01(\x0b\x32!.pulumirpc.TransformInvokeOptions\"\xe2\x01\n\x16TransformInvokeOptions\x12\x10\n\x08provider\x18\x01 \x01(\t\x12\x1b\n\x13plugin_download_url\x18\x02 \x01(\t\x12\x0f\n\x07version\x18\x03 \x01(\t\x12P\n\x10plugin_checksums\x18\x04 \x03(\x0b\x32\x36.pulumirpc.TransformInvokeOptions.PluginChecksumsEntry\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\"\xfb\x01\n\x16RegisterPackageRequest\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0f\n\x07version\x18\x02 \x01(\t\x12\x14\n\x0c\x64ownload_url\x18\x03 \x01(\t\x12\x43\n\tchecksums\x18\x04 \x03(\x0b\x32\x30.pulumirpc.RegisterPackageRequest.ChecksumsEntry\x12\x35\n\x10parameterization\x18\x05 \x01(\x0b\x32\x1b.pulumirpc.Parameterization\x1a\x30\n\x0e\x43hecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\"&\n\x17RegisterPackageResponse\x12\x0b\n\x03ref\x18\x01 \x01(\t\"@\n\x10Parameterization\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0f\n\x07version\x18\x02 \x01(\t\x12\r\n\x05value\x18\x03 \x01(\x0c*)\n\x06Result\x12\x0b\n\x07SUCCESS\x10\x00\x12\x08\n\x04\x46\x41IL\x10\x01\x12\x08\n\x04SKIP\x10\x02\x32\xd0\x06\n\x0fResourceMonitor\x12Z\n\x0fSupportsFeature\x12!.pulumirpc.SupportsFeatureRequest\x1a\".pulumirpc.SupportsFeatureResponse\"\x00\x12G\n\x06Invoke\x12 .pulumirpc.ResourceInvokeRequest\x1a\x19.pulumirpc.InvokeResponse\"\x00\x12O\n\x0cStreamInvoke\x12 .pulumirpc.ResourceInvokeRequest\x1a\x19.pulumirpc.InvokeResponse\"\x00\x30\x01\x12\x41\n\x04\x43\x61ll\x12\x1e.pulumirpc.ResourceCallRequest\x1a\x17.pulumirpc.CallResponse\"\x00\x12Q\n\x0cReadResource\x12\x1e.pulumirpc.ReadResourceRequest\x1a\x1f.pulumirpc.ReadResourceResponse\"\x00\x12]\n\x10RegisterResource\x12\".pulumirpc.RegisterResourceRequest\x1a#.pulumirpc.RegisterResourceResponse\"\x00\x12^\n\x17RegisterResourceOutputs\x12).pulumirpc.RegisterResourceOutputsRequest\x1a\x16.google.protobuf.Empty\"\x00\x12G\n\x16RegisterStackTransform\x12\x13.pulumirpc.Callback\x1a\x16.google.protobuf.Empty\"\x00\x12M\n\x1cRegisterStackInvokeTransform\x12\x13.pulumirpc.Callback\x1a\x16.google.protobuf.Empty\"\x00\x12Z\n\x0fRegisterPackage\x12!.pulumirpc.RegisterPackageRequest\x1a\".pulumirpc.RegisterPackageResponse\"\x00\x42\x34Z2github.com/pulumi/pulumi/sdk/v3/proto/go;pulumirpcb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x15pulumi/resource.proto\x12\tpulumirpc\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x15pulumi/provider.proto\x1a\x12pulumi/alias.proto\x1a\x13pulumi/source.proto\x1a\x15pulumi/callback.proto\"$\n\x16SupportsFeatureRequest\x12\n\n\x02id\x18\x01 \x01(\t\"-\n\x17SupportsFeatureResponse\x12\x12\n\nhasSupport\x18\x01 \x01(\x08\"\xfb\x03\n\x13ReadResourceRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04type\x18\x02 \x01(\t\x12\x0c\n\x04name\x18\x03 \x01(\t\x12\x0e\n\x06parent\x18\x04 \x01(\t\x12+\n\nproperties\x18\x05 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x14\n\x0c\x64\x65pendencies\x18\x06 \x03(\t\x12\x10\n\x08provider\x18\x07 \x01(\t\x12\x0f\n\x07version\x18\x08 \x01(\t\x12\x15\n\racceptSecrets\x18\t \x01(\x08\x12\x1f\n\x17\x61\x64\x64itionalSecretOutputs\x18\n \x03(\t\x12\x17\n\x0f\x61\x63\x63\x65ptResources\x18\x0c \x01(\x08\x12\x19\n\x11pluginDownloadURL\x18\r \x01(\t\x12L\n\x0fpluginChecksums\x18\x0f \x03(\x0b\x32\x33.pulumirpc.ReadResourceRequest.PluginChecksumsEntry\x12\x31\n\x0esourcePosition\x18\x0e \x01(\x0b\x32\x19.pulumirpc.SourcePosition\x12\x12\n\npackageRef\x18\x10 \x01(\t\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01J\x04\x08\x0b\x10\x0cR\x07\x61liases\"P\n\x14ReadResourceResponse\x12\x0b\n\x03urn\x18\x01 \x01(\t\x12+\n\nproperties\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\"\xd1\x0c\n\x17RegisterResourceRequest\x12\x0c\n\x04type\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0e\n\x06parent\x18\x03 \x01(\t\x12\x0e\n\x06\x63ustom\x18\x04 \x01(\x08\x12\'\n\x06object\x18\x05 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x14\n\x07protect\x18\x06 \x01(\x08H\x00\x88\x01\x01\x12\x14\n\x0c\x64\x65pendencies\x18\x07 \x03(\t\x12\x10\n\x08provider\x18\x08 \x01(\t\x12Z\n\x14propertyDependencies\x18\t \x03(\x0b\x32<.pulumirpc.RegisterResourceRequest.PropertyDependenciesEntry\x12\x1b\n\x13\x64\x65leteBeforeReplace\x18\n \x01(\x08\x12\x0f\n\x07version\x18\x0b \x01(\t\x12\x15\n\rignoreChanges\x18\x0c \x03(\t\x12\x15\n\racceptSecrets\x18\r \x01(\x08\x12\x1f\n\x17\x61\x64\x64itionalSecretOutputs\x18\x0e \x03(\t\x12\x11\n\taliasURNs\x18\x0f \x03(\t\x12\x10\n\x08importId\x18\x10 \x01(\t\x12I\n\x0e\x63ustomTimeouts\x18\x11 \x01(\x0b\x32\x31.pulumirpc.RegisterResourceRequest.CustomTimeouts\x12\"\n\x1a\x64\x65leteBeforeReplaceDefined\x18\x12 \x01(\x08\x12\x1d\n\x15supportsPartialValues\x18\x13 \x01(\x08\x12\x0e\n\x06remote\x18\x14 \x01(\x08\x12\x17\n\x0f\x61\x63\x63\x65ptResources\x18\x15 \x01(\x08\x12\x44\n\tproviders\x18\x16 \x03(\x0b\x32\x31.pulumirpc.RegisterResourceRequest.ProvidersEntry\x12\x18\n\x10replaceOnChanges\x18\x17 \x03(\t\x12\x19\n\x11pluginDownloadURL\x18\x18 \x01(\t\x12P\n\x0fpluginChecksums\x18\x1e \x03(\x0b\x32\x37.pulumirpc.RegisterResourceRequest.PluginChecksumsEntry\x12\x1b\n\x0eretainOnDelete\x18\x19 \x01(\x08H\x01\x88\x01\x01\x12!\n\x07\x61liases\x18\x1a \x03(\x0b\x32\x10.pulumirpc.Alias\x12\x13\n\x0b\x64\x65letedWith\x18\x1b \x01(\t\x12\x12\n\naliasSpecs\x18\x1c \x01(\x08\x12\x31\n\x0esourcePosition\x18\x1d \x01(\x0b\x32\x19.pulumirpc.SourcePosition\x12\'\n\ntransforms\x18\x1f \x03(\x0b\x32\x13.pulumirpc.Callback\x12\x1f\n\x17supportsResultReporting\x18  \x01(\x08\x12\x12\n\npackageRef\x18! \x01(\t\x12\x43\n\x0bretryPolicy\x18\" \x01(\x0b\x32..pulumirpc.RegisterResourceRequest.RetryPolicy\x12\x16\n\x0e\x64\x65ploymentWave\x18# \x01(\t\x1a$\n\x14PropertyDependencies\x12\x0c\n\x04urns\x18\x01 \x03(\t\x1a@\n\x0e\x43ustomTimeouts\x12\x0e\n\x06\x63reate\x18\x01 \x01(\t\x12\x0e\n\x06update\x18\x02 \x01(\t\x12\x0e\n\x06\x64\x65lete\x18\x03 \x01(\t\x1aS\n\x0bRetryPolicy\x12\x13\n\x0bmaxAttempts\x18\x01 \x01(\x05\x12\r\n\x05\x64\x65lay\x18\x02 \x01(\t\x12\x10\n\x08maxDelay\x18\x03 \x01(\t\x12\x0e\n\x06\x65rrors\x18\x04 \x03(\t\x1at\n\x19PropertyDependenciesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x46\n\x05value\x18\x02 \x01(\x0b\x32\x37.pulumirpc.RegisterResourceRequest.PropertyDependencies:\x02\x38\x01\x1a\x30\n\x0eProvidersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\x42\n\n\x08_protectB\x11\n\x0f_retainOnDelete\"\x9a\x03\n\x18RegisterResourceResponse\x12\x0b\n\x03urn\x18\x01 \x01(\t\x12\n\n\x02id\x18\x02 \x01(\t\x12\'\n\x06object\x18\x03 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x0e\n\x06stable\x18\x04 \x01(\x08\x12\x0f\n\x07stables\x18\x05 \x03(\t\x12[\n\x14propertyDependencies\x18\x06 \x03(\x0b\x32=.pulumirpc.RegisterResourceResponse.PropertyDependenciesEntry\x12!\n\x06result\x18\x07 \x01(\x0e\x32\x11.pulumirpc.Result\x1a$\n\x14PropertyDependencies\x12\x0c\n\x04urns\x18\x01 \x03(\t\x1au\n\x19PropertyDependenciesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12G\n\x05value\x18\x02 \x01(\x0b\x32\x38.pulumirpc.RegisterResourceResponse.PropertyDependencies:\x02\x38\x01\"W\n\x1eRegisterResourceOutputsRequest\x12\x0b\n\x03urn\x18\x01 \x01(\t\x12(\n\x07outputs\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\"\xf1\x02\n\x15ResourceInvokeRequest\x12\x0b\n\x03tok\x18\x01 \x01(\t\x12%\n\x04\x61rgs\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x10\n\x08provider\x18\x03 \x01(\t\x12\x0f\n\x07version\x18\x04 \x01(\t\x12\x17\n\x0f\x61\x63\x63\x65ptResources\x18\x05 \x01(\x08\x12\x19\n\x11pluginDownloadURL\x18\x06 \x01(\t\x12N\n\x0fpluginChecksums\x18\x08 \x03(\x0b\x32\x35.pulumirpc.ResourceInvokeRequest.PluginChecksumsEntry\x12\x31\n\x0esourcePosition\x18\x07 \x01(\x0b\x32\x19.pulumirpc.SourcePosition\x12\x12\n\npackageRef\x18\t \x01(\t\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\"\xc0\x05\n\x13ResourceCallRequest\x12\x0b\n\x03tok\x18\x01 \x01(\t\x12%\n\x04\x61rgs\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\x12L\n\x0f\x61rgDependencies\x18\x03 \x03(\x0b\x32\x33.pulumirpc.ResourceCallRequest.ArgDependenciesEntry\x12\x10\n\x08provider\x18\x04 \x01(\t\x12\x0f\n\x07version\x18\x05 \x01(\t\x12\x19\n\x11pluginDownloadURL\x18\r \x01(\t\x12L\n\x0fpluginChecksums\x18\x10 \x03(\x0b\x32\x33.pulumirpc.ResourceCallRequest.PluginChecksumsEntry\x12\x31\n\x0esourcePosition\x18\x0f \x01(\x0b\x32\x19.pulumirpc.SourcePosition\x12\x12\n\npackageRef\x18\x11 \x01(\t\x1a$\n\x14\x41rgumentDependencies\x12\x0c\n\x04urns\x18\x01 \x03(\t\x1ak\n\x14\x41rgDependenciesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x42\n\x05value\x18\x02 \x01(\x0b\x32\x33.pulumirpc.ResourceCallRequest.ArgumentDependencies:\x02\x38\x01\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01J\x04\x08\x06\x10\x07J\x04\x08\x07\x10\x08J\x04\x08\x08\x10\tJ\x04\x08\t\x10\nJ\x04\x08\n\x10\x0bJ\x04\x08\x0b\x10\x0cJ\x04\x08\x0c\x10\rJ\x04\x08\x0e\x10\x0fR\x07projectR\x05stackR\x06\x63onfigR\x10\x63onfigSecretKeysR\x06\x64ryRunR\x08parallelR\x0fmonitorEndpointR\x0corganization\"\xe3\x05\n\x18TransformResourceOptions\x12\x12\n\ndepends_on\x18\x01 \x03(\t\x12\x14\n\x07protect\x18\x02 \x01(\x08H\x00\x88\x01\x01\x12\x16\n\x0eignore_changes\x18\x03 \x03(\t\x12\x1a\n\x12replace_on_changes\x18\x04 \x03(\t\x12\x0f\n\x07version\x18\x05 \x01(\t\x12!\n\x07\x61liases\x18\x06 \x03(\x0b\x32\x10.pulumirpc.Alias\x12\x10\n\x08provider\x18\x07 \x01(\t\x12J\n\x0f\x63ustom_timeouts\x18\x08 \x01(\x0b\x32\x31.pulumirpc.RegisterResourceRequest.CustomTimeouts\x12\x1b\n\x13plugin_download_url\x18\t \x01(\t\x12\x1d\n\x10retain_on_delete\x18\n \x01(\x08H\x01\x88\x01\x01\x12\x14\n\x0c\x64\x65leted_with\x18\x0b \x01(\t\x12\"\n\x15\x64\x65lete_before_replace\x18\x0c \x01(\x08H\x02\x88\x01\x01\x12!\n\x19\x61\x64\x64itional_secret_outputs\x18\r \x03(\t\x12\x45\n\tproviders\x18\x0e \x03(\x0b\x32\x32.pulumirpc.TransformResourceOptions.ProvidersEntry\x12R\n\x10plugin_checksums\x18\x0f \x03(\x0b\x32\x38.pulumirpc.TransformResourceOptions.PluginChecksumsEntry\x1a\x30\n\x0eProvidersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\x42\n\n\x08_protectB\x13\n\x11_retain_on_deleteB\x18\n\x16_delete_before_replace\"\xb1\x01\n\x10TransformRequest\x12\x0c\n\x04type\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0e\n\x06\x63ustom\x18\x03 \x01(\x08\x12\x0e\n\x06parent\x18\x04 \x01(\t\x12+\n\nproperties\x18\x05 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x34\n\x07options\x18\x06 \x01(\x0b\x32#.pulumirpc.TransformResourceOptions\"v\n\x11TransformResponse\x12+\n\nproperties\x18\x01 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x34\n\x07options\x18\x02 \x01(\x0b\x32#.pulumirpc.TransformResourceOptions\"\x82\x01\n\x16TransformInvokeRequest\x12\r\n\x05token\x18\x01 \x01(\t\x12%\n\x04\x61rgs\x18\x02 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x32\n\x07options\x18\x03 \x01(\x0b\x32!.pulumirpc.TransformInvokeOptions\"t\n\x17TransformInvokeResponse\x12%\n\x04\x61rgs\x18\x01 \x01(\x0b\x32\x17.google.protobuf.Struct\x12\x32\n\x07options\x18\x02 \x01(\x0b\x32!.pulumirpc.TransformInvokeOptions\"\xe2\x01\n\x16TransformInvokeOptions\x12\x10\n\x08provider\x18\x01 \x01(\t\x12\x1b\n\x13plugin_download_url\x18\x02 \x01(\t\x12\x0f\n\x07version\x18\x03 \x01(\t\x12P\n\x10plugin_checksums\x18\x04 \x03(\x0b\x32\x36.pulumirpc.TransformInvokeOptions.PluginChecksumsEntry\x1a\x36\n\x14PluginChecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\"\xfb\x01\n\x16RegisterPackageRequest\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0f\n\x07version\x18\x02 \x01(\t\x12\x14\n\x0c\x64ownload_url\x18\x03 \x01(\t\x12\x43\n\tchecksums\x18\x04 \x03(\x0b\x32\x30.pulumirpc.RegisterPackageRequest.ChecksumsEntry\x12\x35\n\x10parameterization\x18\x05 \x01(\x0b\x32\x1b.pulumirpc.Parameterization\x1a\x30\n\x0e\x43hecksumsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x0c:\x02\x38\x01\"&\n\x17RegisterPackageResponse\x12\x0b\n\x03ref\x18\x01 \x01(\t\"@\n\x10Parameterization\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0f\n\x07version\x18\x02 \x01(\t\x12\r\n\x05value\x18\x03 \x01(\x0c*)\n\x06Result\x12\x0b\n\x07SUCCESS\x10\x00\x12\x08\n\x04\x46\x41IL\x10\x01\x12\x08\n\x04SKIP\x10\x02\x32\xd0\x06\n\x0fResourceMonitor\x12Z\n\x0fSupportsFeature\x12!.pulumirpc.SupportsFeatureRequest\x1a\".pulumirpc.SupportsFeatureResponse\"\x00\x12G\n\x06Invoke\x12 .pulumirpc.ResourceInvokeRequest\x1a\x19.pulumirpc.InvokeResponse\"\x00\x12O\n\x0cStreamInvoke\x12 .pulumirpc.ResourceInvokeRequest\x1a\x19.pulumirpc.InvokeResponse\"\x00\x30\x01\x12\x41\n\x04\x43\x61ll\x12\x1e.pulumirpc.ResourceCallRequest\x1a\x17.pulumirpc.CallResponse\"\x00\x12Q\n\x0cReadResource\x12\x1e.pulumirpc.ReadResourceRequest\x1a\x1f.pulumirpc.ReadResourceResponse\"\x00\x12]\n\x10RegisterResource\x12\".pulumirpc.RegisterResourceRequest\x1a#.pulumirpc.RegisterResourceResponse\"\x00\x12^\n\x17RegisterResourceOutputs\x12).pulumirpc.RegisterResourceOutputsRequest\x1a\x16.google.protobuf.Empty\"\x00\x12G\n\x16RegisterStackTransform\x12\x13.pulumirpc.Callback\x1a\x16.google.protobuf.Empty\"\x00\x12M\n\x1cRegisterStackInvokeTransform\x12\x13.pulumirpc.Callback\x1a\x16.google.protobuf.Empty\"\x00\x12Z\n\x0fRegisterPackage\x12!.pulumirpc.RegisterPackageRequest\x1a\".pulumirpc.RegisterPackageResponse\"\x00\x42\x34Z2github.com/pulumi/pulumi/sdk/v3/proto/go;pulumirpcb\x06proto3')
 
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, globals())
 _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'pulumi.resource_pb2', globals())
@@ -51,8 +51,8 @@ if _descriptor._USE_C_DESCRIPTORS == False:
   _TRANSFORMINVOKEOPTIONS_PLUGINCHECKSUMSENTRY._serialized_options = b'8\001'
   _REGISTERPACKAGEREQUEST_CHECKSUMSENTRY._options = None
   _REGISTERPACKAGEREQUEST_CHECKSUMSENTRY._serialized_options = b'8\001'
-  _RESULT._serialized_start=5764
-  _RESULT._serialized_end=5805
+  _RESULT._serialized_start=5942
+  _RESULT._serialized_end=5983
   _SUPPORTSFEATUREREQUEST._serialized_start=182
   _SUPPORTSFEATUREREQUEST._serialized_end=218
   _SUPPORTSFEATURERESPONSE._serialized_start=220
@@ -64,63 +64,65 @@ if _descriptor._USE_C_DESCRIPTORS == False:
   _READRESOURCERESPONSE._serialized_start=777
   _READRESOURCERESPONSE._serialized_end=857
   _REGISTERRESOURCEREQUEST._serialized_start=860
-  _REGISTERRESOURCEREQUEST._serialized_end=2299
-  _REGISTERRESOURCEREQUEST_PROPERTYDEPENDENCIES._serialized_start=1942
-  _REGISTERRESOURCEREQUEST_PROPERTYDEPENDENCIES._serialized_end=1978
-  _REGISTERRESOURCEREQUEST_CUSTOMTIMEOUTS._serialized_start=1980
-  _REGISTERRESOURCEREQUEST_CUSTOMTIMEOUTS._serialized_end=2044
-  _REGISTERRESOURCEREQUEST_PROPERTYDEPENDENCIESENTRY._serialized_start=2046
-  _REGISTERRESOURCEREQUEST_PROPERTYDEPENDENCIESENTRY._serialized_end=2162
-  _REGISTERRESOURCEREQUEST_PROVIDERSENTRY._serialized_start=2164
-  _REGISTERRESOURCEREQUEST_PROVIDERSENTRY._serialized_end=2212
+  _REGISTERRESOURCEREQUEST._serialized_end=2477
+  _REGISTERRESOURCEREQUEST_PROPERTYDEPENDENCIES._serialized_start=2035
+  _REGISTERRESOURCEREQUEST_PROPERTYDEPENDENCIES._serialized_end=2071
+  _REGISTERRESOURCEREQUEST_CUSTOMTIMEOUTS._serialized_start=2073
+  _REGISTERRESOURCEREQUEST_CUSTOMTIMEOUTS._serialized_end=2137
+  _REGISTERRESOURCEREQUEST_RETRYPOLICY._serialized_start=2139
+  _REGISTERRESOURCEREQUEST_RETRYPOLICY._serialized_end=2222
+  _REGISTERRESOURCEREQUEST_PROPERTYDEPENDENCIESENTRY._serialized_start=2224
+  _REGISTERRESOURCEREQUEST_PROPERTYDEPENDENCIESENTRY._serialized_end=2340
+  _REGISTERRESOURCEREQUEST_PROVIDERSENTRY._serialized_start=2342
+  _REGISTERRESOURCEREQUEST_PROVIDERSENTRY._serialized_end=2390
   _REGISTERRESOURCEREQUEST_PLUGINCHECKSUMSENTRY._serialized_start=706
   _REGISTERRESOURCEREQUEST_PLUGINCHECKSUMSENTRY._serialized_end=760
-  _REGISTERRESOURCERESPONSE._serialized_start=2302
-  _REGISTERRESOURCERESPONSE._serialized_end=2712
-  _REGISTERRESOURCERESPONSE_PROPERTYDEPENDENCIES._serialized_start=1942
-  _REGISTERRESOURCERESPONSE_PROPERTYDEPENDENCIES._serialized_end=1978
-  _REGISTERRESOURCERESPONSE_PROPERTYDEPENDENCIESENTRY._serialized_start=2595
-  _REGISTERRESOURCERESPONSE_PROPERTYDEPENDENCIESENTRY._serialized_end=2712
-  _REGISTERRESOURCEOUTPUTSREQUEST._serialized_start=2714
-  _REGISTERRESOURCEOUTPUTSREQUEST._serialized_end=2801
-  _RESOURCEINVOKEREQUEST._serialized_start=2804
-  _RESOURCEINVOKEREQUEST._serialized_end=3173
+  _REGISTERRESOURCERESPONSE._serialized_start=2480
+  _REGISTERRESOURCERESPONSE._serialized_end=2890
+  _REGISTERRESOURCERESPONSE_PROPERTYDEPENDENCIES._serialized_start=2035
+  _REGISTERRESOURCERESPONSE_PROPERTYDEPENDENCIES._serialized_end=2071
+  _REGISTERRESOURCERESPONSE_PROPERTYDEPENDENCIESENTRY._serialized_start=2773
+  _REGISTERRESOURCERESPONSE_PROPERTYDEPENDENCIESENTRY._serialized_end=2890
+  _REGISTERRESOURCEOUTPUTSREQUEST._serialized_start=2892
+  _REGISTERRESOURCEOUTPUTSREQUEST._serialized_end=2979
+  _RESOURCEINVOKEREQUEST._serialized_start=2982
+  _RESOURCEINVOKEREQUEST._serialized_end=3351
   _RESOURCEINVOKEREQUEST_PLUGINCHECKSUMSENTRY._serialized_start=706
   _RESOURCEINVOKEREQUEST_PLUGINCHECKSUMSENTRY._serialized_end=760
-  _RESOURCECALLREQUEST._serialized_start=3176
-  _RESOURCECALLREQUEST._serialized_end=3880
-  _RESOURCECALLREQUEST_ARGUMENTDEPENDENCIES._serialized_start=3540
-  _RESOURCECALLREQUEST_ARGUMENTDEPENDENCIES._serialized_end=3576
-  _RESOURCECALLREQUEST_ARGDEPENDENCIESENTRY._serialized_start=3578
-  _RESOURCECALLREQUEST_ARGDEPENDENCIESENTRY._serialized_end=3685
+  _RESOURCECALLREQUEST._serialized_start=3354
+  _RESOURCECALLREQUEST._serialized_end=4058
+  _RESOURCECALLREQUEST_ARGUMENTDEPENDENCIES._serialized_start=3718
+  _RESOURCECALLREQUEST_ARGUMENTDEPENDENCIES._serialized_end=3754
+  _RESOURCECALLREQUEST_ARGDEPENDENCIESENTRY._serialized_start=3756
+  _RESOURCECALLREQUEST_ARGDEPENDENCIESENTRY._serialized_end=3863
   _RESOURCECALLREQUEST_PLUGINCHECKSUMSENTRY._serialized_start=706
   _RESOURCECALLREQUEST_PLUGINCHECKSUMSENTRY._serialized_end=760
-  _TRANSFORMRESOURCEOPTIONS._serialized_start=3883
-  _TRANSFORMRESOURCEOPTIONS._serialized_end=4622
-  _TRANSFORMRESOURCEOPTIONS_PROVIDERSENTRY._serialized_start=2164
-  _TRANSFORMRESOURCEOPTIONS_PROVIDERSENTRY._serialized_end=2212
+  _TRANSFORMRESOURCEOPTIONS._serialized_start=4061
+  _TRANSFORMRESOURCEOPTIONS._serialized_end=4800
+  _TRANSFORMRESOURCEOPTIONS_PROVIDERSENTRY._serialized_start=2342
+  _TRANSFORMRESOURCEOPTIONS_PROVIDERSENTRY._serialized_end=2390
   _TRANSFORMRESOURCEOPTIONS_PLUGINCHECKSUMSENTRY._serialized_start=706
   _TRANSFORMRESOURCEOPTIONS_PLUGINCHECKSUMSENTRY._serialized_end=760
-  _TRANSFORMREQUEST._serialized_start=4625
-  _TRANSFORMREQUEST._serialized_end=4802
-  _TRANSFORMRESPONSE._serialized_start=4804
-  _TRANSFORMRESPONSE._serialized_end=4922
-  _TRANSFORMINVOKEREQUEST._serialized_start=4925
-  _TRANSFORMINVOKEREQUEST._serialized_end=5055
-  _TRANSFORMINVOKERESPONSE._serialized_start=5057
-  _TRANSFORMINVOKERESPONSE._serialized_end=5173
-  _TRANSFORMINVOKEOPTIONS._serialized_start=5176
-  _TRANSFORMINVOKEOPTIONS._serialized_end=5402
+  _TRANSFORMREQUEST._serialized_start=4803
+  _TRANSFORMREQUEST._serialized_end=4980
+  _TRANSFORMRESPONSE._serialized_start=4982
+  _TRANSFORMRESPONSE._serialized_end=5100
+  _TRANSFORMINVOKEREQUEST._serialized_start=5103
+  _TRANSFORMINVOKEREQUEST._serialized_end=5233
+  _TRANSFORMINVOKERESPONSE._serialized_start=5235
+  _TRANSFORMINVOKERESPONSE._serialized_end=5351
+  _TRANSFORMINVOKEOPTIONS._serialized_start=5354
+  _TRANSFORMINVOKEOPTIONS._serialized_end=5580
   _TRANSFORMINVOKEOPTIONS_PLUGINCHECKSUMSENTRY._serialized_start=706
   _TRANSFORMINVOKEOPTIONS_PLUGINCHECKSUMSENTRY._serialized_end=760
-  _REGISTERPACKAGEREQUEST._serialized_start=5405
-  _REGISTERPACKAGEREQUEST._serialized_end=5656
-  _REGISTERPACKAGEREQUEST_CHECKSUMSENTRY._serialized_start=5608
-  _REGISTERPACKAGEREQUEST_CHECKSUMSENTRY._serialized_end=5656
-  _REGISTERPACKAGERESPONSE._serialized_start=5658
-  _REGISTERPACKAGERESPONSE._serialized_end=5696
-  _PARAMETERIZATION._serialized_start=5698
-  _PARAMETERIZATION._serialized_end=5762
-  _RESOURCEMONITOR._serialized_start=5808
-  _RESOURCEMONITOR._serialized_end=6656
+  _REGISTERPACKAGEREQUEST._serialized_start=5583
+  _REGISTERPACKAGEREQUEST._serialized_end=5834
+  _REGISTERPACKAGEREQUEST_CHECKSUMSENTRY._serialized_start=5786
+  _REGISTERPACKAGEREQUEST_CHECKSUMSENTRY._serialized_end=5834
+  _REGISTERPACKAGERESPONSE._serialized_start=5836
+  _REGISTERPACKAGERESPONSE._serialized_end=5874
+  _PARAMETERIZATION._serialized_start=5876
+  _PARAMETERIZATION._serialized_end=5940
+  _RESOURCEMONITOR._serialized_start=5986
+  _RESOURCEMONITOR._serialized_end=6834
 # @@protoc_insertion_point(module_scope)
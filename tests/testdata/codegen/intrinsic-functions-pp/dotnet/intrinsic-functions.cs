@@ -0,0 +1,144 @@
+using System;
+using System.Collections.Generic;
+using System.Globalization;
+using System.Linq;
+using System.Net;
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using Pulumi;
+using Random = Pulumi.Random;
+
+	
+string CidrSubnet(string prefix, int newBits, int netNum) 
+{
+    var parts = prefix.Split('/');
+    var bytes = IPAddress.Parse(parts[0]).GetAddressBytes();
+    var prefixLength = int.Parse(parts[1]);
+    var bits = prefixLength + newBits;
+    if (bits > bytes.Length * 8 || netNum < 0 || netNum >= Math.Pow(2, newBits))
+    {
+        throw new ArgumentException($"cidrsubnet: can't make subnet {netNum} with {newBits} more bits");
+    }
+    for (var bit = prefixLength; bit < bytes.Length * 8; bit++)
+    {
+        var mask = (byte)(1 << (7 - bit % 8));
+        var shift = bits - 1 - bit;
+        var set = bit < bits && shift < 31 && (netNum >> shift & 1) == 1;
+        bytes[bit / 8] = set ? (byte)(bytes[bit / 8] | mask) : (byte)(bytes[bit / 8] & ~mask);
+    }
+    return $"{new IPAddress(bytes)}/{bits}";
+}
+
+	
+string ComputeBase64SHA256(string input) 
+{
+    var hash = SHA256.Create().ComputeHash(Encoding.UTF8.GetBytes(input));
+    return Convert.ToBase64String(hash);
+}
+
+	
+string ComputeMD5(string input) 
+{
+    var hash = MD5.Create().ComputeHash(Encoding.UTF8.GetBytes(input));
+    return BitConverter.ToString(hash).Replace("-","").ToLowerInvariant();
+}
+
+	
+string ComputeSHA256(string input) 
+{
+    var hash = SHA256.Create().ComputeHash(Encoding.UTF8.GetBytes(input));
+    return BitConverter.ToString(hash).Replace("-","").ToLowerInvariant();
+}
+
+	
+string ComputeSHA512(string input) 
+{
+    var hash = SHA512.Create().ComputeHash(Encoding.UTF8.GetBytes(input));
+    return BitConverter.ToString(hash).Replace("-","").ToLowerInvariant();
+}
+
+	
+string Format(string format, params object[] args) 
+{
+    var index = 0;
+    return Regex.Replace(format, "%[sdf%]", match => match.Value switch
+    {
+        "%%" => "%",
+        "%f" => Convert.ToDouble(args[index++], CultureInfo.InvariantCulture).ToString("F6", CultureInfo.InvariantCulture),
+        _ => Convert.ToString(args[index++], CultureInfo.InvariantCulture) ?? "",
+    });
+}
+
+return await Deployment.RunAsync(() => 
+{
+    var config = new Config();
+    var vpcCidr = config.Get("vpcCidr") ?? "10.0.0.0/16";
+    var pet = new Random.RandomPet("pet", new()
+    {
+        Prefix = "doggo",
+    });
+
+    // string functions
+    var shouted = "hello".ToUpperInvariant();
+
+    var whispered = "HELLO".ToLowerInvariant();
+
+    var trimmed = "  hello  ".Trim();
+
+    var replaced = "hello world".Replace("o", "0");
+
+    var formatted = Format("%s has %d legs and weighs %f kg", "doggo", 4, 12.5);
+
+    var counted = Format("%d subnets", Math.Max(2, 4));
+
+    var percentage = Format("%f%%", Math.Abs(-12.5));
+
+    // math functions
+    var smallest = Math.Min(3, Math.Min(1, 2));
+
+    var largest = Math.Max(3, Math.Max(1, 2));
+
+    var absolute = Math.Abs(-5);
+
+    var roundedUp = Math.Ceiling(1.5);
+
+    var roundedDown = Math.Floor(1.5);
+
+    // encoding and hashing functions
+    var encoded = Uri.EscapeDataString("a b&c");
+
+    var md5Digest = ComputeMD5("hello");
+
+    var sha256Digest = ComputeSHA256("hello");
+
+    var sha512Digest = ComputeSHA512("hello");
+
+    var base64Digest = ComputeBase64SHA256("hello");
+
+    return new Dictionary<string, object?>
+    {
+        ["petName"] = pet.Id.Apply(id => id.ToUpperInvariant()).Apply(upper => Format("%s-%d", upper, 4)),
+        ["summary"] = string.Join(",", new[]
+        {
+            shouted,
+            whispered,
+            trimmed,
+            replaced,
+            formatted,
+            counted,
+            percentage,
+            encoded,
+        }),
+        ["numbers"] = Format("%f,%f,%f,%f,%f", smallest, largest, absolute, roundedUp, roundedDown),
+        ["hashes"] = string.Join(",", new[]
+        {
+            md5Digest,
+            sha256Digest,
+            sha512Digest,
+            base64Digest,
+        }),
+        ["subnetCidr"] = CidrSubnet(vpcCidr, 8, 2),
+    };
+});
+
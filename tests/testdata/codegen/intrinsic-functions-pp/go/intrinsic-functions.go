@@ -0,0 +1,118 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+func base64sha256(input string) string {
+	hash := sha256.Sum256([]byte(input))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func cidrsubnet(prefix string, newbits int, netnum int) string {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		panic(err.Error())
+	}
+	bits := p.Bits() + newbits
+	if bits > p.Addr().BitLen() || netnum < 0 || netnum >= 1<<newbits {
+		panic(fmt.Sprintf("cidrsubnet: can't make subnet %d with %d more bits in %s", netnum, newbits, prefix))
+	}
+	addr := p.Masked().Addr().AsSlice()
+	for i := 0; i < newbits; i++ {
+		if netnum&(1<<i) != 0 {
+			bit := bits - 1 - i
+			addr[bit/8] |= 1 << (7 - bit%8)
+		}
+	}
+	subnet, _ := netip.AddrFromSlice(addr)
+	return netip.PrefixFrom(subnet, bits).String()
+}
+
+func md5Hash(input string) string {
+	hash := md5.Sum([]byte(input))
+	return hex.EncodeToString(hash[:])
+}
+
+func sha256Hash(input string) string {
+	hash := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(hash[:])
+}
+
+func sha512Hash(input string) string {
+	hash := sha512.Sum512([]byte(input))
+	return hex.EncodeToString(hash[:])
+}
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		cfg := config.New(ctx, "")
+		vpcCidr := "10.0.0.0/16"
+		if param := cfg.Get("vpcCidr"); param != "" {
+			vpcCidr = param
+		}
+		pet, err := random.NewRandomPet(ctx, "pet", &random.RandomPetArgs{
+			Prefix: pulumi.String("doggo"),
+		})
+		if err != nil {
+			return err
+		}
+		// string functions
+		shouted := strings.ToUpper("hello")
+		whispered := strings.ToLower("HELLO")
+		trimmed := strings.TrimSpace("  hello  ")
+		replaced := strings.ReplaceAll("hello world", "o", "0")
+		formatted := fmt.Sprintf("%s has %d legs and weighs %f kg", "doggo", 4, 12.5)
+		counted := fmt.Sprintf("%d subnets", int(math.Max(2, 4)))
+		percentage := fmt.Sprintf("%f%%", math.Abs(-12.5))
+		// math functions
+		smallest := math.Min(3, math.Min(1, 2))
+		largest := math.Max(3, math.Max(1, 2))
+		absolute := math.Abs(-5)
+		roundedUp := math.Ceil(1.5)
+		roundedDown := math.Floor(1.5)
+		// encoding and hashing functions
+		encoded := url.QueryEscape("a b&c")
+		md5Digest := md5Hash("hello")
+		sha256Digest := sha256Hash("hello")
+		sha512Digest := sha512Hash("hello")
+		base64Digest := base64sha256("hello")
+		ctx.Export("petName", pet.ID().ApplyT(func(id string) (pulumi.String, error) {
+			return pulumi.String(strings.ToUpper(id)), nil
+		}).(pulumi.StringOutput).ApplyT(func(upper string) (pulumi.String, error) {
+			return pulumi.String(fmt.Sprintf("%s-%d", upper, 4)), nil
+		}).(pulumi.StringOutput))
+		ctx.Export("summary", pulumi.String(strings.Join([]string{
+			shouted,
+			whispered,
+			trimmed,
+			replaced,
+			formatted,
+			counted,
+			percentage,
+			encoded,
+		}, ",")))
+		ctx.Export("numbers", pulumi.String(fmt.Sprintf("%f,%f,%f,%f,%f", smallest, largest, absolute, roundedUp, roundedDown)))
+		ctx.Export("hashes", pulumi.String(strings.Join([]string{
+			md5Digest,
+			sha256Digest,
+			sha512Digest,
+			base64Digest,
+		}, ",")))
+		ctx.Export("subnetCidr", pulumi.String(cidrsubnet(vpcCidr, 8, 2)))
+		return nil
+	})
+}
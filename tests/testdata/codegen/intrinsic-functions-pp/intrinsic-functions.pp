@@ -0,0 +1,48 @@
+config vpcCidr string {
+	default = "10.0.0.0/16"
+}
+
+resource pet "random:index/randomPet:RandomPet" {
+	prefix = "doggo"
+}
+
+# string functions
+shouted = upper("hello")
+whispered = lower("HELLO")
+trimmed = trimSpace("  hello  ")
+replaced = replace("hello world", "o", "0")
+formatted = format("%s has %d legs and weighs %f kg", "doggo", 4, 12.5)
+counted = format("%d subnets", max(2, 4))
+percentage = format("%f%%", abs(-12.5))
+
+# math functions
+smallest = min(3, 1, 2)
+largest = max(3, 1, 2)
+absolute = abs(-5)
+roundedUp = ceil(1.5)
+roundedDown = floor(1.5)
+
+# encoding and hashing functions
+encoded = urlEncode("a b&c")
+md5Digest = md5("hello")
+sha256Digest = sha256("hello")
+sha512Digest = sha512("hello")
+base64Digest = base64sha256("hello")
+
+output petName {
+	value = format("%s-%d", upper(pet.id), 4)
+}
+output summary {
+	value = join(",", [shouted, whispered, trimmed, replaced, formatted, counted, percentage, encoded])
+}
+output numbers {
+	value = format("%f,%f,%f,%f,%f", smallest, largest, absolute, roundedUp, roundedDown)
+}
+output hashes {
+	value = join(",", [md5Digest, sha256Digest, sha512Digest, base64Digest])
+}
+
+# network functions
+output subnetCidr {
+	value = cidrsubnet(vpcCidr, 8, 2)
+}
@@ -0,0 +1,110 @@
+import * as pulumi from "@pulumi/pulumi";
+import * as crypto from "crypto";
+import * as random from "@pulumi/random";
+
+function cidrsubnet(prefix: string, newbits: number, netnum: number): string {
+    const [address, length] = prefix.split("/");
+    let bytes: number[];
+    if (address.includes(":")) {
+        const [head, tail] = address.includes("::") ? address.split("::") : [address, undefined];
+        const groups = (s: string | undefined) => s ? s.split(":").map(g => parseInt(g, 16)) : [];
+        const headGroups = groups(head);
+        const tailGroups = groups(tail);
+        const zeros = new Array(8 - headGroups.length - tailGroups.length).fill(0);
+        bytes = [];
+        for (const w of [...headGroups, ...zeros, ...tailGroups]) {
+            bytes.push(w >> 8, w & 0xff);
+        }
+    } else {
+        bytes = address.split(".").map(b => parseInt(b, 10));
+    }
+    const bits = parseInt(length, 10) + newbits;
+    if (bits > bytes.length * 8 || netnum < 0 || netnum >= Math.pow(2, newbits)) {
+        throw new Error(`cidrsubnet: can't make subnet ${netnum} with ${newbits} more bits`);
+    }
+    for (let bit = bits - newbits; bit < bytes.length * 8; bit++) {
+        const i = Math.floor(bit / 8);
+        const mask = 1 << (7 - bit % 8);
+        const set = bit < bits && Math.floor(netnum / Math.pow(2, bits - 1 - bit)) % 2 === 1;
+        bytes[i] = set ? bytes[i] | mask : bytes[i] & ~mask;
+    }
+    if (bytes.length === 4) {
+        return `${bytes.join(".")}/${bits}`;
+    }
+    const words = [];
+    for (let i = 0; i < 16; i += 2) {
+        words.push((bytes[i] << 8) | bytes[i + 1]);
+    }
+    // Compress the longest run of two or more zero groups.
+    let start = -1, run = 1;
+    for (let i = 0; i < 8; i++) {
+        let j = i;
+        while (j < 8 && words[j] === 0) {
+            j++;
+        }
+        if (j - i > run) {
+            start = i;
+            run = j - i;
+        }
+    }
+    const hex = (ws: number[]) => ws.map(w => w.toString(16)).join(":");
+    if (start < 0) {
+        return `${hex(words)}/${bits}`;
+    }
+    return `${hex(words.slice(0, start))}::${hex(words.slice(start + run))}/${bits}`;
+}
+
+
+function format(format: string, ...args: any[]): string {
+    let index = 0;
+    return format.replace(/%[sdf%]/g, verb => {
+        if (verb === "%%") {
+            return "%";
+        }
+        const arg = args[index++];
+        return verb === "%f" ? Number(arg).toFixed(6) : String(arg);
+    });
+}
+
+const config = new pulumi.Config();
+const vpcCidr = config.get("vpcCidr") || "10.0.0.0/16";
+const pet = new random.RandomPet("pet", {prefix: "doggo"});
+// string functions
+const shouted = "hello".toUpperCase();
+const whispered = "HELLO".toLowerCase();
+const trimmed = "  hello  ".trim();
+const replaced = "hello world".split("o").join("0");
+const formatted = format("%s has %d legs and weighs %f kg", "doggo", 4, 12.5);
+const counted = format("%d subnets", Math.max(2, 4));
+const percentage = format("%f%%", Math.abs(-12.5));
+// math functions
+const smallest = Math.min(3, 1, 2);
+const largest = Math.max(3, 1, 2);
+const absolute = Math.abs(-5);
+const roundedUp = Math.ceil(1.5);
+const roundedDown = Math.floor(1.5);
+// encoding and hashing functions
+const encoded = encodeURIComponent("a b&c");
+const md5Digest = crypto.createHash('md5').update("hello").digest('hex');
+const sha256Digest = crypto.createHash('sha256').update("hello").digest('hex');
+const sha512Digest = crypto.createHash('sha512').update("hello").digest('hex');
+const base64Digest = crypto.createHash('sha256').update("hello").digest('base64');
+export const petName = pet.id.apply(id => id.toUpperCase()).apply(upper => format("%s-%d", upper, 4));
+export const summary = [
+    shouted,
+    whispered,
+    trimmed,
+    replaced,
+    formatted,
+    counted,
+    percentage,
+    encoded,
+].join(",");
+export const numbers = format("%f,%f,%f,%f,%f", smallest, largest, absolute, roundedUp, roundedDown);
+export const hashes = [
+    md5Digest,
+    sha256Digest,
+    sha512Digest,
+    base64Digest,
+].join(",");
+export const subnetCidr = cidrsubnet(vpcCidr, 8, 2);
@@ -0,0 +1,61 @@
+import pulumi
+import base64
+import hashlib
+import ipaddress
+import math
+import pulumi_random as random
+import urllib.parse
+
+def cidrsubnet(prefix, newbits, netnum):
+    network = ipaddress.ip_network(prefix, strict=False)
+    prefixlen = network.prefixlen + newbits
+    if prefixlen > network.max_prefixlen or netnum < 0 or netnum >= 2 ** newbits:
+        raise ValueError(f"cidrsubnet: can't make subnet {netnum} with {newbits} more bits in {prefix}")
+    address = int(network.network_address) + (netnum << (network.max_prefixlen - prefixlen))
+    return str(type(network)((address, prefixlen)))
+
+
+config = pulumi.Config()
+vpc_cidr = config.get("vpcCidr")
+if vpc_cidr is None:
+    vpc_cidr = "10.0.0.0/16"
+pet = random.RandomPet("pet", prefix="doggo")
+# string functions
+shouted = "hello".upper()
+whispered = "HELLO".lower()
+trimmed = "  hello  ".strip()
+replaced = "hello world".replace("o", "0")
+formatted = "%s has %d legs and weighs %f kg" % ("doggo", 4, 12.5)
+counted = "%d subnets" % (max(2, 4),)
+percentage = "%f%%" % (abs(-12.5),)
+# math functions
+smallest = min(3, 1, 2)
+largest = max(3, 1, 2)
+absolute = abs(-5)
+rounded_up = math.ceil(1.5)
+rounded_down = math.floor(1.5)
+# encoding and hashing functions
+encoded = urllib.parse.quote("a b&c", safe="")
+md5_digest = hashlib.md5("hello".encode()).hexdigest()
+sha256_digest = hashlib.sha256("hello".encode()).hexdigest()
+sha512_digest = hashlib.sha512("hello".encode()).hexdigest()
+base64_digest = base64.b64encode(hashlib.sha256("hello".encode()).digest()).decode()
+pulumi.export("petName", pet.id.apply(lambda id: id.upper()).apply(lambda upper: "%s-%d" % (upper, 4)))
+pulumi.export("summary", ",".join([
+    shouted,
+    whispered,
+    trimmed,
+    replaced,
+    formatted,
+    counted,
+    percentage,
+    encoded,
+]))
+pulumi.export("numbers", "%f,%f,%f,%f,%f" % (smallest, largest, absolute, rounded_up, rounded_down))
+pulumi.export("hashes", ",".join([
+    md5_digest,
+    sha256_digest,
+    sha512_digest,
+    base64_digest,
+]))
+pulumi.export("subnetCidr", cidrsubnet(vpc_cidr, 8, 2))